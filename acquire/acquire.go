@@ -0,0 +1,222 @@
+// Package acquire turns the wishlist or reading list items that are not
+// in stock into acquisition suggestions: for each, the best edition
+// available to buy, its current price and the supplier offering it, in a
+// report a buyer can order from.
+package acquire
+
+import (
+	"context"
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/price"
+)
+
+// DefaultMaxEditions is the number of editions priced per item when
+// Suggester.MaxEditions is zero.
+const DefaultMaxEditions = 5
+
+// Stock is the inventory wishlist items are matched against.
+type Stock struct {
+	// MinMatch is the lowest title/author match confidence at which a
+	// stock row without a matching ISBN holds an item; zero means
+	// enrich.DefaultMinMatch.
+	MinMatch float64
+	isbns    map[string]bool
+	rows     []*input.Row
+}
+
+// Add records a stock row. Rows with a quantity of zero are out of stock
+// and left out.
+func (s *Stock) Add(row *input.Row) {
+	if n, err := strconv.Atoi(strings.TrimSpace(row.Quantity)); err == nil && n <= 0 {
+		return
+	}
+	if s.isbns == nil {
+		s.isbns = make(map[string]bool)
+	}
+	if code := isbn.To13(isbn.Normalize(row.ISBN)); isbn.Valid13(code) {
+		s.isbns[code] = true
+	}
+	if row.Title != "" {
+		s.rows = append(s.rows, row)
+	}
+}
+
+// Read adds every row of r.
+func (s *Stock) Read(r input.Reader) error {
+	for {
+		row, err := r.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		s.Add(row)
+	}
+}
+
+// Has reports whether the wishlist row is in stock, as the edition it
+// names or, going by title and author, as any edition. b is the record
+// found for the row, or nil.
+func (s *Stock) Has(row *input.Row, b *book.BookInfo) bool {
+	codes := []string{isbn.To13(isbn.Normalize(row.ISBN))}
+	if b == nil {
+		b = &book.BookInfo{Title: row.Title}
+		if row.Author != "" {
+			b.Authors = []string{row.Author}
+		}
+	} else {
+		codes = append(codes, isbn.To13(b.ISBN()))
+	}
+	for _, c := range codes {
+		if s.isbns[c] {
+			return true
+		}
+	}
+	if b.Title == "" {
+		return false
+	}
+	minMatch := s.MinMatch
+	if minMatch <= 0 {
+		minMatch = enrich.DefaultMinMatch
+	}
+	for _, r := range s.rows {
+		if enrich.MatchScore(r.Title, r.Author, b) >= minMatch {
+			return true
+		}
+	}
+	return false
+}
+
+// Suggestion is the edition suggested for one wishlist item.
+type Suggestion struct {
+	Row *input.Row
+	// Book is the record found for the item, or nil.
+	Book *book.BookInfo
+	// Edition is the suggested edition; its Label is empty for the
+	// edition the item names.
+	Edition book.Sibling
+	// Offer is the cheapest offer for the edition; its Amount is zero
+	// when no edition has offers.
+	Offer price.Offer
+	// Offers counts the offers found for the edition.
+	Offers int
+	// Note tells why there is no suggestion, or what to check.
+	Note string
+}
+
+// Suggester picks the edition to buy among the editions of a book.
+type Suggester struct {
+	Prices *price.Lookup
+	// Condition restricts the offers to new or used copies; "" takes
+	// either.
+	Condition price.Condition
+	// MaxEditions limits the editions priced per item; zero means
+	// DefaultMaxEditions.
+	MaxEditions int
+}
+
+// Suggest returns the suggestion for row, found as b. The edition named
+// by the row comes first, then the other editions of its work listed in
+// b.Siblings, which the enricher fills in when it resolves ISBN
+// families. International editions are only considered for an item
+// that is one itself, since they cannot be sold in every market. The
+// cheapest offer wins; on a tie, the earlier edition.
+func (s *Suggester) Suggest(ctx context.Context, row *input.Row, b *book.BookInfo) Suggestion {
+	sg := Suggestion{Row: row, Book: b}
+	if b == nil {
+		sg.Note = "not found"
+		return sg
+	}
+	editions := []book.Sibling{{ISBN13: isbn.To13(b.ISBN()), International: b.International}}
+	for _, sib := range b.Siblings {
+		if !sib.International || b.International {
+			editions = append(editions, sib)
+		}
+	}
+	if editions[0].ISBN13 == "" {
+		editions = editions[1:]
+	}
+	if len(editions) == 0 {
+		sg.Note = "no ISBN to price"
+		return sg
+	}
+	limit := s.MaxEditions
+	if limit <= 0 {
+		limit = DefaultMaxEditions
+	}
+	editions = editions[:min(len(editions), limit)]
+	sg.Edition = editions[0]
+	var errs []string
+	for _, ed := range editions {
+		offers, err := s.Prices.Offers(ctx, ed.ISBN13)
+		if err != nil {
+			// Joined errors of several sources are one per line.
+			errs = append(errs, ed.ISBN13+": "+strings.ReplaceAll(err.Error(), "\n", "; "))
+			continue
+		}
+		var best price.Offer
+		n := 0
+		for _, o := range offers {
+			if s.Condition != "" && o.Condition != s.Condition {
+				continue
+			}
+			n++
+			if best.Amount == 0 || o.Amount < best.Amount {
+				best = o
+			}
+		}
+		if n > 0 && (sg.Offer.Amount == 0 || best.Amount < sg.Offer.Amount) {
+			sg.Edition, sg.Offer, sg.Offers = ed, best, n
+		}
+	}
+	switch {
+	case sg.Offer.Amount == 0 && len(errs) == len(editions):
+		sg.Note = "price lookup failed: " + strings.Join(errs, "; ")
+	case sg.Offer.Amount == 0:
+		sg.Note = "no offers"
+	case sg.Edition.International:
+		sg.Note = "international edition"
+	}
+	return sg
+}
+
+// Header is the header row written by WriteReport.
+var Header = []string{
+	columns.RowHeader, "ISBN", "Title", "Author", "Quantity",
+	"Found Title", "Found Authors", "Suggested ISBN", "Edition",
+	"Condition", "Price", "Currency", "Supplier", "Offers", "Note",
+}
+
+// WriteReport writes suggestions as CSV. Items are numbered by their
+// spreadsheet row in the wishlist, below its header.
+func WriteReport(w io.Writer, suggestions []Suggestion) error {
+	cw := csv.NewWriter(w)
+	cw.Write(Header)
+	for _, s := range suggestions {
+		var title, authors, amount, offers string
+		if s.Book != nil {
+			title, authors = s.Book.FullTitle(), strings.Join(s.Book.Authors, "; ")
+		}
+		if s.Offer.Amount > 0 {
+			amount = strconv.FormatFloat(s.Offer.Amount, 'f', 2, 64)
+			offers = strconv.Itoa(s.Offers)
+		}
+		cw.Write([]string{
+			strconv.Itoa(s.Row.Index + 2), s.Row.ISBN, s.Row.Title, s.Row.Author, s.Row.Quantity,
+			title, authors, s.Edition.ISBN13, s.Edition.Label,
+			string(s.Offer.Condition), amount, s.Offer.Currency, s.Offer.Source, offers, s.Note,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,371 @@
+// Package alert checks a catalog against sales for two kinds of stock
+// problems: fast sellers about to run out, and copies left unsold for so
+// long that they should be marked down.
+package alert
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/reprice"
+)
+
+// Markdown is a step of the markdown schedule: copies unsold for
+// AfterDays are marked down by Percent.
+type Markdown struct {
+	AfterDays int     `json:"after_days"`
+	Percent   float64 `json:"percent"`
+}
+
+// Rules set when alerts are raised.
+type Rules struct {
+	// WindowDays is the period sales are counted over.
+	WindowDays int
+	// FastSales is how many copies a title must have sold in the window
+	// to count as a fast seller.
+	FastSales int
+	// CoverDays raises a low-stock alert for fast sellers whose stock
+	// lasts less than this many days at their rate of sale.
+	CoverDays int
+	// StaleDays raises a stale-stock alert for copies unsold that long
+	// after they were acquired or last sold.
+	StaleDays int
+	// Markdowns is the markdown schedule of stale copies, by AfterDays.
+	Markdowns []Markdown
+	// Pricing rounds the marked-down prices.
+	Pricing reprice.Rules
+}
+
+// DefaultMarkdowns marks stale copies down further the longer they stay.
+var DefaultMarkdowns = []Markdown{{180, 10}, {270, 25}, {365, 40}}
+
+// DefaultRules are the rules of the alerts command.
+func DefaultRules() *Rules {
+	return &Rules{
+		WindowDays: 90,
+		FastSales:  3,
+		CoverDays:  30,
+		StaleDays:  180,
+		Markdowns:  DefaultMarkdowns,
+		Pricing:    reprice.Rules{Ending: reprice.DefaultEnding},
+	}
+}
+
+// markdown returns the markdown of copies unsold for days, or zero.
+func (r *Rules) markdown(days int) float64 {
+	var pct float64
+	after := -1
+	for _, m := range r.Markdowns {
+		if m.AfterDays <= days && m.AfterDays > after {
+			pct, after = m.Percent, m.AfterDays
+		}
+	}
+	return pct
+}
+
+// key identifies a title across the catalog and sales: its ISBN-13, or
+// its title when it has no valid ISBN.
+func key(row *input.Row) string {
+	if code := isbn.To13(isbn.Normalize(row.ISBN)); isbn.Valid13(code) {
+		return code
+	}
+	if t := strings.ToLower(strings.Join(strings.Fields(row.Title), " ")); t != "" {
+		return "title:" + t
+	}
+	return ""
+}
+
+// dateLayouts are the date formats of Date columns. Slashed dates are
+// read month first, as spreadsheets in the US write them.
+var dateLayouts = []string{
+	time.DateOnly, time.RFC3339, "2006-01-02 15:04:05", "2006/01/02",
+	"1/2/2006", "1/2/06", "01-02-06", "1-2-2006", "2 Jan 2006", "Jan 2, 2006", "January 2, 2006",
+}
+
+// ParseDate reads a date cell: an ISO date, a common spreadsheet
+// format, or a spreadsheet serial number.
+func ParseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, l := range dateLayouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t, true
+		}
+	}
+	// Days since 1899-12-30, the epoch of spreadsheet dates.
+	if n, err := strconv.ParseFloat(s, 64); err == nil && n > 1 && n < 100000 {
+		return time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC).AddDate(0, 0, int(n)), true
+	}
+	return time.Time{}, false
+}
+
+// Sales are the sales of each title, from sales imports.
+type Sales struct {
+	sales map[string][]sale
+}
+
+type sale struct {
+	at time.Time
+	n  int
+}
+
+// Read adds the rows of a sales import: one row per sale or per
+// order line, with the copies sold in the quantity column (one when
+// empty) and the date of the sale. It returns how many rows were
+// skipped for want of an ISBN, a title or a readable date.
+func (s *Sales) Read(r input.Reader) (skipped int, err error) {
+	if s.sales == nil {
+		s.sales = make(map[string][]sale)
+	}
+	for {
+		row, err := r.Next()
+		if err == io.EOF {
+			return skipped, nil
+		}
+		if err != nil {
+			return skipped, err
+		}
+		k := key(row)
+		at, ok := ParseDate(row.Date)
+		if k == "" || !ok {
+			skipped++
+			continue
+		}
+		n := 1
+		if q, err := strconv.Atoi(strings.TrimSpace(row.Quantity)); err == nil {
+			n = q
+		}
+		s.sales[k] = append(s.sales[k], sale{at, n})
+	}
+}
+
+// since returns the copies of a title sold since from, and its last
+// sale before now.
+func (s *Sales) since(k string, from, now time.Time) (sold int, last time.Time) {
+	for _, v := range s.sales[k] {
+		if v.at.After(now) {
+			continue
+		}
+		if !v.at.Before(from) {
+			sold += v.n
+		}
+		if v.at.After(last) {
+			last = v.at
+		}
+	}
+	return sold, last
+}
+
+// Kind is the kind of an alert.
+type Kind string
+
+const (
+	// LowStock flags fast sellers about to run out.
+	LowStock Kind = "low stock"
+	// Stale flags copies unsold past the rules' StaleDays.
+	Stale Kind = "stale"
+)
+
+// Alert is a stock problem of a catalog row. For low-stock alerts,
+// Quantity is the stock of the title across the rows listing it.
+type Alert struct {
+	Kind Kind `json:"kind"`
+	// Row is the spreadsheet row number of the catalog row.
+	Row      int       `json:"row"`
+	ISBN     string    `json:"isbn,omitempty"`
+	Title    string    `json:"title,omitempty"`
+	Quantity int       `json:"quantity"`
+	Sold     int       `json:"sold"`
+	LastSale time.Time `json:"last_sale,omitzero"`
+	Acquired time.Time `json:"acquired,omitzero"`
+	// CoverDays is how long the stock lasts at the rate of sale.
+	CoverDays float64 `json:"cover_days,omitempty"`
+	// UnsoldDays is how long stale copies have been waiting for a sale.
+	UnsoldDays int     `json:"unsold_days,omitempty"`
+	Price      float64 `json:"price,omitempty"`
+	// Markdown is the suggested markdown of stale copies, in percent,
+	// and Suggested the marked-down price.
+	Markdown  float64 `json:"markdown,omitempty"`
+	Suggested float64 `json:"suggested_price,omitempty"`
+}
+
+// title is a catalog title, over the rows listing it.
+type title struct {
+	first    *input.Row
+	quantity int
+}
+
+// Check reads a catalog, with the quantity in stock, the acquisition
+// date and the price of each row, and returns its alerts as of now:
+// low-stock alerts first, by days of cover, then stale copies, longest
+// unsold first.
+func (r *Rules) Check(catalog input.Reader, s *Sales, now time.Time) ([]Alert, error) {
+	from := now.AddDate(0, 0, -r.WindowDays)
+	var low, stale []Alert
+	titles := make(map[string]*title)
+	var order []string
+	for {
+		row, err := catalog.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		qty, err := strconv.Atoi(strings.TrimSpace(row.Quantity))
+		if err != nil {
+			continue
+		}
+		k := key(row)
+		if k == "" {
+			continue
+		}
+		if t, ok := titles[k]; ok {
+			t.quantity += qty
+		} else {
+			titles[k] = &title{row, qty}
+			order = append(order, k)
+		}
+		if qty <= 0 {
+			continue
+		}
+		acquired, _ := ParseDate(row.Date)
+		_, last := s.since(k, from, now)
+		waiting := acquired
+		if last.After(waiting) {
+			waiting = last
+		}
+		if waiting.IsZero() {
+			continue
+		}
+		days := int(now.Sub(waiting).Hours() / 24)
+		if days < r.StaleDays {
+			continue
+		}
+		a := alertOf(Stale, row, qty)
+		a.Acquired, a.LastSale, a.UnsoldDays = acquired, last, days
+		a.Markdown = r.markdown(days)
+		if a.Price > 0 && a.Markdown > 0 {
+			a.Suggested = r.Pricing.Round(a.Price * (1 - a.Markdown/100))
+		}
+		stale = append(stale, a)
+	}
+	for _, k := range order {
+		t := titles[k]
+		sold, last := s.since(k, from, now)
+		if sold <= 0 || sold < r.FastSales {
+			continue
+		}
+		cover := float64(max(t.quantity, 0)) * float64(r.WindowDays) / float64(sold)
+		if cover >= float64(r.CoverDays) {
+			continue
+		}
+		a := alertOf(LowStock, t.first, t.quantity)
+		a.Sold, a.LastSale, a.CoverDays = sold, last, math.Round(cover*10)/10
+		a.Acquired, _ = ParseDate(t.first.Date)
+		low = append(low, a)
+	}
+	sort.SliceStable(low, func(i, j int) bool { return low[i].CoverDays < low[j].CoverDays })
+	sort.SliceStable(stale, func(i, j int) bool { return stale[i].UnsoldDays > stale[j].UnsoldDays })
+	return append(low, stale...), nil
+}
+
+func alertOf(k Kind, row *input.Row, qty int) Alert {
+	a := Alert{Kind: k, Row: row.Line(), ISBN: row.ISBN, Title: row.Title, Quantity: qty}
+	a.Price, _ = reprice.ParseAmount(row.Price)
+	return a
+}
+
+// Count returns how many alerts of each kind there are.
+func Count(alerts []Alert) (low, stale int) {
+	for _, a := range alerts {
+		if a.Kind == LowStock {
+			low++
+		} else {
+			stale++
+		}
+	}
+	return low, stale
+}
+
+// ReportHeader is the header row written by WriteReport.
+var ReportHeader = []string{
+	"Alert", columns.RowHeader, "ISBN", "Title", "Quantity", "Sold", "Last Sale", "Acquired",
+	"Days Of Cover", "Days Unsold", "Price", "Markdown %", "Suggested Price",
+}
+
+// WriteReport writes alerts as CSV.
+func WriteReport(w io.Writer, alerts []Alert) error {
+	date := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.DateOnly)
+	}
+	num := func(v float64, prec int, keep bool) string {
+		if v == 0 && !keep {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'f', prec, 64)
+	}
+	cw := csv.NewWriter(w)
+	cw.Write(ReportHeader)
+	for _, a := range alerts {
+		var unsold string
+		if a.Kind == Stale {
+			unsold = strconv.Itoa(a.UnsoldDays)
+		}
+		cw.Write([]string{
+			string(a.Kind), strconv.Itoa(a.Row), a.ISBN, a.Title, strconv.Itoa(a.Quantity),
+			strconv.Itoa(a.Sold), date(a.LastSale), date(a.Acquired),
+			num(a.CoverDays, 1, a.Kind == LowStock), unsold, num(a.Price, 2, false),
+			num(a.Markdown, -1, false), num(a.Suggested, 2, false),
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// Notify posts text and the alerts as JSON to a webhook. The "text"
+// field is what chat webhooks, such as Slack's or Mattermost's, show.
+func Notify(ctx context.Context, c *http.Client, url, text string, alerts []Alert) error {
+	body, err := json.Marshal(struct {
+		Text   string  `json:"text"`
+		Alerts []Alert `json:"alerts"`
+	}{text, alerts})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", provider.UserAgent)
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("notify: %s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
@@ -0,0 +1,323 @@
+// Package author normalizes the personal names providers report for
+// authors, which come as "Tolkien, J. R. R.", "J.R.R. Tolkien" or "John
+// Ronald Reuel Tolkien" for the same person, and detects names that
+// refer to the same person.
+package author
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Format selects how names are written to output.
+type Format string
+
+const (
+	// Natural writes names in reading order, e.g. "J. R. R. Tolkien".
+	Natural Format = "natural"
+	// Inverted writes names surname first, e.g. "Tolkien, J. R. R.", as
+	// library catalogs sort them.
+	Inverted Format = "inverted"
+)
+
+// ParseFormat parses an -author-format flag value. The empty string
+// selects Natural.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(s))); f {
+	case "":
+		return Natural, nil
+	case Natural, Inverted:
+		return f, nil
+	}
+	return "", fmt.Errorf("unknown author format %q (want natural or inverted)", s)
+}
+
+// Format renders one name in format f.
+func (f Format) Format(name string) string {
+	if f == Inverted {
+		return Invert(name)
+	}
+	return Normalize(name)
+}
+
+// FormatAll renders names in format f, dropping the names that refer to
+// a person already listed.
+func (f Format) FormatAll(names []string) []string {
+	names = Dedupe(names)
+	for i, n := range names {
+		names[i] = f.Format(n)
+	}
+	return names
+}
+
+// name is a personal name split into its parts.
+type name struct {
+	// given holds the forenames and initials, initials as "J.".
+	given []string
+	// surname includes its particles, e.g. "van Gogh".
+	surname string
+	// suffix is a generational suffix such as "Jr.".
+	suffix string
+	// verbatim is set for names that are not parsed, such as those of
+	// organizations; they are written as reported.
+	verbatim string
+}
+
+var (
+	// dates matches the life dates libraries append to names, e.g.
+	// ", 1892-1973" or ", b. 1947".
+	dates = regexp.MustCompile(`,?\s*(?:[bd]\.\s*)?\d{3,4}\??\s*-?\s*(?:\d{3,4}\??)?\.?$`)
+	// fuller matches the fuller form of the forenames libraries add in
+	// parentheses, e.g. "Tolkien, J. R. R. (John Ronald Reuel)".
+	fuller = regexp.MustCompile(`\s*\([^)]*\)`)
+	// initials matches run-together initials such as "J.R.R.".
+	initials = regexp.MustCompile(`^(?:\p{Lu}\.){2,}$`)
+	// roles matches the relator terms libraries append, e.g. ", author.".
+	roles = regexp.MustCompile(`(?i),\s*(?:author|editor|ed|illustrator|translator|compiler)\.?$`)
+)
+
+// suffixes are the generational suffixes kept apart from the surname.
+var suffixes = map[string]string{
+	"jr": "Jr.", "jr.": "Jr.", "sr": "Sr.", "sr.": "Sr.",
+	"ii": "II", "iii": "III", "iv": "IV",
+}
+
+// particles are the words that belong to the surname that follows them,
+// e.g. "van" in "Vincent van Gogh" and "Le" in "Ursula K. Le Guin".
+var particles = map[string]bool{
+	"van": true, "von": true, "de": true, "da": true, "di": true, "du": true,
+	"del": true, "della": true, "der": true, "den": true, "ter": true,
+	"la": true, "le": true, "dos": true, "das": true, "bin": true, "ibn": true, "al": true,
+}
+
+// corporate are words that mark the name of an organization, which has
+// no surname to invert.
+var corporate = map[string]bool{
+	"association": true, "committee": true, "company": true, "council": true,
+	"department": true, "inc": true, "institute": true, "ltd": true,
+	"museum": true, "organization": true, "press": true, "society": true,
+	"staff": true, "university": true,
+}
+
+func parse(s string) name {
+	s = strings.Join(strings.Fields(s), " ")
+	s = fuller.ReplaceAllString(s, "")
+	for {
+		t := roles.ReplaceAllString(dates.ReplaceAllString(s, ""), "")
+		if t == s {
+			break
+		}
+		s = t
+	}
+	s = strings.TrimRight(s, " ,;")
+	for _, w := range strings.FieldsFunc(strings.ToLower(s), func(r rune) bool { return !unicode.IsLetter(r) }) {
+		if corporate[w] {
+			return name{verbatim: s}
+		}
+	}
+	var n name
+	if surname, rest, ok := strings.Cut(s, ","); ok {
+		rest = strings.TrimSpace(rest)
+		if suf, ok := suffixes[strings.ToLower(rest)]; ok {
+			// "Martin Luther King, Jr." is in reading order.
+			n = parseNatural(surname)
+			n.suffix = suf
+			return n
+		}
+		given, suffix, _ := strings.Cut(rest, ",")
+		n.surname = fixCase(strings.TrimSpace(surname), false)
+		n.given = givenNames(strings.Fields(given))
+		if suf, ok := suffixes[strings.ToLower(strings.TrimSpace(suffix))]; ok {
+			n.suffix = suf
+		}
+		return n
+	}
+	return parseNatural(s)
+}
+
+// parseNatural parses a name in reading order.
+func parseNatural(s string) name {
+	var n name
+	words := strings.Fields(s)
+	if len(words) > 1 {
+		if suf, ok := suffixes[strings.ToLower(strings.TrimRight(words[len(words)-1], ","))]; ok {
+			n.suffix = suf
+			words = words[:len(words)-1]
+		}
+	}
+	if len(words) == 0 {
+		return n
+	}
+	for i := range words {
+		words[i] = strings.TrimRight(words[i], ",")
+	}
+	// The surname starts at the particles before the last word.
+	start := len(words) - 1
+	for start > 1 && particles[strings.ToLower(words[start-1])] {
+		start--
+	}
+	surname := words[start:]
+	for i, w := range surname {
+		surname[i] = fixCase(w, i < len(surname)-1)
+	}
+	n.surname = strings.Join(surname, " ")
+	n.given = givenNames(words[:start])
+	return n
+}
+
+// givenNames splits run-together initials and writes initials as "J.".
+func givenNames(words []string) []string {
+	var out []string
+	for _, w := range words {
+		switch {
+		case initials.MatchString(w):
+			for _, r := range strings.Split(strings.TrimSuffix(w, "."), ".") {
+				out = append(out, r+".")
+			}
+		case utf8.RuneCountInString(w) == 1 && unicode.IsLetter([]rune(w)[0]):
+			out = append(out, strings.ToUpper(w)+".")
+		default:
+			out = append(out, fixCase(w, false))
+		}
+	}
+	return out
+}
+
+// fixCase capitalizes words written entirely in upper or lower case,
+// such as "TOLKIEN". Particles are kept as written, or lowered when in
+// capitals. Mixed-case words such as "McCarthy" are left alone.
+func fixCase(w string, particle bool) string {
+	if particle && particles[strings.ToLower(w)] {
+		if w == strings.ToUpper(w) {
+			return strings.ToLower(w)
+		}
+		return w
+	}
+	if w != strings.ToUpper(w) && w != strings.ToLower(w) {
+		return w
+	}
+	var b strings.Builder
+	start := true
+	for _, r := range w {
+		if start {
+			b.WriteRune(unicode.ToUpper(r))
+		} else {
+			b.WriteRune(unicode.ToLower(r))
+		}
+		start = r == '-' || r == '\'' || r == '’'
+	}
+	return b.String()
+}
+
+func (n name) natural() string {
+	if n.verbatim != "" {
+		return n.verbatim
+	}
+	parts := append(append([]string(nil), n.given...), n.surname)
+	if n.suffix != "" {
+		parts = append(parts, n.suffix)
+	}
+	return strings.Join(parts, " ")
+}
+
+func (n name) inverted() string {
+	if n.verbatim != "" {
+		return n.verbatim
+	}
+	s := n.surname
+	if len(n.given) > 0 {
+		s += ", " + strings.Join(n.given, " ")
+	}
+	if n.suffix != "" {
+		s += ", " + n.suffix
+	}
+	return s
+}
+
+// Normalize returns name in reading order, without the life dates and
+// fuller forms libraries add, with initials written "J. R. R." and
+// all-capital names capitalized. Names of organizations are only
+// trimmed.
+func Normalize(s string) string {
+	return parse(s).natural()
+}
+
+// Invert returns name surname first, e.g. "Tolkien, J. R. R.".
+func Invert(s string) string {
+	return parse(s).inverted()
+}
+
+// Surname returns the surname of name, with its particles.
+func Surname(s string) string {
+	n := parse(s)
+	if n.verbatim != "" {
+		return n.verbatim
+	}
+	return n.surname
+}
+
+// Same reports whether a and b name the same person: the surnames match
+// and each forename matches the other's, an initial matching any name
+// that starts with it. "J.R.R. Tolkien" is the same as "Tolkien, John
+// Ronald Reuel" but not as "Christopher Tolkien". A surname alone only
+// matches a surname alone.
+func Same(a, b string) bool {
+	na, nb := parse(a), parse(b)
+	if na.verbatim != "" || nb.verbatim != "" {
+		return strings.EqualFold(na.natural(), nb.natural())
+	}
+	if !strings.EqualFold(na.surname, nb.surname) {
+		return false
+	}
+	if na.suffix != "" && nb.suffix != "" && na.suffix != nb.suffix {
+		return false
+	}
+	if (len(na.given) == 0) != (len(nb.given) == 0) {
+		return false
+	}
+	for i := range min(len(na.given), len(nb.given)) {
+		if !sameGiven(na.given[i], nb.given[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameGiven(a, b string) bool {
+	if strings.HasSuffix(a, ".") || strings.HasSuffix(b, ".") {
+		ra, _ := utf8.DecodeRuneInString(a)
+		rb, _ := utf8.DecodeRuneInString(b)
+		return unicode.ToLower(ra) == unicode.ToLower(rb)
+	}
+	return strings.EqualFold(a, b)
+}
+
+// Dedupe normalizes names and drops those naming a person listed
+// earlier, so the first form reported wins.
+func Dedupe(names []string) []string {
+	return Union(nil, names)
+}
+
+// Union appends the names of b naming a person missing from a, in
+// normalized form, and returns the result. The names already in a are
+// kept as they are.
+func Union(a, b []string) []string {
+	for _, s := range b {
+		if strings.TrimSpace(s) == "" {
+			continue
+		}
+		dup := false
+		for _, t := range a {
+			if dup = Same(s, t); dup {
+				break
+			}
+		}
+		if !dup {
+			a = append(a, Normalize(s))
+		}
+	}
+	return a
+}
@@ -0,0 +1,80 @@
+// Package authority looks up the authority data of authors: their life
+// dates and their identifiers in OpenLibrary, VIAF, Wikidata and ISNI,
+// which catalogers use to tell namesakes apart.
+package authority
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/SouadAli10/book_scrapping_tool/author"
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// Source looks up the authority data of an author by name.
+type Source interface {
+	Lookup(ctx context.Context, name string) (book.Author, error)
+}
+
+// Resolver queries its sources in order for each author, the first
+// source taking precedence and later ones filling the gaps. Results are
+// kept for the life of the Resolver, since the same authors recur across
+// a catalog.
+type Resolver struct {
+	Sources []Source
+
+	mu    sync.Mutex
+	cache map[string]book.Author
+}
+
+// Resolve returns the authority data of names, in the same order. An
+// author no source knows gets a record with the name only. Lookup
+// failures other than not found are returned along with the records.
+func (r *Resolver) Resolve(ctx context.Context, names []string) ([]book.Author, error) {
+	out := make([]book.Author, len(names))
+	var errs []error
+	for i, name := range names {
+		key := strings.ToLower(author.Normalize(name))
+		r.mu.Lock()
+		a, ok := r.cache[key]
+		r.mu.Unlock()
+		if ok {
+			a.Name = name
+			out[i] = a
+			continue
+		}
+		a = book.Author{Name: name}
+		failed := false
+		for _, s := range r.Sources {
+			// Sources may return partial data along with an error.
+			d, err := s.Lookup(ctx, name)
+			if err != nil && !errors.Is(err, provider.ErrNotFound) {
+				errs = append(errs, err)
+				failed = true
+			}
+			a.Fill(d)
+		}
+		// Failures may be transient; only complete answers are kept.
+		if !failed {
+			r.mu.Lock()
+			if r.cache == nil {
+				r.cache = make(map[string]book.Author)
+			}
+			r.cache[key] = a
+			r.mu.Unlock()
+		}
+		out[i] = a
+	}
+	return out, errors.Join(errs...)
+}
+
+var yearRe = regexp.MustCompile(`\b\d{4}\b`)
+
+// year returns the four-digit year of a date such as "3 January 1892".
+func year(date string) string {
+	return yearRe.FindString(date)
+}
@@ -0,0 +1,77 @@
+package authority
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/author"
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// DefaultOpenLibraryURL is the OpenLibrary site, whose author records
+// carry life dates and the author's VIAF, Wikidata and ISNI identifiers.
+const DefaultOpenLibraryURL = "https://openlibrary.org"
+
+// OpenLibrary finds authors with the OpenLibrary author search and reads
+// their author records.
+type OpenLibrary struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+type authorSearch struct {
+	Docs []struct {
+		Key       string `json:"key"`
+		Name      string `json:"name"`
+		BirthDate string `json:"birth_date"`
+		DeathDate string `json:"death_date"`
+		WorkCount int    `json:"work_count"`
+	} `json:"docs"`
+}
+
+type authorRecord struct {
+	RemoteIDs struct {
+		VIAF     string `json:"viaf"`
+		Wikidata string `json:"wikidata"`
+		ISNI     string `json:"isni"`
+	} `json:"remote_ids"`
+}
+
+// Lookup implements Source. Of the authors matching name, the one with
+// the most works is taken, as the likeliest to have written the book.
+func (o *OpenLibrary) Lookup(ctx context.Context, name string) (book.Author, error) {
+	base := o.BaseURL
+	if base == "" {
+		base = DefaultOpenLibraryURL
+	}
+	q := url.Values{"q": {author.Normalize(name)}, "limit": {"10"}}
+	var res authorSearch
+	if err := provider.GetJSON(ctx, o.HTTPClient, base+"/search/authors.json?"+q.Encode(), &res); err != nil {
+		return book.Author{}, err
+	}
+	best := -1
+	for i, d := range res.Docs {
+		if author.Same(name, d.Name) && (best < 0 || d.WorkCount > res.Docs[best].WorkCount) {
+			best = i
+		}
+	}
+	if best < 0 {
+		return book.Author{}, provider.ErrNotFound
+	}
+	d := res.Docs[best]
+	a := book.Author{
+		Name:          name,
+		BirthYear:     year(d.BirthDate),
+		DeathYear:     year(d.DeathDate),
+		OpenLibraryID: strings.TrimPrefix(d.Key, "/authors/"),
+	}
+	var rec authorRecord
+	if err := provider.GetJSON(ctx, o.HTTPClient, base+"/authors/"+url.PathEscape(a.OpenLibraryID)+".json", &rec); err != nil {
+		return a, err
+	}
+	a.VIAF, a.Wikidata, a.ISNI = rec.RemoteIDs.VIAF, rec.RemoteIDs.Wikidata, rec.RemoteIDs.ISNI
+	return a, nil
+}
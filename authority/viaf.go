@@ -0,0 +1,59 @@
+package authority
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/SouadAli10/book_scrapping_tool/author"
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// DefaultVIAFURL is the Virtual International Authority File, which
+// links the name authority records of national libraries.
+const DefaultVIAFURL = "https://viaf.org/viaf"
+
+// VIAF finds authors with the VIAF AutoSuggest service.
+type VIAF struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+type suggestResponse struct {
+	Result []struct {
+		DisplayForm string `json:"displayForm"`
+		NameType    string `json:"nametype"`
+		VIAFID      string `json:"viafid"`
+	} `json:"result"`
+}
+
+// lifeDates matches the dates ending a heading, e.g. "1892-1973" or
+// "1947-".
+var lifeDates = regexp.MustCompile(`(\d{4})\??-(\d{4})?\??\.?$`)
+
+// Lookup implements Source with the first personal name heading that
+// matches name. Its life dates come from the heading.
+func (v *VIAF) Lookup(ctx context.Context, name string) (book.Author, error) {
+	base := v.BaseURL
+	if base == "" {
+		base = DefaultVIAFURL
+	}
+	q := url.Values{"query": {author.Normalize(name)}}
+	var res suggestResponse
+	if err := provider.GetJSON(ctx, v.HTTPClient, base+"/AutoSuggest?"+q.Encode(), &res); err != nil {
+		return book.Author{}, err
+	}
+	for _, r := range res.Result {
+		if r.NameType != "personal" || !author.Same(name, r.DisplayForm) {
+			continue
+		}
+		a := book.Author{Name: name, VIAF: r.VIAFID}
+		if m := lifeDates.FindStringSubmatch(r.DisplayForm); m != nil {
+			a.BirthYear, a.DeathYear = m[1], m[2]
+		}
+		return a, nil
+	}
+	return book.Author{}, provider.ErrNotFound
+}
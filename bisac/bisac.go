@@ -0,0 +1,296 @@
+// Package bisac maps subject headings to BISAC subject codes, the
+// classification of the Book Industry Study Group that retailers and
+// ONIX feeds in North America require.
+//
+// The built-in table covers the sections and the common fiction genres
+// rather than the full list of several thousand headings. A heading is
+// chosen when every word of one of its keyword sets occurs in a subject,
+// preferring the keyword sets with the most words, so "Fiction, fantasy,
+// epic" maps to FIC009020 "FICTION / Fantasy / Epic" rather than to
+// FIC009000 "FICTION / Fantasy / General".
+package bisac
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/SouadAli10/book_scrapping_tool/datatable"
+)
+
+// TableVersion is the date the BISAC table was last refreshed.
+const TableVersion = "2026-10-17"
+
+// MaxCodes is the number of codes kept per book; retailers accept up to
+// three.
+const MaxCodes = 3
+
+// Heading is a BISAC subject heading.
+type Heading struct {
+	Code  string `json:"code"`
+	Label string `json:"label"`
+	// Match lists keyword sets, each a space-separated list of words
+	// that must all occur in a subject.
+	Match []string `json:"match,omitempty"`
+}
+
+var table = []Heading{
+	{"FIC009020", "FICTION / Fantasy / Epic", []string{"epic fantasy", "high fantasy", "sword sorcery"}},
+	{"FIC009030", "FICTION / Fantasy / Historical", []string{"historical fantasy"}},
+	{"FIC009050", "FICTION / Fantasy / Paranormal", []string{"paranormal fantasy"}},
+	{"FIC009000", "FICTION / Fantasy / General", []string{"fantasy"}},
+	{"FIC028010", "FICTION / Science Fiction / Action & Adventure", []string{"science fiction adventure"}},
+	{"FIC028020", "FICTION / Science Fiction / Hard Science Fiction", []string{"hard science fiction"}},
+	{"FIC028030", "FICTION / Science Fiction / Space Opera", []string{"space opera"}},
+	{"FIC028000", "FICTION / Science Fiction / General", []string{"science fiction", "sf"}},
+	{"FIC055000", "FICTION / Dystopian", []string{"dystopia", "dystopian"}},
+	{"FIC040000", "FICTION / Alternative History", []string{"alternative history", "alternate history"}},
+	{"FIC014000", "FICTION / Historical / General", []string{"historical fiction", "historical novel"}},
+	{"FIC022000", "FICTION / Mystery & Detective / General", []string{"mystery", "detective", "whodunit"}},
+	{"FIC006000", "FICTION / Thrillers / Espionage", []string{"spy", "espionage"}},
+	{"FIC030000", "FICTION / Thrillers / Suspense", []string{"suspense"}},
+	{"FIC031000", "FICTION / Thrillers / General", []string{"thriller"}},
+	{"FIC050000", "FICTION / Crime", []string{"crime fiction"}},
+	{"FIC015000", "FICTION / Horror", []string{"horror"}},
+	{"FIC012000", "FICTION / Ghost", []string{"ghost stories", "ghost fiction"}},
+	{"FIC024000", "FICTION / Occult & Supernatural", []string{"supernatural fiction", "occult fiction"}},
+	{"FIC027000", "FICTION / Romance / General", []string{"romance", "love stories"}},
+	{"FIC002000", "FICTION / Action & Adventure", []string{"adventure stories", "adventure fiction", "action adventure"}},
+	{"FIC032000", "FICTION / War & Military", []string{"war stories", "war fiction", "military fiction"}},
+	{"FIC010000", "FICTION / Fairy Tales, Folk Tales, Legends & Mythology", []string{"fairy tales", "folk tales", "legends", "mythology fiction"}},
+	{"FIC016000", "FICTION / Humorous / General", []string{"humorous fiction", "humorous stories"}},
+	{"FIC029000", "FICTION / Short Stories (single author)", []string{"short stories"}},
+	{"FIC008000", "FICTION / Sagas", []string{"sagas", "family saga"}},
+	{"FIC045000", "FICTION / Family Life / General", []string{"family life fiction", "domestic fiction"}},
+	{"FIC041000", "FICTION / Biographical", []string{"biographical fiction"}},
+	{"FIC037000", "FICTION / Political", []string{"political fiction"}},
+	{"FIC026000", "FICTION / Religious", []string{"christian fiction", "religious fiction"}},
+	{"FIC004000", "FICTION / Classics", []string{"classics", "classic literature"}},
+	{"FIC019000", "FICTION / Literary", []string{"literary fiction", "psychological fiction"}},
+	{"FIC000000", "FICTION / General", []string{"fiction", "novel"}},
+
+	{"JUV037000", "JUVENILE FICTION / Fantasy & Magic", []string{"juvenile fantasy", "children fantasy", "juvenile magic"}},
+	{"JUV053000", "JUVENILE FICTION / Science Fiction / General", []string{"juvenile science fiction"}},
+	{"JUV028000", "JUVENILE FICTION / Mysteries & Detective Stories", []string{"juvenile mystery", "juvenile detective"}},
+	{"JUV001000", "JUVENILE FICTION / Action & Adventure / General", []string{"juvenile adventure"}},
+	{"JUV002000", "JUVENILE FICTION / Animals / General", []string{"juvenile animals fiction"}},
+	{"JUV000000", "JUVENILE FICTION / General", []string{"juvenile fiction", "children stories", "children fiction"}},
+	{"YAF019000", "YOUNG ADULT FICTION / Fantasy / General", []string{"young adult fantasy"}},
+	{"YAF056000", "YOUNG ADULT FICTION / Science Fiction / General", []string{"young adult science fiction"}},
+	{"YAF052000", "YOUNG ADULT FICTION / Romance / General", []string{"young adult romance"}},
+	{"YAF000000", "YOUNG ADULT FICTION / General", []string{"young adult fiction"}},
+	{"JNF000000", "JUVENILE NONFICTION / General", []string{"juvenile nonfiction", "juvenile literature"}},
+	{"YAN000000", "YOUNG ADULT NONFICTION / General", []string{"young adult nonfiction"}},
+
+	{"CGN004050", "COMICS & GRAPHIC NOVELS / Manga / General", []string{"manga"}},
+	{"CGN000000", "COMICS & GRAPHIC NOVELS / General", []string{"comics", "graphic novels", "comic books"}},
+	{"POE000000", "POETRY / General", []string{"poetry", "poems"}},
+	{"DRA000000", "DRAMA / General", []string{"drama", "plays"}},
+	{"LCO000000", "LITERARY COLLECTIONS / General", []string{"anthologies", "literary collections"}},
+	{"LIT000000", "LITERARY CRITICISM / General", []string{"criticism", "literary criticism"}},
+
+	{"ANT000000", "ANTIQUES & COLLECTIBLES / General", []string{"antiques", "collectibles"}},
+	{"ARC000000", "ARCHITECTURE / General", []string{"architecture"}},
+	{"ART000000", "ART / General", []string{"art", "painting"}},
+	{"BIB000000", "BIBLES / General", []string{"bible"}},
+	{"BIO000000", "BIOGRAPHY & AUTOBIOGRAPHY / General", []string{"biography", "autobiography", "memoir"}},
+	{"OCC000000", "BODY, MIND & SPIRIT / General", []string{"occultism", "astrology", "spiritualism"}},
+	{"BUS000000", "BUSINESS & ECONOMICS / General", []string{"business", "economics", "management", "finance"}},
+	{"CKB000000", "COOKING / General", []string{"cooking", "cookbooks", "recipes"}},
+	{"COM000000", "COMPUTERS / General", []string{"computers", "computer science", "programming"}},
+	{"CRA000000", "CRAFTS & HOBBIES / General", []string{"crafts", "handicraft", "hobbies"}},
+	{"DES000000", "DESIGN / General", []string{"design"}},
+	{"EDU000000", "EDUCATION / General", []string{"education", "teaching"}},
+	{"FAM000000", "FAMILY & RELATIONSHIPS / General", []string{"parenting", "family relationships", "marriage"}},
+	{"FOR000000", "FOREIGN LANGUAGE STUDY / General", []string{"language study", "textbooks foreign speakers"}},
+	{"GAM000000", "GAMES & ACTIVITIES / General", []string{"games", "puzzles"}},
+	{"GAR000000", "GARDENING / General", []string{"gardening"}},
+	{"HEA000000", "HEALTH & FITNESS / General", []string{"health", "fitness", "diet", "nutrition"}},
+	{"HIS027000", "HISTORY / Military / General", []string{"military history"}},
+	{"HIS036000", "HISTORY / United States / General", []string{"united states history"}},
+	{"HIS000000", "HISTORY / General", []string{"history"}},
+	{"HOM000000", "HOUSE & HOME / General", []string{"home improvement", "interior decoration"}},
+	{"HUM000000", "HUMOR / General", []string{"humor", "wit humor"}},
+	{"LAN000000", "LANGUAGE ARTS & DISCIPLINES / General", []string{"linguistics", "grammar", "writing"}},
+	{"LAW000000", "LAW / General", []string{"law", "legal"}},
+	{"MAT005000", "MATHEMATICS / Calculus", []string{"calculus"}},
+	{"MAT000000", "MATHEMATICS / General", []string{"mathematics", "algebra", "geometry"}},
+	{"MED000000", "MEDICAL / General", []string{"medicine", "medical"}},
+	{"MUS000000", "MUSIC / General", []string{"music"}},
+	{"NAT000000", "NATURE / General", []string{"nature", "natural history", "wildlife"}},
+	{"PER000000", "PERFORMING ARTS / General", []string{"performing arts", "theater", "film"}},
+	{"PET000000", "PETS / General", []string{"pets"}},
+	{"PHI000000", "PHILOSOPHY / General", []string{"philosophy", "ethics"}},
+	{"PHO000000", "PHOTOGRAPHY / General", []string{"photography"}},
+	{"POL000000", "POLITICAL SCIENCE / General", []string{"political science", "politics", "government"}},
+	{"PSY000000", "PSYCHOLOGY / General", []string{"psychology"}},
+	{"REF000000", "REFERENCE / General", []string{"reference", "dictionaries", "encyclopedias"}},
+	{"REL000000", "RELIGION / General", []string{"religion", "theology", "christianity"}},
+	{"SCI004000", "SCIENCE / Astronomy", []string{"astronomy"}},
+	{"SCI008000", "SCIENCE / Life Sciences / Biology", []string{"biology"}},
+	{"SCI013000", "SCIENCE / Chemistry / General", []string{"chemistry"}},
+	{"SCI055000", "SCIENCE / Physics / General", []string{"physics"}},
+	{"SCI000000", "SCIENCE / General", []string{"science"}},
+	{"SEL000000", "SELF-HELP / General", []string{"self-help", "self-actualization", "personal development"}},
+	{"SOC000000", "SOCIAL SCIENCE / General", []string{"social science", "sociology", "anthropology"}},
+	{"SPO000000", "SPORTS & RECREATION / General", []string{"sports", "recreation", "martial arts"}},
+	{"STU000000", "STUDY AIDS / General", []string{"study guides", "examinations"}},
+	{"TEC000000", "TECHNOLOGY & ENGINEERING / General", []string{"technology", "engineering"}},
+	{"TRA000000", "TRANSPORTATION / General", []string{"transportation", "automobiles", "railroads"}},
+	{"TRV000000", "TRAVEL / General", []string{"travel", "guidebooks"}},
+	{"TRU000000", "TRUE CRIME / General", []string{"true crime"}},
+}
+
+// rule is a keyword set of a heading, stemmed.
+type rule struct {
+	heading *Heading
+	words   []string
+}
+
+var (
+	byCode  map[string]*Heading
+	byLabel map[string]*Heading
+	rules   []rule
+)
+
+func init() {
+	datatable.Register(datatable.Table{
+		Name:    "bisac",
+		Summary: "BISAC subject headings and the keywords mapped to them",
+		Version: TableVersion,
+		Export:  func() ([]byte, error) { return json.MarshalIndent(table, "", "  ") },
+		Load:    loadTable,
+	})
+	index()
+}
+
+// loadTable replaces table with a downloaded table.
+func loadTable(data []byte) error {
+	var t []Heading
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+	if len(t) == 0 {
+		return errors.New("no BISAC headings")
+	}
+	table = t
+	index()
+	return nil
+}
+
+func index() {
+	byCode = make(map[string]*Heading, len(table))
+	byLabel = make(map[string]*Heading, len(table))
+	rules = rules[:0]
+	for i := range table {
+		h := &table[i]
+		byCode[h.Code] = h
+		byLabel[strings.ToLower(h.Label)] = h
+		for _, m := range h.Match {
+			rules = append(rules, rule{h, words(m)})
+		}
+	}
+}
+
+// words splits s into lower-case words without plural s, so
+// "Love stories" and "love story" compare equal.
+func words(s string) []string {
+	ws := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-'
+	})
+	for i, w := range ws {
+		switch {
+		case strings.HasSuffix(w, "ies") && len(w) > 4:
+			ws[i] = w[:len(w)-3] + "y"
+		case strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") && len(w) > 3:
+			ws[i] = w[:len(w)-1]
+		}
+	}
+	return ws
+}
+
+// Label returns the label of a BISAC code, or "" for a code not in the
+// table.
+func Label(code string) string {
+	if h, ok := byCode[code]; ok {
+		return h.Label
+	}
+	return ""
+}
+
+// match returns the heading of one subject, or nil.
+func match(subject string) *Heading {
+	if h, ok := byLabel[strings.ToLower(strings.TrimSpace(subject))]; ok {
+		return h
+	}
+	have := make(map[string]bool)
+	for _, w := range words(subject) {
+		have[w] = true
+	}
+	var best *Heading
+	bestLen := 0
+	for _, r := range rules {
+		if len(r.words) <= bestLen {
+			continue
+		}
+		all := true
+		for _, w := range r.words {
+			if !have[w] {
+				all = false
+				break
+			}
+		}
+		if all {
+			best, bestLen = r.heading, len(r.words)
+		}
+	}
+	return best
+}
+
+// Map returns the BISAC codes of a book's subjects, at most MaxCodes, in
+// the order of the subjects. A general heading such as "FICTION /
+// Fantasy / General" is left out when a more specific heading of the
+// same section, such as "FICTION / Fantasy / Epic", is mapped as well.
+func Map(subjects []string) []string {
+	var hs []*Heading
+	for _, s := range subjects {
+		if h := match(s); h != nil && !contains(hs, h) {
+			hs = append(hs, h)
+		}
+	}
+	var codes []string
+	for _, h := range hs {
+		if !general(h, hs) {
+			codes = append(codes, h.Code)
+		}
+		if len(codes) == MaxCodes {
+			break
+		}
+	}
+	return codes
+}
+
+func contains(hs []*Heading, h *Heading) bool {
+	for _, o := range hs {
+		if o == h {
+			return true
+		}
+	}
+	return false
+}
+
+// general reports whether h is the general heading of a section another
+// heading of hs belongs to.
+func general(h *Heading, hs []*Heading) bool {
+	section, ok := strings.CutSuffix(h.Label, " / General")
+	if !ok {
+		return false
+	}
+	for _, o := range hs {
+		if o != h && strings.HasPrefix(o.Label, section+" / ") {
+			return true
+		}
+	}
+	return false
+}
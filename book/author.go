@@ -0,0 +1,28 @@
+package book
+
+// Author holds the authority data of one author: life dates and stable
+// identifiers, as catalogers record them.
+type Author struct {
+	Name string `json:"name"`
+	// BirthYear and DeathYear are four-digit years; DeathYear is empty
+	// for living authors.
+	BirthYear string `json:"birth_year,omitempty"`
+	DeathYear string `json:"death_year,omitempty"`
+	// OpenLibraryID is the OpenLibrary author key, e.g. "OL26320A";
+	// VIAF, Wikidata and ISNI are the identifiers of the same name in
+	// those authority files.
+	OpenLibraryID string `json:"openlibrary_id,omitempty"`
+	VIAF          string `json:"viaf,omitempty"`
+	Wikidata      string `json:"wikidata,omitempty"`
+	ISNI          string `json:"isni,omitempty"`
+}
+
+// Fill sets the empty fields of a from o.
+func (a *Author) Fill(o Author) {
+	fillString(&a.BirthYear, o.BirthYear)
+	fillString(&a.DeathYear, o.DeathYear)
+	fillString(&a.OpenLibraryID, o.OpenLibraryID)
+	fillString(&a.VIAF, o.VIAF)
+	fillString(&a.Wikidata, o.Wikidata)
+	fillString(&a.ISNI, o.ISNI)
+}
@@ -0,0 +1,291 @@
+// Package book defines the canonical record produced by the enrichment
+// pipeline. Provider packages decode their own response formats and map
+// them into a BookInfo; nothing outside the providers should depend on a
+// provider's wire format.
+package book
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/author"
+	"github.com/SouadAli10/book_scrapping_tool/units"
+)
+
+// BookInfo is the canonical, provider-independent description of a book.
+type BookInfo struct {
+	ISBN13      string   `json:"isbn_13,omitempty"`
+	ISBN10      string   `json:"isbn_10,omitempty"`
+	Title       string   `json:"title,omitempty"`
+	Subtitle    string   `json:"subtitle,omitempty"`
+	Authors     []string `json:"authors,omitempty"`
+	PublishDate string   `json:"publish_date,omitempty"`
+	// Publishers and PublishPlaces are merged from every provider.
+	Publishers    []string `json:"publishers,omitempty"`
+	PublishPlaces []string `json:"publish_places,omitempty"`
+	// PublishCountry is the ISO 3166-1 alpha-2 country of publication.
+	PublishCountry string `json:"publish_country,omitempty"`
+	Pages          int    `json:"pages,omitempty"`
+	// Format is the canonical edition format; Binding keeps the
+	// provider's wording, e.g. "Mass Market Paperback".
+	Format  Format `json:"format,omitempty"`
+	Binding string `json:"binding,omitempty"`
+	// Dimensions and Weight are normalized to centimetres and grams.
+	Dimensions units.Dimensions `json:"dimensions,omitzero"`
+	Weight     units.Weight     `json:"weight_g,omitempty"`
+	// Languages holds ISO 639 codes as reported by the provider, for
+	// example "eng" (OpenLibrary) or "en" (Google Books).
+	Languages []string `json:"languages,omitempty"`
+	Subjects  []string `json:"subjects,omitempty"`
+	// DeweyDecimal and LCC are shelf classifications; LCCN is the
+	// Library of Congress Control Number and OCLC the WorldCat number.
+	DeweyDecimal string `json:"dewey_decimal,omitempty"`
+	LCC          string `json:"lcc,omitempty"`
+	LCCN         string `json:"lccn,omitempty"`
+	OCLC         string `json:"oclc,omitempty"`
+	// Series and SeriesPosition describe the series the book belongs
+	// to, e.g. "Discworld" and "1".
+	Series         string `json:"series,omitempty"`
+	SeriesPosition string `json:"series_position,omitempty"`
+	// SeriesInferred is set when the series was parsed from the title
+	// rather than reported by a provider.
+	SeriesInferred bool   `json:"series_inferred,omitempty"`
+	CoverURL       string `json:"cover_url,omitempty"`
+	// CoverFile is the downloaded cover, CoverWebFile its processed
+	// version and CoverPalette its dominant colors as #rrggbb, when
+	// covers are downloaded. Fill and Merge do not copy them.
+	CoverFile    string `json:"cover_file,omitempty"`
+	CoverWebFile string `json:"cover_web_file,omitempty"`
+	// CoverHash is the perceptual hash of the cover, and CoverSameAs the
+	// ISBN of an earlier book with a matching cover.
+	CoverHash    string   `json:"cover_hash,omitempty"`
+	CoverSameAs  string   `json:"cover_same_as,omitempty"`
+	CoverPalette []string `json:"cover_palette,omitempty"`
+	// CoverAlt is the alt text of the cover image.
+	CoverAlt string `json:"cover_alt,omitempty"`
+	// Kind is the type of a catalogued item other than a book, e.g.
+	// "dvd" or "board game", and GTIN its product barcode; both are
+	// empty for books.
+	Kind string `json:"kind,omitempty"`
+	GTIN string `json:"gtin,omitempty"`
+	// Description is the plain-text synopsis of the book or its work.
+	Description string `json:"description,omitempty"`
+	// OLWorkID is the OpenLibrary work identifier, e.g. "OL27479W",
+	// OLEditionID its edition identifier, e.g. "OL7353617M", and
+	// GoogleVolumeID the Google Books volume identifier.
+	OLWorkID       string `json:"ol_work_id,omitempty"`
+	OLEditionID    string `json:"ol_edition_id,omitempty"`
+	GoogleVolumeID string `json:"google_volume_id,omitempty"`
+	// AuthorDetails holds the authority data of Authors, in the same
+	// order, when author details are looked up. Fill and Merge do not
+	// copy them.
+	AuthorDetails []Author `json:"author_details,omitempty"`
+	// Siblings are the other editions of the work, and International is
+	// set when the book is an international edition itself, when the
+	// ISBN family is looked up. Fill and Merge do not copy them.
+	Siblings      []Sibling `json:"siblings,omitempty"`
+	International bool      `json:"international,omitempty"`
+	// BISAC holds the BISAC subject codes mapped from Subjects, when
+	// they are mapped.
+	BISAC []string `json:"bisac,omitempty"`
+	// Imprint and ParentPublisher are the canonical imprint of the
+	// publisher and the group that owns it, when they are resolved.
+	Imprint         string `json:"imprint,omitempty"`
+	ParentPublisher string `json:"parent_publisher,omitempty"`
+	// MatchMethod tells how the record was found: "isbn" or "search".
+	// MatchConfidence rates a search match between 0 and 1; ISBN
+	// matches are 1.
+	MatchMethod     string  `json:"match_method,omitempty"`
+	MatchConfidence float64 `json:"match_confidence,omitempty"`
+	// WorkLevel is set when the record describes a work rather than
+	// one of its editions, as OpenLibrary search results do: its
+	// publishers and date then span the editions. Fill and Merge do
+	// not copy it.
+	WorkLevel bool `json:"work_level,omitempty"`
+	// Source names the provider the record came from.
+	Source string `json:"source,omitempty"`
+	// Provenance records the sources of each field once other records
+	// are merged into this one; see Sources and FieldSources.
+	Provenance Provenance `json:"provenance,omitempty"`
+	// Prices summarizes current market prices when a price lookup is
+	// enabled. Fill and Merge do not copy them.
+	Prices Prices `json:"prices,omitzero"`
+	// Translation holds the translated title, subjects and description
+	// when a translation step is enabled. Fill and Merge do not copy it.
+	Translation *Translation `json:"translation,omitempty"`
+	// Warnings lists non-fatal problems with the record, such as a date
+	// that could not be parsed. Fill and Merge do not copy them.
+	Warnings []string `json:"warnings,omitempty"`
+	// Violations lists the validation rules the record breaks, when
+	// records are validated. Fill and Merge do not copy them.
+	Violations []string `json:"violations,omitempty"`
+}
+
+// Warn records a non-fatal problem with b.
+func (b *BookInfo) Warn(format string, args ...any) {
+	b.Warnings = append(b.Warnings, fmt.Sprintf(format, args...))
+}
+
+var yearRe = regexp.MustCompile(`\b\d{4}\b`)
+
+// Year returns the four-digit year of PublishDate, or "" when it has
+// none.
+func (b *BookInfo) Year() string {
+	return yearRe.FindString(b.PublishDate)
+}
+
+// ISBN returns the most specific ISBN known for b.
+func (b *BookInfo) ISBN() string {
+	if b.ISBN13 != "" {
+		return b.ISBN13
+	}
+	return b.ISBN10
+}
+
+// FullTitle joins the title and subtitle the way they are printed.
+func (b *BookInfo) FullTitle() string {
+	if b.Subtitle == "" {
+		return b.Title
+	}
+	return b.Title + ": " + b.Subtitle
+}
+
+// IsEmpty reports whether b carries no useful data.
+func (b *BookInfo) IsEmpty() bool {
+	return b == nil || (b.Title == "" && len(b.Authors) == 0 && b.ISBN() == "")
+}
+
+// Join formats a list field for a single output cell.
+func Join(vs []string) string {
+	return strings.Join(vs, ", ")
+}
+
+// Names joins names for running text: "A", "A and B", "A, B and C".
+func Names(vs []string) string {
+	switch len(vs) {
+	case 0:
+		return ""
+	case 1:
+		return vs[0]
+	}
+	return strings.Join(vs[:len(vs)-1], ", ") + " and " + vs[len(vs)-1]
+}
+
+// Fill copies every field of o into b that is empty in b. Fields from
+// another source are credited to it in b's Provenance. Source is left
+// unchanged.
+func (b *BookInfo) Fill(o *BookInfo) {
+	if o == nil {
+		return
+	}
+	if o.Source != "" && o.Source != b.Source {
+		before := b.filled()
+		defer func() {
+			for _, f := range b.filled() {
+				if !slices.Contains(before, f) {
+					b.Credit(o.Source, f)
+				}
+			}
+		}()
+	}
+	fillString(&b.ISBN13, o.ISBN13)
+	fillString(&b.ISBN10, o.ISBN10)
+	fillString(&b.Title, o.Title)
+	fillString(&b.Subtitle, o.Subtitle)
+	fillSlice(&b.Authors, o.Authors)
+	fillString(&b.PublishDate, o.PublishDate)
+	fillSlice(&b.Publishers, o.Publishers)
+	fillSlice(&b.PublishPlaces, o.PublishPlaces)
+	fillString(&b.PublishCountry, o.PublishCountry)
+	if b.Pages == 0 {
+		b.Pages = o.Pages
+	}
+	if b.Format == "" {
+		b.Format, b.Binding = o.Format, o.Binding
+	}
+	if b.Dimensions.IsZero() {
+		b.Dimensions = o.Dimensions
+	}
+	if b.Weight <= 0 {
+		b.Weight = o.Weight
+	}
+	fillSlice(&b.Languages, o.Languages)
+	fillSlice(&b.Subjects, o.Subjects)
+	fillString(&b.DeweyDecimal, o.DeweyDecimal)
+	fillString(&b.LCC, o.LCC)
+	fillString(&b.LCCN, o.LCCN)
+	fillString(&b.OCLC, o.OCLC)
+	if b.Series == "" {
+		b.Series, b.SeriesPosition = o.Series, o.SeriesPosition
+		b.SeriesInferred = o.SeriesInferred
+	}
+	fillString(&b.CoverURL, o.CoverURL)
+	fillString(&b.Kind, o.Kind)
+	fillString(&b.GTIN, o.GTIN)
+	fillString(&b.Description, o.Description)
+	fillString(&b.OLWorkID, o.OLWorkID)
+	fillString(&b.OLEditionID, o.OLEditionID)
+	fillString(&b.GoogleVolumeID, o.GoogleVolumeID)
+}
+
+// Merge fills the empty fields of b from o like Fill, and additionally
+// unions the fields where every provider can contribute, such as
+// authors, publishers and publication places. Authors named in
+// different forms are only listed once.
+func (b *BookInfo) Merge(o *BookInfo) {
+	if o == nil {
+		return
+	}
+	if len(b.Authors) > 0 {
+		if u := author.Union(b.Authors, o.Authors); len(u) > len(b.Authors) {
+			b.Authors = u
+			b.Credit(o.Source, "authors")
+		}
+	}
+	if u := Union(b.Publishers, o.Publishers); len(u) > len(b.Publishers) {
+		b.Publishers = u
+		b.Credit(o.Source, "publishers")
+	}
+	if u := Union(b.PublishPlaces, o.PublishPlaces); len(u) > len(b.PublishPlaces) {
+		b.PublishPlaces = u
+		b.Credit(o.Source, "publish_places")
+	}
+	b.Fill(o)
+}
+
+// Union appends the values of b missing from a, comparing
+// case-insensitively and ignoring surrounding punctuation, and returns
+// the result.
+func Union(a, b []string) []string {
+	seen := make(map[string]bool, len(a))
+	for _, v := range a {
+		seen[unionKey(v)] = true
+	}
+	for _, v := range b {
+		k := unionKey(v)
+		if k == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		a = append(a, strings.TrimSpace(v))
+	}
+	return a
+}
+
+func unionKey(s string) string {
+	return strings.ToLower(strings.Trim(s, " .,;:[]()"))
+}
+
+func fillString(dst *string, v string) {
+	if *dst == "" {
+		*dst = v
+	}
+}
+
+func fillSlice(dst *[]string, v []string) {
+	if len(*dst) == 0 && len(v) > 0 {
+		*dst = v
+	}
+}
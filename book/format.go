@@ -0,0 +1,46 @@
+package book
+
+import "strings"
+
+// Format is the canonical edition format.
+type Format string
+
+const (
+	Hardcover Format = "hardcover"
+	Paperback Format = "paperback"
+	Ebook     Format = "ebook"
+	Audio     Format = "audio"
+	// OtherFormat covers formats that are known but none of the above,
+	// such as board books, spiral bindings or loose-leaf editions.
+	OtherFormat Format = "other"
+)
+
+// ParseFormat classifies a provider's free-text binding, such as
+// "Mass Market Paperback", "Library Binding", "Kindle Edition" or
+// "Audio CD". It returns "" when s is empty.
+func ParseFormat(s string) Format {
+	f := strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case f == "":
+		return ""
+	case strings.Contains(f, "audio"), strings.Contains(f, "mp3"),
+		strings.Contains(f, "sound"), strings.Contains(f, "cassette"):
+		return Audio
+	case strings.Contains(f, "ebook"), strings.Contains(f, "e-book"),
+		strings.Contains(f, "kindle"), strings.Contains(f, "epub"),
+		strings.Contains(f, "electronic"), strings.Contains(f, "digital"),
+		strings.Contains(f, "online"):
+		return Ebook
+	case strings.Contains(f, "hardcover"), strings.Contains(f, "hardback"),
+		strings.Contains(f, "hard cover"), strings.Contains(f, "library binding"),
+		strings.Contains(f, "cloth"), strings.Contains(f, "gebunden"),
+		strings.Contains(f, "relié"):
+		return Hardcover
+	case strings.Contains(f, "paperback"), strings.Contains(f, "softcover"),
+		strings.Contains(f, "soft cover"), strings.Contains(f, "trade paper"),
+		strings.Contains(f, "pbk"), strings.Contains(f, "taschenbuch"),
+		strings.Contains(f, "broché"), strings.Contains(f, "poche"):
+		return Paperback
+	}
+	return OtherFormat
+}
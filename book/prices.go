@@ -0,0 +1,16 @@
+package book
+
+// Prices summarizes the offers found for an edition in one currency.
+// Zero amounts mean no offer of that condition was found.
+type Prices struct {
+	Currency   string  `json:"currency,omitempty"`
+	NewMin     float64 `json:"new_min,omitempty"`
+	NewMedian  float64 `json:"new_median,omitempty"`
+	UsedMin    float64 `json:"used_min,omitempty"`
+	UsedMedian float64 `json:"used_median,omitempty"`
+	// Offers is the number of offers the summary is based on.
+	Offers int `json:"offers,omitempty"`
+}
+
+// IsZero reports whether no offer was found.
+func (p Prices) IsZero() bool { return p.Offers == 0 }
@@ -0,0 +1,108 @@
+package book
+
+import (
+	"slices"
+	"strings"
+)
+
+// Provenance maps the JSON name of each field of a record to the
+// sources that supplied it, in the order they were merged.
+type Provenance map[string][]string
+
+// filled returns the JSON names of b's non-empty provider fields.
+func (b *BookInfo) filled() []string {
+	var names []string
+	add := func(name string, ok bool) {
+		if ok {
+			names = append(names, name)
+		}
+	}
+	add("isbn_13", b.ISBN13 != "")
+	add("isbn_10", b.ISBN10 != "")
+	add("title", b.Title != "")
+	add("subtitle", b.Subtitle != "")
+	add("authors", len(b.Authors) > 0)
+	add("publish_date", b.PublishDate != "")
+	add("publishers", len(b.Publishers) > 0)
+	add("publish_places", len(b.PublishPlaces) > 0)
+	add("publish_country", b.PublishCountry != "")
+	add("pages", b.Pages > 0)
+	add("format", b.Format != "")
+	add("dimensions", !b.Dimensions.IsZero())
+	add("weight_g", b.Weight > 0)
+	add("languages", len(b.Languages) > 0)
+	add("subjects", len(b.Subjects) > 0)
+	add("dewey_decimal", b.DeweyDecimal != "")
+	add("lcc", b.LCC != "")
+	add("lccn", b.LCCN != "")
+	add("oclc", b.OCLC != "")
+	add("series", b.Series != "")
+	add("cover_url", b.CoverURL != "")
+	add("kind", b.Kind != "")
+	add("gtin", b.GTIN != "")
+	add("description", b.Description != "")
+	add("ol_work_id", b.OLWorkID != "")
+	add("ol_edition_id", b.OLEditionID != "")
+	add("google_volume_id", b.GoogleVolumeID != "")
+	return names
+}
+
+// track starts recording provenance, crediting the fields already set
+// to b's source.
+func (b *BookInfo) track() {
+	if b.Provenance != nil {
+		return
+	}
+	b.Provenance = make(Provenance)
+	if b.Source == "" {
+		return
+	}
+	for _, f := range b.filled() {
+		b.Provenance[f] = []string{b.Source}
+	}
+}
+
+// Credit records that source supplied fields, named as in JSON. Fields
+// from b's own source need no credit.
+func (b *BookInfo) Credit(source string, fields ...string) {
+	if source == "" || source == b.Source {
+		return
+	}
+	b.track()
+	for _, f := range fields {
+		if !slices.Contains(b.Provenance[f], source) {
+			b.Provenance[f] = append(b.Provenance[f], source)
+		}
+	}
+}
+
+// Sources returns the sources that contributed to b: its own source
+// first, then those merged into it.
+func (b *BookInfo) Sources() []string {
+	var srcs []string
+	if b.Source != "" {
+		srcs = append(srcs, b.Source)
+	}
+	for _, f := range b.filled() {
+		for _, s := range b.Provenance[f] {
+			if !slices.Contains(srcs, s) {
+				srcs = append(srcs, s)
+			}
+		}
+	}
+	return srcs
+}
+
+// FieldSources describes the fields that did not come from b's own
+// source alone, as "pages=googlebooks; publishers=openlibrary+isbndb".
+func (b *BookInfo) FieldSources() string {
+	var parts []string
+	for _, f := range b.filled() {
+		srcs := b.Provenance[f]
+		if len(srcs) == 0 || (len(srcs) == 1 && srcs[0] == b.Source) {
+			continue
+		}
+		parts = append(parts, f+"="+strings.Join(srcs, "+"))
+	}
+	return strings.Join(parts, "; ")
+}
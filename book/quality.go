@@ -0,0 +1,45 @@
+package book
+
+import "slices"
+
+// coreFields are the fields, named as in JSON, whose presence Quality
+// rewards: those most catalogs and shops need.
+var coreFields = []string{
+	"title", "authors", "publishers", "publish_date", "pages",
+	"format", "languages", "subjects", "cover_url", "description",
+}
+
+// itemCoreFields replace coreFields for items other than books, which
+// have no pages or authors to speak of.
+var itemCoreFields = []string{
+	"title", "publishers", "publish_date", "subjects", "cover_url", "description",
+}
+
+// Completeness returns the share of the core fields b fills, from 0 to
+// 1.
+func (b *BookInfo) Completeness() float64 {
+	core := coreFields
+	if b.Kind != "" {
+		core = itemCoreFields
+	}
+	n := 0
+	for _, f := range b.filled() {
+		if slices.Contains(core, f) {
+			n++
+		}
+	}
+	return float64(n) / float64(len(core))
+}
+
+// Quality scores how far b can be trusted, from 0 to 1: the confidence
+// of the match (1 for an ISBN match, the title/author similarity for a
+// search match), scaled down by up to half for missing core fields.
+func (b *BookInfo) Quality() float64 {
+	match := b.MatchConfidence
+	if b.MatchMethod == "" && match <= 0 {
+		// Records not found by a lookup, such as library entries,
+		// are what they say they are.
+		match = 1
+	}
+	return match * (0.5 + 0.5*b.Completeness())
+}
@@ -0,0 +1,21 @@
+package book
+
+// Sibling is another edition of the same work, such as the international
+// or loose-leaf edition of a textbook.
+type Sibling struct {
+	ISBN13 string `json:"isbn_13"`
+	// Label describes the edition, e.g. "5th edition, loose-leaf".
+	Label string `json:"label,omitempty"`
+	// International is set for editions sold outside their home market,
+	// often printed "International Edition" or "Not for sale in the USA".
+	International bool `json:"international,omitempty"`
+	// Title, Publisher, PublishDate, Binding, Pages and Languages
+	// describe the edition as its source catalogs it, for listing the
+	// editions of a work.
+	Title       string   `json:"title,omitempty"`
+	Publisher   string   `json:"publisher,omitempty"`
+	PublishDate string   `json:"publish_date,omitempty"`
+	Binding     string   `json:"binding,omitempty"`
+	Pages       int      `json:"pages,omitempty"`
+	Languages   []string `json:"languages,omitempty"`
+}
@@ -0,0 +1,49 @@
+package book
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	tagRe        = regexp.MustCompile(`<[^>]*>`)
+	breakTagRe   = regexp.MustCompile(`(?i)<\s*(br|/p|/div|/li)\s*/?\s*>`)
+	spaceRunRe   = regexp.MustCompile(`[ \t]+`)
+	newlineRunRe = regexp.MustCompile(`\n{3,}`)
+)
+
+// PlainText strips HTML markup and entities from provider text fields and
+// collapses runs of whitespace, keeping paragraph breaks.
+func PlainText(s string) string {
+	if s == "" {
+		return ""
+	}
+	s = breakTagRe.ReplaceAllString(s, "\n")
+	s = tagRe.ReplaceAllString(s, "")
+	s = html.UnescapeString(s)
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	s = spaceRunRe.ReplaceAllString(s, " ")
+	s = newlineRunRe.ReplaceAllString(s, "\n\n")
+	return strings.TrimSpace(s)
+}
+
+// Truncate shortens s to at most n runes, cutting at a word boundary when
+// one is near and appending an ellipsis. n <= 0 disables truncation.
+func Truncate(s string, n int) string {
+	r := []rune(s)
+	if n <= 0 || len(r) <= n {
+		return s
+	}
+	cut := n - 1
+	for i := cut; i > cut*4/5; i-- {
+		if unicode.IsSpace(r[i]) {
+			cut = i
+			break
+		}
+	}
+	return strings.TrimRightFunc(string(r[:cut]), func(c rune) bool {
+		return unicode.IsSpace(c) || unicode.IsPunct(c)
+	}) + "…"
+}
@@ -0,0 +1,11 @@
+package book
+
+// Translation holds the title, subjects and description of a record
+// machine-translated into another language.
+type Translation struct {
+	// Language is the ISO 639-1 code of the translation, e.g. "ar".
+	Language    string   `json:"language"`
+	Title       string   `json:"title,omitempty"`
+	Subjects    []string `json:"subjects,omitempty"`
+	Description string   `json:"description,omitempty"`
+}
@@ -0,0 +1,34 @@
+package calibre
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DefaultCalibredb is the calibredb command used by Apply.
+const DefaultCalibredb = "calibredb"
+
+// Apply writes the changes of one book to the library with calibredb
+// set_metadata. Calibre must not be running on the library, as calibredb
+// then refuses to write to it.
+func (l *Library) Apply(ctx context.Context, calibredb string, id int64, changes []Change) error {
+	args := []string{"set_metadata", "--with-library", l.Dir}
+	for _, c := range changes {
+		args = append(args, "--field", c.Field+":"+c.New)
+	}
+	args = append(args, strconv.FormatInt(id, 10))
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, calibredb, args...)
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return fmt.Errorf("calibre: book %d: %w: %s", id, err, msg)
+		}
+		return fmt.Errorf("calibre: book %d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,174 @@
+// Package calibre reads the books of a Calibre library from its
+// metadata.db, compares them with enriched records and writes the
+// missing fields back through calibredb.
+//
+// The database is only ever opened read-only: Calibre's triggers rely on
+// SQL functions registered by Calibre itself, so updates go through its
+// command-line tool instead.
+package calibre
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/input"
+)
+
+// DBName is the name of the metadata database in a library directory.
+const DBName = "metadata.db"
+
+// undefinedDate is the value Calibre stores for a book without a
+// publication date.
+const undefinedDate = "0101-01-01"
+
+// Book is the metadata Calibre holds for one book.
+type Book struct {
+	ID      int64
+	Title   string
+	Authors []string
+	// Identifiers maps identifier types such as "isbn" to values.
+	Identifiers map[string]string
+	Publisher   string
+	// PubDate is "YYYY-MM-DD", or "" when undefined.
+	PubDate     string
+	Languages   []string
+	Tags        []string
+	Series      string
+	SeriesIndex float64
+	Comments    string
+}
+
+// ISBN returns the book's ISBN identifier.
+func (b *Book) ISBN() string { return b.Identifiers["isbn"] }
+
+// Library is an open Calibre library.
+type Library struct {
+	Dir string
+	db  *sql.DB
+}
+
+// Open opens the library in dir read-only with the named database/sql
+// driver, which must be an SQLite driver accepting URI file names.
+func Open(driver, dir string) (*Library, error) {
+	abs, err := filepath.Abs(filepath.Join(dir, DBName))
+	if err != nil {
+		return nil, err
+	}
+	dsn := (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs), RawQuery: "mode=ro"}).String()
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("calibre: %w", err)
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("calibre: open %s: %w", abs, err)
+	}
+	return &Library{Dir: dir, db: db}, nil
+}
+
+// Close closes the database.
+func (l *Library) Close() error { return l.db.Close() }
+
+// Books returns every book of the library, ordered by id.
+func (l *Library) Books(ctx context.Context) ([]*Book, error) {
+	rows, err := l.db.QueryContext(ctx,
+		`SELECT id, title, COALESCE(pubdate, ''), COALESCE(series_index, 1) FROM books ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("calibre: %w", err)
+	}
+	var books []*Book
+	byID := make(map[int64]*Book)
+	for rows.Next() {
+		b := &Book{Identifiers: make(map[string]string)}
+		if err := rows.Scan(&b.ID, &b.Title, &b.PubDate, &b.SeriesIndex); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("calibre: %w", err)
+		}
+		// Dates are stored as "2006-01-02 15:04:05+00:00".
+		if b.PubDate = b.PubDate[:min(len(b.PubDate), 10)]; b.PubDate == undefinedDate {
+			b.PubDate = ""
+		}
+		books = append(books, b)
+		byID[b.ID] = b
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("calibre: %w", err)
+	}
+
+	// The link tables are read whole rather than per book; libraries
+	// hold at most a few hundred thousand books.
+	links := []struct {
+		query string
+		set   func(b *Book, key, value string)
+	}{
+		{`SELECT l.book, '', a.name FROM books_authors_link l JOIN authors a ON a.id = l.author ORDER BY l.id`,
+			func(b *Book, _, v string) { b.Authors = append(b.Authors, strings.ReplaceAll(v, "|", ",")) }},
+		{`SELECT book, type, val FROM identifiers`,
+			func(b *Book, k, v string) { b.Identifiers[k] = v }},
+		{`SELECT l.book, '', p.name FROM books_publishers_link l JOIN publishers p ON p.id = l.publisher`,
+			func(b *Book, _, v string) { b.Publisher = v }},
+		{`SELECT l.book, '', g.lang_code FROM books_languages_link l JOIN languages g ON g.id = l.lang_code ORDER BY l.item_order`,
+			func(b *Book, _, v string) { b.Languages = append(b.Languages, v) }},
+		{`SELECT l.book, '', t.name FROM books_tags_link l JOIN tags t ON t.id = l.tag ORDER BY t.name`,
+			func(b *Book, _, v string) { b.Tags = append(b.Tags, v) }},
+		{`SELECT l.book, '', s.name FROM books_series_link l JOIN series s ON s.id = l.series`,
+			func(b *Book, _, v string) { b.Series = v }},
+		{`SELECT book, '', text FROM comments`,
+			func(b *Book, _, v string) { b.Comments = v }},
+	}
+	for _, link := range links {
+		if err := l.scan(ctx, link.query, byID, link.set); err != nil {
+			return nil, err
+		}
+	}
+	return books, nil
+}
+
+// scan runs a query returning (book, key, value) rows and passes each
+// row of a known book to set.
+func (l *Library) scan(ctx context.Context, query string, byID map[int64]*Book, set func(*Book, string, string)) error {
+	rows, err := l.db.QueryContext(ctx, query)
+	if err != nil {
+		return fmt.Errorf("calibre: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int64
+		var key, value sql.NullString
+		if err := rows.Scan(&id, &key, &value); err != nil {
+			return fmt.Errorf("calibre: %w", err)
+		}
+		if b, ok := byID[id]; ok && value.String != "" {
+			set(b, key.String, value.String)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("calibre: %w", err)
+	}
+	return nil
+}
+
+// unknown is the title and author Calibre assigns when they are missing.
+const unknown = "Unknown"
+
+// Rows returns the books as enrichment input. Row.Index is the book's
+// position in books.
+func Rows(books []*Book) input.Reader {
+	rows := make([]*input.Row, len(books))
+	for i, b := range books {
+		r := &input.Row{Index: i, ISBN: b.ISBN()}
+		if b.Title != unknown {
+			r.Title = b.Title
+		}
+		if len(b.Authors) > 0 && b.Authors[0] != unknown {
+			r.Author = b.Authors[0]
+		}
+		rows[i] = r
+	}
+	return input.Slice(rows)
+}
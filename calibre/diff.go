@@ -0,0 +1,121 @@
+package calibre
+
+import (
+	"encoding/csv"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/lang"
+)
+
+// MaxTags is the number of subjects added as tags to a book without
+// tags. Providers often list dozens of subjects.
+var MaxTags = 10
+
+// Change sets one Calibre field of a book. Field is a calibredb field
+// name; Old and New are in calibredb's text form.
+type Change struct {
+	BookID int64
+	Title  string
+	Field  string
+	Old    string
+	New    string
+}
+
+// Changes returns the fields of cb that are missing in Calibre and known
+// in b. Fields Calibre already has are never changed.
+func Changes(cb *Book, b *book.BookInfo) []Change {
+	var cs []Change
+	set := func(field, old, value string) {
+		if value != "" {
+			cs = append(cs, Change{BookID: cb.ID, Title: cb.Title, Field: field, Old: old, New: value})
+		}
+	}
+	if (cb.Title == "" || cb.Title == unknown) && b.Title != "" {
+		set("title", cb.Title, b.FullTitle())
+	}
+	if (len(cb.Authors) == 0 || cb.Authors[0] == unknown) && len(b.Authors) > 0 {
+		set("authors", strings.Join(cb.Authors, " & "), strings.Join(b.Authors, " & "))
+	}
+	if cb.ISBN() == "" && b.ISBN() != "" {
+		// calibredb replaces all identifiers, so the others are kept
+		// in the new value.
+		ids := maps.Clone(cb.Identifiers)
+		old := formatIdentifiers(ids)
+		ids["isbn"] = b.ISBN()
+		set("identifiers", old, formatIdentifiers(ids))
+	}
+	if cb.Publisher == "" && len(b.Publishers) > 0 {
+		set("publisher", "", b.Publishers[0])
+	}
+	if cb.PubDate == "" {
+		set("pubdate", "", pubDate(b.PublishDate))
+	}
+	if len(cb.Languages) == 0 {
+		set("languages", "", strings.Join(lang.Code.FormatAll(b.Languages), ","))
+	}
+	if len(cb.Tags) == 0 && len(b.Subjects) > 0 {
+		tags := slices.Clone(b.Subjects[:min(len(b.Subjects), MaxTags)])
+		// Commas separate tags; Calibre itself replaces them with
+		// semicolons.
+		for i, t := range tags {
+			tags[i] = strings.ReplaceAll(t, ",", ";")
+		}
+		set("tags", "", strings.Join(tags, ","))
+	}
+	if cb.Series == "" && b.Series != "" {
+		set("series", "", b.Series)
+		if n, err := strconv.ParseFloat(b.SeriesPosition, 64); err == nil {
+			set("series_index", strconv.FormatFloat(cb.SeriesIndex, 'f', -1, 64), strconv.FormatFloat(n, 'f', -1, 64))
+		}
+	}
+	if strings.TrimSpace(cb.Comments) == "" {
+		set("comments", "", b.Description)
+	}
+	return cs
+}
+
+func formatIdentifiers(ids map[string]string) string {
+	var parts []string
+	for _, k := range slices.Sorted(maps.Keys(ids)) {
+		parts = append(parts, k+":"+ids[k])
+	}
+	return strings.Join(parts, ",")
+}
+
+// pubDate converts a provider's publish date to the "YYYY-MM-DD" form
+// calibredb accepts. Dates without a day or month fall on the first.
+func pubDate(s string) string {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{
+		"2006-01-02", "January 2, 2006", "Jan 2, 2006", "2 January 2006",
+		"2006-01", "January 2006", "Jan 2006",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t.Format("2006-01-02")
+		}
+	}
+	if y := (&book.BookInfo{PublishDate: s}).Year(); y != "" {
+		return y + "-01-01"
+	}
+	return ""
+}
+
+// DiffHeader is the header row written by WriteDiff.
+var DiffHeader = []string{"Book ID", "Title", "Field", "Current", "New"}
+
+// WriteDiff writes changes as CSV, for review before they are applied.
+func WriteDiff(w io.Writer, changes []Change) error {
+	cw := csv.NewWriter(w)
+	cw.Write(DiffHeader)
+	for _, c := range changes {
+		cw.Write([]string{strconv.FormatInt(c.BookID, 10), c.Title, c.Field, c.Old, c.New})
+	}
+	cw.Flush()
+	return cw.Error()
+}
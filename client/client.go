@@ -0,0 +1,241 @@
+// Package client calls the booktool HTTP service (booktool serve) from
+// Go: book lookups, file enrichment, the job history and the provider
+// settings. The service describes the same API at /openapi.json, for
+// clients in other languages.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/jobs"
+)
+
+// Client calls a booktool service.
+type Client struct {
+	// BaseURL is the service address, such as "http://localhost:8080".
+	BaseURL string
+	// Token is the bearer token of a user of the service; empty for
+	// services without users.
+	Token string
+	// HTTPClient sends the requests; nil selects http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+// New returns a client of the service at baseURL, authenticating with
+// token.
+func New(baseURL, token string) *Client {
+	return &Client{BaseURL: strings.TrimSuffix(baseURL, "/"), Token: token}
+}
+
+// Error is an error answered by the service.
+type Error struct {
+	// StatusCode is the HTTP status, e.g. 404 for an unknown ISBN.
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("booktool: %d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// do sends a request and decodes the JSON answer into out, unless out
+// is nil; the caller then reads and closes the response body.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body io.Reader, contentType string, out any) (*http.Response, error) {
+	u := strings.TrimSuffix(c.BaseURL, "/") + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	req.Header.Set("Accept", "application/json")
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		e := &Error{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(data))}
+		var body struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(data, &body) == nil && body.Error != "" {
+			e.Message = body.Error
+		}
+		return nil, e
+	}
+	if out == nil {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return nil, fmt.Errorf("booktool: decode %s %s: %w", method, path, err)
+	}
+	return resp, nil
+}
+
+// Book looks a book up by ISBN.
+func (c *Client) Book(ctx context.Context, isbn string) (*book.BookInfo, error) {
+	var b book.BookInfo
+	if _, err := c.do(ctx, http.MethodGet, "/book/"+url.PathEscape(isbn), nil, nil, "", &b); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+// EnrichOptions select the formats of an enrichment. Zero fields keep
+// the service's defaults.
+type EnrichOptions struct {
+	// InputFormat is the format of the upload; detected from its
+	// content and name by default.
+	InputFormat string
+	// Sheet is the worksheet of spreadsheet uploads.
+	Sheet string
+	// Format is the output format; the upload's by default.
+	Format string
+}
+
+// Enriched is the result of an enrichment.
+type Enriched struct {
+	// Job is the ID of the job in the history.
+	Job int64
+	// Rows and Failed count the rows enriched and those whose lookup
+	// failed.
+	Rows, Failed int
+	// Filename and ContentType describe Data, the enriched file.
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Enrich uploads a file and returns it enriched. name is the file name,
+// whose extension helps detect the formats.
+func (c *Client) Enrich(ctx context.Context, name string, file io.Reader, opts EnrichOptions) (*Enriched, error) {
+	q := url.Values{}
+	for k, v := range map[string]string{"filename": name, "input_format": opts.InputFormat, "sheet": opts.Sheet, "format": opts.Format} {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	resp, err := c.do(ctx, http.MethodPost, "/enrich", q, file, "application/octet-stream", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	res := &Enriched{ContentType: resp.Header.Get("Content-Type"), Data: data}
+	res.Job, _ = strconv.ParseInt(resp.Header.Get("X-Booktool-Job"), 10, 64)
+	res.Rows, _ = strconv.Atoi(resp.Header.Get("X-Booktool-Rows"))
+	res.Failed, _ = strconv.Atoi(resp.Header.Get("X-Booktool-Failed"))
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		res.Filename = params["filename"]
+	}
+	return res, nil
+}
+
+// Jobs returns the jobs of the history matching f, newest first. A zero
+// Limit selects the service's default.
+func (c *Client) Jobs(ctx context.Context, f jobs.Filter) ([]*jobs.Job, error) {
+	q := url.Values{}
+	set := func(k, v string) {
+		if v != "" {
+			q.Set(k, v)
+		}
+	}
+	set("user", f.User)
+	set("kind", f.Kind)
+	set("status", string(f.Status))
+	if !f.Since.IsZero() {
+		set("since", f.Since.Format(time.RFC3339))
+	}
+	if !f.Until.IsZero() {
+		set("until", f.Until.Format(time.RFC3339))
+	}
+	if f.Limit > 0 {
+		set("limit", strconv.Itoa(f.Limit))
+	}
+	var js []*jobs.Job
+	if _, err := c.do(ctx, http.MethodGet, "/jobs", q, nil, "", &js); err != nil {
+		return nil, err
+	}
+	return js, nil
+}
+
+// Job returns the job with the given ID.
+func (c *Client) Job(ctx context.Context, id int64) (*jobs.Job, error) {
+	var j jobs.Job
+	if _, err := c.do(ctx, http.MethodGet, "/jobs/"+strconv.FormatInt(id, 10), nil, nil, "", &j); err != nil {
+		return nil, err
+	}
+	return &j, nil
+}
+
+type providersBody struct {
+	Providers []string `json:"providers"`
+}
+
+// Providers returns the service's providers, in priority order.
+func (c *Client) Providers(ctx context.Context) ([]string, error) {
+	var body providersBody
+	if _, err := c.do(ctx, http.MethodGet, "/admin/providers", nil, nil, "", &body); err != nil {
+		return nil, err
+	}
+	return body.Providers, nil
+}
+
+// SetProviders replaces the service's providers and returns the new
+// list.
+func (c *Client) SetProviders(ctx context.Context, providers []string) ([]string, error) {
+	data, err := json.Marshal(providersBody{providers})
+	if err != nil {
+		return nil, err
+	}
+	var body providersBody
+	if _, err := c.do(ctx, http.MethodPut, "/admin/providers", nil, bytes.NewReader(data), "application/json", &body); err != nil {
+		return nil, err
+	}
+	return body.Providers, nil
+}
+
+// Readiness is the answer of the readiness probe: "ok" or the error of
+// each check.
+type Readiness struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Ready runs the service's readiness checks. A service that is not
+// ready answers with an *Error of status 503.
+func (c *Client) Ready(ctx context.Context) (*Readiness, error) {
+	var r Readiness
+	if _, err := c.do(ctx, http.MethodGet, "/readyz", nil, nil, "", &r); err != nil {
+		return nil, err
+	}
+	return &r, nil
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/SouadAli10/book_scrapping_tool/acquire"
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/price"
+)
+
+// acquireFlags holds the flags of the acquire command.
+type acquireFlags struct {
+	enrichFlags
+	config        string
+	wishlist      string
+	stock         string
+	output        string
+	condition     string
+	maxEditions   int
+	otherEditions bool
+}
+
+func (f *acquireFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.config, "config", "", "configuration file (default: "+config.DefaultPath+" if present)")
+	fs.StringVar(&f.wishlist, "wishlist", "", "wishlist or reading list file (xlsx, csv, tsv, jsonl)")
+	fs.StringVar(&f.stock, "stock", "", "inventory file (xlsx, csv, tsv, jsonl); rows with a quantity of 0 are out of stock")
+	fs.StringVar(&f.output, "output", "", "write the report as CSV to this file (default: standard output)")
+	fs.StringVar(&f.condition, "condition", "any", "copies to buy: any, new or used")
+	fs.IntVar(&f.maxEditions, "max-editions", acquire.DefaultMaxEditions, "editions priced per item")
+	fs.BoolVar(&f.otherEditions, "other-editions", true, "also price the other editions of each item's work, from OpenLibrary")
+	f.enrichFlags.register(fs)
+	// Suggestions need prices; AbeBooks needs no key.
+	f.prices = "abebooks"
+}
+
+// cmdAcquire looks up the wishlist items that are not in stock and
+// reports the best edition to buy for each, with its price and
+// supplier.
+func cmdAcquire(args []string) error {
+	fs := flag.NewFlagSet("acquire", flag.ContinueOnError)
+	var f acquireFlags
+	f.register(fs)
+	sealer, err := sealerFor(flagArg(args, "key-file"))
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(flagArg(args, "config"), sealer)
+	if err != nil {
+		return err
+	}
+	f.enrichFlags.apply(cfg)
+	f.http.cipher = sealer
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	f.resolveKeys()
+	if f.wishlist == "" || f.stock == "" {
+		fs.Usage()
+		return errors.New(i18n.T("acquire needs a -wishlist and a -stock file"))
+	}
+	var cond price.Condition
+	switch f.condition {
+	case "any":
+	case string(price.New), string(price.Used):
+		cond = price.Condition(f.condition)
+	default:
+		return fmt.Errorf("invalid -condition %q (want any, new or used)", f.condition)
+	}
+	if f.prices == "" {
+		return errors.New(i18n.T("acquire needs at least one price source (-prices)"))
+	}
+	f.isbnFamily = f.isbnFamily || f.otherEditions
+	if err := f.restrictHosts(); err != nil {
+		return err
+	}
+
+	stock := &acquire.Stock{MinMatch: f.minMatch}
+	r, err := input.Open(f.stock, "", input.Options{})
+	if err != nil {
+		return err
+	}
+	err = stock.Read(r)
+	r.Close()
+	if err != nil {
+		return err
+	}
+	wishlist, err := input.Open(f.wishlist, "", input.Options{})
+	if err != nil {
+		return err
+	}
+	defer wishlist.Close()
+
+	ctx := context.Background()
+	metrics := new(httpx.Metrics)
+	c := f.http.client(metrics)
+	e, budgets, err := f.enricher(f.providers, c)
+	if err != nil {
+		return err
+	}
+	// Prices are looked up per edition by the suggester.
+	prices := e.Prices
+	e.Prices = nil
+	s := &acquire.Suggester{Prices: prices, Condition: cond, MaxEditions: f.maxEditions}
+
+	var suggestions []acquire.Suggestion
+	var items, inStock int
+	prog := newProgress(f.plain)
+	err = e.Run(ctx, wishlist, func(res *enrich.Result) error {
+		items++
+		if stock.Has(res.Row, res.Book) {
+			inStock++
+		} else {
+			suggestions = append(suggestions, s.Suggest(ctx, res.Row, res.Book))
+		}
+		prog.update(i18n.Sprintf("%d items checked, %d in stock", items, inStock))
+		return nil
+	})
+	prog.finish()
+	if err != nil {
+		return err
+	}
+	if err := writeAcquireReport(f.output, suggestions); err != nil {
+		return err
+	}
+	i18n.Fprintf(os.Stderr, "%d of %d items are not in stock\n", len(suggestions), items)
+	printMetrics(os.Stderr, metrics)
+	printBudgets(os.Stderr, budgets)
+	return nil
+}
+
+// writeAcquireReport writes the suggestions to path, or to standard
+// output if path is empty.
+func writeAcquireReport(path string, suggestions []acquire.Suggestion) error {
+	if path == "" {
+		return acquire.WriteReport(os.Stdout, suggestions)
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := acquire.WriteReport(out, suggestions); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	i18n.Fprintf(os.Stderr, "wrote %s\n", path)
+	return nil
+}
@@ -0,0 +1,246 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/alert"
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/reprice"
+)
+
+// markdownFlag is the -markdown schedule: "days=percent", also as a
+// comma-separated list. Setting it replaces the default schedule.
+type markdownFlag struct {
+	steps []alert.Markdown
+	set   bool
+}
+
+func (f *markdownFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	parts := make([]string, len(f.steps))
+	for i, m := range f.steps {
+		parts[i] = strconv.Itoa(m.AfterDays) + "=" + strconv.FormatFloat(m.Percent, 'f', -1, 64)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (f *markdownFlag) Set(s string) error {
+	if !f.set {
+		f.steps, f.set = nil, true
+	}
+	for _, part := range strings.Split(s, ",") {
+		days, pct, ok := strings.Cut(part, "=")
+		if !ok {
+			return fmt.Errorf("want days=percent, got %q", part)
+		}
+		d, err := strconv.Atoi(strings.TrimSpace(days))
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid days %q", days)
+		}
+		p, err := strconv.ParseFloat(strings.TrimSuffix(strings.TrimSpace(pct), "%"), 64)
+		if err != nil || p <= 0 || p >= 100 {
+			return fmt.Errorf("invalid markdown %q (want a percent between 0 and 100)", pct)
+		}
+		f.steps = append(f.steps, alert.Markdown{AfterDays: d, Percent: p})
+	}
+	return nil
+}
+
+// alertsFlags holds the flags of the alerts command.
+type alertsFlags struct {
+	config    string
+	keyFile   string
+	catalog   string
+	sheet     string
+	sales     string
+	output    string
+	asOf      string
+	notify    string
+	window    int
+	fastSales int
+	cover     int
+	stale     int
+	markdowns markdownFlag
+	ending    float64
+	floor     float64
+}
+
+func (f *alertsFlags) register(fs *flag.FlagSet) {
+	r := alert.DefaultRules()
+	fs.StringVar(&f.config, "config", "", "configuration file (default: "+config.DefaultPath+" if present)")
+	fs.StringVar(&f.keyFile, "key-file", "", keyFileUsage)
+	fs.StringVar(&f.catalog, "catalog", "", "catalog (xlsx, csv, tsv, jsonl) with quantity, acquisition date and price columns")
+	fs.StringVar(&f.sheet, "sheet", "", "worksheet to read from spreadsheet catalogs (default: first)")
+	fs.StringVar(&f.sales, "sales", "", "comma-separated sales imports (xlsx, csv, tsv, jsonl) with ISBN or title, quantity sold and date columns")
+	fs.StringVar(&f.output, "output", "", "write the alerts as CSV to this file (default: standard output)")
+	fs.StringVar(&f.asOf, "as-of", "", "date the stock is checked on, such as 2026-10-17 (default: today)")
+	fs.StringVar(&f.notify, "notify", "", "post the alerts as JSON to this webhook URL, such as a Slack incoming webhook, when there are any")
+	fs.IntVar(&f.window, "window-days", r.WindowDays, "count sales over this many days")
+	fs.IntVar(&f.fastSales, "fast-sales", r.FastSales, "copies sold in the window that make a title a fast seller")
+	fs.IntVar(&f.cover, "cover-days", r.CoverDays, "flag fast sellers whose stock lasts fewer days than this at their rate of sale")
+	fs.IntVar(&f.stale, "stale-days", r.StaleDays, "flag copies unsold this many days after they were acquired or last sold")
+	f.markdowns.steps = r.Markdowns
+	fs.Var(&f.markdowns, "markdown", "markdown schedule of stale copies as days=percent, e.g. \"180=10,365=40\" (repeatable)")
+	fs.Float64Var(&f.ending, "ending", r.Pricing.Ending, "round marked-down prices to the nearest amount with these cents (0: keep the cents)")
+	fs.Float64Var(&f.floor, "floor", 0, "lowest marked-down price (0: none)")
+}
+
+// apply copies the alerts section of the configuration into f.
+func (f *alertsFlags) apply(c config.Alerts) {
+	if c.WindowDays > 0 {
+		f.window = c.WindowDays
+	}
+	if c.FastSales > 0 {
+		f.fastSales = c.FastSales
+	}
+	if c.CoverDays > 0 {
+		f.cover = c.CoverDays
+	}
+	if c.StaleDays > 0 {
+		f.stale = c.StaleDays
+	}
+	if len(c.Markdowns) > 0 {
+		f.markdowns.steps = make([]alert.Markdown, len(c.Markdowns))
+		for i, m := range c.Markdowns {
+			f.markdowns.steps[i] = alert.Markdown{AfterDays: m.AfterDays, Percent: m.Percent}
+		}
+	}
+	if c.Notify != "" {
+		f.notify = c.Notify
+	}
+}
+
+// rules returns the alert rules of the flags.
+func (f *alertsFlags) rules() (*alert.Rules, error) {
+	if f.window <= 0 || f.fastSales < 0 || f.cover < 0 || f.stale <= 0 {
+		return nil, errors.New("-window-days and -stale-days must be positive, -fast-sales and -cover-days not negative")
+	}
+	if f.ending < 0 || f.ending >= 1 {
+		return nil, fmt.Errorf("invalid -ending %v (want cents between 0 and 0.99)", f.ending)
+	}
+	return &alert.Rules{
+		WindowDays: f.window,
+		FastSales:  f.fastSales,
+		CoverDays:  f.cover,
+		StaleDays:  f.stale,
+		Markdowns:  slices.Clone(f.markdowns.steps),
+		Pricing:    reprice.Rules{Ending: f.ending, Floor: f.floor},
+	}, nil
+}
+
+// cmdAlerts reports fast sellers running low and stale copies to mark
+// down, from a catalog and sales imports.
+func cmdAlerts(args []string) error {
+	fs := flag.NewFlagSet("alerts", flag.ContinueOnError)
+	var f alertsFlags
+	f.register(fs)
+	sealer, err := sealerFor(flagArg(args, "key-file"))
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(flagArg(args, "config"), sealer)
+	if err != nil {
+		return err
+	}
+	f.apply(cfg.Alerts)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if f.catalog == "" && fs.NArg() > 0 {
+		f.catalog = fs.Arg(0)
+	}
+	if f.catalog == "" {
+		fs.Usage()
+		return errors.New(i18n.T("no catalog given"))
+	}
+	rules, err := f.rules()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	if f.asOf != "" {
+		t, ok := alert.ParseDate(f.asOf)
+		if !ok {
+			return fmt.Errorf("invalid -as-of %q (want a date such as 2026-10-17)", f.asOf)
+		}
+		// The whole day counts.
+		now = t.AddDate(0, 0, 1).Add(-time.Second)
+	}
+
+	sales := new(alert.Sales)
+	for _, path := range strings.Split(f.sales, ",") {
+		if path = strings.TrimSpace(path); path == "" {
+			continue
+		}
+		r, err := input.Open(path, "", input.Options{})
+		if err != nil {
+			return err
+		}
+		skipped, err := sales.Read(r)
+		r.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		if skipped > 0 {
+			i18n.Fprintf(os.Stderr, "%s: skipped %d sales rows without an ISBN, a title or a readable date\n", path, skipped)
+		}
+	}
+	r, err := input.Open(f.catalog, "", input.Options{Sheet: f.sheet})
+	if err != nil {
+		return err
+	}
+	alerts, err := rules.Check(r, sales, now)
+	r.Close()
+	if err != nil {
+		return err
+	}
+	if err := writeAlertsReport(f.output, alerts); err != nil {
+		return err
+	}
+	low, stale := alert.Count(alerts)
+	i18n.Fprintf(os.Stderr, "%d low-stock and %d stale items\n", low, stale)
+	if f.notify != "" && len(alerts) > 0 {
+		text := i18n.Sprintf("%s: %d low-stock and %d stale items", filepath.Base(f.catalog), low, stale)
+		c := &http.Client{Timeout: 30 * time.Second}
+		if err := alert.Notify(context.Background(), c, f.notify, text, alerts); err != nil {
+			return err
+		}
+		i18n.Fprintf(os.Stderr, "posted the alerts to the -notify webhook\n")
+	}
+	return nil
+}
+
+// writeAlertsReport writes the alerts to path, or to standard output if
+// path is empty.
+func writeAlertsReport(path string, alerts []alert.Alert) error {
+	if path == "" {
+		return alert.WriteReport(os.Stdout, alerts)
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := alert.WriteReport(out, alerts); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	i18n.Fprintf(os.Stderr, "wrote %s\n", path)
+	return nil
+}
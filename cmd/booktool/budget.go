@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// budgetFlag is a repeatable "provider=N" flag, also accepting a
+// comma-separated list.
+type budgetFlag map[string]int
+
+func (b budgetFlag) String() string {
+	var parts []string
+	for k, v := range b {
+		parts = append(parts, k+"="+strconv.Itoa(v))
+	}
+	return strings.Join(parts, ",")
+}
+
+func (b budgetFlag) Set(s string) error {
+	for _, part := range strings.Split(s, ",") {
+		name, n, ok := strings.Cut(part, "=")
+		if !ok {
+			return fmt.Errorf("want provider=N, got %q", part)
+		}
+		v, err := strconv.Atoi(strings.TrimSpace(n))
+		if err != nil || v <= 0 {
+			return fmt.Errorf("invalid call budget %q", part)
+		}
+		name = canonicalProvider(name)
+		if e, ok := providerTable[name]; !ok || e.new == nil {
+			return fmt.Errorf("unknown provider %q", name)
+		}
+		b[name] = v
+	}
+	return nil
+}
+
+// applyBudgets wraps the providers that have a call budget.
+func applyBudgets(ps []provider.Provider, budgets map[string]int) []*provider.Budgeted {
+	var wrapped []*provider.Budgeted
+	for i, p := range ps {
+		if n, ok := budgets[p.Name()]; ok {
+			b := provider.WithBudget(p, int64(n))
+			ps[i] = b
+			wrapped = append(wrapped, b)
+		}
+	}
+	return wrapped
+}
+
+func printBudgets(w io.Writer, bs []*provider.Budgeted) {
+	for _, b := range bs {
+		note := ""
+		if b.Exhausted() {
+			note = i18n.T(" (exhausted; later rows skipped this provider)")
+		}
+		i18n.Fprintf(w, "  %-14s %d of %d calls%s\n", b.Name(), b.Calls(), b.Max, note)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"os"
+	"slices"
+
+	"github.com/SouadAli10/book_scrapping_tool/calibre"
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+)
+
+// calibreFlags holds the flags of the calibre command.
+type calibreFlags struct {
+	enrichFlags
+	config    string
+	library   string
+	diff      string
+	apply     bool
+	calibredb string
+}
+
+func (f *calibreFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.config, "config", "", "configuration file (default: "+config.DefaultPath+" if present)")
+	fs.StringVar(&f.library, "library", "", "Calibre library directory, the one holding "+calibre.DBName)
+	fs.StringVar(&f.diff, "diff", "", "write the changes as CSV to this file (default: standard output unless -apply)")
+	fs.BoolVar(&f.apply, "apply", false, "write the missing fields to the library with calibredb")
+	fs.StringVar(&f.calibredb, "calibredb", calibre.DefaultCalibredb, "calibredb command used by -apply")
+	f.enrichFlags.register(fs)
+}
+
+// cmdCalibre enriches the books of a Calibre library and fills in the
+// fields Calibre is missing. Fields that are already set are left alone.
+func cmdCalibre(args []string) error {
+	fs := flag.NewFlagSet("calibre", flag.ContinueOnError)
+	var f calibreFlags
+	f.register(fs)
+	sealer, err := sealerFor(flagArg(args, "key-file"))
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(flagArg(args, "config"), sealer)
+	if err != nil {
+		return err
+	}
+	f.enrichFlags.apply(cfg)
+	f.http.cipher = sealer
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	f.resolveKeys()
+	if f.library == "" && fs.NArg() > 0 {
+		f.library = fs.Arg(0)
+	}
+	if f.library == "" {
+		fs.Usage()
+		return errors.New(i18n.T("no Calibre library given"))
+	}
+	if !slices.Contains(sql.Drivers(), sqliteDriver) {
+		return errors.New(i18n.T("reading a Calibre library needs a booktool built with -tags sqlite"))
+	}
+	if err := f.restrictHosts(); err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	lib, err := calibre.Open(sqliteDriver, f.library)
+	if err != nil {
+		return err
+	}
+	defer lib.Close()
+	books, err := lib.Books(ctx)
+	if err != nil {
+		return err
+	}
+	metrics := new(httpx.Metrics)
+	e, budgets, err := f.enricher(f.providers, f.http.client(metrics))
+	if err != nil {
+		return err
+	}
+
+	changes := make([][]calibre.Change, len(books))
+	var done, failed, changed int
+	prog := newProgress(f.plain)
+	err = e.Run(ctx, calibre.Rows(books), func(res *enrich.Result) error {
+		done++
+		if res.Err != nil {
+			failed++
+		}
+		if res.Book != nil {
+			changes[res.Row.Index] = calibre.Changes(books[res.Row.Index], res.Book)
+			if len(changes[res.Row.Index]) > 0 {
+				changed++
+			}
+		}
+		prog.update(i18n.Sprintf("%d of %d books enriched, %d failed", done, len(books), failed))
+		return nil
+	})
+	prog.finish()
+	if err != nil {
+		return err
+	}
+	all := slices.Concat(changes...)
+	if f.diff != "" || !f.apply {
+		if err := writeCalibreDiff(f.diff, all); err != nil {
+			return err
+		}
+	}
+	i18n.Fprintf(os.Stderr, "%d of %d books have missing fields to fill (%d changes)\n", changed, len(books), len(all))
+	if f.apply {
+		applied := 0
+		for i, cs := range changes {
+			if len(cs) == 0 {
+				continue
+			}
+			if err := lib.Apply(ctx, f.calibredb, books[i].ID, cs); err != nil {
+				return i18n.Errorf("%w (%d books updated before the failure)", err, applied)
+			}
+			applied++
+		}
+		i18n.Fprintf(os.Stderr, "updated %d books in %s\n", applied, f.library)
+	}
+	printMetrics(os.Stderr, metrics)
+	printBudgets(os.Stderr, budgets)
+	return nil
+}
+
+// writeCalibreDiff writes the changes to path, or to standard output if
+// path is empty.
+func writeCalibreDiff(path string, changes []calibre.Change) error {
+	if path == "" {
+		return calibre.WriteDiff(os.Stdout, changes)
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := calibre.WriteDiff(out, changes); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	i18n.Fprintf(os.Stderr, "wrote %s\n", path)
+	return nil
+}
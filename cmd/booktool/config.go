@@ -0,0 +1,181 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/cover"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+	"github.com/SouadAli10/book_scrapping_tool/profile"
+	"github.com/SouadAli10/book_scrapping_tool/seal"
+)
+
+func schema() *config.Schema {
+	s := &config.Schema{
+		Providers:           make(map[string]config.ProviderSpec),
+		ItemProviders:       make(map[string]config.ProviderSpec),
+		InputFormats:        input.Names(),
+		OutputFormats:       output.Names(),
+		Profiles:            profile.Names(),
+		PriceSources:        priceSourceNames(),
+		Services:            append([]string{cover.VisionService}, translationServiceNames()...),
+		TranslationServices: translationServiceNames(),
+	}
+	for name, e := range providerTable {
+		if e.new != nil {
+			s.Providers[name] = e.spec
+		}
+		if e.item != nil {
+			s.ItemProviders[name] = e.spec
+		}
+	}
+	return s
+}
+
+// flagArg finds the value of flag name in args before the flag set is
+// parsed, e.g. the -config file that supplies the flag defaults.
+func flagArg(args []string, name string) string {
+	for i, a := range args {
+		a = strings.TrimLeft(a, "-")
+		if v, ok := strings.CutPrefix(a, name+"="); ok {
+			return v
+		}
+		if a == name && i+1 < len(args) {
+			return args[i+1]
+		}
+		if a == "" { // "--" ends the flags
+			break
+		}
+	}
+	return ""
+}
+
+// loadConfig reads and validates the configuration at path, or at
+// config.DefaultPath when path is empty. A missing default file is not an
+// error. Encrypted files are opened with s. Validation problems are
+// printed and abort the run before any lookup is made.
+func loadConfig(path string, s *seal.Sealer) (*config.Config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = config.DefaultPath
+	}
+	data, err := s.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return &config.Config{}, nil
+		}
+		return nil, err
+	}
+	if probs := config.Check(data, schema()); len(probs) > 0 {
+		for _, p := range probs {
+			fmt.Fprintf(os.Stderr, "%s: %s\n", path, p)
+		}
+		return nil, fmt.Errorf("%d problem(s) in %s", len(probs), path)
+	}
+	return config.Parse(path, data)
+}
+
+func cmdConfig(args []string) error {
+	if len(args) == 0 || args[0] != "check" {
+		return errors.New("usage: booktool config check [-config file]")
+	}
+	fs := flag.NewFlagSet("config check", flag.ContinueOnError)
+	registerLang(fs)
+	path := fs.String("config", config.DefaultPath, "configuration file to check")
+	keyFile := fs.String("key-file", "", keyFileUsage)
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	s, err := sealerFor(*keyFile)
+	if err != nil {
+		return err
+	}
+	data, err := s.ReadFile(*path)
+	if err != nil {
+		return err
+	}
+	probs := config.Check(data, schema())
+	if len(probs) == 0 {
+		fmt.Printf("%s: OK\n", *path)
+		return nil
+	}
+	for _, p := range probs {
+		fmt.Fprintf(os.Stderr, "%s: %s\n", *path, p)
+	}
+	return fmt.Errorf("%d problem(s) in %s", len(probs), *path)
+}
+
+// apply copies the configuration into the run flags. It is called after
+// the flags are registered and before they are parsed, so explicit flags
+// win.
+func (f *runFlags) apply(c *config.Config) {
+	f.enrichFlags.apply(c)
+	f.costs = c.Costs
+	if c.CostThreshold != nil {
+		f.costThreshold = *c.CostThreshold
+	}
+	if c.Input.Format != "" {
+		f.inputFormat = c.Input.Format
+	}
+	if c.Input.Sheet != "" {
+		f.sheet = c.Input.Sheet
+	}
+	if len(c.Input.Sheets) > 0 {
+		f.sheetList = strings.Join(c.Input.Sheets, ",")
+	}
+	f.allSheets = c.Input.AllSheets
+	f.columns = c.Input.Columns
+	if c.Output.Format != "" {
+		f.outputFormat = c.Output.Format
+	}
+	if c.Output.MinConfidence > 0 {
+		f.minConfidence = c.Output.MinConfidence
+	}
+	f.editions = f.editions || c.Output.Editions
+	for name, o := range c.Output.Order {
+		f.order.Set(name + "=" + o) // validated with the configuration
+	}
+	f.db.apply(c.Output.Database)
+	f.site.apply(c.Output.Site)
+	f.sheets.apply(c.GoogleSheets)
+}
+
+// apply copies the HTTP section of the configuration into f.
+func (f *httpFlags) apply(c config.HTTP) {
+	if c.Timeout > 0 {
+		f.timeout = time.Duration(c.Timeout)
+	}
+	if c.Rate > 0 {
+		f.interval = time.Duration(c.Rate)
+	}
+	for _, w := range c.RateSchedule {
+		f.schedule.add(w) // validated with the configuration
+	}
+	if c.Retries > 0 {
+		f.retries = c.Retries
+	}
+	if c.CacheDir != "" {
+		f.cacheDir = c.CacheDir
+	}
+	if len(c.AllowHosts) > 0 {
+		f.allowHosts = strings.Join(c.AllowHosts, ",")
+	}
+	if c.Proxy != "" {
+		f.proxy = c.Proxy
+	}
+	if len(c.CACerts) > 0 {
+		f.caCerts = strings.Join(c.CACerts, ",")
+	}
+	if c.UserAgent != "" {
+		f.userAgent = c.UserAgent
+	}
+	for name, v := range c.Headers {
+		f.headers.Set(name + ": " + v) // validated with the configuration
+	}
+}
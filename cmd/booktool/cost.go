@@ -0,0 +1,95 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/SouadAli10/book_scrapping_tool/cost"
+	"github.com/SouadAli10/book_scrapping_tool/gtin"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+)
+
+// isTerminal reports whether f is an interactive terminal.
+func isTerminal(f *os.File) bool {
+	fi, err := f.Stat()
+	return err == nil && fi.Mode()&os.ModeCharDevice != 0
+}
+
+// planRun counts the rows of the input the way the run will see them.
+func planRun(f *runFlags, providers, items []string) (*cost.Plan, error) {
+	rows, err := f.openInput()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	p := &cost.Plan{Providers: providers, Items: items, Extra: f.paidFeatures()}
+	for {
+		row, err := rows.Next()
+		if err == io.EOF {
+			return p, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(items) > 0 && gtin.IsItem(row.ISBN) {
+			p.ItemRows++
+		} else if isbn.Valid(row.ISBN) {
+			p.ISBNRows++
+		} else if row.Title != "" {
+			p.SearchRows++
+		}
+	}
+}
+
+// confirmCost estimates the spend on paid services and asks before
+// exceeding the threshold. It only reads the input when a paid service
+// is enabled.
+func confirmCost(f *runFlags, providers, items []string) error {
+	// Offline runs make no paid calls.
+	if f.http.offline {
+		return nil
+	}
+	prices := cost.Merge(f.costs)
+	paid := false
+	for _, p := range slices.Concat(providers, items, f.paidFeatures()) {
+		paid = paid || prices[p] > 0
+	}
+	if !paid {
+		return nil
+	}
+	// Standard input cannot be read twice to count the rows.
+	if f.input == input.Stdin {
+		if f.yes {
+			return nil
+		}
+		return errors.New(i18n.T("paid services are enabled and the cost of standard input cannot be estimated; rerun with -yes to accept"))
+	}
+	plan, err := planRun(f, providers, items)
+	if err != nil {
+		return err
+	}
+	est := plan.Estimate(prices)
+	if est.Total <= f.costThreshold {
+		return nil
+	}
+	i18n.Fprintf(os.Stderr, "This run uses paid services (%d rows):\n", plan.ISBNRows+plan.SearchRows+plan.ItemRows)
+	est.Print(os.Stderr)
+	if f.yes {
+		return nil
+	}
+	if !isTerminal(os.Stdin) {
+		return i18n.Errorf("estimated cost $%.2f exceeds the $%.2f threshold; rerun with -yes to accept", est.Total, f.costThreshold)
+	}
+	fmt.Fprint(os.Stderr, i18n.T("Continue? [y/N] "))
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	if i18n.IsYes(answer) {
+		return nil
+	}
+	return errors.New(i18n.T("aborted"))
+}
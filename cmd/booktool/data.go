@@ -0,0 +1,59 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/datatable"
+	"github.com/SouadAli10/book_scrapping_tool/state"
+)
+
+// dataDir returns the directory holding downloaded data tables.
+func dataDir() string {
+	return stateDir.Path(state.Data)
+}
+
+// loadDataTables switches to the downloaded data tables. Problems are
+// reported but not fatal: the built-in tables still work.
+func loadDataTables() {
+	if err := datatable.LoadDir(dataDir()); err != nil {
+		fmt.Fprintf(os.Stderr, "booktool: using built-in data tables: %v\n", err)
+	}
+}
+
+func cmdUpdateData(args []string) error {
+	fs := flag.NewFlagSet("update-data", flag.ContinueOnError)
+	registerLang(fs)
+	index := fs.String("index", datatable.DefaultIndexURL, "URL of the published data table index")
+	dir := fs.String("dir", dataDir(), "directory to download the tables into")
+	registerStateDir(fs)
+	export := fs.String("export", "", "write the tables in use and their index to this directory instead, for publishing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *export != "" {
+		if err := datatable.Export(*export); err != nil {
+			return err
+		}
+		fmt.Printf("exported %d tables to %s\n", len(datatable.All()), *export)
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	updated, err := datatable.Update(ctx, http.DefaultClient, *index, *dir)
+	if len(updated) > 0 {
+		fmt.Printf("updated %s in %s\n", strings.Join(updated, ", "), *dir)
+	}
+	if err != nil {
+		return err
+	}
+	if len(updated) == 0 {
+		fmt.Println("data tables are up to date")
+	}
+	return nil
+}
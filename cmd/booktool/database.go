@@ -0,0 +1,64 @@
+package main
+
+import (
+	"database/sql"
+	"flag"
+	"fmt"
+	"slices"
+
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+)
+
+// databaseFlags configures the optional SQL export target.
+type databaseFlags struct {
+	driver, dsn, table string
+}
+
+func (f *databaseFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.driver, "db-driver", "", "also upsert rows into a database: pgx (PostgreSQL) or mysql")
+	fs.StringVar(&f.dsn, "db-dsn", "", "database connection string")
+	fs.StringVar(&f.table, "db-table", "books", "database table to upsert into")
+}
+
+func (f *databaseFlags) apply(c config.Database) {
+	if c.Driver != "" {
+		f.driver = c.Driver
+	}
+	if dsn := c.Source(); dsn != "" {
+		f.dsn = dsn
+	}
+	if c.Table != "" {
+		f.table = c.Table
+	}
+}
+
+// open connects to the database and returns its writer, or nil when no
+// driver is configured. Drivers are linked in with the postgres and
+// mysql build tags.
+func (f *databaseFlags) open() (*sql.DB, *output.SQLWriter, error) {
+	if f.driver == "" {
+		return nil, nil, nil
+	}
+	d, err := output.DialectFor(f.driver)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !slices.Contains(sql.Drivers(), f.driver) {
+		return nil, nil, fmt.Errorf("database driver %q is not included; build booktool with -tags %s", f.driver, d)
+	}
+	db, err := sql.Open(f.driver, f.dsn)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("connect to database: %w", err)
+	}
+	w, err := output.NewSQL(db, d, f.table)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	return db, w, nil
+}
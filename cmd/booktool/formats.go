@@ -0,0 +1,17 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+	"github.com/SouadAli10/book_scrapping_tool/profile"
+)
+
+func cmdFormats(args []string) error {
+	fmt.Println("input: ", strings.Join(input.Names(), ", "))
+	fmt.Println("output:", strings.Join(output.Names(), ", "))
+	fmt.Println("profiles:", strings.Join(profile.Names(), ", "))
+	return nil
+}
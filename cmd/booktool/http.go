@@ -0,0 +1,295 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/authority"
+	"github.com/SouadAli10/book_scrapping_tool/cover"
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/provider/openlibrary"
+	"github.com/SouadAli10/book_scrapping_tool/seal"
+	"github.com/SouadAli10/book_scrapping_tool/series"
+	"github.com/SouadAli10/book_scrapping_tool/state"
+)
+
+// httpFlags configures the middleware stack around provider requests.
+type httpFlags struct {
+	timeout   time.Duration
+	interval  time.Duration
+	schedule  scheduleFlag
+	retries   int
+	cacheDir  string
+	noCache   bool
+	offline   bool
+	recordDir string
+	verbose   bool
+	// allowHosts is the comma-separated network allowlist; see
+	// restrictHosts.
+	allowHosts string
+	// proxy, caCerts, userAgent and headers set up the network; see
+	// network.
+	proxy     string
+	caCerts   string
+	userAgent string
+	headers   headerFlag
+	// keyFile is read from the arguments before parsing, since the
+	// configuration may itself be encrypted; see sealerFor.
+	keyFile string
+	// cipher encrypts cached and recorded responses when set.
+	cipher *seal.Sealer
+}
+
+func (f *httpFlags) register(fs *flag.FlagSet) {
+	fs.DurationVar(&f.timeout, "timeout", 30*time.Second, "HTTP timeout per provider request")
+	fs.DurationVar(&f.interval, "rate", 250*time.Millisecond, "minimum interval between requests to the same host")
+	fs.Var(&f.schedule, "rate-schedule", "intervals for times of day overriding -rate, as [days ]HH:MM-HH:MM=interval, e.g. \"mon-fri 09:00-18:00=2s,22:00-06:00=100ms\" (repeatable; the first matching window wins)")
+	fs.IntVar(&f.retries, "retries", 3, "attempts per request on network errors, 429 and 5xx")
+	fs.StringVar(&f.cacheDir, "cache-dir", stateDir.Path(state.Cache), "directory for cached provider responses")
+	fs.BoolVar(&f.noCache, "no-cache", false, "do not read or write the response cache")
+	fs.BoolVar(&f.offline, "offline", false, "answer lookups from the response cache only; rows needing the network are marked pending")
+	fs.StringVar(&f.recordDir, "record-dir", "", "also save every raw provider response, including those answered from the cache, to this directory")
+	fs.BoolVar(&f.verbose, "v", false, "log every provider request")
+	fs.StringVar(&f.proxy, "proxy", "", "proxy URL for outbound requests, e.g. http://proxy.example.org:3128 (default: from $HTTPS_PROXY, $HTTP_PROXY and $NO_PROXY)")
+	fs.StringVar(&f.caCerts, "ca-certs", "", "comma-separated PEM files of certificate authorities to trust besides the system's, e.g. that of a TLS-inspecting proxy")
+	fs.StringVar(&f.userAgent, "user-agent", "", "User-Agent of outbound requests (default: "+provider.UserAgent+")")
+	f.headers = make(headerFlag)
+	fs.Var(f.headers, "header", "\"Name: value\" header added to outbound requests (repeatable)")
+	fs.StringVar(&f.allowHosts, "allow-hosts", "", "only connect to these comma-separated hosts (\"*.example.org\" for subdomains, \""+allowProviders+"\" for the configured providers); default: no restriction")
+	fs.StringVar(&f.keyFile, "key-file", "", keyFileUsage)
+}
+
+// headerFlag is a repeatable -header flag of "Name: value" lines.
+// Headers given on the command line replace those of the configuration
+// with the same name.
+type headerFlag http.Header
+
+func (h headerFlag) String() string {
+	var lines []string
+	for k, vs := range h {
+		for _, v := range vs {
+			lines = append(lines, k+": "+v)
+		}
+	}
+	sort.Strings(lines)
+	return strings.Join(lines, ", ")
+}
+
+func (h headerFlag) Set(s string) error {
+	name, value, err := httpx.ParseHeader(s)
+	if err != nil {
+		return err
+	}
+	http.Header(h).Set(name, value)
+	return nil
+}
+
+// scheduleFlag is a repeatable -rate-schedule flag of comma-separated
+// windows. Windows given on the command line replace those of the
+// configuration.
+type scheduleFlag struct {
+	specs   []string
+	windows []httpx.Window
+	set     bool
+}
+
+func (s *scheduleFlag) String() string {
+	return strings.Join(s.specs, ",")
+}
+
+func (s *scheduleFlag) Set(v string) error {
+	if !s.set {
+		s.specs, s.windows, s.set = nil, nil, true
+	}
+	return s.add(v)
+}
+
+func (s *scheduleFlag) add(v string) error {
+	sched, err := httpx.ParseSchedule(0, v)
+	if err != nil {
+		return err
+	}
+	s.specs = append(s.specs, v)
+	s.windows = append(s.windows, sched.Windows...)
+	return nil
+}
+
+// client builds the provider HTTP client. Middleware order matters:
+// metrics, logging and the recorder see cache hits, so -record-dir
+// archives every response a run used, the cache answers before the
+// rate limiter delays anything, identical requests in flight share one
+// trip past it, and retries sit closest to the network. In offline mode
+// nothing gets past the cache.
+func (f *httpFlags) client(m *httpx.Metrics) *http.Client {
+	var mws []httpx.Middleware
+	mws = append(mws, m.Middleware())
+	if f.verbose {
+		mws = append(mws, httpx.Logging(log.New(os.Stderr, "http: ", log.Ltime)))
+	}
+	if f.recordDir != "" {
+		mws = append(mws, httpx.Record(f.store(f.recordDir)))
+	}
+	if !f.noCache && f.cacheDir != "" {
+		mws = append(mws, httpx.Caching(f.store(f.cacheDir)))
+	}
+	if f.offline {
+		mws = append(mws, httpx.Offline())
+	} else {
+		mws = append(mws, httpx.Coalescing(), httpx.Counting(), httpx.ScheduledRateLimit(httpx.Schedule{Default: f.interval, Windows: f.schedule.windows}), httpx.Retry(f.retries, time.Second))
+	}
+	return &http.Client{Timeout: f.timeout, Transport: httpx.Chain(nil, mws...)}
+}
+
+// systemTransport is http.DefaultTransport as the program started, which
+// network builds on.
+var systemTransport = http.DefaultTransport.(*http.Transport)
+
+// network installs the proxy, the extra certificate authorities and the
+// headers of outbound requests in http.DefaultTransport, so that every
+// request of the command uses them, the providers' and the others'. It
+// starts from systemTransport each time, so a command running another
+// one, as -resume does, installs them once.
+func (f *httpFlags) network() error {
+	t := systemTransport.Clone()
+	if f.proxy != "" {
+		u, err := httpx.ParseProxy(f.proxy)
+		if err != nil {
+			return fmt.Errorf("-proxy: %w", err)
+		}
+		t.Proxy = http.ProxyURL(u)
+		if f.verbose {
+			log.Printf("proxy: %s", u.Redacted())
+		}
+	}
+	if f.caCerts != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		for _, path := range strings.Split(f.caCerts, ",") {
+			if path = strings.TrimSpace(path); path == "" {
+				continue
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("-ca-certs: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(data) {
+				return fmt.Errorf("-ca-certs: no PEM certificate in %s", path)
+			}
+		}
+		t.TLSClientConfig = &tls.Config{RootCAs: pool}
+	}
+	var rt http.RoundTripper = t
+	h := http.Header(f.headers).Clone()
+	if h == nil {
+		h = make(http.Header)
+	}
+	if f.userAgent != "" {
+		h.Set("User-Agent", f.userAgent)
+	}
+	if len(h) > 0 {
+		rt = httpx.Headers(h)(rt)
+	}
+	http.DefaultTransport = rt
+	return nil
+}
+
+// allowProviders in -allow-hosts stands for the hosts of the configured
+// providers and price sources, and of Wikidata, the author authority
+// files and the cover images when they are enabled.
+const allowProviders = "providers"
+
+// restrictHosts installs the network settings (see httpFlags.network)
+// and the -allow-hosts allowlist in http.DefaultTransport, beneath every
+// client the command builds: the provider client, the readiness probes
+// and Google Sheets. It must run before those clients are created. extra
+// lists hosts needed by the inputs and outputs of the run, which are
+// allowed along with the providers.
+func (f *enrichFlags) restrictHosts(extra ...string) error {
+	if err := f.http.network(); err != nil {
+		return err
+	}
+	if f.http.allowHosts == "" {
+		return nil
+	}
+	var hosts []string
+	for _, h := range strings.Split(f.http.allowHosts, ",") {
+		switch h = strings.TrimSpace(h); h {
+		case "":
+		case allowProviders:
+			ps, err := providerHosts(f.providers + "," + f.itemProviders)
+			if err != nil {
+				return err
+			}
+			hosts = append(hosts, ps...)
+			hosts = append(hosts, priceHosts(f.prices)...)
+			if f.covers != "" {
+				hosts = append(hosts, coverHosts(f.providers+","+f.itemProviders)...)
+			}
+			if f.wikidata {
+				u, _ := url.Parse(series.DefaultSPARQLEndpoint)
+				hosts = append(hosts, u.Hostname())
+			}
+			if f.authorDetails {
+				for _, s := range []string{authority.DefaultOpenLibraryURL, authority.DefaultVIAFURL} {
+					u, _ := url.Parse(s)
+					hosts = append(hosts, u.Hostname())
+				}
+			}
+			if f.isbnFamily {
+				u, _ := url.Parse(openlibrary.DefaultBaseURL)
+				hosts = append(hosts, u.Hostname())
+			}
+			if h := f.translateHost(); h != "" {
+				hosts = append(hosts, h)
+			}
+			if f.coverAlt == cover.AltVision {
+				if u, err := url.Parse(f.altURL); err == nil {
+					hosts = append(hosts, u.Hostname())
+				}
+			}
+			hosts = append(hosts, extra...)
+		default:
+			hosts = append(hosts, h)
+		}
+	}
+	http.DefaultTransport = httpx.AllowHosts(hosts)(http.DefaultTransport)
+	if f.http.verbose {
+		log.Printf("network allowlist: %s", strings.Join(hosts, ", "))
+	}
+	return nil
+}
+
+// store returns the disk cache in dir, encrypted when a key is set.
+func (f *httpFlags) store(dir string) httpx.Cache {
+	var c httpx.Cache = &httpx.DiskCache{Dir: dir}
+	if f.cipher != nil {
+		c = httpx.Encrypted(c, f.cipher)
+	}
+	return c
+}
+
+// printMetrics writes a per-host request summary.
+func printMetrics(w io.Writer, m *httpx.Metrics) {
+	for _, s := range m.Snapshot() {
+		avg := time.Duration(0)
+		if n := s.Requests - s.CacheHits; n > 0 {
+			avg = (s.Latency / time.Duration(n)).Round(time.Millisecond)
+		}
+		i18n.Fprintf(w, "  %-28s %5d requests, %5d cached, %3d errors, avg %s\n",
+			s.Host, s.Requests, s.CacheHits, s.Errors, avg)
+	}
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+)
+
+// wizard asks questions on a terminal.
+type wizard struct {
+	in  *bufio.Scanner
+	out io.Writer
+}
+
+func (w *wizard) ask(question, def string) string {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+	if !w.in.Scan() {
+		return def
+	}
+	if v := strings.TrimSpace(w.in.Text()); v != "" {
+		return v
+	}
+	return def
+}
+
+func (w *wizard) yes(question string, def bool) bool {
+	d := i18n.T("y/N")
+	if def {
+		d = i18n.T("Y/n")
+	}
+	for {
+		switch a := w.ask(question+" ("+d+")", ""); {
+		case a == "":
+			return def
+		case i18n.IsYes(a):
+			return true
+		case i18n.IsNo(a):
+			return false
+		}
+	}
+}
+
+// choose asks for one of options by number or name.
+func (w *wizard) choose(question string, options []string, def string) string {
+	for i, o := range options {
+		fmt.Fprintf(w.out, "  %d) %s\n", i+1, o)
+	}
+	for {
+		v := w.ask(question, def)
+		if n, err := strconv.Atoi(v); err == nil && n >= 1 && n <= len(options) {
+			return options[n-1]
+		}
+		for _, o := range options {
+			if strings.EqualFold(o, v) {
+				return o
+			}
+		}
+		fmt.Fprintln(w.out, i18n.T("Please answer with a number or one of the names above."))
+	}
+}
+
+func cmdInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ContinueOnError)
+	registerLang(fs)
+	path := fs.String("config", config.DefaultPath, "configuration file to create")
+	sample := fs.String("input", "", "example spreadsheet to map columns from")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	w := &wizard{in: bufio.NewScanner(os.Stdin), out: os.Stdout}
+	fmt.Fprintln(w.out, i18n.T("This will create a booktool configuration. Press Enter to accept the [default]."))
+	fmt.Fprintln(w.out)
+
+	if _, err := os.Stat(*path); err == nil && !w.yes(i18n.Sprintf("%s exists. Overwrite it?", *path), false) {
+		return errors.New(i18n.T("aborted"))
+	}
+
+	var c config.Config
+	fmt.Fprintln(w.out, i18n.T("Providers are queried in the order you enable them."))
+	for _, name := range []string{"openlibrary", "googlebooks", "loc", "isbndb"} {
+		e := providerTable[name]
+		if !w.yes(i18n.Sprintf("Use %s?", e.summary), name == "openlibrary" || name == "googlebooks") {
+			continue
+		}
+		c.Providers = append(c.Providers, name)
+		if e.keyEnv == "" {
+			continue
+		}
+		env := e.keyEnv
+		fmt.Fprintln(w.out, i18n.T("  Keys can be stored in the file or read from an environment variable."))
+		key := w.ask(i18n.Sprintf("  Paste the %s API key (leave empty to use $%s)", name, env), "")
+		if c.Credentials == nil {
+			c.Credentials = make(map[string]config.Credentials)
+		}
+		if key != "" {
+			c.Credentials[name] = config.Credentials{APIKey: key}
+		} else {
+			c.Credentials[name] = config.Credentials{APIKeyEnv: env}
+		}
+	}
+	if len(c.Providers) == 0 {
+		return errors.New(i18n.T("at least one provider is needed"))
+	}
+	fmt.Fprintln(w.out)
+
+	if *sample == "" {
+		*sample = w.ask(i18n.T("Path to one of your spreadsheets, to map its columns (optional)"), "")
+	}
+	if *sample != "" {
+		if err := w.mapColumns(&c, *sample); err != nil {
+			i18n.Fprintf(w.out, "Could not read %s: %v\nColumns will be detected automatically.\n", *sample, err)
+		}
+		fmt.Fprintln(w.out)
+	}
+
+	fmt.Fprintln(w.out, i18n.T("Output format for enriched files:"))
+	c.Output.Format = w.choose(i18n.T("Format"), output.Names(), "xlsx")
+	c.Output.LanguageFormat = w.choose(i18n.T("Languages as"), []string{"name", "code", "code2"}, "name")
+	c.Output.Units = w.choose(i18n.T("Units"), []string{"metric", "imperial"}, "metric")
+
+	if probs := c.Validate(schema()); len(probs) > 0 {
+		for _, p := range probs {
+			fmt.Fprintln(w.out, i18n.T("warning:"), p)
+		}
+	}
+	data, err := json.MarshalIndent(&c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(*path, append(data, '\n'), 0o600); err != nil {
+		return err
+	}
+	i18n.Fprintf(w.out, "\nWrote %s. Run \"booktool -input yourfile.xlsx\" to enrich a file.\n", *path)
+	return nil
+}
+
+// mapColumns shows the headers of the file at path and asks which column
+// holds each input field.
+func (w *wizard) mapColumns(c *config.Config, path string) error {
+	r, err := input.Open(path, "", input.Options{})
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	t, ok := input.AsTabular(r)
+	if !ok {
+		return errors.New(i18n.T("this format has no header row to map"))
+	}
+	header := t.Header()
+	detected := t.Mapping()
+	fmt.Fprintln(w.out, i18n.T("Columns found:"))
+	none := i18n.T("(none)")
+	options := append([]string{none}, header...)
+	for _, field := range input.Fields {
+		def := detected[field]
+		if def == "" {
+			def = none
+		}
+		i18n.Fprintf(w.out, "Which column holds the %s?\n", field)
+		col := w.choose(strings.ToUpper(field[:1])+field[1:], options, def)
+		if col == none || col == detected[field] {
+			continue
+		}
+		if c.Input.Columns == nil {
+			c.Input.Columns = make(map[string]string)
+		}
+		c.Input.Columns[field] = col
+	}
+	return nil
+}
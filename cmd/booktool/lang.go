@@ -0,0 +1,23 @@
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+)
+
+// registerLang defines -lang. Like -state-dir, it is read before the
+// command runs, by setLanguage.
+func registerLang(fs *flag.FlagSet) {
+	fs.String("lang", "", "language of messages: "+strings.Join(i18n.Languages(), ", ")+" (default: from $"+i18n.EnvLang+" or the locale)")
+}
+
+// setLanguage selects the language of messages from -lang, falling back
+// to the environment.
+func setLanguage(code string) error {
+	if code == "" {
+		code = i18n.FromEnv()
+	}
+	return i18n.Set(code)
+}
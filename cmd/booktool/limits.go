@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/gsheets"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+)
+
+// boundedReader stops reading after a number of rows, past a deadline
+// or once interrupted, and remembers where it stopped.
+type boundedReader struct {
+	input.Reader
+	maxRows  int
+	deadline time.Time
+	n        int
+	// interrupted is set from the signal handler; see stopOnSignal.
+	interrupted atomic.Bool
+	// stopped is set when rows were left unread; next is the index of
+	// the first of them.
+	stopped bool
+	next    int
+}
+
+func (b *boundedReader) Next() (*input.Row, error) {
+	if b.rowsDone() || b.interrupted.Load() || (!b.deadline.IsZero() && !time.Now().Before(b.deadline)) {
+		// Peek at the next row to tell a boundary from the end of the
+		// input.
+		if row, err := b.Reader.Next(); err == nil {
+			b.stopped, b.next = true, row.Index
+		}
+		return nil, io.EOF
+	}
+	row, err := b.Reader.Next()
+	if err == nil {
+		b.n++
+	}
+	return row, err
+}
+
+// rowsDone reports whether the row limit was reached.
+func (b *boundedReader) rowsDone() bool {
+	return b.maxRows > 0 && b.n >= b.maxRows
+}
+
+// stopOnSignal makes b stop like at a limit on the first SIGINT or
+// SIGTERM: no new rows are dispatched, and the rows in progress are
+// finished and written before the run checkpoints. A second signal gets
+// the default action and ends the process at once. The returned function
+// stops listening.
+func (b *boundedReader) stopOnSignal(plain bool) (stop func()) {
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-sig:
+			signal.Stop(sig)
+			b.interrupted.Store(true)
+			if !plain {
+				// End the status line.
+				fmt.Fprintln(os.Stderr)
+			}
+			i18n.Fprintf(os.Stderr, "interrupted; finishing the rows in progress (interrupt again to quit)\n")
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(sig)
+		close(done)
+	}
+}
+
+// partSuffix matches the part number that continuation outputs end
+// with.
+var partSuffix = regexp.MustCompile(`[. ]part(\d+)$`)
+
+// nextPart names the output of the run continuing one that wrote to
+// out: books_enriched.xlsx becomes books_enriched.part2.xlsx, then
+// books_enriched.part3.xlsx. Google Sheets tabs get " part2" and so on.
+func nextPart(out string) string {
+	if out == stdout {
+		return out
+	}
+	bump := func(name, sep string) string {
+		part := 2
+		if m := partSuffix.FindStringSubmatch(name); m != nil {
+			n, _ := strconv.Atoi(m[1])
+			part = n + 1
+			name = name[:len(name)-len(m[0])]
+		}
+		return name + sep + "part" + strconv.Itoa(part)
+	}
+	if ref, ok := gsheets.ParseRef(out); ok {
+		ref.Sheet = bump(ref.Sheet, " ")
+		return ref.String()
+	}
+	ext := filepath.Ext(out)
+	return bump(strings.TrimSuffix(out, ext), ".") + ext
+}
+
+// continueArgs returns args with the given flags set, replacing any
+// occurrence of them. The new flags come first, since flag parsing
+// stops at the first positional argument.
+func continueArgs(args []string, set map[string]string, order ...string) []string {
+	var out []string
+	for _, name := range order {
+		out = append(out, "-"+name, set[name])
+	}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		name, _, hasValue := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		if _, ok := set[name]; ok && strings.HasPrefix(a, "-") {
+			if !hasValue {
+				i++
+			}
+			continue
+		}
+		if a == "--" {
+			out = append(out, args[i:]...)
+			break
+		}
+		out = append(out, a)
+	}
+	return out
+}
+
+// shellCommand formats a command line that can be pasted into a POSIX
+// shell.
+func shellCommand(args ...string) string {
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shellQuote(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+var shellSafe = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+
+func shellQuote(s string) string {
+	if shellSafe.MatchString(s) {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
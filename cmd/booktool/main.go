@@ -0,0 +1,92 @@
+// Command booktool enriches spreadsheets of books with metadata from
+// OpenLibrary, Google Books and other providers.
+//
+// Usage:
+//
+//	booktool [run] -input books.xlsx [-output enriched.xlsx] [flags]
+//	booktool <command> [flags]
+//
+// Run "booktool help" for the list of commands.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/state"
+)
+
+// command is a booktool subcommand.
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]*command{
+	"run":         {"enrich an input file (default command)", cmdRun},
+	"alerts":      {"report low-stock fast sellers and stale stock", cmdAlerts},
+	"acquire":     {"suggest editions to buy for wishlist items not in stock", cmdAcquire},
+	"calibre":     {"enrich a Calibre library and fill in missing fields", cmdCalibre},
+	"config":      {"validate the configuration file (config check)", cmdConfig},
+	"init":        {"create a configuration interactively", cmdInit},
+	"formats":     {"list the supported input and output formats", cmdFormats},
+	"serve":       {"run the HTTP service", cmdServe},
+	"state":       {"show or clean the state directory", cmdState},
+	"seal":        {"encrypt or decrypt configuration and credential files", cmdSeal},
+	"scan":        {"enrich ISBNs read from a barcode scanner or standard input", cmdScan},
+	"reprice":     {"reprice a catalog from market prices", cmdReprice},
+	"replay":      {"reproduce a recorded run and compare", cmdReplay},
+	"oldump":      {"index OpenLibrary data dumps for offline lookups", cmdOLDump},
+	"selftest":    {"check the provider mappings against recorded responses", cmdSelftest},
+	"update-data": {"download newer data tables", cmdUpdateData},
+	"version":     {"print the version and data table versions", cmdVersion},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, i18n.T("usage: booktool [command] [flags]\n\ncommands:"))
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Fprintf(os.Stderr, "  %-12s %s\n", n, i18n.T(commands[n].summary))
+	}
+	fmt.Fprintln(os.Stderr, i18n.T("\nRun \"booktool <command> -h\" for the flags of a command."))
+}
+
+func main() {
+	args := os.Args[1:]
+	if err := setLanguage(flagArg(args, "lang")); err != nil {
+		fmt.Fprintln(os.Stderr, "booktool:", err)
+		os.Exit(2)
+	}
+	name := "run"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		usage()
+		return
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		i18n.Fprintf(os.Stderr, "booktool: unknown command %q\n\n", name)
+		usage()
+		os.Exit(2)
+	}
+	stateDir = state.Resolve(flagArg(args, "state-dir"))
+	loadDataTables()
+	if err := cmd.run(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "booktool:", err)
+		os.Exit(1)
+	}
+}
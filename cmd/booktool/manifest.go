@@ -0,0 +1,183 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/gsheets"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+)
+
+// responsesSuffix names the default response archive of a manifest.
+const responsesSuffix = ".responses"
+
+// runManifest records what a replay needs to reproduce a run: its
+// arguments, the files it read and wrote, and the archive of the raw
+// provider responses it used.
+type runManifest struct {
+	Version string    `json:"version"`
+	Time    time.Time `json:"time"`
+	// Dir is the working directory of the run, which the paths of Args
+	// and of the manifest are relative to.
+	Dir  string   `json:"dir"`
+	Args []string `json:"args"`
+	// Config is the configuration file, if the run had one.
+	Config *manifestFile `json:"config,omitempty"`
+	Input  manifestFile  `json:"input"`
+	Output manifestFile  `json:"output"`
+	// Responses is the -record-dir of the run.
+	Responses string `json:"responses"`
+	Rows      int    `json:"rows"`
+	Failed    int    `json:"failed"`
+}
+
+// manifestFile identifies the contents of a file read or written by a
+// run.
+type manifestFile struct {
+	Path   string `json:"path"`
+	SHA256 string `json:"sha256"`
+}
+
+// secretFlags are left out of manifests: proxy URLs and headers may
+// hold credentials too. A replay answers from the response archive, so
+// it needs none of them.
+var secretFlags = map[string]bool{"google-api-key": true, "db-dsn": true, "proxy": true, "header": true}
+
+// checkManifest checks that the run can be replayed and archives its
+// responses next to the manifest unless -record-dir says otherwise.
+func (f *runFlags) checkManifest() error {
+	_, in := gsheets.ParseRef(f.input)
+	_, out := gsheets.ParseRef(f.output)
+	switch {
+	case f.update != "":
+		return errors.New("-manifest cannot record -update runs, which rewrite their input")
+	case in || out || f.input == input.Stdin || f.output == stdout:
+		return errors.New("-manifest needs an input file and an output file")
+	}
+	if f.http.recordDir == "" {
+		f.http.recordDir = strings.TrimSuffix(f.manifest, filepath.Ext(f.manifest)) + responsesSuffix
+	}
+	return nil
+}
+
+// writeManifest writes the manifest of a finished run.
+func writeManifest(f *runFlags, args []string, start time.Time, rows, failed int) error {
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	m := &runManifest{
+		Version:   buildVersion(),
+		Time:      start.UTC().Truncate(time.Second),
+		Dir:       dir,
+		Args:      withoutFlags(args, secretFlags),
+		Responses: f.http.recordDir,
+		Rows:      rows,
+		Failed:    failed,
+	}
+	cfg := f.config
+	if cfg == "" {
+		if _, err := os.Stat(config.DefaultPath); err == nil {
+			cfg = config.DefaultPath
+		}
+	}
+	if cfg != "" {
+		if m.Config, err = digestFile(cfg); err != nil {
+			return err
+		}
+	}
+	in, err := digestFile(f.input)
+	if err != nil {
+		return err
+	}
+	out, err := digestFile(f.output)
+	if err != nil {
+		return err
+	}
+	m.Input, m.Output = *in, *out
+	// Runs answered without the network still leave an archive.
+	if err := os.MkdirAll(f.http.recordDir, 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(f.manifest, append(data, '\n'), 0o644)
+}
+
+// readManifest reads the manifest at path.
+func readManifest(path string) (*runManifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	m := new(runManifest)
+	if err := json.Unmarshal(data, m); err != nil {
+		return nil, fmt.Errorf("manifest %s: %w", path, err)
+	}
+	if len(m.Args) == 0 || m.Input.Path == "" || m.Output.Path == "" || m.Responses == "" {
+		return nil, fmt.Errorf("manifest %s: not a run manifest", path)
+	}
+	return m, nil
+}
+
+// digestFile returns the SHA-256 digest of the file at path.
+func digestFile(path string) (*manifestFile, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, file); err != nil {
+		return nil, err
+	}
+	return &manifestFile{Path: path, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// changed reports whether the file no longer has the recorded contents.
+func (mf *manifestFile) changed() (bool, error) {
+	now, err := digestFile(mf.Path)
+	if err != nil {
+		return false, err
+	}
+	return now.SHA256 != mf.SHA256, nil
+}
+
+// withoutFlags returns args without the named flags. The value of a
+// name is whether the flag takes a value, which follows it unless it
+// is given as -name=value.
+func withoutFlags(args []string, flags map[string]bool) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if a == "--" {
+			out = append(out, args[i:]...)
+			break
+		}
+		if !strings.HasPrefix(a, "-") {
+			out = append(out, a)
+			continue
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(a, "-"), "=")
+		valued, ok := flags[name]
+		if !ok {
+			out = append(out, a)
+			continue
+		}
+		if valued && !hasValue {
+			i++
+		}
+	}
+	return out
+}
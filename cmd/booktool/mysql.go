@@ -0,0 +1,7 @@
+//go:build mysql
+
+package main
+
+// Registers the "mysql" driver for the database export. Build with
+// -tags mysql to include it.
+import _ "github.com/go-sql-driver/mysql"
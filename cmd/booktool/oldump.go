@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/provider/oldump"
+	"github.com/SouadAli10/book_scrapping_tool/state"
+)
+
+// oldumpIndex is where "booktool oldump" writes the index the oldump
+// provider reads.
+func oldumpIndex() string {
+	return filepath.Join(stateDir.Path(state.Dumps), "openlibrary.db")
+}
+
+// checkOLDumpIndex reports why the oldump provider cannot read its
+// index.
+func checkOLDumpIndex() error {
+	if !slices.Contains(sql.Drivers(), sqliteDriver) {
+		return errors.New(i18n.T("the OpenLibrary dump index needs a booktool built with -tags sqlite"))
+	}
+	if _, err := os.Stat(oldumpIndex()); err != nil {
+		return i18n.Errorf("no index at %s; build it from the OpenLibrary dumps with \"booktool oldump\"", oldumpIndex())
+	}
+	return nil
+}
+
+// cmdOLDump indexes OpenLibrary data dumps for the oldump provider.
+func cmdOLDump(args []string) error {
+	fs := flag.NewFlagSet("oldump", flag.ContinueOnError)
+	registerLang(fs)
+	registerStateDir(fs)
+	plain := fs.Bool("plain", false, "plain status output for screen readers and dumb terminals: no progress line, one message per line")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: booktool oldump [flags] <dump file>...")
+		fmt.Fprintln(fs.Output(), "\nIndexes the OpenLibrary editions, works and authors dumps (https://openlibrary.org/developers/dumps),\ngzip-compressed or not, for the oldump provider. Files ingested again replace their records.")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New(i18n.T("no dump file given"))
+	}
+	if !slices.Contains(sql.Drivers(), sqliteDriver) {
+		return errors.New(i18n.T("the OpenLibrary dump index needs a booktool built with -tags sqlite"))
+	}
+	if _, err := stateDir.Ensure(state.Dumps); err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	for _, path := range fs.Args() {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		prog := newProgress(*plain)
+		n, err := oldump.Ingest(ctx, sqliteDriver, oldumpIndex(), file, func(n oldump.Counts) {
+			prog.update(i18n.Sprintf("%s: %d editions, %d works, %d authors", path, n.Editions, n.Works, n.Authors))
+		})
+		prog.finish()
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		i18n.Fprintf(os.Stderr, "%s: %d editions (%d ISBNs), %d works, %d authors, %d skipped\n", path, n.Editions, n.ISBNs, n.Works, n.Authors, n.Skipped)
+	}
+	i18n.Fprintf(os.Stderr, "indexed in %s; look books up in it with -providers %s\n", oldumpIndex(), oldump.Name)
+	return nil
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/gsheets"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+)
+
+// orderFlag is a repeatable -order flag: an order for every output
+// format, or "format=order" for one, also as a comma-separated list.
+// Orders for all formats are stored under config.DefaultOrderKey.
+type orderFlag map[string]enrich.Order
+
+func (o orderFlag) String() string {
+	var parts []string
+	for k, v := range o {
+		parts = append(parts, k+"="+v.String())
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, ",")
+}
+
+func (o orderFlag) Set(s string) error {
+	for _, part := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			name, value = config.DefaultOrderKey, part
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != config.DefaultOrderKey && name != "gsheet" && !slices.Contains(output.Names(), name) {
+			return fmt.Errorf("unknown output format %q", name)
+		}
+		v, err := enrich.ParseOrder(value)
+		if err != nil {
+			return err
+		}
+		o[name] = v
+	}
+	return nil
+}
+
+// get returns the order for an output format.
+func (o orderFlag) get(format string) enrich.Order {
+	v, _ := o.lookup(format)
+	return v
+}
+
+// lookup returns the order for an output format and whether one was
+// set.
+func (o orderFlag) lookup(format string) (enrich.Order, bool) {
+	if v, ok := o[format]; ok {
+		return v, true
+	}
+	v, ok := o[config.DefaultOrderKey]
+	return v, ok
+}
+
+// resolveOutputFormat returns the format of the output file. Standard
+// output defaults to JSONL, for pipelines.
+func (f *runFlags) resolveOutputFormat() (*output.Format, error) {
+	if f.output == stdout && f.outputFormat == "" {
+		return output.Resolve("jsonl", "")
+	}
+	return output.Resolve(f.outputFormat, f.output)
+}
+
+// outputFormatName names the format of the run's output: "gsheet" for
+// Google Sheets, or "" when it cannot be resolved yet.
+func (f *runFlags) outputFormatName() string {
+	if _, ok := gsheets.ParseRef(f.output); ok {
+		return "gsheet"
+	}
+	if format, err := f.resolveOutputFormat(); err == nil {
+		return format.Name
+	}
+	return ""
+}
@@ -0,0 +1,568 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/author"
+	"github.com/SouadAli10/book_scrapping_tool/authority"
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/country"
+	"github.com/SouadAli10/book_scrapping_tool/cover"
+	"github.com/SouadAli10/book_scrapping_tool/customs"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/family"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/lang"
+	"github.com/SouadAli10/book_scrapping_tool/price"
+	"github.com/SouadAli10/book_scrapping_tool/profile"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/provider/googlebooks"
+	"github.com/SouadAli10/book_scrapping_tool/provider/openlibrary"
+	"github.com/SouadAli10/book_scrapping_tool/series"
+	"github.com/SouadAli10/book_scrapping_tool/shipping"
+	"github.com/SouadAli10/book_scrapping_tool/subject"
+	"github.com/SouadAli10/book_scrapping_tool/tax"
+	"github.com/SouadAli10/book_scrapping_tool/units"
+	"github.com/SouadAli10/book_scrapping_tool/validate"
+)
+
+// enrichFlags configures the lookup pipeline and the output layout. The
+// run and serve commands share them.
+type enrichFlags struct {
+	providers      string
+	itemProviders  string
+	googleAPIKey   string
+	keys           map[string]string
+	workers        int
+	rowTimeout     time.Duration
+	http           httpFlags
+	wikidata       bool
+	authorDetails  bool
+	isbnFamily     bool
+	bisac          bool
+	imprints       bool
+	romanize       bool
+	validate       string
+	customs        bool
+	customsOrigin  string
+	shippingRates  string
+	taxTable       string
+	taxDestination string
+	merge          bool
+	dedupe         bool
+	minMatch       float64
+	maxCalls       budgetFlag
+	languageFormat string
+	authorFormat   string
+	rawSubjects    bool
+	maxSubjects    int
+	subjectVocab   string
+	maxDescription int
+	units          string
+	profile        string
+	columns        string
+	passThrough    bool
+	prices         string
+	priceCurrency  string
+	covers         string
+	coverColors    int
+	coverSize      string
+	coverSquare    bool
+	coverFormat    string
+	coverQuality   int
+	coverAlt       string
+	altTemplate    string
+	altURL         string
+	altModel       string
+	translate      string
+	translateTo    string
+	translateURL   string
+	plain          bool
+}
+
+func (f *enrichFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.providers, "providers", "openlibrary,googlebooks", "comma-separated providers in priority order")
+	fs.StringVar(&f.itemProviders, "item-providers", "", "comma-separated providers in priority order for rows holding the barcode (UPC, EAN) of an item other than a book, such as a DVD or a board game ("+strings.Join(itemProviderNames(), ", ")+"); default: none, such rows are invalid ISBNs")
+	fs.StringVar(&f.googleAPIKey, "google-api-key", os.Getenv(providerTable[googlebooks.Name].keyEnv), "Google Books API key")
+	fs.IntVar(&f.workers, "workers", enrich.DefaultWorkers, "rows looked up concurrently")
+	fs.DurationVar(&f.rowTimeout, "row-timeout", 0, "give up on a row whose lookup takes longer than this across all providers (e.g. 30s), reporting it as failed (0: no limit)")
+	f.http.register(fs)
+	registerStateDir(fs)
+	registerLang(fs)
+	fs.BoolVar(&f.merge, "merge", true, "query every provider for each ISBN and merge the results")
+	fs.BoolVar(&f.dedupe, "dedupe", true, "look up repeated ISBNs and title/author pairs once and flag the repeats")
+	fs.BoolVar(&f.wikidata, "wikidata", true, "query Wikidata for series information")
+	fs.BoolVar(&f.authorDetails, "author-details", false, "look up the authors' birth and death years and their OpenLibrary, VIAF, Wikidata and ISNI identifiers, in extra columns")
+	fs.BoolVar(&f.isbnFamily, "isbn-family", false, "look up the other editions of each book (international, loose-leaf, access code bundles, other edition numbers) from OpenLibrary, in extra columns")
+	fs.BoolVar(&f.bisac, "bisac", false, "map the subjects to BISAC subject codes and headings, in extra columns")
+	fs.BoolVar(&f.imprints, "imprints", false, "map the publishers to their canonical imprints and parent publishing groups, in extra columns")
+	fs.BoolVar(&f.romanize, "romanize", false, "add the titles and authors of books in Arabic script romanized, in extra columns")
+	fs.StringVar(&f.validate, "validate", "", "check each record before it is saved (ISBN check digits, numeric page counts, parsable publish dates, ISO 639 language codes): report (list the problems in a "+columns.ViolationsHeader+" column) or reject (fail the rows that have any); default: off")
+	fs.BoolVar(&f.customs, "customs", false, "add export paperwork columns: country of origin, HS tariff code and customs description")
+	fs.StringVar(&f.customsOrigin, "customs-origin", "", "ISO 3166 country declared as origin of books whose country of publication is unknown, e.g. your own (with -customs)")
+	fs.StringVar(&f.shippingRates, "shipping-rates", "", "add shipping tier and cost columns estimated from each book's weight and dimensions with this JSON rate table, or \"default\" for built-in letter and parcel rates")
+	fs.StringVar(&f.taxTable, "tax-table", "", "add tax category, code and rate columns from this JSON tax table, or \"default\" for built-in European rates")
+	fs.StringVar(&f.taxDestination, "tax-destination", "", "ISO 3166 country the books are sold to, overriding the tax table's destination (implies -tax-table default)")
+	fs.StringVar(&f.languageFormat, "language-format", "name", "language output: name, code (ISO 639-2) or code2 (ISO 639-1)")
+	fs.StringVar(&f.authorFormat, "author-format", string(author.Natural), "author name output: natural (J. R. R. Tolkien) or inverted (Tolkien, J. R. R.)")
+	fs.BoolVar(&f.rawSubjects, "raw-subjects", false, "keep the subject headings as the providers report them, without dropping junk headings, title-casing and removing duplicates")
+	fs.IntVar(&f.maxSubjects, "max-subjects", 0, "subject headings kept per book (0: all)")
+	fs.StringVar(&f.subjectVocab, "subject-vocabulary", "", "CSV file of heading,term rows mapping subject headings to preferred terms; an empty term drops the heading")
+	fs.StringVar(&f.units, "units", "metric", "units for dimensions and weight: metric (cm, g) or imperial (in, oz)")
+	f.maxCalls = make(budgetFlag)
+	fs.Var(f.maxCalls, "max-calls", "per-provider network call budget, e.g. googlebooks=900 (repeatable)")
+	fs.Float64Var(&f.minMatch, "min-match", enrich.DefaultMinMatch, "lowest title/author match confidence (0-1) accepted for rows without ISBN")
+	fs.StringVar(&f.prices, "prices", "", "comma-separated price sources for market price columns ("+strings.Join(priceSourceNames(), ", ")+"); default: none")
+	fs.StringVar(&f.priceCurrency, "price-currency", price.DefaultCurrency, "currency of the price columns; offers in other currencies are ignored")
+	fs.StringVar(&f.covers, "covers", "", "download covers to this directory, named by ISBN, with palette columns")
+	fs.IntVar(&f.coverColors, "cover-colors", cover.DefaultColors, "dominant colors extracted from each downloaded cover (0: none)")
+	fs.StringVar(&f.coverSize, "cover-size", "", "make web versions of the covers, in the "+cover.WebDir+" subdirectory, scaled down to fit WxH, e.g. 600x900 (0 for no bound)")
+	fs.BoolVar(&f.coverSquare, "cover-square", false, "crop the web versions of the covers to a centered square")
+	fs.StringVar(&f.coverFormat, "cover-format", "", "format of the web versions of the covers: "+strings.Join(cover.Formats, ", ")+" (webp and avif need cwebp and avifenc; default: jpeg)")
+	fs.IntVar(&f.coverQuality, "cover-quality", 0, "encoding quality of the web versions of the covers, 1-100 (default 80)")
+	fs.StringVar(&f.coverAlt, "cover-alt", "", "add a cover alt text column, written by "+cover.AltTemplate+" (from -cover-alt-template) or "+cover.AltVision+" (a vision model describing the downloaded covers; needs -covers and $"+visionKeyEnv+")")
+	fs.StringVar(&f.altTemplate, "cover-alt-template", cover.DefaultAltTemplate, "Go template of the alt text, executed with the book record, e.g. \"{{.Title}} ({{.Year}})\"; names joins author lists")
+	fs.StringVar(&f.altURL, "cover-alt-url", cover.DefaultVisionURL, "OpenAI-style chat completions endpoint of the vision model")
+	fs.StringVar(&f.altModel, "cover-alt-model", cover.DefaultVisionModel, "vision model writing the alt text")
+	fs.StringVar(&f.translate, "translate", "", "translate the title, subjects and description with this service ("+strings.Join(translationServiceNames(), ", ")+"), in extra columns next to the originals; needs -translate-to")
+	fs.StringVar(&f.translateTo, "translate-to", "", "language to translate into, e.g. ar or fr")
+	fs.StringVar(&f.translateURL, "translate-url", "", "endpoint of the translation service, e.g. a self-hosted LibreTranslate")
+	fs.StringVar(&f.profile, "profile", "", "export profile replacing the standard columns: "+strings.Join(profile.Names(), ", "))
+	fs.BoolVar(&f.passThrough, "pass-through", true, "keep every column of inputs with a header row, as read and in their order, before the enrichment columns (false: only "+strings.Join(input.Columns, ", ")+"); inputs read from several worksheets keep only those")
+	fs.StringVar(&f.columns, "columns", "", "comma-separated enrichment columns to write, by header and in this order, e.g. \"Full Title,Publisher,Used Price Median (USD)\" (default: all)")
+	fs.IntVar(&f.maxDescription, "max-description-length", 0, "truncate descriptions to this many characters (0: no limit)")
+	fs.BoolVar(&f.plain, "plain", false, "plain status output for screen readers and dumb terminals: no progress line, one message per line")
+}
+
+// apply copies the pipeline settings of the configuration into f.
+func (f *enrichFlags) apply(c *config.Config) {
+	if len(c.Providers) > 0 {
+		f.providers = strings.Join(c.Providers, ",")
+	}
+	if len(c.ItemProviders) > 0 {
+		f.itemProviders = strings.Join(c.ItemProviders, ",")
+	}
+	f.keys = make(map[string]string)
+	for name, cred := range c.Credentials {
+		if k := cred.Key(); k != "" {
+			f.keys[name] = k
+		}
+	}
+	if c.Workers > 0 {
+		f.workers = c.Workers
+	}
+	if c.RowTimeout > 0 {
+		f.rowTimeout = time.Duration(c.RowTimeout)
+	}
+	if c.Merge != nil {
+		f.merge = *c.Merge
+	}
+	if c.Dedupe != nil {
+		f.dedupe = *c.Dedupe
+	}
+	if c.Wikidata != nil {
+		f.wikidata = *c.Wikidata
+	}
+	f.authorDetails = f.authorDetails || c.AuthorDetails
+	f.isbnFamily = f.isbnFamily || c.ISBNFamily
+	f.bisac = f.bisac || c.BISAC
+	f.imprints = f.imprints || c.Imprints
+	f.romanize = f.romanize || c.Romanize
+	f.customs = f.customs || c.Customs.Enabled
+	if c.Customs.DefaultOrigin != "" {
+		f.customsOrigin = c.Customs.DefaultOrigin
+	}
+	if c.Shipping.Rates != "" {
+		f.shippingRates = c.Shipping.Rates
+	}
+	if c.Tax.Table != "" {
+		f.taxTable = c.Tax.Table
+	}
+	if c.Tax.Destination != "" {
+		f.taxDestination = c.Tax.Destination
+	}
+	if c.Validation != "" {
+		f.validate = c.Validation
+	}
+	for name, n := range c.MaxCalls {
+		f.maxCalls[name] = n
+	}
+	if c.Output.LanguageFormat != "" {
+		f.languageFormat = c.Output.LanguageFormat
+	}
+	if c.Output.AuthorFormat != "" {
+		f.authorFormat = c.Output.AuthorFormat
+	}
+	f.rawSubjects = f.rawSubjects || c.Subjects.Raw
+	if c.Subjects.Max > 0 {
+		f.maxSubjects = c.Subjects.Max
+	}
+	if c.Subjects.Vocabulary != "" {
+		f.subjectVocab = c.Subjects.Vocabulary
+	}
+	if c.Output.Units != "" {
+		f.units = c.Output.Units
+	}
+	if len(c.Prices.Sources) > 0 {
+		f.prices = strings.Join(c.Prices.Sources, ",")
+	}
+	if c.Prices.Currency != "" {
+		f.priceCurrency = c.Prices.Currency
+	}
+	if c.Covers.Dir != "" {
+		f.covers = c.Covers.Dir
+	}
+	if c.Covers.Colors > 0 {
+		f.coverColors = c.Covers.Colors
+	}
+	if c.Covers.MaxWidth > 0 || c.Covers.MaxHeight > 0 {
+		f.coverSize = fmt.Sprintf("%dx%d", c.Covers.MaxWidth, c.Covers.MaxHeight)
+	}
+	f.coverSquare = f.coverSquare || c.Covers.Square
+	if c.Covers.Format != "" {
+		f.coverFormat = c.Covers.Format
+	}
+	if c.Covers.Quality > 0 {
+		f.coverQuality = c.Covers.Quality
+	}
+	if c.Covers.Alt != "" {
+		f.coverAlt = c.Covers.Alt
+	}
+	if c.Covers.AltTemplate != "" {
+		f.altTemplate = c.Covers.AltTemplate
+	}
+	if c.Covers.AltURL != "" {
+		f.altURL = c.Covers.AltURL
+	}
+	if c.Covers.AltModel != "" {
+		f.altModel = c.Covers.AltModel
+	}
+	if c.Translate.Service != "" {
+		f.translate = c.Translate.Service
+	}
+	if c.Translate.Target != "" {
+		f.translateTo = c.Translate.Target
+	}
+	if c.Translate.URL != "" {
+		f.translateURL = c.Translate.URL
+	}
+	if c.Output.Profile != "" {
+		f.profile = c.Output.Profile
+	}
+	if c.Output.PassThrough != nil {
+		f.passThrough = *c.Output.PassThrough
+	}
+	if len(c.Output.Columns) > 0 {
+		f.columns = strings.Join(c.Output.Columns, ",")
+	}
+	if c.Output.MaxDescriptionLength > 0 {
+		f.maxDescription = c.Output.MaxDescriptionLength
+	}
+	f.http.apply(c.HTTP)
+}
+
+// resolveKeys fills in API keys from -google-api-key and the providers'
+// environment variables. It is called after parsing.
+func (f *enrichFlags) resolveKeys() {
+	if f.googleAPIKey != "" {
+		f.keys[googlebooks.Name] = f.googleAPIKey
+	}
+	for name, e := range providerTable {
+		if f.keys[name] == "" && e.keyEnv != "" {
+			if k := os.Getenv(e.keyEnv); k != "" {
+				f.keys[name] = k
+			}
+		}
+	}
+	if f.keys[cover.VisionService] == "" {
+		if k := os.Getenv(visionKeyEnv); k != "" {
+			f.keys[cover.VisionService] = k
+		}
+	}
+}
+
+// visionKeyEnv is the conventional environment variable for the key of
+// the vision model.
+const visionKeyEnv = "OPENAI_API_KEY"
+
+// table returns the output layout: the profile if one is selected, the
+// standard columns otherwise, or those of them -columns selects.
+func (f *enrichFlags) table() (columns.Layout, error) {
+	if f.profile != "" {
+		if f.columns != "" {
+			return nil, errors.New(i18n.T("-columns does not apply to export profiles, which have their own columns"))
+		}
+		return profile.Lookup(f.profile)
+	}
+	langMode, err := lang.ParseMode(f.languageFormat)
+	if err != nil {
+		return nil, err
+	}
+	authorFormat, err := author.ParseFormat(f.authorFormat)
+	if err != nil {
+		return nil, err
+	}
+	sys, err := units.ParseSystem(f.units)
+	if err != nil {
+		return nil, err
+	}
+	validation, err := validate.ParseMode(f.validate)
+	if err != nil {
+		return nil, fmt.Errorf("-validate: %w", err)
+	}
+	fields := append(append([]columns.Field(nil), columns.Default...), columns.Physical(sys)...)
+	if f.authorDetails {
+		fields = append(fields, columns.AuthorDetails...)
+	}
+	if f.bisac {
+		fields = append(fields, columns.BISAC...)
+	}
+	if f.imprints {
+		fields = append(fields, columns.Imprint...)
+	}
+	if f.romanize {
+		fields = append(fields, columns.Romanized...)
+	}
+	if f.isbnFamily {
+		fields = append(fields, columns.Family...)
+	}
+	if f.itemProviders != "" {
+		fields = append(fields, columns.Items...)
+	}
+	if f.shippingRates != "" {
+		rates, err := f.rateTable()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, columns.Shipping(rates)...)
+	}
+	if f.taxTable != "" || f.taxDestination != "" {
+		t, err := f.taxRules()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, columns.Tax(t)...)
+	}
+	if f.customs {
+		if f.customsOrigin != "" && !country.Valid(f.customsOrigin) {
+			return nil, fmt.Errorf("invalid -customs-origin %q (want an ISO 3166 alpha-2 country code)", f.customsOrigin)
+		}
+		fields = append(fields, columns.Customs(customs.Options{DefaultOrigin: f.customsOrigin})...)
+	}
+	if f.prices != "" {
+		fields = append(fields, columns.Prices(strings.ToUpper(f.priceCurrency))...)
+	}
+	if f.covers != "" {
+		fields = append(fields, columns.Covers(max(f.coverColors, 0), f.processCovers())...)
+	}
+	if f.coverAlt != "" {
+		fields = append(fields, columns.CoverAlt)
+	}
+	if f.translate != "" {
+		target, err := f.translateTarget()
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, columns.Translation(target)...)
+	}
+	if validation == validate.Report {
+		fields = append(fields, columns.Violations)
+	}
+	if f.columns != "" {
+		if fields, err = columns.Select(fields, strings.Split(f.columns, ",")); err != nil {
+			return nil, fmt.Errorf("-columns: %w", err)
+		}
+	}
+	return &columns.Table{
+		Fields:  fields,
+		Options: &columns.Options{MaxDescriptionLength: f.maxDescription, Languages: langMode, Authors: authorFormat},
+	}, nil
+}
+
+// rateTable returns the shipping rate table of -shipping-rates.
+func (f *enrichFlags) rateTable() (*shipping.RateTable, error) {
+	if f.shippingRates == "default" {
+		return &shipping.DefaultRateTable, nil
+	}
+	return shipping.LoadRateTable(f.shippingRates)
+}
+
+// taxRules returns the tax table of -tax-table, sending books to
+// -tax-destination when set.
+func (f *enrichFlags) taxRules() (*tax.Table, error) {
+	t := tax.DefaultTable
+	if f.taxTable != "" && f.taxTable != "default" {
+		loaded, err := tax.LoadTable(f.taxTable)
+		if err != nil {
+			return nil, err
+		}
+		t = *loaded
+	}
+	if f.taxDestination != "" {
+		if !country.Valid(f.taxDestination) {
+			return nil, fmt.Errorf("invalid -tax-destination %q (want an ISO 3166 alpha-2 country code)", f.taxDestination)
+		}
+		t.Destination = f.taxDestination
+	}
+	return &t, nil
+}
+
+// enricher builds an Enricher for a comma-separated provider list, with
+// the call budgets applied.
+func (f *enrichFlags) enricher(list string, c *http.Client) (*enrich.Enricher, []*provider.Budgeted, error) {
+	if f.http.offline && (f.http.noCache || f.http.cacheDir == "") {
+		return nil, nil, errors.New(i18n.T("-offline answers from the response cache, which -no-cache disables"))
+	}
+	settings := &providerSettings{HTTPClient: c, Keys: f.keys}
+	providers, err := newProviders(list, settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	items, err := newItemProviders(f.itemProviders, settings)
+	if err != nil {
+		return nil, nil, err
+	}
+	validation, err := validate.ParseMode(f.validate)
+	if err != nil {
+		return nil, nil, fmt.Errorf("-validate: %w", err)
+	}
+	budgets := applyBudgets(providers, f.maxCalls)
+	e := &enrich.Enricher{
+		Providers:  providers,
+		Items:      items,
+		Workers:    f.workers,
+		RowTimeout: f.rowTimeout,
+		Merge:      f.merge,
+		MinMatch:   f.minMatch,
+		Dedupe:     f.dedupe,
+		BISAC:      f.bisac,
+		Imprints:   f.imprints,
+		Series:     &series.Resolver{},
+		Validation: validation,
+	}
+	// Offline providers, such as mock, must not lead to Wikidata or
+	// author queries either.
+	online := slices.ContainsFunc(providers, func(p provider.Provider) bool {
+		return providerTable[p.Name()].probe != ""
+	})
+	if f.wikidata && online {
+		e.Series.Wikidata = &series.Wikidata{HTTPClient: c}
+	}
+	if f.isbnFamily && online {
+		e.Family = &family.Resolver{OpenLibrary: &openlibrary.Client{HTTPClient: c}}
+	}
+	if !f.rawSubjects {
+		e.Subjects = &subject.Normalizer{Max: f.maxSubjects}
+		if f.subjectVocab != "" {
+			if e.Subjects.Vocabulary, err = subject.LoadVocabulary(f.subjectVocab); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	if f.authorDetails && online {
+		e.Authors = &authority.Resolver{Sources: []authority.Source{
+			&authority.OpenLibrary{HTTPClient: c},
+			&authority.VIAF{HTTPClient: c},
+		}}
+	}
+	if e.Prices, err = newPriceLookup(f.prices, f.priceCurrency, c); err != nil {
+		return nil, nil, err
+	}
+	if f.covers != "" {
+		e.Covers = &cover.Fetcher{
+			HTTPClient: c,
+			Dir:        f.covers,
+			Colors:     f.coverColors,
+			Index:      &cover.Index{PlaceholderCount: cover.DefaultPlaceholderCount},
+		}
+		if f.coverColors <= 0 {
+			e.Covers.Colors = -1
+		}
+		if e.Covers.Process, err = f.coverProcessing(); err != nil {
+			return nil, nil, err
+		}
+	}
+	if e.Alt, err = f.alt(c); err != nil {
+		return nil, nil, err
+	}
+	if e.Translate, err = f.translator(c); err != nil {
+		return nil, nil, err
+	}
+	return e, budgets, nil
+}
+
+// processCovers reports whether web versions of the covers are made.
+func (f *enrichFlags) processCovers() bool {
+	return f.coverSize != "" || f.coverSquare || f.coverFormat != "" || f.coverQuality != 0
+}
+
+// coverProcessing returns the settings of the web versions of the
+// covers, or nil when they are not made.
+func (f *enrichFlags) coverProcessing() (*cover.Processing, error) {
+	if !f.processCovers() {
+		return nil, nil
+	}
+	p := &cover.Processing{Square: f.coverSquare, Format: f.coverFormat, Quality: f.coverQuality}
+	if f.coverSize != "" {
+		w, h, ok := strings.Cut(f.coverSize, "x")
+		var err1, err2 error
+		p.MaxWidth, err1 = strconv.Atoi(w)
+		p.MaxHeight, err2 = strconv.Atoi(h)
+		if !ok || err1 != nil || err2 != nil || p.MaxWidth < 0 || p.MaxHeight < 0 {
+			return nil, fmt.Errorf("invalid -cover-size %q (want WxH, e.g. 600x900)", f.coverSize)
+		}
+	}
+	if p.Format != "" && !slices.Contains(cover.Formats, p.Format) {
+		return nil, fmt.Errorf("unknown -cover-format %q (want %s)", p.Format, strings.Join(cover.Formats, ", "))
+	}
+	if p.Quality < 0 || p.Quality > 100 {
+		return nil, fmt.Errorf("invalid -cover-quality %d (want 1 to 100)", p.Quality)
+	}
+	return p, nil
+}
+
+// alt returns the alt text writer selected by -cover-alt, or nil.
+func (f *enrichFlags) alt(c *http.Client) (*cover.Alt, error) {
+	switch f.coverAlt {
+	case "":
+		return nil, nil
+	case cover.AltTemplate, cover.AltVision:
+	default:
+		return nil, fmt.Errorf("unknown -cover-alt %q (want %s)", f.coverAlt, strings.Join(cover.AltSources, " or "))
+	}
+	t, err := cover.ParseAltTemplate(f.altTemplate)
+	if err != nil {
+		return nil, err
+	}
+	a := &cover.Alt{Template: t}
+	if f.coverAlt == cover.AltVision {
+		if f.covers == "" {
+			return nil, errors.New(i18n.T("-cover-alt vision describes the downloaded covers; set -covers"))
+		}
+		a.Vision = &cover.Vision{HTTPClient: c, URL: f.altURL, APIKey: f.keys[cover.VisionService], Model: f.altModel}
+	}
+	return a, nil
+}
+
+// paidFeatures lists the paid services used for every row besides the
+// providers.
+func (f *enrichFlags) paidFeatures() []string {
+	var paid []string
+	if f.coverAlt == cover.AltVision {
+		paid = append(paid, cover.VisionService)
+	}
+	if name := strings.ToLower(f.translate); translateTable[name].paid {
+		paid = append(paid, name)
+	}
+	return paid
+}
@@ -0,0 +1,7 @@
+//go:build postgres
+
+package main
+
+// Registers the "pgx" PostgreSQL driver for the database export. Build
+// with -tags postgres to include it.
+import _ "github.com/jackc/pgx/v5/stdlib"
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/price"
+)
+
+// ebayTokenEnv holds the eBay OAuth application token.
+const ebayTokenEnv = "EBAY_OAUTH_TOKEN"
+
+// priceEntry describes a built-in price source.
+type priceEntry struct {
+	summary string
+	// endpoint is the base URL, for the network allowlist.
+	endpoint string
+	new      func(c *http.Client) (price.Source, error)
+}
+
+var priceTable = map[string]priceEntry{
+	"abebooks": {
+		summary:  "AbeBooks best new and used offers (free, no key)",
+		endpoint: price.AbeBooksURL,
+		new: func(c *http.Client) (price.Source, error) {
+			return &price.AbeBooks{HTTPClient: c}, nil
+		},
+	},
+	"ebay": {
+		summary:  "eBay fixed-price listings (needs $" + ebayTokenEnv + ")",
+		endpoint: price.EbayURL,
+		new: func(c *http.Client) (price.Source, error) {
+			tok := os.Getenv(ebayTokenEnv)
+			if tok == "" {
+				return nil, fmt.Errorf("price source ebay needs an OAuth token in $%s", ebayTokenEnv)
+			}
+			return &price.Ebay{HTTPClient: c, Token: tok}, nil
+		},
+	},
+}
+
+// priceSourceNames returns the built-in price source names, sorted.
+func priceSourceNames() []string {
+	names := make([]string, 0, len(priceTable))
+	for n := range priceTable {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// newPriceLookup builds the price lookup for a comma-separated source
+// list; an empty list disables it.
+func newPriceLookup(list, currency string, c *http.Client) (*price.Lookup, error) {
+	var l price.Lookup
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		e, ok := priceTable[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown price source %q (available: %s)", name, strings.Join(priceSourceNames(), ", "))
+		}
+		s, err := e.new(c)
+		if err != nil {
+			return nil, err
+		}
+		l.Sources = append(l.Sources, s)
+	}
+	if len(l.Sources) == 0 {
+		return nil, nil
+	}
+	l.Currency = currency
+	return &l, nil
+}
+
+// priceHosts returns the hosts the sources in a comma-separated list
+// connect to.
+func priceHosts(list string) []string {
+	var hosts []string
+	for _, name := range strings.Split(list, ",") {
+		if e, ok := priceTable[strings.ToLower(strings.TrimSpace(name))]; ok {
+			u, _ := url.Parse(e.endpoint)
+			hosts = append(hosts, u.Hostname())
+		}
+	}
+	return hosts
+}
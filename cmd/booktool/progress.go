@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// plainInterval is how often plain mode reports progress.
+const plainInterval = 10 * time.Second
+
+// progress reports how far a run is. On terminals it rewrites one status
+// line in place. In plain mode, for screen readers, dumb terminals and
+// redirected output, it prints a complete line every plainInterval and
+// the final count, so each message can be read on its own.
+type progress struct {
+	w     io.Writer
+	plain bool
+	last  time.Time
+	// pending is the latest status not printed yet in plain mode, or
+	// whether a status line is drawn otherwise.
+	pending string
+}
+
+func newProgress(plain bool) *progress {
+	plain = plain || !isTerminal(os.Stderr) || os.Getenv("TERM") == "dumb"
+	return &progress{w: os.Stderr, plain: plain}
+}
+
+// update sets the current status.
+func (p *progress) update(status string) {
+	if !p.plain {
+		fmt.Fprint(p.w, "\r"+status)
+		p.pending = status
+		return
+	}
+	p.pending = status
+	if now := time.Now(); now.Sub(p.last) >= plainInterval {
+		fmt.Fprintln(p.w, status)
+		p.last, p.pending = now, ""
+	}
+}
+
+// finish ends the status line, printing the last status in plain mode.
+func (p *progress) finish() {
+	if p.pending != "" {
+		if p.plain {
+			fmt.Fprint(p.w, p.pending)
+		}
+		fmt.Fprintln(p.w)
+	}
+	p.pending = ""
+}
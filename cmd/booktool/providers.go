@@ -0,0 +1,265 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/provider/anilist"
+	"github.com/SouadAli10/book_scrapping_tool/provider/googlebooks"
+	"github.com/SouadAli10/book_scrapping_tool/provider/isbndb"
+	"github.com/SouadAli10/book_scrapping_tool/provider/loc"
+	"github.com/SouadAli10/book_scrapping_tool/provider/mock"
+	"github.com/SouadAli10/book_scrapping_tool/provider/oldump"
+	"github.com/SouadAli10/book_scrapping_tool/provider/openlibrary"
+	"github.com/SouadAli10/book_scrapping_tool/provider/sru"
+	"github.com/SouadAli10/book_scrapping_tool/provider/upcitemdb"
+)
+
+// providerSettings holds what the provider constructors need.
+type providerSettings struct {
+	HTTPClient *http.Client
+	// Keys maps provider names to API keys.
+	Keys map[string]string
+}
+
+// providerEntry describes a built-in provider.
+type providerEntry struct {
+	summary string
+	// keyEnv is the conventional environment variable for the API key.
+	keyEnv string
+	spec   config.ProviderSpec
+	// probe is the URL the readiness probe checks for reachability;
+	// empty for providers that work offline.
+	probe string
+	// coverHosts serve the provider's cover images.
+	coverHosts []string
+	// new builds the book provider; it is nil for providers of other
+	// items only.
+	new func(s *providerSettings) provider.Provider
+	// item builds the provider of items other than books, when the
+	// provider has one.
+	item func(s *providerSettings) provider.ItemProvider
+	// check reports why the provider cannot work, for providers that
+	// need more than the network, such as a local index.
+	check func() error
+}
+
+var providerTable = map[string]providerEntry{
+	openlibrary.Name: {
+		summary: "OpenLibrary (free, no key)",
+		probe:   openlibrary.DefaultBaseURL,
+		// Covers redirect to the Internet Archive.
+		coverHosts: []string{"covers.openlibrary.org", "*.archive.org"},
+		new: func(s *providerSettings) provider.Provider {
+			return &openlibrary.Client{HTTPClient: s.HTTPClient}
+		},
+	},
+	googlebooks.Name: {
+		summary:    "Google Books (free; an API key raises the quota)",
+		probe:      googlebooks.DefaultBaseURL,
+		coverHosts: []string{"books.google.com"},
+		keyEnv:     "GOOGLE_BOOKS_API_KEY",
+		new: func(s *providerSettings) provider.Provider {
+			return &googlebooks.Client{HTTPClient: s.HTTPClient, APIKey: s.Keys[googlebooks.Name]}
+		},
+	},
+	isbndb.Name: {
+		summary:    "ISBNdb (paid, API key required; dimensions and weight)",
+		probe:      isbndb.DefaultBaseURL,
+		coverHosts: []string{"images.isbndb.com"},
+		keyEnv:     "ISBNDB_API_KEY",
+		spec:       config.ProviderSpec{RequiresKey: true},
+		new: func(s *providerSettings) provider.Provider {
+			return &isbndb.Client{HTTPClient: s.HTTPClient, APIKey: s.Keys[isbndb.Name]}
+		},
+	},
+	loc.Name: {
+		summary: "Library of Congress (free; LC classification and LCCN)",
+		probe:   loc.DefaultBaseURL,
+		new: func(s *providerSettings) provider.Provider {
+			return &loc.Client{HTTPClient: s.HTTPClient}
+		},
+	},
+	sru.BL.Name: {
+		summary: "British Library (free, no key; British books, SRU)",
+		probe:   sru.BL.BaseURL,
+		new: func(s *providerSettings) provider.Provider {
+			return &sru.Client{Catalog: sru.BL, HTTPClient: s.HTTPClient}
+		},
+	},
+	sru.DNB.Name: {
+		summary: "Deutsche Nationalbibliothek (free, no key; German-language books, SRU)",
+		probe:   sru.DNB.BaseURL,
+		new: func(s *providerSettings) provider.Provider {
+			return &sru.Client{Catalog: sru.DNB, HTTPClient: s.HTTPClient}
+		},
+	},
+	sru.BnF.Name: {
+		summary: "Bibliothèque nationale de France (free, no key; French books, SRU)",
+		probe:   sru.BnF.BaseURL,
+		new: func(s *providerSettings) provider.Provider {
+			return &sru.Client{Catalog: sru.BnF, HTTPClient: s.HTTPClient}
+		},
+	},
+	anilist.Name: {
+		summary:    "AniList (free, no key; manga and light novels by series and volume)",
+		probe:      anilist.DefaultBaseURL,
+		coverHosts: []string{"s4.anilist.co"},
+		new: func(s *providerSettings) provider.Provider {
+			return &anilist.Client{HTTPClient: s.HTTPClient}
+		},
+	},
+	upcitemdb.Name: {
+		summary: "UPCitemdb (DVDs, CDs and games by barcode; free trial of 100 lookups a day)",
+		probe:   upcitemdb.DefaultBaseURL,
+		keyEnv:  "UPCITEMDB_API_KEY",
+		item: func(s *providerSettings) provider.ItemProvider {
+			return &upcitemdb.Client{HTTPClient: s.HTTPClient, APIKey: s.Keys[upcitemdb.Name]}
+		},
+	},
+	oldump.Name: {
+		summary: "Local index of the OpenLibrary data dumps, built with \"booktool oldump\" (offline, no key; ISBN lookups only)",
+		new: func(*providerSettings) provider.Provider {
+			return &oldump.Client{Driver: sqliteDriver, Path: oldumpIndex()}
+		},
+		check: checkOLDumpIndex,
+	},
+	mock.Name: {
+		summary: "Invented records for demos and training (offline, no key)",
+		new: func(*providerSettings) provider.Provider {
+			return &mock.Client{}
+		},
+		item: func(*providerSettings) provider.ItemProvider {
+			return &mock.Client{}
+		},
+	},
+}
+
+// providerAliases are accepted on the command line for convenience.
+var providerAliases = map[string]string{"google": googlebooks.Name, "ol": openlibrary.Name}
+
+func providerNames() []string {
+	var names []string
+	for n, e := range providerTable {
+		if e.new != nil {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// itemProviderNames lists the providers of items other than books.
+func itemProviderNames() []string {
+	var names []string
+	for n, e := range providerTable {
+		if e.item != nil {
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// canonicalProvider resolves aliases and case.
+func canonicalProvider(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if alias, ok := providerAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// newProvider builds the provider registered under name.
+func newProvider(name string, s *providerSettings) (provider.Provider, error) {
+	name = canonicalProvider(name)
+	e, ok := providerTable[name]
+	if !ok || e.new == nil {
+		return nil, fmt.Errorf("unknown provider %q (available: %s)", name, strings.Join(providerNames(), ", "))
+	}
+	if e.check != nil {
+		if err := e.check(); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return e.new(s), nil
+}
+
+// newProviders builds providers from a comma-separated list, keeping the
+// order as priority.
+func newProviders(list string, s *providerSettings) ([]provider.Provider, error) {
+	var ps []provider.Provider
+	for _, name := range strings.Split(list, ",") {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		p, err := newProvider(name, s)
+		if err != nil {
+			return nil, err
+		}
+		ps = append(ps, p)
+	}
+	if len(ps) == 0 {
+		return nil, fmt.Errorf("no providers configured")
+	}
+	return ps, nil
+}
+
+// newItemProviders builds the providers of items other than books from
+// a comma-separated list, keeping the order as priority. An empty list
+// yields none.
+func newItemProviders(list string, s *providerSettings) ([]provider.ItemProvider, error) {
+	var ps []provider.ItemProvider
+	for _, name := range strings.Split(list, ",") {
+		name = canonicalProvider(name)
+		if name == "" {
+			continue
+		}
+		e, ok := providerTable[name]
+		if !ok || e.item == nil {
+			return nil, fmt.Errorf("unknown item provider %q (available: %s)", name, strings.Join(itemProviderNames(), ", "))
+		}
+		ps = append(ps, e.item(s))
+	}
+	return ps, nil
+}
+
+// coverHosts returns the hosts serving the covers of the providers in a
+// comma-separated list.
+func coverHosts(list string) []string {
+	var hosts []string
+	for _, name := range strings.Split(list, ",") {
+		hosts = append(hosts, providerTable[canonicalProvider(name)].coverHosts...)
+	}
+	return hosts
+}
+
+// providerHosts returns the hosts the providers in a comma-separated list
+// connect to.
+func providerHosts(list string) ([]string, error) {
+	var hosts []string
+	for _, name := range strings.Split(list, ",") {
+		name = canonicalProvider(name)
+		if name == "" {
+			continue
+		}
+		e, ok := providerTable[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown provider %q (available: %s)", name, strings.Join(providerNames(), ", "))
+		}
+		if e.probe == "" {
+			continue
+		}
+		u, err := url.Parse(e.probe)
+		if err != nil {
+			return nil, err
+		}
+		hosts = append(hosts, u.Hostname())
+	}
+	return hosts, nil
+}
@@ -0,0 +1,378 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+)
+
+// replayOverrides are the flags of a recorded run a replay sets itself,
+// mapped to whether they take a value; see withoutFlags.
+var replayOverrides = map[string]bool{
+	"output": true, "cache-dir": true, "record-dir": true, "manifest": true,
+	"db-driver": true, "db-dsn": true, "site-dir": true,
+	"offline": false, "no-cache": false, "yes": false, "open": false,
+}
+
+// cmdReplay runs a recorded run again, answering every lookup from the
+// run's response archive, and compares the output with the recorded
+// one. Differences come from changes to the provider mappings, the
+// merge rules and the data tables since the run, and are listed by
+// column. Lookups the archive cannot answer, such as translations and
+// cover descriptions, which are not GET requests, come out as pending.
+func cmdReplay(args []string) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	registerLang(fs)
+	out := fs.String("output", "", "keep the replayed output in this file (default: compare and remove it)")
+	examples := fs.Int("examples", 3, "differing cells shown per column")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: booktool replay [flags] <run.manifest>")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New(i18n.T("no manifest given"))
+	}
+	m, err := readManifest(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	keep := *out != ""
+	if keep {
+		if *out, err = filepath.Abs(*out); err != nil {
+			return err
+		}
+	} else {
+		tmp, err := os.MkdirTemp("", "booktool-replay-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(tmp)
+		*out = filepath.Join(tmp, filepath.Base(m.Output.Path))
+	}
+	// The paths of the manifest are relative to the run's directory.
+	if err := os.Chdir(m.Dir); err != nil {
+		return err
+	}
+	if changed, err := m.Input.changed(); err != nil {
+		return err
+	} else if changed {
+		return i18n.Errorf("%s changed since the run; a replay needs the same input", m.Input.Path)
+	}
+	if _, err := os.Stat(m.Responses); err != nil {
+		return i18n.Errorf("response archive: %w", err)
+	}
+	if m.Config != nil {
+		if changed, err := m.Config.changed(); err != nil || changed {
+			i18n.Fprintf(os.Stderr, "warning: the configuration %s changed since the run\n", m.Config.Path)
+		}
+	}
+	if v := buildVersion(); v != m.Version {
+		i18n.Fprintf(os.Stderr, "the run was made with booktool %s; replaying with %s\n", m.Version, v)
+	}
+
+	run := append([]string{
+		"-output=" + *out, "-cache-dir=" + m.Responses, "-offline=true", "-no-cache=false",
+		"-record-dir=", "-manifest=", "-db-driver=", "-site-dir=", "-yes=true", "-open=false",
+	}, withoutFlags(m.Args, replayOverrides)...)
+	if err := enrichRun(run, true); err != nil {
+		return err
+	}
+	if changed, err := m.Output.changed(); err != nil {
+		return err
+	} else if changed {
+		return i18n.Errorf("the recorded output %s changed since the run; nothing to compare with", m.Output.Path)
+	}
+	format, err := output.Resolve(flagArg(m.Args, "output-format"), m.Output.Path)
+	if err != nil {
+		return err
+	}
+	n, err := compareOutputs(os.Stdout, m.Output.Path, *out, format, max(*examples, 0))
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return i18n.Errorf("the replay differs from %s in %d cells", m.Output.Path, n)
+	}
+	i18n.Fprintf(os.Stderr, "reproduced %s exactly (%d rows)\n", m.Output.Path, m.Rows)
+	if keep {
+		i18n.Fprintf(os.Stderr, "wrote %s\n", *out)
+	}
+	return nil
+}
+
+// table is a sheet of an output file.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// cell returns column i of row, which spreadsheets may cut short.
+func cell(row []string, i int) string {
+	if i < len(row) {
+		return row[i]
+	}
+	return ""
+}
+
+// errNotTabular is returned by readTables for outputs without a header
+// row, which are compared as a whole.
+var errNotTabular = errors.New("not a tabular output")
+
+// readTables reads the sheets of an output file: each worksheet of a
+// workbook, or the one table of other formats, named "".
+func readTables(path string, format *output.Format) (names []string, tables map[string]*table, err error) {
+	tables = make(map[string]*table)
+	add := func(name string, rows [][]string) {
+		t := new(table)
+		if len(rows) > 0 {
+			t.header, t.rows = rows[0], rows[1:]
+		}
+		names = append(names, name)
+		tables[name] = t
+	}
+	switch format.Name {
+	case "xlsx":
+		f, err := excelize.OpenFile(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer f.Close()
+		for _, name := range f.GetSheetList() {
+			rows, err := f.GetRows(name)
+			if err != nil {
+				return nil, nil, err
+			}
+			add(name, rows)
+		}
+	case "csv", "tsv":
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		defer file.Close()
+		r := csv.NewReader(file)
+		r.FieldsPerRecord = -1
+		if format.Name == "tsv" {
+			r.Comma = '\t'
+		}
+		rows, err := r.ReadAll()
+		if err != nil {
+			return nil, nil, err
+		}
+		add("", rows)
+	case "jsonl":
+		rows, err := readJSONLTable(path)
+		if err != nil {
+			return nil, nil, err
+		}
+		add("", rows)
+	default:
+		return nil, nil, errNotTabular
+	}
+	return names, tables, nil
+}
+
+// readJSONLTable reads JSONL output as rows under a header of the keys
+// of its objects, sorted.
+func readJSONLTable(path string) ([][]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	var objs []map[string]string
+	keys := make(map[string]bool)
+	sc := bufio.NewScanner(file)
+	sc.Buffer(nil, 16<<20)
+	for sc.Scan() {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		var obj map[string]string
+		if err := json.Unmarshal(sc.Bytes(), &obj); err != nil {
+			return nil, err
+		}
+		for k := range obj {
+			keys[k] = true
+		}
+		objs = append(objs, obj)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	header := make([]string, 0, len(keys))
+	for k := range keys {
+		header = append(header, k)
+	}
+	sort.Strings(header)
+	rows := [][]string{header}
+	for _, obj := range objs {
+		row := make([]string, len(header))
+		for i, k := range header {
+			row[i] = obj[k]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// compareOutputs writes the differences between the recorded output and
+// the replayed one to w, column by column with up to examples differing
+// cells each, and returns how many cells differ. Rows are matched by
+// their Input Row column when the output has one, since rows written in
+// completion order come in any order.
+func compareOutputs(w io.Writer, recorded, replayed string, format *output.Format, examples int) (int, error) {
+	names, old, err := readTables(recorded, format)
+	if errors.Is(err, errNotTabular) {
+		a, err := os.ReadFile(recorded)
+		if err != nil {
+			return 0, err
+		}
+		b, err := os.ReadFile(replayed)
+		if err != nil {
+			return 0, err
+		}
+		if bytes.Equal(a, b) {
+			return 0, nil
+		}
+		i18n.Fprintf(w, "%s differs from the replay (compared as a whole, not cell by cell)\n", recorded)
+		return 1, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	newNames, replay, err := readTables(replayed, format)
+	if err != nil {
+		return 0, err
+	}
+	for _, name := range newNames {
+		if _, ok := old[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	diffs := 0
+	for _, name := range names {
+		prefix := ""
+		if name != "" {
+			prefix = name + ": "
+		}
+		a, b := old[name], replay[name]
+		switch {
+		case b == nil:
+			i18n.Fprintf(w, "%sonly in the recorded output\n", prefix)
+			diffs += len(a.rows) * len(a.header)
+			continue
+		case a == nil:
+			i18n.Fprintf(w, "%sonly in the replay\n", prefix)
+			diffs += len(b.rows) * len(b.header)
+			continue
+		}
+		diffs += compareTables(w, prefix, a, b, examples)
+	}
+	return diffs, nil
+}
+
+// compareTables compares a sheet of the recorded output with the same
+// sheet of the replay.
+func compareTables(w io.Writer, prefix string, a, b *table, examples int) int {
+	keyed := func(t *table) (keys []string, rows map[string][]string) {
+		col := slices.Index(t.header, columns.RowHeader)
+		rows = make(map[string][]string, len(t.rows))
+		for i, row := range t.rows {
+			// Data rows start below the header.
+			k := strconv.Itoa(i + 2)
+			if col >= 0 {
+				k = cell(row, col)
+			}
+			keys = append(keys, k)
+			rows[k] = row
+		}
+		return keys, rows
+	}
+	label := "row %s"
+	if slices.Contains(a.header, columns.RowHeader) {
+		label = "input row %s"
+	}
+	aKeys, aRows := keyed(a)
+	bKeys, bRows := keyed(b)
+	diffs := 0
+	missing := func(keys []string, other map[string][]string, msg string, width int) {
+		var only []string
+		for _, k := range keys {
+			if _, ok := other[k]; !ok {
+				only = append(only, k)
+			}
+		}
+		if len(only) == 0 {
+			return
+		}
+		diffs += len(only) * width
+		shown := only[:min(len(only), max(examples, 1))]
+		list := strings.Join(shown, ", ")
+		if len(shown) < len(only) {
+			list += ", …"
+		}
+		i18n.Fprintf(w, msg, prefix, len(only), list)
+	}
+	missing(aKeys, bRows, "%s%d rows only in the recorded output: %s\n", len(a.header))
+	missing(bKeys, aRows, "%s%d rows only in the replay: %s\n", len(b.header))
+
+	for _, h := range a.header {
+		if !slices.Contains(b.header, h) {
+			i18n.Fprintf(w, "%scolumn %q only in the recorded output\n", prefix, h)
+			diffs += len(a.rows)
+		}
+	}
+	for _, h := range b.header {
+		if !slices.Contains(a.header, h) {
+			i18n.Fprintf(w, "%scolumn %q only in the replay\n", prefix, h)
+			diffs += len(b.rows)
+		}
+	}
+	for i, h := range a.header {
+		j := slices.Index(b.header, h)
+		if j < 0 {
+			continue
+		}
+		var changed []string
+		for _, k := range aKeys {
+			br, ok := bRows[k]
+			if !ok {
+				continue
+			}
+			if before, after := cell(aRows[k], i), cell(br, j); before != after {
+				changed = append(changed, fmt.Sprintf("    "+label+": %q → %q", k, before, after))
+			}
+		}
+		if len(changed) == 0 {
+			continue
+		}
+		diffs += len(changed)
+		i18n.Fprintf(w, "%scolumn %q: %d of %d rows differ\n", prefix, h, len(changed), len(aKeys))
+		for _, line := range changed[:min(len(changed), examples)] {
+			fmt.Fprintln(w, line)
+		}
+	}
+	return diffs
+}
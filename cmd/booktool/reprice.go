@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+	"github.com/SouadAli10/book_scrapping_tool/price"
+	"github.com/SouadAli10/book_scrapping_tool/profile"
+	"github.com/SouadAli10/book_scrapping_tool/reprice"
+)
+
+// factorFlag is a repeatable -factor flag: "condition=factor", also as
+// a comma-separated list.
+type factorFlag map[profile.Condition]float64
+
+func (f factorFlag) String() string {
+	var parts []string
+	for c, v := range f {
+		parts = append(parts, c.String()+"="+strconv.FormatFloat(v, 'f', -1, 64))
+	}
+	slices.Sort(parts)
+	return strings.Join(parts, ",")
+}
+
+func (f factorFlag) Set(s string) error {
+	for _, part := range strings.Split(s, ",") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return fmt.Errorf("want condition=factor, got %q", part)
+		}
+		c, err := reprice.ParseCondition(name)
+		if err != nil {
+			return err
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil || v <= 0 {
+			return fmt.Errorf("invalid factor %q for %s", value, c)
+		}
+		f[c] = v
+	}
+	return nil
+}
+
+// repriceFlags holds the flags of the reprice command.
+type repriceFlags struct {
+	enrichFlags
+	config  string
+	catalog string
+	sheet   string
+	report  string
+	apply   bool
+	refresh bool
+	basis   string
+	factors factorFlag
+	ending  float64
+	floor   float64
+	ceiling float64
+}
+
+func (f *repriceFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.config, "config", "", "configuration file (default: "+config.DefaultPath+" if present)")
+	fs.StringVar(&f.catalog, "catalog", "", "enriched catalog (xlsx, csv, tsv) with condition and price columns")
+	fs.StringVar(&f.sheet, "sheet", "", "worksheet to read from spreadsheet catalogs (default: first)")
+	fs.StringVar(&f.report, "report", "", "write the price changes as CSV to this file (default: standard output)")
+	fs.BoolVar(&f.apply, "apply", false, "write the new prices to the catalog's price column, in place")
+	fs.BoolVar(&f.refresh, "refresh", false, "look the market prices up again with the -prices sources instead of reading the catalog's market price columns")
+	fs.StringVar(&f.basis, "basis", string(reprice.Median), "market price the rules start from: median or min")
+	f.factors = make(factorFlag)
+	fs.Var(f.factors, "factor", "market price factor by condition, e.g. \"very good=0.85\"; conditions: new, like new, very good, good, acceptable, poor, other (repeatable)")
+	fs.Float64Var(&f.ending, "ending", reprice.DefaultEnding, "round prices to the nearest amount with these cents (0: keep the cents)")
+	fs.Float64Var(&f.floor, "floor", 0, "lowest new price (0: none)")
+	fs.Float64Var(&f.ceiling, "ceiling", 0, "highest new price (0: none)")
+	f.enrichFlags.register(fs)
+}
+
+// applyReprice copies the pricing rules of the configuration into f.
+func (f *repriceFlags) applyReprice(c *config.Config) {
+	if c.Reprice.Basis != "" {
+		f.basis = c.Reprice.Basis
+	}
+	for name, v := range c.Reprice.Factors {
+		if cond, err := reprice.ParseCondition(name); err == nil {
+			f.factors[cond] = v
+		}
+	}
+	if c.Reprice.Ending != nil {
+		f.ending = *c.Reprice.Ending
+	}
+	if c.Reprice.Floor > 0 {
+		f.floor = c.Reprice.Floor
+	}
+	if c.Reprice.Ceiling > 0 {
+		f.ceiling = c.Reprice.Ceiling
+	}
+}
+
+// rules returns the pricing rules of the flags.
+func (f *repriceFlags) rules() (*reprice.Rules, error) {
+	basis, err := reprice.ParseBasis(f.basis)
+	if err != nil {
+		return nil, err
+	}
+	if f.ending < 0 || f.ending >= 1 {
+		return nil, fmt.Errorf("invalid -ending %v (want cents between 0 and 0.99)", f.ending)
+	}
+	if f.floor < 0 || f.ceiling < 0 || (f.ceiling > 0 && f.floor > f.ceiling) {
+		return nil, fmt.Errorf("invalid -floor %v and -ceiling %v", f.floor, f.ceiling)
+	}
+	return &reprice.Rules{Basis: basis, Factors: maps.Clone(f.factors), Ending: f.ending, Floor: f.floor, Ceiling: f.ceiling}, nil
+}
+
+// cmdReprice computes new prices for an enriched catalog from market
+// prices and reports the changes. With -apply it writes them to the
+// catalog.
+func cmdReprice(args []string) error {
+	fs := flag.NewFlagSet("reprice", flag.ContinueOnError)
+	var f repriceFlags
+	f.register(fs)
+	sealer, err := sealerFor(flagArg(args, "key-file"))
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(flagArg(args, "config"), sealer)
+	if err != nil {
+		return err
+	}
+	f.enrichFlags.apply(cfg)
+	f.applyReprice(cfg)
+	f.http.cipher = sealer
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	f.resolveKeys()
+	if f.catalog == "" && fs.NArg() > 0 {
+		f.catalog = fs.Arg(0)
+	}
+	if f.catalog == "" {
+		fs.Usage()
+		return errors.New(i18n.T("no catalog given"))
+	}
+	rules, err := f.rules()
+	if err != nil {
+		return err
+	}
+	if f.refresh && f.prices == "" {
+		return errors.New(i18n.T("-refresh needs price sources (-prices)"))
+	}
+	if err := f.restrictHosts(); err != nil {
+		return err
+	}
+
+	r, err := input.Open(f.catalog, "", input.Options{Sheet: f.sheet})
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	tab, ok := input.AsTabular(r)
+	if !ok {
+		return errors.New(i18n.T("reprice needs a catalog with a header row, such as xlsx or csv"))
+	}
+	header := tab.Header()
+	priceCol := slices.Index(header, tab.Mapping()["price"])
+	if priceCol < 0 && f.apply {
+		return i18n.Errorf("%s has no price column to write to", f.catalog)
+	}
+	titleCol := slices.Index(header, "Full Title")
+	marketCols := make([]int, 4)
+	for i, h := range columns.Header(columns.Prices(strings.ToUpper(f.priceCurrency))) {
+		marketCols[i] = slices.Index(header, h)
+	}
+	if !f.refresh && !slices.ContainsFunc(marketCols, func(i int) bool { return i >= 0 }) {
+		return i18n.Errorf("%s has no market price columns in %s; enrich it with -prices, or use -refresh", f.catalog, strings.ToUpper(f.priceCurrency))
+	}
+
+	metrics := new(httpx.Metrics)
+	var lookup *price.Lookup
+	if f.refresh {
+		if lookup, err = newPriceLookup(f.prices, f.priceCurrency, f.http.client(metrics)); err != nil {
+			return err
+		}
+	}
+
+	ctx := context.Background()
+	var rows []*input.Row
+	var changes []reprice.Change
+	changed := 0
+	prog := newProgress(f.plain)
+	for {
+		row, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		for len(row.Record) < len(header) {
+			row.Record = append(row.Record, "")
+		}
+		rows = append(rows, row)
+		c := reprice.Change{Row: row.Index + 2, ISBN: row.ISBN, Title: row.Title, Condition: profile.ParseCondition(row.Condition)}
+		if c.Title == "" && titleCol >= 0 {
+			c.Title = row.Record[titleCol]
+		}
+		c.Old, _ = reprice.ParseAmount(row.Price)
+		var market book.Prices
+		if lookup != nil {
+			if code := isbn.To13(isbn.Normalize(row.ISBN)); isbn.Valid13(code) {
+				if market, err = lookup.Lookup(ctx, code); err != nil {
+					c.Note = fmt.Sprintf("price lookup failed: %v", err)
+				}
+			}
+		} else {
+			amounts := make([]float64, 4)
+			for i, col := range marketCols {
+				if col >= 0 {
+					amounts[i], _ = reprice.ParseAmount(row.Record[col])
+				}
+			}
+			market = book.Prices{NewMin: amounts[0], NewMedian: amounts[1], UsedMin: amounts[2], UsedMedian: amounts[3]}
+		}
+		c.Market, c.Factor = rules.Market(market, c.Condition), rules.Factor(c.Condition)
+		if v, ok := rules.Price(market, c.Condition); ok {
+			c.New = v
+		} else if c.Note == "" {
+			c.Note = "no market price"
+		}
+		if c.Changed() {
+			changed++
+		}
+		changes = append(changes, c)
+		prog.update(i18n.Sprintf("%d rows priced, %d changes", len(changes), changed))
+	}
+	prog.finish()
+
+	if err := writeRepriceReport(f.report, changes); err != nil {
+		return err
+	}
+	i18n.Fprintf(os.Stderr, "%d of %d prices change\n", changed, len(changes))
+	if f.apply && changed > 0 {
+		recs := make([]*output.Record, len(rows))
+		for i, row := range rows {
+			rec := &output.Record{Index: row.Index, Cells: row.Record}
+			if changes[i].Changed() {
+				rec.Cells = slices.Clone(row.Record)
+				rec.Cells[priceCol] = strconv.FormatFloat(changes[i].New, 'f', 2, 64)
+			}
+			recs[i] = rec
+		}
+		if err := rewriteFile(f.catalog, "", header, recs); err != nil {
+			return err
+		}
+		i18n.Fprintf(os.Stderr, "updated %d prices in %s\n", changed, f.catalog)
+	}
+	printMetrics(os.Stderr, metrics)
+	return nil
+}
+
+// writeRepriceReport writes the price changes to path, or to standard
+// output if path is empty.
+func writeRepriceReport(path string, changes []reprice.Change) error {
+	if path == "" {
+		return reprice.WriteReport(os.Stdout, changes)
+	}
+	out, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err := reprice.WriteReport(out, changes); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	i18n.Fprintf(os.Stderr, "wrote %s\n", path)
+	return nil
+}
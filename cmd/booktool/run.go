@@ -0,0 +1,641 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/cost"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/gsheets"
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+	"github.com/SouadAli10/book_scrapping_tool/provider/openlibrary"
+	"github.com/SouadAli10/book_scrapping_tool/state"
+	"github.com/SouadAli10/book_scrapping_tool/validate"
+)
+
+// runFlags holds the flags of the run command.
+type runFlags struct {
+	enrichFlags
+	config               string
+	input, inputFormat   string
+	output, outputFormat string
+	sheet                string
+	sheetList            string
+	allSheets            bool
+	columns              map[string]string
+	db                   databaseFlags
+	site                 siteFlags
+	sheets               sheetsFlags
+	sample               int
+	maxRows              int
+	maxDuration          time.Duration
+	skipRows             int
+	costs                map[string]float64
+	costThreshold        float64
+	yes                  bool
+	open                 bool
+	order                orderFlag
+	update               string
+	minConfidence        float64
+	manifest             string
+	resume               bool
+	editions             bool
+	// review and editionsOut describe where rows needing review and
+	// the editions lists went, once the output is created.
+	review, editionsOut string
+}
+
+func (f *runFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.config, "config", "", "configuration file (default: "+config.DefaultPath+" if present)")
+	fs.StringVar(&f.input, "input", "", "input file (xlsx, csv, tsv, jsonl), Google Sheets URL, or - for standard input")
+	fs.StringVar(&f.inputFormat, "input-format", "", "input format; detected from content and extension by default")
+	fs.StringVar(&f.output, "output", "", "output file, gsheet:ID/Tab, or - to stream JSONL to standard output (default: <input>_enriched.xlsx, an \"enriched\" tab next to a Google Sheets input, or - for standard input)")
+	fs.StringVar(&f.outputFormat, "output-format", "", "output format; inferred from the output extension by default")
+	fs.StringVar(&f.sheet, "sheet", "", "worksheet to read from spreadsheet inputs (default: first)")
+	fs.StringVar(&f.sheetList, "sheets", "", "comma-separated worksheets of an xlsx input to enrich one after the other, each written to a sheet of the same name in xlsx output (a \""+columns.SheetHeader+"\" column in other formats)")
+	fs.BoolVar(&f.allSheets, "all-sheets", false, "enrich every worksheet of an xlsx input like -sheets, skipping those without an ISBN or title column")
+	f.enrichFlags.register(fs)
+	f.db.register(fs)
+	f.site.register(fs)
+	f.sheets.register(fs)
+	fs.IntVar(&f.sample, "sample", 0, "enrich only the first N rows, to check mappings before a full run")
+	fs.IntVar(&f.maxRows, "max-rows", 0, "stop cleanly after N rows, keeping a checkpoint and printing the command that continues the run")
+	fs.DurationVar(&f.maxDuration, "max-duration", 0, "stop reading new rows after this long (e.g. 2h), like -max-rows")
+	fs.IntVar(&f.skipRows, "skip-rows", 0, "skip the first N input rows, to continue a run stopped by -max-rows or -max-duration")
+	fs.BoolVar(&f.resume, "resume", false, "continue the stopped or interrupted run of the input from its checkpoint, with the flags it was started with")
+	fs.Float64Var(&f.costThreshold, "cost-threshold", cost.DefaultThreshold, "ask for confirmation when paid services may cost more than this (USD)")
+	fs.BoolVar(&f.yes, "yes", false, "do not ask for confirmation")
+	fs.BoolVar(&f.open, "open", false, "open the output file when done")
+	fs.Float64Var(&f.minConfidence, "min-confidence", 0, "move rows whose "+columns.QualityHeader+" (0-1) is below this to a "+output.SheetReview+" sheet, or a <output>_review file for formats without sheets (0: keep every row)")
+	fs.BoolVar(&f.editions, "editions", false, "list the editions of each book's work from OpenLibrary, up to "+strconv.Itoa(openlibrary.MaxWorkEditions)+", one per row with its publisher, date, binding and language, on an "+output.SheetEditions+" sheet, or in a <output>_editions file for formats without sheets; implies -isbn-family, whose columns list their ISBNs")
+	fs.StringVar(&f.update, "update", "", "enrich again the rows of this enriched file that have no enrichment yet, filling only empty and N/A cells, and rewrite it in place")
+	fs.StringVar(&f.manifest, "manifest", "", "write a manifest of the run to this file, for \"booktool replay\" to reproduce it; the raw responses are archived in -record-dir (default: the manifest's name with "+responsesSuffix+")")
+	f.order = make(orderFlag)
+	fs.Var(f.order, "order", "row order: input (buffer finished rows until their turn) or completion (write each row when done, with an \""+columns.RowHeader+"\" column); also format=order, e.g. jsonl=completion (repeatable)")
+}
+
+// stdout is the -output value naming standard output.
+const stdout = "-"
+
+func defaultOutputPath(in string, sample bool) string {
+	suffix := "_enriched.xlsx"
+	if sample {
+		suffix = "_sample.xlsx"
+	}
+	return strings.TrimSuffix(in, filepath.Ext(in)) + suffix
+}
+
+// sheetNames returns the worksheets selected with -sheets.
+func (f *runFlags) sheetNames() []string {
+	var names []string
+	for _, s := range strings.Split(f.sheetList, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			names = append(names, s)
+		}
+	}
+	return names
+}
+
+// multiSheet reports whether the input is read from several worksheets.
+func (f *runFlags) multiSheet() bool {
+	return f.allSheets || len(f.sheetNames()) > 0
+}
+
+// openInput opens the input file or Google Sheet, from the first row not
+// skipped and limited to the sample size if one is set.
+func (f *runFlags) openInput() (input.Reader, error) {
+	opts := input.Options{Sheet: f.sheet, Sheets: f.sheetNames(), AllSheets: f.allSheets, Columns: f.columns}
+	var r input.Reader
+	if ref, ok := gsheets.ParseRef(f.input); ok {
+		if f.multiSheet() {
+			return nil, errors.New("-sheets and -all-sheets need an xlsx input")
+		}
+		c, err := f.sheets.get()
+		if err != nil {
+			return nil, err
+		}
+		if ref.Sheet == "" {
+			ref.Sheet = f.sheet
+		}
+		recs, err := c.Read(context.Background(), ref)
+		if err != nil {
+			return nil, err
+		}
+		if r, err = input.FromRecords(recs, opts); err != nil {
+			return nil, err
+		}
+	} else {
+		var err error
+		if r, err = input.Open(f.input, f.inputFormat, opts); err != nil {
+			return nil, err
+		}
+	}
+	if f.skipRows > 0 {
+		r = input.Skip(r, f.skipRows)
+	}
+	if f.sample > 0 {
+		r = input.Limit(r, f.sample)
+	}
+	return r, nil
+}
+
+// defaultOutput names the output when none is given: a file next to the
+// input, a new tab in the input spreadsheet, or standard output in a
+// pipeline.
+func (f *runFlags) defaultOutput() (string, error) {
+	if f.input == input.Stdin {
+		return stdout, nil
+	}
+	ref, ok := gsheets.ParseRef(f.input)
+	if !ok {
+		return defaultOutputPath(f.input, f.sample > 0), nil
+	}
+	c, err := f.sheets.get()
+	if err != nil {
+		return "", err
+	}
+	if ref.Sheet == "" {
+		ref.Sheet = f.sheet
+	}
+	if ref, err = c.Resolve(context.Background(), ref); err != nil {
+		return "", err
+	}
+	suffix := " enriched"
+	if f.sample > 0 {
+		suffix = " sample"
+	}
+	ref.Sheet += suffix
+	return ref.String(), nil
+}
+
+// createOutput returns the writer for the output file or Google Sheet,
+// and a function closing the file after the writer. With
+// -min-confidence, rows needing review go to a Review sheet, a
+// "review" tab next to a Google Sheets output, or a _review file next
+// to outputs of other formats; a stream keeps them. With -editions, the
+// other editions of each book go to an Editions sheet, an "editions"
+// tab or an _editions file the same way, in CSV for formats that are
+// not tables; a stream has none.
+func (f *runFlags) createOutput() (output.Writer, func() error, error) {
+	if ref, ok := gsheets.ParseRef(f.output); ok {
+		c, err := f.sheets.get()
+		if err != nil {
+			return nil, nil, err
+		}
+		gw, err := gsheets.NewWriter(c, ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		var w output.Writer = gw
+		if f.minConfidence > 0 {
+			rref := ref
+			rref.Sheet += " review"
+			rw, err := gsheets.NewWriter(c, rref)
+			if err != nil {
+				return nil, nil, err
+			}
+			f.review = rref.String()
+			w = output.Split(w, rw)
+		}
+		if f.editions {
+			ref.Sheet += " editions"
+			ew, err := gsheets.NewWriter(c, ref)
+			if err != nil {
+				return nil, nil, err
+			}
+			f.editionsOut = ref.String()
+			w = output.SplitEditions(w, ew)
+		}
+		return w, func() error { return nil }, nil
+	}
+	format, err := f.resolveOutputFormat()
+	if err != nil {
+		return nil, nil, err
+	}
+	if f.output == stdout {
+		w, err := format.New(os.Stdout)
+		return w, func() error { return nil }, err
+	}
+	w, closeOut, err := createFile(format, f.output)
+	if err != nil {
+		return nil, nil, err
+	}
+	if format.Name == "xlsx" {
+		if f.minConfidence > 0 {
+			f.review = fmt.Sprintf("%s (%s)", f.output, output.SheetReview)
+		}
+		if f.editions {
+			f.editionsOut = fmt.Sprintf("%s (%s)", f.output, output.SheetEditions)
+		}
+		return w, closeOut, nil
+	}
+	if f.minConfidence > 0 {
+		f.review = sidePath(f.output, "_review", "")
+		rw, closeReview, err := createFile(format, f.review)
+		if err != nil {
+			closeOut()
+			return nil, nil, err
+		}
+		w, closeOut = output.Split(w, rw), joinClose(closeOut, closeReview)
+	}
+	if f.editions {
+		ef := format
+		if !tableFormats[format.Name] {
+			ef, _ = output.Lookup("csv")
+		}
+		f.editionsOut = sidePath(f.output, "_editions", ef.Extensions[0])
+		ew, closeEditions, err := createFile(ef, f.editionsOut)
+		if err != nil {
+			closeOut()
+			return nil, nil, err
+		}
+		w, closeOut = output.SplitEditions(w, ew), joinClose(closeOut, closeEditions)
+	}
+	return w, closeOut, nil
+}
+
+// tableFormats are the output formats writing the header and cells of
+// records, which can hold the editions lists.
+var tableFormats = map[string]bool{"csv": true, "tsv": true, "jsonl": true}
+
+// sidePath returns the path of a file written next to the output path,
+// with suffix added to its name and ext replacing its extension unless
+// empty.
+func sidePath(path, suffix, ext string) string {
+	if ext == "" {
+		ext = filepath.Ext(path)
+	}
+	return strings.TrimSuffix(path, filepath.Ext(path)) + suffix + ext
+}
+
+// joinClose returns a function calling each of closers.
+func joinClose(closers ...func() error) func() error {
+	return func() error {
+		var errs []error
+		for _, c := range closers {
+			errs = append(errs, c())
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// createFile creates path and a writer of format on it.
+func createFile(format *output.Format, path string) (output.Writer, func() error, error) {
+	out, err := os.Create(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	w, err := format.New(out)
+	if err != nil {
+		out.Close()
+		return nil, nil, err
+	}
+	return w, out.Close, nil
+}
+
+func cmdRun(args []string) error {
+	return enrichRun(args, false)
+}
+
+// enrichRun runs the run command. A replay of a recorded run leaves the
+// run history, the checkpoints and the manifest alone.
+func enrichRun(args []string, replay bool) error {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	var f runFlags
+	f.register(fs)
+	sealer, err := sealerFor(flagArg(args, "key-file"))
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(flagArg(args, "config"), sealer)
+	if err != nil {
+		return err
+	}
+	f.apply(cfg)
+	f.http.cipher, f.sheets.sealer = sealer, sealer
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	f.resolveKeys()
+	if f.minConfidence < 0 || f.minConfidence > 1 {
+		return fmt.Errorf("invalid -min-confidence %g (want 0 to 1)", f.minConfidence)
+	}
+	f.isbnFamily = f.isbnFamily || f.editions
+	if f.allSheets && f.sheetList != "" {
+		return errors.New("use either -sheets or -all-sheets")
+	}
+	if f.update != "" && f.multiSheet() {
+		return errors.New("-update rewrites a single sheet; it cannot be used with -sheets or -all-sheets")
+	}
+	if f.update != "" {
+		f.input, f.output = f.update, f.update
+	}
+	if f.input == "" && fs.NArg() > 0 {
+		f.input = fs.Arg(0)
+	}
+	if f.input == "" {
+		fs.Usage()
+		return errors.New(i18n.T("no input file given"))
+	}
+	if f.resume && !replay {
+		return resume(f.input)
+	}
+	if f.manifest != "" {
+		if err := f.checkManifest(); err != nil {
+			return err
+		}
+	}
+	var extra []string
+	_, in := gsheets.ParseRef(f.input)
+	_, out := gsheets.ParseRef(f.output)
+	if in || out {
+		extra = gsheets.Hosts()
+	}
+	if err := f.restrictHosts(extra...); err != nil {
+		return err
+	}
+	if f.output == "" {
+		if f.output, err = f.defaultOutput(); err != nil {
+			return err
+		}
+	}
+
+	table, err := f.table()
+	if err != nil {
+		return err
+	}
+	metrics := new(httpx.Metrics)
+	e, budgets, err := f.enricher(f.providers, f.http.client(metrics))
+	if err != nil {
+		return err
+	}
+	if f.editions && e.Family != nil {
+		e.Family.All = true
+	}
+	// Pipelines get each row as soon as it is done unless an order was
+	// asked for.
+	order, ok := f.order.lookup(f.outputFormatName())
+	if !ok && f.output == stdout {
+		order = enrich.CompletionOrder
+	}
+	if e.Order = order; e.Order == enrich.CompletionOrder {
+		table = columns.WithRowNumber(table)
+	}
+	// Only workbooks keep the input sheets apart.
+	if f.multiSheet() && f.outputFormatName() != "xlsx" {
+		table = columns.WithSheet(table)
+	}
+	names := make([]string, len(e.Providers))
+	for i, p := range e.Providers {
+		names[i] = p.Name()
+	}
+	items := make([]string, len(e.Items))
+	for i, p := range e.Items {
+		items[i] = p.Name()
+	}
+	if err := confirmCost(&f, names, items); err != nil {
+		return err
+	}
+	if f.update != "" {
+		err := cmdUpdate(&f, e, table, names)
+		if err == nil {
+			printMetrics(os.Stderr, metrics)
+			printBudgets(os.Stderr, budgets)
+		}
+		return err
+	}
+
+	rows, err := f.openInput()
+	if err != nil {
+		return i18n.Errorf("open input: %w", err)
+	}
+	defer rows.Close()
+	if tab, ok := input.AsTabular(rows); ok && f.passThrough {
+		table = columns.PassThrough(table, tab.Header())
+	}
+
+	db, dbw, err := f.db.open()
+	if err != nil {
+		return err
+	}
+	if db != nil {
+		defer db.Close()
+	}
+	w, closeOut, err := f.createOutput()
+	if err != nil {
+		return err
+	}
+	defer closeOut()
+	if dbw != nil {
+		w = output.Multi(w, dbw)
+	}
+	sw, err := f.site.open()
+	if err != nil {
+		return err
+	}
+	if sw != nil {
+		w = output.Multi(w, sw)
+	}
+	if err := w.WriteHeader(table.Header()); err != nil {
+		return err
+	}
+
+	start := time.Now()
+	bounded := &boundedReader{Reader: rows, maxRows: f.maxRows}
+	if f.maxDuration > 0 {
+		bounded.deadline = start.Add(f.maxDuration)
+	}
+	prog := newProgress(f.plain)
+	if !replay {
+		defer bounded.stopOnSignal(prog.plain)()
+	}
+	var done, failed, dups, warned, pending, review, timedOut, editions, invalid, rejected int
+	err = e.Run(context.Background(), bounded, func(res *enrich.Result) error {
+		done++
+		if res.Err != nil {
+			failed++
+		}
+		if errors.Is(res.Err, httpx.ErrOffline) {
+			pending++
+		}
+		if errors.Is(res.Err, enrich.ErrRowTimeout) {
+			timedOut++
+		}
+		if res.DuplicateOf >= 0 {
+			dups++
+		}
+		if res.Book != nil && len(res.Book.Warnings) > 0 {
+			warned++
+		}
+		if res.Book != nil && len(res.Book.Violations) > 0 {
+			invalid++
+		}
+		if errors.Is(res.Err, validate.ErrInvalid) {
+			rejected++
+		}
+		rec := &output.Record{
+			Index:    res.Row.Index,
+			Book:     res.Book,
+			Cells:    table.Row(res),
+			Err:      res.Err,
+			Sheet:    res.Row.Sheet,
+			Line:     res.Row.Line(),
+			Editions: f.editions,
+		}
+		if f.review != "" && res.Book != nil && res.Book.Quality() < f.minConfidence {
+			rec.Review = true
+			review++
+		}
+		if f.editions && res.Book != nil {
+			editions += len(res.Book.Siblings)
+		}
+		prog.update(i18n.Sprintf("%d rows enriched, %d failed", done, failed))
+		return w.Write(rec)
+	})
+	prog.finish()
+	if err == nil {
+		err = w.Close()
+	}
+	if err == nil {
+		err = closeOut()
+	}
+	if !replay {
+		run := state.Run{Start: start, Duration: time.Since(start), Input: f.input, Output: f.output, Providers: names, Rows: done, Failed: failed}
+		if err != nil {
+			run.Error = err.Error()
+		}
+		if herr := stateDir.AddRun(run); herr != nil {
+			i18n.Fprintf(os.Stderr, "cannot record the run in the history: %v\n", herr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+	i18n.Fprintf(os.Stderr, "wrote %s (%d rows, %d failed)\n", f.output, done, failed)
+	// Profiles have neither column.
+	if dups > 0 && f.profile == "" {
+		i18n.Fprintf(os.Stderr, "%d duplicate rows reused an earlier lookup; see the %q column\n", dups, columns.DuplicateHeader)
+	}
+	if warned > 0 && f.profile == "" {
+		i18n.Fprintf(os.Stderr, "%d rows have warnings; see the %q column\n", warned, columns.WarningsHeader)
+	}
+	if invalid > 0 && f.profile == "" {
+		i18n.Fprintf(os.Stderr, "%d rows break validation rules; see the %q column\n", invalid, columns.ViolationsHeader)
+	}
+	if rejected > 0 {
+		i18n.Fprintf(os.Stderr, "%d rows broke validation rules and are reported as failed\n", rejected)
+	}
+	if timedOut > 0 {
+		i18n.Fprintf(os.Stderr, "%d rows timed out after -row-timeout %s and are reported as failed\n", timedOut, f.rowTimeout)
+	}
+	if review > 0 {
+		i18n.Fprintf(os.Stderr, "%d rows scored below -min-confidence %.2f; they are in %s for review\n", review, f.minConfidence, f.review)
+	}
+	if editions > 0 && f.editionsOut != "" {
+		i18n.Fprintf(os.Stderr, "%d other editions listed in %s\n", editions, f.editionsOut)
+	}
+	if pending > 0 && !replay {
+		i18n.Fprintf(os.Stderr, "%d rows are pending because their lookups are not in the cache; to fill them, run online with -update %s\n", pending, f.output)
+	}
+	if dbw != nil {
+		i18n.Fprintf(os.Stderr, "upserted into %s table %q", f.db.driver, f.db.table)
+		if n := dbw.Skipped(); n > 0 {
+			i18n.Fprintf(os.Stderr, " (%d rows without ISBN skipped)", n)
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+	if sw != nil {
+		i18n.Fprintf(os.Stderr, "wrote the sitemap and SEO metadata to %s", sw.Dir())
+		if n := sw.Skipped(); n > 0 {
+			i18n.Fprintf(os.Stderr, " (%d rows without ISBN skipped)", n)
+		}
+		fmt.Fprintln(os.Stderr)
+	}
+	printMetrics(os.Stderr, metrics)
+	printBudgets(os.Stderr, budgets)
+	if f.sample > 0 {
+		abs, _ := filepath.Abs(f.output)
+		i18n.Fprintf(os.Stderr, "sample of %d rows written to %s\ncheck it, then run again without -sample for the full file\n", done, abs)
+	}
+	if replay {
+		return nil
+	}
+	if f.manifest != "" {
+		if err := writeManifest(&f, args, start, done, failed); err != nil {
+			return err
+		}
+		i18n.Fprintf(os.Stderr, "wrote the manifest %s; replay the run with \"booktool replay %s\"\n", f.manifest, f.manifest)
+	}
+	if bounded.stopped {
+		checkpoint(&f, args, bounded, done)
+	} else if err := stateDir.RemoveCheckpoint(f.input); err != nil {
+		i18n.Fprintf(os.Stderr, "cannot remove the checkpoint: %v\n", err)
+	}
+	if f.open && f.output != stdout {
+		target := f.output
+		if ref, ok := gsheets.ParseRef(f.output); ok {
+			target = "https://docs.google.com/spreadsheets/d/" + ref.SpreadsheetID
+		}
+		if err := openFile(target); err != nil {
+			i18n.Fprintf(os.Stderr, "cannot open %s: %v\n", f.output, err)
+		}
+	}
+	return nil
+}
+
+// checkpoint records where a run stopped by -max-rows, -max-duration or
+// a signal left off and prints the command that continues it, writing
+// to a new part of the output.
+func checkpoint(f *runFlags, args []string, b *boundedReader, done int) {
+	next := continueArgs(args, map[string]string{
+		"skip-rows": strconv.Itoa(b.next),
+		"output":    nextPart(f.output),
+	}, "skip-rows", "output")
+	dir, _ := os.Getwd()
+	cp := state.Checkpoint{Input: f.input, Output: f.output, Next: b.next, Args: next, Dir: dir, Time: time.Now()}
+	if err := stateDir.SaveCheckpoint(cp); err != nil {
+		i18n.Fprintf(os.Stderr, "cannot save the checkpoint: %v\n", err)
+	}
+	cmd := shellCommand(append([]string{os.Args[0], "run"}, next...)...)
+	if b.interrupted.Load() {
+		i18n.Fprintf(os.Stderr, "interrupted after %d rows; continue with:\n  %s\n", done, cmd)
+	} else {
+		limit := "-max-duration"
+		if b.rowsDone() {
+			limit = "-max-rows"
+		}
+		i18n.Fprintf(os.Stderr, "stopped at the %s limit after %d rows; continue with:\n  %s\n", limit, done, cmd)
+	}
+	i18n.Fprintf(os.Stderr, "or with:\n  %s\n", shellCommand(os.Args[0], "run", "-resume", f.input))
+}
+
+// resume continues the stopped run of input from its checkpoint, in the
+// run's working directory and with its flags.
+func resume(input string) error {
+	cp, ok, err := stateDir.LoadCheckpoint(input)
+	if err != nil {
+		return i18n.Errorf("read the checkpoint: %w", err)
+	}
+	if !ok {
+		return i18n.Errorf("no stopped run of %s to resume", input)
+	}
+	if cp.Dir != "" {
+		if err := os.Chdir(cp.Dir); err != nil {
+			return err
+		}
+	}
+	i18n.Fprintf(os.Stderr, "resuming %s after its first %d rows, writing to %s\n", cp.Input, cp.Next, flagArg(cp.Args, "output"))
+	return enrichRun(cp.Args, false)
+}
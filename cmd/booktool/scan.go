@@ -0,0 +1,129 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+)
+
+// scanFlags holds the flags of the scan command.
+type scanFlags struct {
+	enrichFlags
+	config       string
+	output       string
+	outputFormat string
+}
+
+func (f *scanFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.config, "config", "", "configuration file (default: "+config.DefaultPath+" if present)")
+	fs.StringVar(&f.output, "output", "", "output file (default: scan-<date>-<time>.xlsx)")
+	fs.StringVar(&f.outputFormat, "output-format", "", "output format; inferred from the output extension by default")
+	f.enrichFlags.register(fs)
+}
+
+// cmdScan builds an enriched spreadsheet from ISBNs read on standard
+// input, one per line, as typed by a USB barcode scanner. Each book is
+// looked up as soon as it is scanned and confirmed on the terminal.
+func cmdScan(args []string) error {
+	fs := flag.NewFlagSet("scan", flag.ContinueOnError)
+	var f scanFlags
+	f.register(fs)
+	sealer, err := sealerFor(flagArg(args, "key-file"))
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(flagArg(args, "config"), sealer)
+	if err != nil {
+		return err
+	}
+	f.enrichFlags.apply(cfg)
+	f.http.cipher = sealer
+	if cfg.Output.Format != "" {
+		f.outputFormat = cfg.Output.Format
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	f.resolveKeys()
+	if err := f.restrictHosts(); err != nil {
+		return err
+	}
+	if f.output == "" {
+		f.output = time.Now().Format("scan-20060102-1504.xlsx")
+	}
+	format, err := output.Resolve(f.outputFormat, f.output)
+	if err != nil {
+		return err
+	}
+	table, err := f.table()
+	if err != nil {
+		return err
+	}
+	metrics := new(httpx.Metrics)
+	e, budgets, err := f.enricher(f.providers, f.http.client(metrics))
+	if err != nil {
+		return err
+	}
+	// Scanned books are confirmed one by one; there is nothing to gain
+	// from looking up several at once.
+	e.Workers = 1
+
+	out, err := os.Create(f.output)
+	if err != nil {
+		return err
+	}
+	defer out.Close()
+	w, err := format.New(out)
+	if err != nil {
+		return err
+	}
+	if err := w.WriteHeader(table.Header()); err != nil {
+		return err
+	}
+	rows, _ := input.NewISBNList(os.Stdin, input.Options{})
+	interactive := isTerminal(os.Stdin)
+	if interactive {
+		fmt.Fprintln(os.Stderr, i18n.T("Scan barcodes or type ISBNs, one per line. Press Ctrl-D when done."))
+	}
+	// A bell makes failures noticeable without looking at the screen.
+	bell := interactive && !f.plain && isTerminal(os.Stderr)
+	var done, failed int
+	err = e.Run(context.Background(), rows, func(res *enrich.Result) error {
+		done++
+		switch {
+		case res.Err != nil:
+			failed++
+			if bell {
+				fmt.Fprint(os.Stderr, "\a")
+			}
+			i18n.Fprintf(os.Stderr, "%d. %s: not found (%v)\n", done, res.Row.ISBN, res.Err)
+		case res.DuplicateOf >= 0:
+			i18n.Fprintf(os.Stderr, "%d. %s: %s (again)\n", done, res.Row.ISBN, res.Book.FullTitle())
+		default:
+			i18n.Fprintf(os.Stderr, "%d. %s: %s\n", done, res.Row.ISBN, res.Book.FullTitle())
+		}
+		return w.Write(&output.Record{Index: res.Row.Index, Book: res.Book, Cells: table.Row(res), Err: res.Err})
+	})
+	if err == nil {
+		err = w.Close()
+	}
+	if err == nil {
+		err = out.Close()
+	}
+	if err != nil {
+		return err
+	}
+	i18n.Fprintf(os.Stderr, "wrote %s (%d rows, %d failed)\n", f.output, done, failed)
+	printMetrics(os.Stderr, metrics)
+	printBudgets(os.Stderr, budgets)
+	return nil
+}
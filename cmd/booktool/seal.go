@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/SouadAli10/book_scrapping_tool/seal"
+)
+
+// passphraseEnv holds the passphrase for encrypted files, as an
+// alternative to -key-file.
+const passphraseEnv = "BOOKTOOL_PASSPHRASE"
+
+const keyFileUsage = "key file for encrypting the cache and reading encrypted configuration and credentials (or set $" + passphraseEnv + ")"
+
+// sealerFor returns the Sealer for a key file or the passphrase in the
+// environment, or nil when neither is given.
+func sealerFor(keyFile string) (*seal.Sealer, error) {
+	if keyFile != "" {
+		return seal.LoadKeyFile(keyFile)
+	}
+	if p := os.Getenv(passphraseEnv); p != "" {
+		return seal.FromPassphrase(p)
+	}
+	return nil, nil
+}
+
+// writeFileAtomic replaces path with data without leaving a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".booktool-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func cmdSeal(args []string) error {
+	const usage = "usage: booktool seal keygen | encrypt [-key-file f] [-o out] file | decrypt [-key-file f] [-o out] file"
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	op, args := args[0], args[1:]
+	if op == "keygen" {
+		k, err := seal.NewKey()
+		if err != nil {
+			return err
+		}
+		fmt.Print(k)
+		return nil
+	}
+	if op != "encrypt" && op != "decrypt" {
+		return errors.New(usage)
+	}
+	fs := flag.NewFlagSet("seal "+op, flag.ContinueOnError)
+	registerLang(fs)
+	keyFile := fs.String("key-file", "", "key file (default: passphrase from $"+passphraseEnv+")")
+	out := fs.String("o", "", "output file (default: replace the input)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return errors.New(usage)
+	}
+	in := fs.Arg(0)
+	s, err := sealerFor(*keyFile)
+	if err != nil {
+		return err
+	}
+	if s == nil {
+		return errors.New("give -key-file or set $" + passphraseEnv)
+	}
+	data, err := os.ReadFile(in)
+	if err != nil {
+		return err
+	}
+	if op == "encrypt" {
+		if seal.IsSealed(data) {
+			return fmt.Errorf("%s is already encrypted", in)
+		}
+		data, err = s.Seal(data)
+	} else {
+		data, err = s.Open(data)
+	}
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		*out = in
+	}
+	return writeFileAtomic(*out, data)
+}
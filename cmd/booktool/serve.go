@@ -0,0 +1,205 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+	"github.com/SouadAli10/book_scrapping_tool/jobs"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/server"
+)
+
+// serveFlags holds the flags of the serve command.
+type serveFlags struct {
+	enrichFlags
+	config  string
+	addr    string
+	jobsDB  string
+	maxJobs int
+}
+
+func (f *serveFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.config, "config", "", "configuration file (default: "+config.DefaultPath+" if present)")
+	fs.StringVar(&f.addr, "addr", ":8080", "address to listen on")
+	fs.StringVar(&f.jobsDB, "jobs-db", "", "SQLite file for the job history (default: keep it in memory)")
+	fs.IntVar(&f.maxJobs, "max-jobs", server.DefaultMaxJobs, "enrichment jobs run at once; later uploads wait")
+	f.enrichFlags.register(fs)
+}
+
+// healthChecks builds the readiness checks: the response cache must be
+// writable and every configured provider must answer.
+func (f *serveFlags) healthChecks() ([]server.Check, error) {
+	var checks []server.Check
+	if !f.http.noCache && f.http.cacheDir != "" {
+		dc := &httpx.DiskCache{Dir: f.http.cacheDir}
+		checks = append(checks, server.Check{Name: "cache", Run: func(context.Context) error { return dc.Ping() }})
+	}
+	ps, err := newProviders(f.providers, &providerSettings{})
+	if err != nil {
+		return nil, err
+	}
+	// Probes bypass the cache and retries: they must reflect the network
+	// as it is now.
+	c := &http.Client{Timeout: f.http.timeout}
+	for _, p := range ps {
+		url := providerTable[p.Name()].probe
+		if url == "" {
+			continue
+		}
+		checks = append(checks, server.Check{Name: "provider:" + p.Name(), Run: func(ctx context.Context) error {
+			return provider.Ping(ctx, c, url)
+		}})
+	}
+	return checks, nil
+}
+
+// sqliteDriver is the database/sql driver used for the job history. It
+// is registered by sqlite.go in builds with -tags sqlite.
+const sqliteDriver = "sqlite"
+
+func (f *serveFlags) openJobs() (jobs.Store, error) {
+	if f.jobsDB == "" {
+		return new(jobs.MemoryStore), nil
+	}
+	if !slices.Contains(sql.Drivers(), sqliteDriver) {
+		return nil, errors.New("-jobs-db needs a booktool built with -tags sqlite")
+	}
+	return jobs.OpenSQL(sqliteDriver, f.jobsDB)
+}
+
+// serverUsers converts the configured users; the configuration has
+// already been validated.
+func serverUsers(cs []config.User) ([]server.User, error) {
+	users := make([]server.User, len(cs))
+	for i, c := range cs {
+		role, err := server.ParseRole(c.Role)
+		if err != nil {
+			return nil, err
+		}
+		users[i] = server.User{Name: c.Name, Token: c.Secret(), Role: role}
+	}
+	return users, nil
+}
+
+// validateProviders checks a provider list set through the admin API.
+func validateProviders(names []string) error {
+	for _, n := range names {
+		if e, ok := providerTable[canonicalProvider(n)]; !ok || e.new == nil {
+			return fmt.Errorf("unknown provider %q (available: %s)", n, strings.Join(providerNames(), ", "))
+		}
+	}
+	return nil
+}
+
+func cmdServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	var f serveFlags
+	f.register(fs)
+	sealer, err := sealerFor(flagArg(args, "key-file"))
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig(flagArg(args, "config"), sealer)
+	if err != nil {
+		return err
+	}
+	f.http.cipher = sealer
+	f.enrichFlags.apply(cfg)
+	if cfg.Server.MaxJobs > 0 {
+		f.maxJobs = cfg.Server.MaxJobs
+	}
+	if cfg.Server.Addr != "" {
+		f.addr = cfg.Server.Addr
+	}
+	if cfg.Server.JobsDB != "" {
+		f.jobsDB = cfg.Server.JobsDB
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	f.resolveKeys()
+	if err := f.restrictHosts(); err != nil {
+		return err
+	}
+	table, err := f.table()
+	if err != nil {
+		return err
+	}
+	httpMetrics, lookups := new(httpx.Metrics), new(provider.Stats)
+	httpClient := f.http.client(httpMetrics)
+	checks, err := f.healthChecks()
+	if err != nil {
+		return err
+	}
+	store, err := f.openJobs()
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	if p, ok := store.(interface{ Ping(context.Context) error }); ok {
+		checks = append(checks, server.Check{Name: "jobs", Run: p.Ping})
+	}
+	users, err := serverUsers(cfg.Server.Users)
+	if err != nil {
+		return err
+	}
+	if len(users) == 0 {
+		fmt.Fprintln(os.Stderr, "warning: no users configured; the server is open to anyone who can reach it")
+	}
+	s := &server.Server{
+		Health:   &server.Health{Checks: checks},
+		Jobs:     store,
+		Settings: server.NewSettings(strings.Split(f.providers, ","), validateProviders),
+		Users:    users,
+		Log:      log.New(os.Stderr, "audit: ", log.LstdFlags),
+		Enricher: func(providers []string) (*enrich.Enricher, error) {
+			e, _, err := f.enricher(strings.Join(providers, ","), httpClient)
+			if err != nil {
+				return nil, err
+			}
+			for i, p := range e.Providers {
+				e.Providers[i] = lookups.Instrument(p)
+			}
+			return e, nil
+		},
+		Table:       table,
+		PassThrough: f.passThrough,
+		MaxJobs:     f.maxJobs,
+		Version:     buildVersion(),
+		Lookups:     lookups,
+		HTTPMetrics: httpMetrics,
+	}
+	s.Health.Checks = append(s.Health.Checks, server.Stalled("jobs-queue", 5*time.Minute, s.QueueProgress))
+	hs := &http.Server{Addr: f.addr, Handler: s.Handler(), ReadHeaderTimeout: 10 * time.Second}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	errc := make(chan error, 1)
+	go func() { errc <- hs.ListenAndServe() }()
+	fmt.Fprintf(os.Stderr, "listening on %s\n", f.addr)
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+	shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := hs.Shutdown(shutdown); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
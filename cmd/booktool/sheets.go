@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/gsheets"
+	"github.com/SouadAli10/book_scrapping_tool/seal"
+	"github.com/SouadAli10/book_scrapping_tool/state"
+)
+
+// tokenEnv holds an OAuth access token for Google Sheets, e.g. from
+// "gcloud auth print-access-token", as an alternative to a service
+// account key.
+const tokenEnv = "GOOGLE_OAUTH_ACCESS_TOKEN"
+
+// sheetsFlags configures Google Sheets input and output.
+type sheetsFlags struct {
+	credentials string
+	// sealer opens an encrypted credentials file.
+	sealer *seal.Sealer
+	client *gsheets.Client
+}
+
+func (f *sheetsFlags) register(fs *flag.FlagSet) {
+	def := os.Getenv("GOOGLE_APPLICATION_CREDENTIALS")
+	if def == "" {
+		def = stateFile(state.Credentials, googleCredentialsFile)
+	}
+	fs.StringVar(&f.credentials, "google-credentials", def,
+		"service account key for Google Sheets input and output (default: "+googleCredentialsFile+" in the state directory's credentials, or set $"+tokenEnv+")")
+}
+
+func (f *sheetsFlags) apply(c config.GoogleSheets) {
+	if c.CredentialsFile != "" {
+		f.credentials = c.CredentialsFile
+	}
+}
+
+// get returns the Sheets client, creating it on first use.
+func (f *sheetsFlags) get() (*gsheets.Client, error) {
+	if f.client != nil {
+		return f.client, nil
+	}
+	var ts gsheets.TokenSource
+	switch {
+	case os.Getenv(tokenEnv) != "":
+		ts = gsheets.StaticToken(os.Getenv(tokenEnv))
+	case f.credentials != "":
+		data, err := f.sealer.ReadFile(f.credentials)
+		if err != nil {
+			return nil, err
+		}
+		sa, err := gsheets.ParseServiceAccount(data)
+		if err != nil {
+			return nil, err
+		}
+		ts = sa
+	default:
+		return nil, errors.New("Google Sheets needs -google-credentials (a service account key) or $" + tokenEnv)
+	}
+	f.client = &gsheets.Client{Tokens: ts}
+	return f.client, nil
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"text/template"
+
+	"github.com/SouadAli10/book_scrapping_tool/config"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+)
+
+// siteFlags configures the optional sitemap and SEO metadata export.
+type siteFlags struct {
+	dir, url, title string
+}
+
+func (f *siteFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.dir, "site-dir", "", "also write a sitemap.xml and per-book SEO metadata ("+output.SEODir+"/<isbn>.json) to this directory, for a static site builder")
+	fs.StringVar(&f.url, "site-url", "", "Go template of each book's canonical URL, e.g. \"https://shop.example/books/{{.ISBN13}}/{{slug .Title}}\"")
+	fs.StringVar(&f.title, "site-title", output.DefaultSEOTitle, "Go template of the title tags, executed with the book record")
+}
+
+func (f *siteFlags) apply(c config.Site) {
+	if c.Dir != "" {
+		f.dir = c.Dir
+	}
+	if c.URL != "" {
+		f.url = c.URL
+	}
+	if c.Title != "" {
+		f.title = c.Title
+	}
+}
+
+// open returns the site writer, or nil when no directory is set.
+func (f *siteFlags) open() (*output.SiteWriter, error) {
+	if f.dir == "" {
+		return nil, nil
+	}
+	if f.url == "" {
+		return nil, errors.New(i18n.T("-site-dir needs -site-url, the template of the books' canonical URLs"))
+	}
+	var canonical, title *template.Template
+	var err error
+	if canonical, err = output.ParseSiteTemplate("-site-url", f.url); err != nil {
+		return nil, err
+	}
+	if title, err = output.ParseSiteTemplate("-site-title", f.title); err != nil {
+		return nil, err
+	}
+	return output.NewSite(f.dir, canonical, title)
+}
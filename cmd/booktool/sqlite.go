@@ -0,0 +1,7 @@
+//go:build sqlite
+
+package main
+
+// Registers the pure-Go SQLite driver for the serve command's job
+// history and the calibre command. Build with -tags sqlite to include it.
+import _ "modernc.org/sqlite"
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/state"
+)
+
+// stateDir is resolved from -state-dir before the command parses its
+// flags, since loading data tables and flag defaults depend on it.
+var stateDir state.Dir
+
+// registerStateDir defines -state-dir on commands that use the state
+// directory. The value is read by main; see stateDir.
+func registerStateDir(fs *flag.FlagSet) {
+	fs.String("state-dir", stateDir.Root, "state directory for the cache, checkpoints, history, credentials and data tables (or set $"+state.EnvDir+")")
+}
+
+// googleCredentialsFile is the service account key looked up in the
+// credentials area when none is given.
+const googleCredentialsFile = "google-service-account.json"
+
+// stateFile returns the path of name in an area of the state directory
+// if it exists, or "".
+func stateFile(area, name string) string {
+	p := filepath.Join(stateDir.Path(area), name)
+	if _, err := os.Stat(p); err != nil {
+		return ""
+	}
+	return p
+}
+
+func cmdState(args []string) error {
+	fs := flag.NewFlagSet("state", flag.ContinueOnError)
+	registerLang(fs)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: booktool state [flags]               show the state directory\n       booktool state clean [flags] [area...] remove cached and temporary state")
+		fs.PrintDefaults()
+	}
+	registerStateDir(fs)
+	clean := len(args) > 0 && args[0] == "clean"
+	if clean {
+		args = args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if clean {
+		if err := stateDir.Clean(fs.Args()...); err != nil {
+			return err
+		}
+		what := "cache, checkpoints and history"
+		if fs.NArg() > 0 {
+			what = strings.Join(fs.Args(), ", ")
+		}
+		fmt.Printf("cleaned %s in %s\n", what, stateDir.Root)
+		return nil
+	}
+
+	fmt.Println("state directory:", stateDir.Root)
+	for _, a := range state.Areas {
+		u, err := stateDir.Usage(a.Name)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("  %-12s %-36s %s\n", a.Name, a.Summary, u)
+	}
+	runs, err := stateDir.Runs()
+	if err != nil {
+		return err
+	}
+	if len(runs) > 0 {
+		fmt.Println("\nrecent runs:")
+		for _, r := range runs[max(0, len(runs)-5):] {
+			status := fmt.Sprintf("%d rows, %d failed", r.Rows, r.Failed)
+			if r.Error != "" {
+				status = "error: " + r.Error
+			}
+			fmt.Printf("  %s  %s -> %s  (%s, %s)\n", r.Start.Local().Format("2006-01-02 15:04"), r.Input, r.Output, status, r.Duration.Round(time.Second))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/lang"
+	"github.com/SouadAli10/book_scrapping_tool/translate"
+)
+
+// translateEntry describes a built-in translation service.
+type translateEntry struct {
+	summary string
+	// endpoint is the default URL, for the network allowlist.
+	endpoint string
+	// keyEnv is the conventional environment variable for the key.
+	keyEnv string
+	// paid is set for services billed per call, for cost estimates.
+	paid bool
+	new  func(c *http.Client, url, key string) (translate.Service, error)
+}
+
+var translateTable = map[string]translateEntry{
+	"libretranslate": {
+		summary:  "LibreTranslate (free when self-hosted; set -translate-url)",
+		endpoint: translate.DefaultLibreTranslateURL,
+		keyEnv:   "LIBRETRANSLATE_API_KEY",
+		new: func(c *http.Client, url, key string) (translate.Service, error) {
+			return &translate.LibreTranslate{HTTPClient: c, URL: url, APIKey: key}, nil
+		},
+	},
+	"deepl": {
+		summary:  "DeepL (needs $DEEPL_AUTH_KEY)",
+		endpoint: translate.DefaultDeepLURL,
+		keyEnv:   "DEEPL_AUTH_KEY",
+		paid:     true,
+		new: func(c *http.Client, url, key string) (translate.Service, error) {
+			if key == "" {
+				return nil, fmt.Errorf("translation service deepl needs an API key in $DEEPL_AUTH_KEY")
+			}
+			return &translate.DeepL{HTTPClient: c, URL: url, APIKey: key}, nil
+		},
+	},
+	"google": {
+		summary:  "Google Cloud Translation (needs $GOOGLE_TRANSLATE_API_KEY)",
+		endpoint: translate.DefaultGoogleURL,
+		keyEnv:   "GOOGLE_TRANSLATE_API_KEY",
+		paid:     true,
+		new: func(c *http.Client, url, key string) (translate.Service, error) {
+			if key == "" {
+				return nil, fmt.Errorf("translation service google needs an API key in $GOOGLE_TRANSLATE_API_KEY")
+			}
+			return &translate.Google{HTTPClient: c, URL: url, APIKey: key}, nil
+		},
+	},
+}
+
+// translationServiceNames returns the built-in translation service
+// names, sorted.
+func translationServiceNames() []string {
+	names := make([]string, 0, len(translateTable))
+	for n := range translateTable {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// translateKey returns the key of a translation service, from the
+// configuration credentials or its environment variable.
+func (f *enrichFlags) translateKey(name string) string {
+	if k := f.keys[name]; k != "" {
+		return k
+	}
+	return os.Getenv(translateTable[name].keyEnv)
+}
+
+// translateTarget returns the ISO 639-1 code of -translate-to.
+func (f *enrichFlags) translateTarget() (string, error) {
+	l, ok := lang.Lookup(f.translateTo)
+	if !ok || l.Alpha2 == "" {
+		return "", fmt.Errorf("invalid -translate-to %q (want a language with an ISO 639-1 code, such as ar or fr)", f.translateTo)
+	}
+	return l.Alpha2, nil
+}
+
+// translator builds the translator selected by -translate, or nil.
+func (f *enrichFlags) translator(c *http.Client) (*translate.Translator, error) {
+	name := strings.ToLower(strings.TrimSpace(f.translate))
+	if name == "" {
+		return nil, nil
+	}
+	e, ok := translateTable[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown translation service %q (available: %s)", name, strings.Join(translationServiceNames(), ", "))
+	}
+	target, err := f.translateTarget()
+	if err != nil {
+		return nil, err
+	}
+	s, err := e.new(c, f.translateURL, f.translateKey(name))
+	if err != nil {
+		return nil, err
+	}
+	return &translate.Translator{Service: s, Target: target}, nil
+}
+
+// translateHost returns the host the translation service connects to.
+func (f *enrichFlags) translateHost() string {
+	e, ok := translateTable[strings.ToLower(strings.TrimSpace(f.translate))]
+	if !ok {
+		return ""
+	}
+	endpoint := f.translateURL
+	switch {
+	case endpoint != "":
+	case e.endpoint == translate.DefaultDeepLURL && strings.HasSuffix(f.translateKey("deepl"), ":fx"):
+		endpoint = translate.DefaultDeepLFreeURL
+	default:
+		endpoint = e.endpoint
+	}
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	return u.Hostname()
+}
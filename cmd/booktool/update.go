@@ -0,0 +1,182 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/i18n"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+	"github.com/SouadAli10/book_scrapping_tool/state"
+)
+
+// updatedColumns returns the positions in header of the enrichment
+// columns of table, mapped to their position in the table's rows. Input
+// columns and the bookkeeping columns are left out: they say nothing
+// about whether a row was enriched.
+func updatedColumns(header []string, table columns.Layout) map[int]int {
+	skip := append(slices.Clone(input.Columns), columns.DuplicateHeader, columns.RowHeader, columns.WarningsHeader)
+	cols := make(map[int]int)
+	for i, h := range header {
+		if slices.Contains(skip, h) {
+			continue
+		}
+		if k := slices.Index(table.Header(), h); k >= 0 {
+			cols[i] = k
+		}
+	}
+	return cols
+}
+
+func blank(cell string) bool {
+	return cell == "" || cell == columns.NA
+}
+
+// runUpdate enriches again the rows of a previously enriched file whose
+// enrichment columns are all empty or N/A, such as failed lookups and
+// rows added since, and rewrites the file. Only blank cells are filled:
+// other cells, including manual edits, are kept as they are, and so are
+// columns the layout does not produce.
+func runUpdate(f *runFlags, e *enrich.Enricher, table columns.Layout) (done, failed int, err error) {
+	r, err := input.Open(f.update, f.inputFormat, input.Options{Sheet: f.sheet, Columns: f.columns})
+	if err != nil {
+		return 0, 0, i18n.Errorf("open input: %w", err)
+	}
+	defer r.Close()
+	tab, ok := input.AsTabular(r)
+	if !ok {
+		return 0, 0, errors.New(i18n.T("-update needs a file with a header row, such as xlsx or csv"))
+	}
+	header := tab.Header()
+	cols := updatedColumns(header, table)
+	if len(cols) == 0 {
+		return 0, 0, i18n.Errorf("%s has none of the enrichment columns", f.update)
+	}
+
+	var rows, todo []*input.Row
+	for {
+		row, err := r.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, 0, err
+		}
+		// Worksheets drop trailing empty cells.
+		for len(row.Record) < len(header) {
+			row.Record = append(row.Record, "")
+		}
+		rows = append(rows, row)
+		pending := true
+		for i := range cols {
+			pending = pending && blank(row.Record[i])
+		}
+		if pending {
+			todo = append(todo, row)
+		}
+	}
+
+	results := make(map[int]*enrich.Result, len(todo))
+	prog := newProgress(f.plain)
+	err = e.Run(context.Background(), input.Slice(todo), func(res *enrich.Result) error {
+		results[res.Row.Index] = res
+		done++
+		if res.Err != nil {
+			failed++
+		}
+		prog.update(i18n.Sprintf("%d rows enriched, %d failed", done, failed))
+		return nil
+	})
+	prog.finish()
+	if err != nil {
+		return done, failed, err
+	}
+	return done, failed, writeUpdate(f, header, rows, results, cols, table)
+}
+
+// writeUpdate rewrites the updated file.
+func writeUpdate(f *runFlags, header []string, rows []*input.Row, results map[int]*enrich.Result, cols map[int]int, table columns.Layout) error {
+	recs := make([]*output.Record, len(rows))
+	for j, row := range rows {
+		rec := &output.Record{Index: row.Index, Cells: row.Record}
+		if res, ok := results[row.Index]; ok {
+			rec.Book, rec.Err = res.Book, res.Err
+			cells := table.Row(res)
+			rec.Cells = slices.Clone(row.Record)
+			for i, k := range cols {
+				if blank(rec.Cells[i]) {
+					rec.Cells[i] = cells[k]
+				}
+			}
+		}
+		recs[j] = rec
+	}
+	return rewriteFile(f.update, f.outputFormat, header, recs)
+}
+
+// rewriteFile replaces the file at path with header and recs, in the
+// named format or the one of its extension, only once the new contents
+// are complete. The file keeps its permissions.
+func rewriteFile(path, formatName string, header []string, recs []*output.Record) error {
+	format, err := output.Resolve(formatName, path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".booktool-update-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	if err := tmp.Chmod(info.Mode().Perm()); err != nil {
+		return err
+	}
+	w, err := format.New(tmp)
+	if err != nil {
+		return err
+	}
+	if err := w.WriteHeader(header); err != nil {
+		return err
+	}
+	for _, rec := range recs {
+		if err := w.Write(rec); err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// cmdUpdate runs the -update mode of the run command.
+func cmdUpdate(f *runFlags, e *enrich.Enricher, table columns.Layout, names []string) error {
+	start := time.Now()
+	done, failed, err := runUpdate(f, e, table)
+	run := state.Run{Start: start, Duration: time.Since(start), Input: f.update, Output: f.update, Providers: names, Rows: done, Failed: failed}
+	if err != nil {
+		run.Error = err.Error()
+	}
+	if herr := stateDir.AddRun(run); herr != nil {
+		i18n.Fprintf(os.Stderr, "cannot record the run in the history: %v\n", herr)
+	}
+	if err != nil {
+		return err
+	}
+	i18n.Fprintf(os.Stderr, "updated %s (%d rows enriched again, %d failed)\n", f.update, done, failed)
+	return nil
+}
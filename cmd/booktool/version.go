@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/datatable"
+)
+
+// version is set at build time with -ldflags "-X main.version=v1.2.3".
+var version string
+
+// buildVersion returns version, or the module version and VCS revision
+// recorded by the Go toolchain.
+func buildVersion() string {
+	if version != "" {
+		return version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	// Module versions of builds from a checkout already carry the
+	// revision.
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		return v
+	}
+	v := "devel"
+	var rev, modified string
+	for _, s := range info.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			rev = s.Value
+		case "vcs.modified":
+			if s.Value == "true" {
+				modified = "+dirty"
+			}
+		}
+	}
+	if rev != "" {
+		v += " (" + rev[:min(12, len(rev))] + modified + ")"
+	}
+	return v
+}
+
+func cmdVersion(args []string) error {
+	fs := flag.NewFlagSet("version", flag.ContinueOnError)
+	registerLang(fs)
+	check := fs.Bool("check", false, "check online for newer data tables")
+	index := fs.String("index", datatable.DefaultIndexURL, "URL of the published data table index (with -check)")
+	registerStateDir(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	fmt.Printf("booktool %s (%s, %s/%s)\n", buildVersion(), runtime.Version(), runtime.GOOS, runtime.GOARCH)
+
+	var idx *datatable.Index
+	if *check {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		var err error
+		if idx, err = datatable.FetchIndex(ctx, http.DefaultClient, *index); err != nil {
+			return fmt.Errorf("check data tables: %w", err)
+		}
+	}
+	fmt.Println("\ndata tables:")
+	newer := 0
+	for _, t := range datatable.All() {
+		var status []string
+		if t.Installed {
+			status = append(status, "downloaded")
+		}
+		if idx != nil {
+			if e, ok := idx.Newer(t); ok {
+				status = append(status, e.Version+" available")
+				newer++
+			} else {
+				status = append(status, "up to date")
+			}
+		}
+		line := fmt.Sprintf("  %-16s %s  %-34s %s", t.Name, t.Version, t.Summary, strings.Join(status, ", "))
+		fmt.Println(strings.TrimRight(line, " "))
+	}
+	if newer > 0 {
+		fmt.Printf("\nnewer data tables available: %d; run \"booktool update-data\" to download them\n", newer)
+	}
+	return nil
+}
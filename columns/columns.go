@@ -0,0 +1,461 @@
+// Package columns turns canonical book records into the tabular cells
+// written by the spreadsheet-style output formats.
+package columns
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/SouadAli10/book_scrapping_tool/author"
+	"github.com/SouadAli10/book_scrapping_tool/bisac"
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/customs"
+	"github.com/SouadAli10/book_scrapping_tool/lang"
+	"github.com/SouadAli10/book_scrapping_tool/shipping"
+	"github.com/SouadAli10/book_scrapping_tool/tax"
+	"github.com/SouadAli10/book_scrapping_tool/translit"
+	"github.com/SouadAli10/book_scrapping_tool/units"
+)
+
+// NA is written for fields no provider could fill.
+const NA = "N/A"
+
+// Options controls how fields are rendered.
+type Options struct {
+	// MaxDescriptionLength truncates descriptions to this many
+	// characters; 0 keeps them whole.
+	MaxDescriptionLength int
+	// Languages selects language names or codes.
+	Languages lang.Mode
+	// Authors selects author names in reading order or surname first.
+	Authors author.Format
+}
+
+// Field is one output column.
+type Field struct {
+	Header string
+	Value  func(b *book.BookInfo, o *Options) string
+}
+
+// MatchedISBNHeader is the header of the column holding the ISBN a
+// title and author search found for a row without one.
+const MatchedISBNHeader = "Matched ISBN"
+
+// QualityHeader is the header of the data quality score column.
+const QualityHeader = "Quality Score"
+
+// Default is the standard enrichment layout. It follows the input
+// columns, which already carry the ISBN.
+var Default = []Field{
+	{"Full Title", func(b *book.BookInfo, _ *Options) string { return b.FullTitle() }},
+	{"Authors", func(b *book.BookInfo, o *Options) string { return book.Join(o.Authors.FormatAll(b.Authors)) }},
+	{"Publisher", func(b *book.BookInfo, _ *Options) string { return book.Join(b.Publishers) }},
+	{"Publication Place", func(b *book.BookInfo, _ *Options) string { return book.Join(b.PublishPlaces) }},
+	{"Publish Date", func(b *book.BookInfo, _ *Options) string { return b.PublishDate }},
+	{"Format", func(b *book.BookInfo, _ *Options) string { return string(b.Format) }},
+	{"Binding", func(b *book.BookInfo, _ *Options) string { return b.Binding }},
+	{"Pages", func(b *book.BookInfo, _ *Options) string { return itoa(b.Pages) }},
+	{"Language", func(b *book.BookInfo, o *Options) string {
+		return book.Join(o.Languages.FormatAll(b.Languages))
+	}},
+	{"Subjects", func(b *book.BookInfo, _ *Options) string { return book.Join(b.Subjects) }},
+	{"Dewey Decimal", func(b *book.BookInfo, _ *Options) string { return b.DeweyDecimal }},
+	{"LC Classification", func(b *book.BookInfo, _ *Options) string { return b.LCC }},
+	{"LCCN", func(b *book.BookInfo, _ *Options) string { return b.LCCN }},
+	{"OCLC Number", func(b *book.BookInfo, _ *Options) string { return b.OCLC }},
+	{"OpenLibrary Edition ID", func(b *book.BookInfo, _ *Options) string { return b.OLEditionID }},
+	{"OpenLibrary Work ID", func(b *book.BookInfo, _ *Options) string { return b.OLWorkID }},
+	{"Google Volume ID", func(b *book.BookInfo, _ *Options) string { return b.GoogleVolumeID }},
+	{"Series", func(b *book.BookInfo, _ *Options) string { return b.Series }},
+	{"Series Position", func(b *book.BookInfo, _ *Options) string { return b.SeriesPosition }},
+	{"Series Confidence", func(b *book.BookInfo, _ *Options) string {
+		switch {
+		case b.Series == "":
+			return ""
+		case b.SeriesInferred:
+			return "inferred"
+		}
+		return "confirmed"
+	}},
+	{"Match Confidence", func(b *book.BookInfo, _ *Options) string {
+		if b.MatchConfidence <= 0 {
+			return ""
+		}
+		return strconv.FormatFloat(b.MatchConfidence, 'f', 2, 64)
+	}},
+	{MatchedISBNHeader, func(b *book.BookInfo, _ *Options) string { return MatchedISBN(b) }},
+	{"Matched Edition", func(b *book.BookInfo, _ *Options) string {
+		if MatchedISBN(b) == "" {
+			return ""
+		}
+		return matchedEdition(b)
+	}},
+	{QualityHeader, func(b *book.BookInfo, _ *Options) string {
+		return strconv.FormatFloat(b.Quality(), 'f', 2, 64)
+	}},
+	{"Sources", func(b *book.BookInfo, _ *Options) string { return strings.Join(b.Sources(), "+") }},
+	{"Field Sources", func(b *book.BookInfo, _ *Options) string { return b.FieldSources() }},
+	{"Cover URL", func(b *book.BookInfo, _ *Options) string { return b.CoverURL }},
+	{"Description", func(b *book.BookInfo, o *Options) string {
+		return book.Truncate(b.Description, o.MaxDescriptionLength)
+	}},
+}
+
+// MatchedISBN returns the ISBN of b when it was found by searching its
+// title and author, or "" when it was looked up by ISBN or the search
+// matched a work without telling which of its editions.
+func MatchedISBN(b *book.BookInfo) string {
+	if b.MatchMethod != "search" || b.WorkLevel {
+		return ""
+	}
+	return b.ISBN()
+}
+
+// matchedEdition describes the edition a search matched by its binding,
+// publisher and year, e.g. "Paperback, Penguin Books, 2003", since a
+// title has as many ISBNs as editions. It is empty when the match is a
+// work, whose publishers and first publication year are not those of
+// the edition of the matched ISBN.
+func matchedEdition(b *book.BookInfo) string {
+	if b.WorkLevel {
+		return ""
+	}
+	var parts []string
+	switch {
+	case b.Binding != "":
+		parts = append(parts, b.Binding)
+	case b.Format != "":
+		parts = append(parts, string(b.Format))
+	}
+	if len(b.Publishers) > 0 {
+		parts = append(parts, b.Publishers[0])
+	}
+	if y := b.Year(); y != "" {
+		parts = append(parts, y)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Physical returns the dimension and weight columns in unit system sys.
+// The unit is part of the header so the cells stay numeric.
+func Physical(sys units.System) []Field {
+	h := sys.Columns()
+	return []Field{
+		{h[0], func(b *book.BookInfo, _ *Options) string { return sys.FormatLength(b.Dimensions.Height) }},
+		{h[1], func(b *book.BookInfo, _ *Options) string { return sys.FormatLength(b.Dimensions.Width) }},
+		{h[2], func(b *book.BookInfo, _ *Options) string { return sys.FormatLength(b.Dimensions.Thickness) }},
+		{h[3], func(b *book.BookInfo, _ *Options) string { return sys.FormatWeight(b.Weight) }},
+	}
+}
+
+// Header returns the header names of fields.
+func Header(fields []Field) []string {
+	h := make([]string, len(fields))
+	for i, f := range fields {
+		h[i] = f.Header
+	}
+	return h
+}
+
+// Cells renders b as one cell per field. Empty values become NA so gaps
+// are visible; a nil record yields a row of NA.
+func Cells(fields []Field, b *book.BookInfo, o *Options) []string {
+	cells := make([]string, len(fields))
+	for i, f := range fields {
+		v := ""
+		if b != nil {
+			v = f.Value(b, o)
+		}
+		if v == "" {
+			v = NA
+		}
+		cells[i] = v
+	}
+	return cells
+}
+
+// Select returns the fields named in names, in that order, for layouts
+// leaving out columns a user never wants. Names match the headers
+// ignoring case, and with underscores or hyphens for spaces, such as
+// "publish_date" for "Publish Date".
+func Select(fields []Field, names []string) ([]Field, error) {
+	byName := make(map[string]Field, len(fields))
+	for _, f := range fields {
+		byName[fold(f.Header)] = f
+	}
+	selected := make([]Field, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		k := fold(name)
+		f, ok := byName[k]
+		if !ok {
+			return nil, fmt.Errorf("unknown column %q (available: %s)", name, strings.Join(Header(fields), ", "))
+		}
+		if seen[k] {
+			return nil, fmt.Errorf("column %q is selected twice", name)
+		}
+		seen[k] = true
+		selected = append(selected, f)
+	}
+	if len(selected) == 0 {
+		return nil, errors.New("no columns selected")
+	}
+	return selected, nil
+}
+
+// fold normalizes a header for Select.
+func fold(h string) string {
+	words := strings.FieldsFunc(h, func(r rune) bool {
+		return r == '_' || r == '-' || unicode.IsSpace(r)
+	})
+	return strings.ToLower(strings.Join(words, " "))
+}
+
+func itoa(n int) string {
+	if n <= 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+// Prices returns the market price columns, in currency cur.
+func Prices(cur string) []Field {
+	amount := func(v float64) string {
+		if v <= 0 {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+	return []Field{
+		{"New Price Min (" + cur + ")", func(b *book.BookInfo, _ *Options) string { return amount(b.Prices.NewMin) }},
+		{"New Price Median (" + cur + ")", func(b *book.BookInfo, _ *Options) string { return amount(b.Prices.NewMedian) }},
+		{"Used Price Min (" + cur + ")", func(b *book.BookInfo, _ *Options) string { return amount(b.Prices.UsedMin) }},
+		{"Used Price Median (" + cur + ")", func(b *book.BookInfo, _ *Options) string { return amount(b.Prices.UsedMedian) }},
+	}
+}
+
+// Translation returns the translated columns, into language code, next
+// to which the Full Title, Subjects and Description columns hold the
+// originals. Books already in that language are left empty.
+func Translation(code string) []Field {
+	tr := func(get func(*book.Translation) string) func(*book.BookInfo, *Options) string {
+		return func(b *book.BookInfo, _ *Options) string {
+			if b.Translation == nil {
+				return ""
+			}
+			return get(b.Translation)
+		}
+	}
+	return []Field{
+		{"Title (" + code + ")", tr(func(t *book.Translation) string { return t.Title })},
+		{"Subjects (" + code + ")", tr(func(t *book.Translation) string { return book.Join(t.Subjects) })},
+		{"Description (" + code + ")", func(b *book.BookInfo, o *Options) string {
+			if b.Translation == nil {
+				return ""
+			}
+			return book.Truncate(b.Translation.Description, o.MaxDescriptionLength)
+		}},
+	}
+}
+
+// Romanized are the romanized title and authors of books in Arabic
+// script, next to which the Full Title and Authors columns hold the
+// originals. Books in other scripts are left empty.
+var Romanized = []Field{
+	{"Title (Romanized)", func(b *book.BookInfo, _ *Options) string { return translit.Romanize(b.FullTitle()) }},
+	{"Authors (Romanized)", func(b *book.BookInfo, o *Options) string {
+		return translit.Romanize(book.Join(o.Authors.FormatAll(b.Authors)))
+	}},
+}
+
+// ViolationsHeader is the header of the Violations column.
+const ViolationsHeader = "Violations"
+
+// Violations is the column listing the validation rules a record breaks,
+// separated by semicolons.
+var Violations = Field{ViolationsHeader, func(b *book.BookInfo, _ *Options) string { return strings.Join(b.Violations, "; ") }}
+
+// CoverAlt is the cover alt text column.
+var CoverAlt = Field{"Cover Alt Text", func(b *book.BookInfo, _ *Options) string { return b.CoverAlt }}
+
+// AuthorDetails are the authority data columns of the authors, one
+// value per author in the order of the Authors column, separated by
+// semicolons.
+var AuthorDetails = []Field{
+	{"Author Birth Years", authorDetail(func(a book.Author) string { return a.BirthYear })},
+	{"Author Death Years", authorDetail(func(a book.Author) string { return a.DeathYear })},
+	{"Author OpenLibrary IDs", authorDetail(func(a book.Author) string { return a.OpenLibraryID })},
+	{"Author VIAF IDs", authorDetail(func(a book.Author) string { return a.VIAF })},
+	{"Author Wikidata IDs", authorDetail(func(a book.Author) string { return a.Wikidata })},
+	{"Author ISNIs", authorDetail(func(a book.Author) string { return a.ISNI })},
+}
+
+func authorDetail(get func(book.Author) string) func(*book.BookInfo, *Options) string {
+	return func(b *book.BookInfo, _ *Options) string {
+		vs := make([]string, len(b.AuthorDetails))
+		found := false
+		for i, a := range b.AuthorDetails {
+			vs[i] = get(a)
+			found = found || vs[i] != ""
+		}
+		if !found {
+			return ""
+		}
+		return strings.Join(vs, "; ")
+	}
+}
+
+// BISAC are the BISAC subject columns: the codes and their headings,
+// separated by semicolons.
+var BISAC = []Field{
+	{"BISAC Codes", func(b *book.BookInfo, _ *Options) string { return strings.Join(b.BISAC, "; ") }},
+	{"BISAC Headings", func(b *book.BookInfo, _ *Options) string {
+		vs := make([]string, len(b.BISAC))
+		for i, c := range b.BISAC {
+			vs[i] = bisac.Label(c)
+		}
+		return strings.Join(vs, "; ")
+	}},
+}
+
+// Imprint are the publisher columns: the canonical imprint and the
+// group that owns it.
+var Imprint = []Field{
+	{"Imprint", func(b *book.BookInfo, _ *Options) string { return b.Imprint }},
+	{"Parent Publisher", func(b *book.BookInfo, _ *Options) string { return b.ParentPublisher }},
+}
+
+// Family are the ISBN family columns: whether the book is an
+// international edition, and the ISBNs and labels of the other editions
+// of its work, separated by semicolons, with the international ones
+// listed apart.
+var Family = []Field{
+	{"International Edition", func(b *book.BookInfo, _ *Options) string {
+		if b.International {
+			return "yes"
+		}
+		return ""
+	}},
+	{"Sibling ISBNs", sibling(func(s book.Sibling) string { return s.ISBN13 })},
+	{"Sibling Editions", sibling(func(s book.Sibling) string { return s.Label })},
+	{"International Sibling ISBNs", func(b *book.BookInfo, _ *Options) string {
+		var vs []string
+		for _, s := range b.Siblings {
+			if s.International {
+				vs = append(vs, s.ISBN13)
+			}
+		}
+		return joinList(vs)
+	}},
+}
+
+func sibling(get func(book.Sibling) string) func(*book.BookInfo, *Options) string {
+	return func(b *book.BookInfo, _ *Options) string {
+		vs := make([]string, len(b.Siblings))
+		for i, s := range b.Siblings {
+			vs[i] = get(s)
+		}
+		return joinList(vs)
+	}
+}
+
+// MaxListLength is the longest list cell written, below the 32767
+// characters a spreadsheet cell holds.
+const MaxListLength = 32000
+
+// joinList joins vs with semicolons, leaving out the values past
+// MaxListLength characters and saying how many were left out.
+func joinList(vs []string) string {
+	var sb strings.Builder
+	for i, v := range vs {
+		if i > 0 {
+			if sb.Len()+2+len(v) > MaxListLength-len("; … and 99999 more") {
+				fmt.Fprintf(&sb, "; … and %d more", len(vs)-i)
+				break
+			}
+			sb.WriteString("; ")
+		}
+		sb.WriteString(v)
+	}
+	return sb.String()
+}
+
+// Items are the columns of catalogued items other than books: the type
+// of item and its product barcode.
+var Items = []Field{
+	{"Item Type", func(b *book.BookInfo, _ *Options) string { return b.Kind }},
+	{"Barcode", func(b *book.BookInfo, _ *Options) string { return b.GTIN }},
+}
+
+// Customs returns the export paperwork columns: the country of origin,
+// the HS tariff code and a customs description.
+func Customs(o customs.Options) []Field {
+	return rowFields(customs.Columns(), func(b *book.BookInfo) []string {
+		return customs.Row(o.Derive(b, formatOf(b)))
+	})
+}
+
+// Shipping returns the shipping estimate columns from rate table t: the
+// cheapest tier fitting each book's weight and dimensions, and its cost.
+// Books of unknown weight, or too heavy for every tier, get empty cells.
+func Shipping(t *shipping.RateTable) []Field {
+	return rowFields(shipping.Columns(t.Currency), func(b *book.BookInfo) []string {
+		return shipping.Row(t.Estimate(context.Background(), shipping.Parcel{Dimensions: b.Dimensions, Weight: b.Weight}))
+	})
+}
+
+// Tax returns the tax category, code and rate columns, classifying each
+// book by its format for the destination of table t.
+func Tax(t *tax.Table) []Field {
+	return rowFields(tax.Columns(), func(b *book.BookInfo) []string {
+		return tax.Row(t.Assign(string(b.Format), ""))
+	})
+}
+
+// rowFields returns one field per header, each taking its cell from
+// the cells row computes for a book, for the packages that render
+// several columns at once.
+func rowFields(headers []string, row func(b *book.BookInfo) []string) []Field {
+	fields := make([]Field, len(headers))
+	for i, h := range headers {
+		fields[i] = Field{h, func(b *book.BookInfo, _ *Options) string { return row(b)[i] }}
+	}
+	return fields
+}
+
+// formatOf returns the edition format of b as the tax and customs rules
+// read it: the provider's binding, else the canonical format.
+func formatOf(b *book.BookInfo) string {
+	if b.Binding != "" {
+		return b.Binding
+	}
+	return string(b.Format)
+}
+
+// Covers returns the downloaded cover columns: the file, its processed
+// version when web is set, its perceptual hash and the ISBN of an
+// earlier book with the same cover, and n palette colors, most common
+// first.
+func Covers(n int, web bool) []Field {
+	fields := []Field{{"Cover File", func(b *book.BookInfo, _ *Options) string { return b.CoverFile }}}
+	if web {
+		fields = append(fields, Field{"Cover Web File", func(b *book.BookInfo, _ *Options) string { return b.CoverWebFile }})
+	}
+	fields = append(fields,
+		Field{"Cover Hash", func(b *book.BookInfo, _ *Options) string { return b.CoverHash }},
+		Field{"Same Cover As", func(b *book.BookInfo, _ *Options) string { return b.CoverSameAs }},
+	)
+	for i := range n {
+		fields = append(fields, Field{"Cover Color " + strconv.Itoa(i+1), func(b *book.BookInfo, _ *Options) string {
+			if i < len(b.CoverPalette) {
+				return b.CoverPalette[i]
+			}
+			return ""
+		}})
+	}
+	return fields
+}
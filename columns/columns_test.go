@@ -0,0 +1,135 @@
+package columns_test
+
+import (
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+)
+
+// field returns the field of fields with header h.
+func field(t *testing.T, fields []columns.Field, h string) columns.Field {
+	t.Helper()
+	for _, f := range fields {
+		if f.Header == h {
+			return f
+		}
+	}
+	t.Fatalf("no %q column", h)
+	return columns.Field{}
+}
+
+func TestFamilyListLength(t *testing.T) {
+	for _, n := range []int{0, 3, 5000} {
+		b := &book.BookInfo{}
+		for i := range n {
+			b.Siblings = append(b.Siblings, book.Sibling{ISBN13: "979" + strconv.Itoa(1000000000+i), Label: "5th edition, international, paperback", International: true})
+		}
+		for _, h := range []string{"Sibling ISBNs", "Sibling Editions", "International Sibling ISBNs"} {
+			cell := field(t, columns.Family, h).Value(b, &columns.Options{})
+			if l := utf8.RuneCountInString(cell); l > columns.MaxListLength {
+				t.Errorf("%d siblings: %s cell of %d characters, over %d", n, h, l, columns.MaxListLength)
+			}
+			listed := 0
+			if cell != "" {
+				listed = len(strings.Split(cell, "; "))
+			}
+			if more := strings.HasSuffix(cell, " more"); listed != n && !more || listed == n && more {
+				t.Errorf("%d siblings: %s lists %d, ending %q", n, h, listed, cell[max(0, len(cell)-20):])
+			}
+		}
+	}
+}
+
+func TestCells(t *testing.T) {
+	fields := []columns.Field{
+		field(t, columns.Default, "Full Title"),
+		field(t, columns.Default, "Pages"),
+		field(t, columns.Default, "Description"),
+	}
+	for _, tc := range []struct {
+		name string
+		b    *book.BookInfo
+		o    columns.Options
+		want []string
+	}{
+		{"no record", nil, columns.Options{}, []string{columns.NA, columns.NA, columns.NA}},
+		{"empty fields", &book.BookInfo{Title: "Dune"}, columns.Options{}, []string{"Dune", columns.NA, columns.NA}},
+		{"all fields", &book.BookInfo{Title: "Dune", Subtitle: "A Novel", Pages: 412, Description: "Desert planet."}, columns.Options{}, []string{"Dune: A Novel", "412", "Desert planet."}},
+		{"options", &book.BookInfo{Title: "Dune", Description: "Desert planet."}, columns.Options{MaxDescriptionLength: 6}, []string{"Dune", columns.NA, "Deser…"}},
+	} {
+		if got := columns.Cells(fields, tc.b, &tc.o); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: cells %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestTableRow(t *testing.T) {
+	const isbn13, isbn10 = "9780618260300", "0618260307"
+	edition := book.BookInfo{
+		ISBN13:      isbn13,
+		Title:       "The Hobbit",
+		Binding:     "Paperback",
+		Publishers:  []string{"Houghton Mifflin", "Mariner"},
+		PublishDate: "August 2002",
+		MatchMethod: "search",
+	}
+	work := edition
+	work.WorkLevel = true
+	tenOnly := edition
+	tenOnly.ISBN13, tenOnly.ISBN10 = "", isbn10
+	byISBN := edition
+	byISBN.MatchMethod = "isbn"
+	warned := byISBN
+	warned.Warnings = []string{"no pages", "no cover"}
+
+	hobbit := &input.Row{Title: "The Hobbit", Author: "Tolkien", Quantity: "2"}
+	withISBN := &input.Row{ISBN: "0-618-26030-7", Title: "The Hobbit"}
+	table := &columns.Table{
+		Fields: []columns.Field{
+			field(t, columns.Default, columns.MatchedISBNHeader),
+			field(t, columns.Default, "Matched Edition"),
+		},
+		Options: &columns.Options{},
+	}
+	for _, tc := range []struct {
+		name string
+		res  enrich.Result
+		want []string
+	}{
+		{"isbn lookup", enrich.Result{Row: withISBN, Book: &byISBN, DuplicateOf: -1},
+			[]string{"0-618-26030-7", "The Hobbit", "", "", "", columns.NA, columns.NA, ""}},
+		{"edition search", enrich.Result{Row: hobbit, Book: &edition, DuplicateOf: -1},
+			[]string{isbn13, "The Hobbit", "Tolkien", "2", "", isbn13, "Paperback, Houghton Mifflin, 2002", ""}},
+		{"isbn-10 search", enrich.Result{Row: hobbit, Book: &tenOnly, DuplicateOf: -1},
+			[]string{isbn10, "The Hobbit", "Tolkien", "2", "", isbn10, "Paperback, Houghton Mifflin, 2002", ""}},
+		// A work has no ISBN of its own to fill in.
+		{"work search", enrich.Result{Row: hobbit, Book: &work, DuplicateOf: -1},
+			[]string{"", "The Hobbit", "Tolkien", "2", "", columns.NA, columns.NA, ""}},
+		{"failed", enrich.Result{Row: hobbit, Err: errors.New("not found"), DuplicateOf: -1},
+			[]string{"", "The Hobbit", "Tolkien", "2", "", columns.NA, columns.NA, ""}},
+		{"duplicate", enrich.Result{Row: hobbit, Book: &edition, DuplicateOf: 3},
+			[]string{isbn13, "The Hobbit", "Tolkien", "2", "5", isbn13, "Paperback, Houghton Mifflin, 2002", ""}},
+		{"warnings", enrich.Result{Row: withISBN, Book: &warned, DuplicateOf: -1},
+			[]string{"0-618-26030-7", "The Hobbit", "", "", "", columns.NA, columns.NA, "no pages; no cover"}},
+	} {
+		if got := table.Row(&tc.res); !slices.Equal(got, tc.want) {
+			t.Errorf("%s: row %q, want %q", tc.name, got, tc.want)
+		}
+	}
+
+	// Input columns passed through are kept as read, ISBN included.
+	passed := columns.PassThrough(table, []string{"Title", "Shelf", "Notes"})
+	row := &input.Row{Title: "The Hobbit", Record: []string{"The Hobbit", "A3"}}
+	want := []string{"The Hobbit", "A3", "", "", isbn13, "Paperback, Houghton Mifflin, 2002", ""}
+	if got := passed.Row(&enrich.Result{Row: row, Book: &edition, DuplicateOf: -1}); !slices.Equal(got, want) {
+		t.Errorf("passed through: row %q, want %q", got, want)
+	}
+}
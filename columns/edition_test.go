@@ -0,0 +1,27 @@
+package columns
+
+import (
+	"testing"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+)
+
+func TestMatchedEdition(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		b    book.BookInfo
+		want string
+	}{
+		{"binding", book.BookInfo{Binding: "Mass Market Paperback", Format: book.Paperback, Publishers: []string{"Penguin Books", "Viking"}, PublishDate: "2003-05-01"}, "Mass Market Paperback, Penguin Books, 2003"},
+		{"format", book.BookInfo{Format: book.Hardcover, Publishers: []string{"Penguin Books"}, PublishDate: "May 2003"}, "hardcover, Penguin Books, 2003"},
+		{"publisher only", book.BookInfo{Publishers: []string{"Penguin Books"}}, "Penguin Books"},
+		{"nothing known", book.BookInfo{}, ""},
+		// The publishers and date of a work are not those of the
+		// edition of its ISBN.
+		{"work", book.BookInfo{Binding: "Paperback", Publishers: []string{"Penguin Books"}, PublishDate: "1937", WorkLevel: true}, ""},
+	} {
+		if got := matchedEdition(&tc.b); got != tc.want {
+			t.Errorf("%s: %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
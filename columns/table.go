@@ -0,0 +1,158 @@
+package columns
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+)
+
+// DuplicateHeader names the column flagging repeated input rows.
+const DuplicateHeader = "Duplicate Of Row"
+
+// RowHeader names the column holding the input row of each result, added
+// when results are not written in input order.
+const RowHeader = "Input Row"
+
+// SheetHeader names the column holding the input worksheet of each
+// result, added for inputs read from several worksheets when the output
+// cannot keep them apart as sheets.
+const SheetHeader = "Sheet"
+
+// InputSuffix is added to the headers of input columns passed through
+// under the name of an enrichment column.
+const InputSuffix = " (input)"
+
+// WarningsHeader names the column listing non-fatal problems with a
+// row's record, as opposed to the lookup errors of failed rows.
+const WarningsHeader = "Warnings"
+
+// Layout turns enrichment results into rows: the standard Table or an
+// export profile.
+type Layout interface {
+	Header() []string
+	Row(res *enrich.Result) []string
+}
+
+// Table is the complete row layout of an enrichment: the input columns,
+// the duplicate flag, the enrichment fields and the warnings.
+type Table struct {
+	Fields  []Field
+	Options *Options
+	// Input is the header of the input when its columns are passed
+	// through; see PassThrough. Nil selects input.Columns.
+	Input []string
+}
+
+// Header returns the column headers.
+func (t *Table) Header() []string {
+	in := t.Input
+	if in == nil {
+		in = input.Columns
+	}
+	h := append(slices.Clone(in), DuplicateHeader)
+	h = append(h, Header(t.Fields)...)
+	return append(h, WarningsHeader)
+}
+
+// Row returns the cells of an enrichment result. The ISBN cell of a row
+// without one gets the ISBN its title and author search found, so that
+// the output can be looked up by ISBN from then on; input columns passed
+// through are kept as read, next to the MatchedISBNHeader column.
+func (t *Table) Row(res *enrich.Result) []string {
+	var cells []string
+	if t.Input != nil {
+		// Worksheets drop trailing empty cells, and rows may run past
+		// the header.
+		cells = make([]string, len(t.Input))
+		copy(cells, res.Row.Record)
+	} else {
+		cells = res.Row.Cells()
+		// The ISBN comes first in input.Columns.
+		if cells[0] == "" && res.Book != nil {
+			cells[0] = MatchedISBN(res.Book)
+		}
+	}
+	cells = append(cells, duplicateCell(res))
+	cells = append(cells, Cells(t.Fields, res.Book, t.Options)...)
+	return append(cells, warningsCell(res))
+}
+
+// PassThrough returns l keeping every column of an input with the given
+// header, as read and in its order, in place of the standard input
+// columns, so that columns such as a shelf location or notes survive
+// enrichment. Input columns named like a column of the layout, such as
+// a Publisher column, get an InputSuffix so that every header stays
+// unique. Export profiles have their own columns and are returned as
+// they are.
+func PassThrough(l Layout, header []string) Layout {
+	switch l := l.(type) {
+	case *Table:
+		t := *l
+		taken := append(Header(t.Fields), DuplicateHeader, WarningsHeader, RowHeader, SheetHeader)
+		t.Input = make([]string, len(header))
+		for i, h := range header {
+			if slices.ContainsFunc(taken, func(s string) bool { return strings.EqualFold(s, h) }) {
+				h += InputSuffix
+			}
+			t.Input[i] = h
+		}
+		return &t
+	case rowNumbered:
+		return rowNumbered{PassThrough(l.Layout, header)}
+	case sheetNamed:
+		return sheetNamed{PassThrough(l.Layout, header)}
+	}
+	return l
+}
+
+// WithRowNumber returns l with a leading RowHeader column, for output
+// written in completion order.
+func WithRowNumber(l Layout) Layout {
+	return rowNumbered{l}
+}
+
+type rowNumbered struct{ Layout }
+
+func (r rowNumbered) Header() []string {
+	return append([]string{RowHeader}, r.Layout.Header()...)
+}
+
+func (r rowNumbered) Row(res *enrich.Result) []string {
+	return append([]string{strconv.Itoa(res.Row.Line())}, r.Layout.Row(res)...)
+}
+
+// WithSheet returns l with a leading SheetHeader column, for output of
+// several input worksheets to a format without sheets.
+func WithSheet(l Layout) Layout {
+	return sheetNamed{l}
+}
+
+type sheetNamed struct{ Layout }
+
+func (s sheetNamed) Header() []string {
+	return append([]string{SheetHeader}, s.Layout.Header()...)
+}
+
+func (s sheetNamed) Row(res *enrich.Result) []string {
+	return append([]string{res.Row.Sheet}, s.Layout.Row(res)...)
+}
+
+// warningsCell lists the warnings of res's record.
+func warningsCell(res *enrich.Result) string {
+	if res.Book == nil {
+		return ""
+	}
+	return strings.Join(res.Book.Warnings, "; ")
+}
+
+// duplicateCell returns the spreadsheet row number (data rows start at 2,
+// below the header) of the row res duplicates, or "" when it is unique.
+func duplicateCell(res *enrich.Result) string {
+	if res.DuplicateOf < 0 {
+		return ""
+	}
+	return strconv.Itoa(res.DuplicateOf + 2)
+}
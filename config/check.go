@@ -0,0 +1,433 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/author"
+	"github.com/SouadAli10/book_scrapping_tool/country"
+	"github.com/SouadAli10/book_scrapping_tool/cover"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/fuzzy"
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/lang"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+	"github.com/SouadAli10/book_scrapping_tool/reprice"
+	"github.com/SouadAli10/book_scrapping_tool/units"
+	"github.com/SouadAli10/book_scrapping_tool/validate"
+)
+
+// Problem is one validation failure, located by its JSON path.
+type Problem struct {
+	Path    string
+	Message string
+}
+
+func (p Problem) String() string {
+	if p.Path == "" {
+		return p.Message
+	}
+	return p.Path + ": " + p.Message
+}
+
+// ProviderSpec describes what the configuration may say about a provider.
+type ProviderSpec struct {
+	// RequiresKey is set for providers that refuse anonymous requests.
+	RequiresKey bool
+}
+
+// Schema lists the names a configuration may refer to. It is supplied by
+// the caller so this package does not depend on every provider and
+// format.
+type Schema struct {
+	Providers map[string]ProviderSpec
+	// ItemProviders lists the providers of items other than books.
+	ItemProviders map[string]ProviderSpec
+	InputFormats  []string
+	OutputFormats []string
+	Profiles      []string
+	PriceSources  []string
+	// TranslationServices lists the translation services.
+	TranslationServices []string
+	// Services lists the services other than providers that may have
+	// credentials.
+	Services []string
+}
+
+// Check validates the raw configuration data against the Config structure
+// and s, returning every problem found rather than stopping at the first.
+func Check(data []byte, s *Schema) []Problem {
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		var syn *json.SyntaxError
+		if errors.As(err, &syn) {
+			line, col := position(data, syn.Offset)
+			return []Problem{{Path: fmt.Sprintf("line %d, column %d", line, col), Message: err.Error()}}
+		}
+		return []Problem{{Message: err.Error()}}
+	}
+	var probs []Problem
+	checkKeys(raw, reflect.TypeOf(Config{}), "", &probs)
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		var typ *json.UnmarshalTypeError
+		if errors.As(err, &typ) {
+			return append(probs, Problem{Path: typ.Field,
+				Message: fmt.Sprintf("cannot use a JSON %s here (want %s)", typ.Value, typ.Type)})
+		}
+		return append(probs, Problem{Message: err.Error()})
+	}
+	probs = append(probs, c.Validate(s)...)
+	sort.SliceStable(probs, func(i, j int) bool { return probs[i].Path < probs[j].Path })
+	return probs
+}
+
+// Validate checks the values of c against s.
+func (c *Config) Validate(s *Schema) []Problem {
+	var probs []Problem
+	add := func(path, format string, args ...any) {
+		probs = append(probs, Problem{Path: path, Message: fmt.Sprintf(format, args...)})
+	}
+	known := sortedKeys(s.Providers)
+	for i, name := range c.Providers {
+		spec, ok := s.Providers[name]
+		if !ok {
+			add(fmt.Sprintf("providers[%d]", i), "unknown provider %q%s", name, suggestion(name, known))
+			continue
+		}
+		if spec.RequiresKey && c.Credentials[name].Key() == "" {
+			cred := c.Credentials[name]
+			if cred.APIKeyEnv != "" {
+				add("credentials."+name, "provider %q needs an API key but $%s is not set", name, cred.APIKeyEnv)
+			} else {
+				add("credentials."+name, "provider %q is enabled but has no api_key or api_key_env", name)
+			}
+		}
+	}
+	itemKnown := sortedKeys(s.ItemProviders)
+	for i, name := range c.ItemProviders {
+		spec, ok := s.ItemProviders[name]
+		if !ok {
+			add(fmt.Sprintf("item_providers[%d]", i), "unknown item provider %q%s", name, suggestion(name, itemKnown))
+			continue
+		}
+		if spec.RequiresKey && c.Credentials[name].Key() == "" {
+			add("credentials."+name, "item provider %q is enabled but has no api_key or api_key_env", name)
+		}
+	}
+	for name := range c.Credentials {
+		_, item := s.ItemProviders[name]
+		if _, ok := s.Providers[name]; !ok && !item && !contains(s.Services, name) {
+			add("credentials."+name, "credentials for unknown provider %q%s", name, suggestion(name, known))
+		}
+	}
+	if f := c.Input.Format; f != "" && !contains(s.InputFormats, f) {
+		add("input.format", "unknown input format %q%s", f, suggestion(f, s.InputFormats))
+	}
+	for field := range c.Input.Columns {
+		if !contains(input.Fields, field) {
+			add("input.columns."+field, "unknown input field %q (want one of %s)", field, strings.Join(input.Fields, ", "))
+		}
+	}
+	if c.Input.AllSheets && len(c.Input.Sheets) > 0 {
+		add("input.sheets", "sheets and all_sheets both select worksheets; keep one")
+	}
+	if f := c.Output.Format; f != "" && !contains(s.OutputFormats, f) {
+		add("output.format", "unknown output format %q%s", f, suggestion(f, s.OutputFormats))
+	}
+	for i, src := range c.Prices.Sources {
+		if !contains(s.PriceSources, src) {
+			add(fmt.Sprintf("prices.sources[%d]", i), "unknown price source %q%s", src, suggestion(src, s.PriceSources))
+		}
+	}
+	if cur := c.Prices.Currency; cur != "" && (len(cur) != 3 || strings.ToUpper(cur) != cur) {
+		add("prices.currency", "want a three-letter ISO 4217 code such as USD, got %q", cur)
+	}
+	if p := c.Output.Profile; p != "" && !contains(s.Profiles, p) {
+		add("output.profile", "unknown profile %q%s", p, suggestion(p, s.Profiles))
+	}
+	if len(c.Output.Columns) > 0 && c.Output.Profile != "" {
+		add("output.columns", "an export profile has its own columns; keep columns or profile")
+	}
+	if _, err := lang.ParseMode(c.Output.LanguageFormat); err != nil {
+		add("output.language_format", "%v", err)
+	}
+	if _, err := author.ParseFormat(c.Output.AuthorFormat); err != nil {
+		add("output.author_format", "%v", err)
+	}
+	if _, err := units.ParseSystem(c.Output.Units); err != nil {
+		add("output.units", "%v", err)
+	}
+	if _, err := validate.ParseMode(c.Validation); err != nil {
+		add("validate", "%v", err)
+	}
+	if c.Subjects.Max < 0 {
+		add("subjects.max", "must not be negative")
+	}
+	if v := c.Subjects.Vocabulary; v != "" {
+		if _, err := os.Stat(v); err != nil {
+			add("subjects.vocabulary", "%v", err)
+		}
+	}
+	if t := c.Translate; t.Service != "" {
+		if !contains(s.TranslationServices, t.Service) {
+			add("translate.service", "unknown translation service %q%s", t.Service, suggestion(t.Service, s.TranslationServices))
+		}
+		if t.Target == "" {
+			add("translate.target", "the language to translate into is missing")
+		} else if l, ok := lang.Lookup(t.Target); !ok || l.Alpha2 == "" {
+			add("translate.target", "want a language with an ISO 639-1 code, such as ar or fr, got %q", t.Target)
+		}
+	}
+	if _, err := reprice.ParseBasis(c.Reprice.Basis); err != nil {
+		add("reprice.basis", "%v", err)
+	}
+	for name, v := range c.Reprice.Factors {
+		if _, err := reprice.ParseCondition(name); err != nil {
+			add("reprice.factors."+name, "%v", err)
+		} else if v <= 0 {
+			add("reprice.factors."+name, "must be positive")
+		}
+	}
+	if e := c.Reprice.Ending; e != nil && (*e < 0 || *e >= 1) {
+		add("reprice.ending", "want cents between 0 and 0.99, got %v", *e)
+	}
+	if r := c.Reprice; r.Floor < 0 || r.Ceiling < 0 {
+		add("reprice", "floor and ceiling must not be negative")
+	} else if r.Ceiling > 0 && r.Floor > r.Ceiling {
+		add("reprice.floor", "%v is above the ceiling %v", r.Floor, r.Ceiling)
+	}
+	if a := c.Alerts; a.WindowDays < 0 || a.FastSales < 0 || a.CoverDays < 0 || a.StaleDays < 0 {
+		add("alerts", "window_days, fast_sales, cover_days and stale_days must not be negative")
+	}
+	for i, m := range c.Alerts.Markdowns {
+		if m.AfterDays < 0 || m.Percent <= 0 || m.Percent >= 100 {
+			add(fmt.Sprintf("alerts.markdowns[%d]", i), "want after_days of 0 or more and a percent between 0 and 100")
+		}
+	}
+	if u := c.Alerts.Notify; u != "" && !strings.HasPrefix(u, "https://") && !strings.HasPrefix(u, "http://") {
+		add("alerts.notify", "want an http or https webhook URL, got %q", u)
+	}
+	if c.Output.MaxDescriptionLength < 0 {
+		add("output.max_description_length", "must not be negative")
+	}
+	if db := c.Output.Database; db.Enabled() {
+		if _, err := output.DialectFor(db.Driver); err != nil {
+			add("output.database.driver", "%v", err)
+		}
+		if db.Source() == "" {
+			if db.DSNEnv != "" {
+				add("output.database.dsn_env", "$%s is not set and no dsn is given", db.DSNEnv)
+			} else {
+				add("output.database", "missing dsn or dsn_env")
+			}
+		}
+	} else if db.DSN != "" || db.DSNEnv != "" || db.Table != "" {
+		add("output.database.driver", "missing; the database export needs a driver")
+	}
+	for name, o := range c.Output.Order {
+		if name != DefaultOrderKey && name != "gsheet" && !contains(s.OutputFormats, name) {
+			add("output.order."+name, "unknown output format %q%s", name, suggestion(name, s.OutputFormats))
+		} else if _, err := enrich.ParseOrder(o); err != nil {
+			add("output.order."+name, "%v", err)
+		}
+	}
+	for name, n := range c.MaxCalls {
+		if _, ok := s.Providers[name]; !ok {
+			add("max_calls."+name, "unknown provider %q%s", name, suggestion(name, known))
+		} else if n <= 0 {
+			add("max_calls."+name, "must be positive")
+		}
+	}
+	for name, price := range c.Costs {
+		if price < 0 {
+			add("costs."+name, "price must not be negative")
+		}
+	}
+	tokens := make(map[string]bool)
+	for i, u := range c.Server.Users {
+		path := fmt.Sprintf("server.users[%d]", i)
+		if u.Name == "" {
+			add(path+".name", "missing")
+		}
+		if !contains(Roles, u.Role) {
+			add(path+".role", "unknown role %q (want one of %s)", u.Role, strings.Join(Roles, ", "))
+		}
+		switch tok := u.Secret(); {
+		case tok == "" && u.TokenEnv != "":
+			add(path+".token_env", "$%s is not set", u.TokenEnv)
+		case tok == "":
+			add(path, "missing token or token_env")
+		case tokens[tok]:
+			add(path, "token is shared with another user")
+		}
+		tokens[u.Secret()] = true
+	}
+	if c.Workers < 0 {
+		add("workers", "must not be negative")
+	}
+	if c.RowTimeout < 0 {
+		add("row_timeout", "must not be negative")
+	}
+	if c.Covers.Colors < 0 {
+		add("covers.colors", "must not be negative")
+	}
+	if c.Covers.MaxWidth < 0 {
+		add("covers.max_width", "must not be negative")
+	}
+	if c.Covers.MaxHeight < 0 {
+		add("covers.max_height", "must not be negative")
+	}
+	if f := c.Covers.Format; f != "" && !contains(cover.Formats, f) {
+		add("covers.format", "unknown format %q (want %s)%s", f, strings.Join(cover.Formats, ", "), suggestion(f, cover.Formats))
+	}
+	if q := c.Covers.Quality; q < 0 || q > 100 {
+		add("covers.quality", "want 1 to 100, got %d", q)
+	}
+	if a := c.Covers.Alt; a != "" && !contains(cover.AltSources, a) {
+		add("covers.alt", "unknown alt text source %q (want %s)%s", a, strings.Join(cover.AltSources, " or "), suggestion(a, cover.AltSources))
+	}
+	if t := c.Covers.AltTemplate; t != "" {
+		if _, err := cover.ParseAltTemplate(t); err != nil {
+			add("covers.alt_template", "%v", err)
+		}
+	}
+	if c.Covers.Alt == cover.AltVision && c.Covers.AltURL == "" && c.Credentials[cover.VisionService].Key() == "" {
+		add("credentials."+cover.VisionService, "covers.alt is %q but the vision model has no api_key or api_key_env", cover.AltVision)
+	}
+	if d := c.Tax.Destination; d != "" && !country.Valid(d) {
+		add("tax.destination", "want an ISO 3166 alpha-2 country code, got %q", d)
+	}
+	if o := c.Customs.DefaultOrigin; o != "" && !country.Valid(o) {
+		add("customs.default_origin", "want an ISO 3166 alpha-2 country code, got %q", o)
+	}
+	if m := c.Output.MinConfidence; m < 0 || m > 1 {
+		add("output.min_confidence", "want 0 to 1, got %g", m)
+	}
+	if c.Output.Site.Dir != "" && c.Output.Site.URL == "" {
+		add("output.site.url", "missing; the sitemap needs each book's canonical URL")
+	}
+	for path, t := range map[string]string{"output.site.url": c.Output.Site.URL, "output.site.title": c.Output.Site.Title} {
+		if t == "" {
+			continue
+		}
+		if _, err := output.ParseSiteTemplate(path, t); err != nil {
+			add(path, "%v", err)
+		}
+	}
+	if c.HTTP.Retries < 0 {
+		add("http.retries", "must not be negative")
+	}
+	for i, w := range c.HTTP.RateSchedule {
+		if _, err := httpx.ParseWindow(w); err != nil {
+			add(fmt.Sprintf("http.rate_schedule[%d]", i), "%v", err)
+		}
+	}
+	for i, h := range c.HTTP.AllowHosts {
+		if h == "" || strings.ContainsAny(h, "/ ,") {
+			add(fmt.Sprintf("http.allow_hosts[%d]", i), "want a host name such as \"openlibrary.org\", got %q", h)
+		}
+	}
+	if p := c.HTTP.Proxy; p != "" {
+		if _, err := httpx.ParseProxy(p); err != nil {
+			add("http.proxy", "%v", err)
+		}
+	}
+	for name, v := range c.HTTP.Headers {
+		if _, _, err := httpx.ParseHeader(name + ": " + v); err != nil || strings.Contains(name, ":") {
+			add("http.headers."+name, "want a header name such as \"X-Site\" and a single-line value")
+		}
+	}
+	return probs
+}
+
+// checkKeys reports object keys in raw that have no field in t.
+func checkKeys(raw any, t reflect.Type, path string, probs *[]Problem) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch v := raw.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := jsonFields(t)
+			names := sortedKeys(fields)
+			for k, val := range v {
+				ft, ok := fields[k]
+				if !ok {
+					*probs = append(*probs, Problem{Path: join(path, k),
+						Message: "unknown key" + suggestion(k, names)})
+					continue
+				}
+				checkKeys(val, ft, join(path, k), probs)
+			}
+		case reflect.Map:
+			for k, val := range v {
+				checkKeys(val, t.Elem(), join(path, k), probs)
+			}
+		}
+	case []any:
+		if t.Kind() == reflect.Slice {
+			for i, val := range v {
+				checkKeys(val, t.Elem(), fmt.Sprintf("%s[%d]", path, i), probs)
+			}
+		}
+	}
+}
+
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	m := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		m[name] = f.Type
+	}
+	return m
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func suggestion(s string, candidates []string) string {
+	if c, ok := fuzzy.Closest(s, candidates); ok {
+		return fmt.Sprintf(" (did you mean %q?)", c)
+	}
+	if len(candidates) > 0 && len(candidates) <= 12 {
+		return " (want one of " + strings.Join(candidates, ", ") + ")"
+	}
+	return ""
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
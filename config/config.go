@@ -0,0 +1,441 @@
+// Package config loads the booktool configuration file. Every setting
+// has a command-line flag equivalent; the file provides defaults that
+// flags override.
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultPath is the configuration file read when none is given.
+const DefaultPath = "booktool.json"
+
+// Duration is a time.Duration written as a string such as "30s".
+type Duration time.Duration
+
+// UnmarshalJSON accepts Go duration strings and plain numbers of seconds.
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var secs float64
+		if err2 := json.Unmarshal(data, &secs); err2 != nil {
+			return fmt.Errorf("want a duration such as \"30s\"")
+		}
+		*d = Duration(secs * float64(time.Second))
+		return nil
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q (want e.g. \"30s\", \"250ms\")", s)
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalJSON writes d as a duration string.
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// Credentials holds the secret of one provider or service. APIKeyEnv
+// names an environment variable to read the key from, which keeps keys
+// out of the file.
+type Credentials struct {
+	APIKey    string `json:"api_key,omitempty"`
+	APIKeyEnv string `json:"api_key_env,omitempty"`
+}
+
+// Key returns the configured key, preferring the environment variable.
+func (c Credentials) Key() string {
+	if c.APIKeyEnv != "" {
+		if v := os.Getenv(c.APIKeyEnv); v != "" {
+			return v
+		}
+	}
+	return c.APIKey
+}
+
+// Input configures how input files are read.
+type Input struct {
+	Format  string            `json:"format,omitempty"`
+	Sheet   string            `json:"sheet,omitempty"`
+	Columns map[string]string `json:"columns,omitempty"`
+	// Sheets selects several worksheets to enrich one after the other,
+	// and AllSheets every worksheet.
+	Sheets    []string `json:"sheets,omitempty"`
+	AllSheets bool     `json:"all_sheets,omitempty"`
+}
+
+// Output configures the enriched output.
+type Output struct {
+	Format               string `json:"format,omitempty"`
+	LanguageFormat       string `json:"language_format,omitempty"`
+	MaxDescriptionLength int    `json:"max_description_length,omitempty"`
+	// AuthorFormat is "natural" or "inverted" (surname first).
+	AuthorFormat string `json:"author_format,omitempty"`
+	// Units is "metric" or "imperial".
+	Units string `json:"units,omitempty"`
+	// Profile selects an export profile, such as "shopify", instead of
+	// the standard columns.
+	Profile string `json:"profile,omitempty"`
+	// Columns lists the enrichment fields to write, by header, in the
+	// order to write them, such as ["Full Title", "Publisher"]. Empty
+	// writes all of them.
+	Columns []string `json:"columns,omitempty"`
+	// PassThrough keeps every input column, in place of the ISBN,
+	// Title, Author and Quantity columns; on when unset.
+	PassThrough *bool `json:"pass_through,omitempty"`
+	// Order maps output formats ("gsheet" for Google Sheets) to the
+	// order rows are written in: "input" or "completion". The "default"
+	// key applies to the other formats.
+	Order map[string]string `json:"order,omitempty"`
+	// MinConfidence moves rows whose quality score is below it to a
+	// Review sheet; see book.BookInfo.Quality.
+	MinConfidence float64 `json:"min_confidence,omitempty"`
+	// Editions lists the other editions of each book's work on an
+	// Editions sheet, like the -editions flag.
+	Editions bool `json:"editions,omitempty"`
+	// Database is an optional export target next to the output file.
+	Database Database `json:"database"`
+	// Site configures the sitemap and SEO metadata export.
+	Site Site `json:"site"`
+}
+
+// Subjects configures how subject headings are cleaned up. See
+// subject.Normalizer.
+type Subjects struct {
+	// Raw keeps the headings as the providers report them.
+	Raw bool `json:"raw,omitempty"`
+	// Max limits the headings kept per book; 0 keeps all.
+	Max int `json:"max,omitempty"`
+	// Vocabulary is a CSV file of heading,term rows mapping headings to
+	// preferred terms ahead of the built-in vocabulary.
+	Vocabulary string `json:"vocabulary,omitempty"`
+}
+
+// Site configures the files written for a static site builder: a
+// sitemap and per-book SEO metadata. See output.SiteWriter.
+type Site struct {
+	// Dir is where the files are written; empty disables the export.
+	Dir string `json:"dir,omitempty"`
+	// URL is the template of each book's canonical URL, e.g.
+	// "https://shop.example/books/{{.ISBN13}}/{{slug .Title}}".
+	URL string `json:"url,omitempty"`
+	// Title is the template of the title tags.
+	Title string `json:"title,omitempty"`
+}
+
+// DefaultOrderKey is the Output.Order key applying to formats without
+// their own entry.
+const DefaultOrderKey = "default"
+
+// Database configures the export of enriched rows into an SQL table.
+type Database struct {
+	// Driver is "pgx" (or "postgres") or "mysql".
+	Driver string `json:"driver,omitempty"`
+	DSN    string `json:"dsn,omitempty"`
+	// DSNEnv names an environment variable holding the DSN, to keep
+	// passwords out of the file.
+	DSNEnv string `json:"dsn_env,omitempty"`
+	Table  string `json:"table,omitempty"`
+}
+
+// Enabled reports whether a database export is configured.
+func (d Database) Enabled() bool { return d.Driver != "" }
+
+// Source returns the DSN, preferring the environment variable.
+func (d Database) Source() string {
+	if d.DSNEnv != "" {
+		if v := os.Getenv(d.DSNEnv); v != "" {
+			return v
+		}
+	}
+	return d.DSN
+}
+
+// Prices configures the market price lookup.
+type Prices struct {
+	// Sources lists the price sources; empty disables the lookup.
+	Sources []string `json:"sources,omitempty"`
+	// Currency is the ISO 4217 currency of the price columns.
+	Currency string `json:"currency,omitempty"`
+}
+
+// Translate configures the translation of titles, subjects and
+// descriptions.
+type Translate struct {
+	// Service is the translation service; empty disables translation.
+	Service string `json:"service,omitempty"`
+	// Target is the language translated into, e.g. "ar" or "fr".
+	Target string `json:"target,omitempty"`
+	// URL overrides the service endpoint, e.g. for a self-hosted
+	// LibreTranslate.
+	URL string `json:"url,omitempty"`
+}
+
+// Reprice configures the pricing rules of the reprice command.
+type Reprice struct {
+	// Basis is the market price the rules start from: median or min.
+	Basis string `json:"basis,omitempty"`
+	// Factors multiply the market price by condition: new, like new,
+	// very good, good, acceptable, poor, or other for conditions that
+	// cannot be read.
+	Factors map[string]float64 `json:"factors,omitempty"`
+	// Ending rounds prices to the nearest amount with these cents, e.g.
+	// 0.99; 0 keeps the cents.
+	Ending *float64 `json:"ending,omitempty"`
+	// Floor and Ceiling bound the new prices; 0 leaves them unbounded.
+	Floor   float64 `json:"floor,omitempty"`
+	Ceiling float64 `json:"ceiling,omitempty"`
+}
+
+// Alerts configures the stock alerts command.
+type Alerts struct {
+	// WindowDays is the period sales are counted over.
+	WindowDays int `json:"window_days,omitempty"`
+	// FastSales is how many copies a title must sell in the window to
+	// count as a fast seller, and CoverDays the stock, in days of
+	// sales, below which fast sellers are flagged.
+	FastSales int `json:"fast_sales,omitempty"`
+	CoverDays int `json:"cover_days,omitempty"`
+	// StaleDays flags copies unsold that long after they were acquired
+	// or last sold.
+	StaleDays int `json:"stale_days,omitempty"`
+	// Markdowns is the markdown schedule of stale copies.
+	Markdowns []Markdown `json:"markdowns,omitempty"`
+	// Notify is a webhook URL the alerts are posted to.
+	Notify string `json:"notify,omitempty"`
+}
+
+// Markdown marks copies unsold for AfterDays down by Percent.
+type Markdown struct {
+	AfterDays int     `json:"after_days"`
+	Percent   float64 `json:"percent"`
+}
+
+// Customs configures the export paperwork columns.
+type Customs struct {
+	// Enabled adds the Country of Origin, HS Code and Customs
+	// Description columns.
+	Enabled bool `json:"enabled,omitempty"`
+	// DefaultOrigin is the ISO 3166 country declared for books whose
+	// country of publication is unknown, typically the seller's own.
+	DefaultOrigin string `json:"default_origin,omitempty"`
+}
+
+// Shipping configures the shipping cost columns.
+type Shipping struct {
+	// Rates is a JSON rate table file, or "default" for the built-in
+	// letter and parcel rates; empty leaves the columns out.
+	Rates string `json:"rates,omitempty"`
+}
+
+// Tax configures the tax classification columns.
+type Tax struct {
+	// Table is a JSON tax table file, or "default" for the built-in
+	// European rates.
+	Table string `json:"table,omitempty"`
+	// Destination is the ISO 3166 country the books are sold to,
+	// overriding the table's own destination.
+	Destination string `json:"destination,omitempty"`
+}
+
+// Covers configures cover downloads.
+type Covers struct {
+	// Dir is where covers are saved, named by ISBN; empty disables
+	// downloads.
+	Dir string `json:"dir,omitempty"`
+	// Colors is the number of palette colors extracted from each cover.
+	Colors int `json:"colors,omitempty"`
+	// MaxWidth, MaxHeight, Square, Format and Quality configure the
+	// web versions of the covers; see cover.Processing. Web versions
+	// are made when any of them is set.
+	MaxWidth  int    `json:"max_width,omitempty"`
+	MaxHeight int    `json:"max_height,omitempty"`
+	Square    bool   `json:"square,omitempty"`
+	Format    string `json:"format,omitempty"`
+	Quality   int    `json:"quality,omitempty"`
+	// Alt adds an alt text column, from AltTemplate ("template") or
+	// from a vision model describing each cover ("vision"). The vision
+	// model's key is read from the "vision" credentials.
+	Alt         string `json:"alt,omitempty"`
+	AltTemplate string `json:"alt_template,omitempty"`
+	// AltURL and AltModel select an OpenAI-style chat completions
+	// endpoint and vision model.
+	AltURL   string `json:"alt_url,omitempty"`
+	AltModel string `json:"alt_model,omitempty"`
+}
+
+// HTTP configures provider requests.
+type HTTP struct {
+	Timeout Duration `json:"timeout,omitempty"`
+	Rate    Duration `json:"rate,omitempty"`
+	// RateSchedule overrides Rate at times of day, with windows such as
+	// "mon-fri 09:00-18:00=2s"; see httpx.ParseWindow.
+	RateSchedule []string `json:"rate_schedule,omitempty"`
+	Retries      int      `json:"retries,omitempty"`
+	CacheDir     string   `json:"cache_dir,omitempty"`
+	// AllowHosts restricts the hosts the tool connects to; see the
+	// -allow-hosts flag.
+	AllowHosts []string `json:"allow_hosts,omitempty"`
+	// Proxy is the URL of the proxy of outbound requests, replacing
+	// the one $HTTPS_PROXY, $HTTP_PROXY and $NO_PROXY select.
+	Proxy string `json:"proxy,omitempty"`
+	// CACerts are PEM files of certificate authorities to trust besides
+	// the system's, such as that of a TLS-inspecting proxy.
+	CACerts []string `json:"ca_certs,omitempty"`
+	// UserAgent replaces the User-Agent of outbound requests.
+	UserAgent string `json:"user_agent,omitempty"`
+	// Headers are added to outbound requests, by name.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// GoogleSheets configures access to spreadsheets given as input or
+// output.
+type GoogleSheets struct {
+	// CredentialsFile is a service account JSON key.
+	CredentialsFile string `json:"credentials_file,omitempty"`
+}
+
+// Server configures the serve command.
+type Server struct {
+	Addr string `json:"addr,omitempty"`
+	// JobsDB is the SQLite file holding the job history; empty keeps the
+	// history in memory.
+	JobsDB string `json:"jobs_db,omitempty"`
+	// MaxJobs caps the enrichment jobs run at once.
+	MaxJobs int `json:"max_jobs,omitempty"`
+	// Users are the API clients. Without users the server is open to
+	// anyone who can reach it.
+	Users []User `json:"users,omitempty"`
+}
+
+// Roles lists the server roles, from least to most privileged.
+var Roles = []string{"viewer", "operator", "admin"}
+
+// User is a server API client.
+type User struct {
+	Name string `json:"name"`
+	// Token is the bearer token; TokenEnv names an environment variable
+	// holding it instead.
+	Token    string `json:"token,omitempty"`
+	TokenEnv string `json:"token_env,omitempty"`
+	Role     string `json:"role"`
+}
+
+// Secret returns the user's token, preferring the environment variable.
+func (u User) Secret() string {
+	if u.TokenEnv != "" {
+		if v := os.Getenv(u.TokenEnv); v != "" {
+			return v
+		}
+	}
+	return u.Token
+}
+
+// Config is the contents of the configuration file.
+type Config struct {
+	// Providers lists provider names in priority order.
+	Providers   []string               `json:"providers,omitempty"`
+	Credentials map[string]Credentials `json:"credentials,omitempty"`
+	Workers     int                    `json:"workers,omitempty"`
+	Merge       *bool                  `json:"merge,omitempty"`
+	Wikidata    *bool                  `json:"wikidata,omitempty"`
+	Dedupe      *bool                  `json:"dedupe,omitempty"`
+	// RowTimeout bounds the lookup of one row, across all providers.
+	RowTimeout Duration `json:"row_timeout,omitempty"`
+	// ItemProviders look up the barcodes of items other than books, in
+	// priority order.
+	ItemProviders []string `json:"item_providers,omitempty"`
+	// AuthorDetails looks up the authors' life dates and identifiers.
+	AuthorDetails bool `json:"author_details,omitempty"`
+	// ISBNFamily looks up the other editions of each book, flagging
+	// international editions.
+	ISBNFamily bool `json:"isbn_family,omitempty"`
+	// BISAC maps the subjects to BISAC codes, in extra columns.
+	BISAC bool `json:"bisac,omitempty"`
+	// Imprints maps the publishers to their imprints and parent groups,
+	// in extra columns.
+	Imprints bool `json:"imprints,omitempty"`
+	// Romanize adds the titles and authors of books in Arabic script
+	// romanized, in extra columns.
+	Romanize bool `json:"romanize,omitempty"`
+	// Validation checks each record before it is saved: "report" lists
+	// the rules it breaks in a column, "reject" fails its row.
+	Validation string `json:"validate,omitempty"`
+	// MaxCalls limits the network calls per provider in one run.
+	MaxCalls map[string]int `json:"max_calls,omitempty"`
+	// Costs overrides the per-call price of paid services, in USD.
+	Costs map[string]float64 `json:"costs,omitempty"`
+	// CostThreshold is the estimated spend above which a run asks for
+	// confirmation.
+	CostThreshold *float64     `json:"cost_threshold,omitempty"`
+	Prices        Prices       `json:"prices"`
+	Covers        Covers       `json:"covers"`
+	Customs       Customs      `json:"customs"`
+	Shipping      Shipping     `json:"shipping"`
+	Tax           Tax          `json:"tax"`
+	Subjects      Subjects     `json:"subjects"`
+	Translate     Translate    `json:"translate"`
+	Reprice       Reprice      `json:"reprice"`
+	Alerts        Alerts       `json:"alerts"`
+	Input         Input        `json:"input"`
+	Output        Output       `json:"output"`
+	HTTP          HTTP         `json:"http"`
+	GoogleSheets  GoogleSheets `json:"google_sheets"`
+	Server        Server       `json:"server"`
+}
+
+// Load reads and decodes the file at path. Syntax and type errors are
+// reported with their line and column; unknown keys are not rejected
+// here but reported by Check.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(path, data)
+}
+
+// Parse decodes configuration data read from path.
+func Parse(path string, data []byte) (*Config, error) {
+	var c Config
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, positionError(path, data, err)
+	}
+	return &c, nil
+}
+
+func positionError(path string, data []byte, err error) error {
+	var off int64
+	var syn *json.SyntaxError
+	var typ *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syn):
+		off = syn.Offset
+	case errors.As(err, &typ):
+		off = typ.Offset
+		err = fmt.Errorf("%s: cannot use %s as %s", typ.Field, typ.Value, typ.Type)
+	default:
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	line, col := position(data, off)
+	return fmt.Errorf("%s:%d:%d: %w", path, line, col, err)
+}
+
+// position converts a byte offset in data to a 1-based line and column.
+func position(data []byte, off int64) (line, col int) {
+	line, col = 1, 1
+	for _, b := range data[:min(int(off), len(data))] {
+		if b == '\n' {
+			line, col = line+1, 1
+		} else {
+			col++
+		}
+	}
+	return line, col
+}
@@ -0,0 +1,105 @@
+// Package cost estimates what a run will spend on paid providers before
+// it starts, so large runs can be confirmed first.
+package cost
+
+import (
+	"fmt"
+	"io"
+	"sort"
+)
+
+// DefaultPrices are indicative per-call prices in USD for the paid
+// services booktool can use. Plans differ; configure real prices with
+// the "costs" setting.
+var DefaultPrices = map[string]float64{
+	"isbndb": 0.001,
+	"vision": 0.001,
+	// Translations are billed by the character; these assume a book
+	// of about 1,500 characters.
+	"deepl":  0.04,
+	"google": 0.03,
+}
+
+// DefaultThreshold is the estimate above which a run asks for
+// confirmation.
+const DefaultThreshold = 1.0
+
+// Plan describes the calls a run may make.
+type Plan struct {
+	// ISBNRows have a valid ISBN and are looked up by ISBN.
+	ISBNRows int
+	// SearchRows have no usable ISBN and are searched by title.
+	SearchRows int
+	// ItemRows hold the barcode of an item other than a book and are
+	// looked up with Items only.
+	ItemRows int
+	// Providers lists the enabled providers in priority order.
+	Providers []string
+	// Items lists the enabled item providers in priority order.
+	Items []string
+	// Extra lists per-row calls of optional features (translation,
+	// LLM-generated text...) by service name.
+	Extra []string
+}
+
+// Line is the estimate for one paid service.
+type Line struct {
+	Service string
+	Calls   int
+	Cost    float64
+}
+
+// Estimate is the worst-case spending of a plan.
+type Estimate struct {
+	Lines []Line
+	Total float64
+}
+
+// Estimate computes the worst case: every provider may be reached for
+// every row, because earlier providers can miss. Each row that reaches a
+// provider is counted as one billable call.
+func (p *Plan) Estimate(prices map[string]float64) Estimate {
+	calls := make(map[string]int)
+	rows := p.ISBNRows + p.SearchRows
+	for _, name := range p.Providers {
+		calls[name] += rows
+	}
+	for _, name := range p.Items {
+		calls[name] += p.ItemRows
+	}
+	for _, name := range p.Extra {
+		calls[name] += rows + p.ItemRows
+	}
+	var e Estimate
+	for name, n := range calls {
+		price := prices[name]
+		if price <= 0 || n == 0 {
+			continue
+		}
+		l := Line{Service: name, Calls: n, Cost: float64(n) * price}
+		e.Lines = append(e.Lines, l)
+		e.Total += l.Cost
+	}
+	sort.Slice(e.Lines, func(i, j int) bool { return e.Lines[i].Service < e.Lines[j].Service })
+	return e
+}
+
+// Print writes a human-readable breakdown of e.
+func (e Estimate) Print(w io.Writer) {
+	for _, l := range e.Lines {
+		fmt.Fprintf(w, "  %-14s up to %6d calls  ~$%.2f\n", l.Service, l.Calls, l.Cost)
+	}
+	fmt.Fprintf(w, "  %-14s                    ~$%.2f\n", "total", e.Total)
+}
+
+// Merge returns DefaultPrices overridden by configured prices.
+func Merge(configured map[string]float64) map[string]float64 {
+	m := make(map[string]float64, len(DefaultPrices)+len(configured))
+	for k, v := range DefaultPrices {
+		m[k] = v
+	}
+	for k, v := range configured {
+		m[k] = v
+	}
+	return m
+}
@@ -0,0 +1,90 @@
+// Package country converts the country codes used by bibliographic
+// sources into ISO 3166-1 alpha-2 codes.
+package country
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/datatable"
+)
+
+// TableVersion is the date the MARC country table was last refreshed.
+const TableVersion = "2025-03-01"
+
+func init() {
+	datatable.Register(datatable.Table{
+		Name:    "marc-countries",
+		Summary: "MARC 21 country codes",
+		Version: TableVersion,
+		Export:  func() ([]byte, error) { return json.MarshalIndent(marcCodes, "", "  ") },
+		Load:    loadTable,
+	})
+}
+
+// loadTable replaces marcCodes with a downloaded table.
+func loadTable(data []byte) error {
+	var codes map[string]string
+	if err := json.Unmarshal(data, &codes); err != nil {
+		return err
+	}
+	if len(codes) == 0 {
+		return errors.New("no country codes")
+	}
+	marcCodes = codes
+	return nil
+}
+
+// marcCodes maps MARC 21 country codes (as found in OpenLibrary's
+// publish_country field) to ISO 3166-1 alpha-2. US states, Canadian
+// provinces, UK nations and Australian states are handled by suffix in
+// FromMARC and are not listed here.
+var marcCodes = map[string]string{
+	"aa": "AL", "ae": "DZ", "ag": "AR", "ai": "AM", "au": "AT", "be": "BE",
+	"bl": "BR", "bu": "BG", "cc": "CN", "ch": "TW", "ck": "CO", "cl": "CL",
+	"cs": "CZ", "cu": "CU", "cy": "CY", "dk": "DK", "ec": "EC", "er": "EE",
+	"fi": "FI", "fr": "FR", "gr": "GR", "gw": "DE", "hu": "HU", "ic": "IS",
+	"ie": "IE", "ii": "IN", "io": "ID", "ir": "IR", "is": "IL", "it": "IT",
+	"ja": "JP", "jo": "JO", "ko": "KR", "ku": "KW", "le": "LB", "li": "LT",
+	"lu": "LU", "lv": "LV", "mo": "MA", "mr": "MA", "mx": "MX", "my": "MY",
+	"ne": "NL", "no": "NO", "nz": "NZ", "pe": "PE", "ph": "PH", "pk": "PK",
+	"pl": "PL", "po": "PT", "qa": "QA", "rm": "RO", "ru": "RU", "sa": "ZA",
+	"si": "SG", "sp": "ES", "su": "SA", "sw": "SE", "sz": "CH", "th": "TH",
+	"ti": "TN", "tu": "TR", "ts": "AE", "ua": "EG", "un": "UA", "uy": "UY",
+	"ve": "VE", "vm": "VN", "xo": "SK", "xv": "SI", "ye": "YE", "sy": "SY",
+	"iq": "IQ", "xx": "",
+}
+
+// FromMARC converts a MARC country code such as "nyu", "enk" or "fr" to
+// an ISO 3166-1 alpha-2 code. Unknown codes yield "".
+func FromMARC(code string) string {
+	c := strings.ToLower(strings.TrimSpace(code))
+	switch {
+	case c == "":
+		return ""
+	case len(c) == 3 && c[2] == 'u':
+		return "US"
+	case len(c) == 3 && c[2] == 'k':
+		return "GB"
+	case len(c) == 3 && c[2] == 'c':
+		return "CA"
+	case len(c) == 3 && c[2] == 'a' && c != "xxa":
+		return "AU"
+	}
+	return marcCodes[c]
+}
+
+// Valid reports whether code has the form of an ISO 3166-1 alpha-2
+// code, two letters in either case.
+func Valid(code string) bool {
+	if len(code) != 2 {
+		return false
+	}
+	for _, r := range strings.ToUpper(code) {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,212 @@
+package cover
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// DefaultAltTemplate is the alt text written for covers when no
+// template is configured.
+const DefaultAltTemplate = `Cover of {{.FullTitle}}{{with .Authors}} by {{names .}}{{end}}`
+
+// Alt text sources: the template alone, or a vision model describing
+// the downloaded cover with the template as fallback.
+const (
+	AltTemplate = "template"
+	AltVision   = "vision"
+)
+
+// AltSources lists the alt text sources.
+var AltSources = []string{AltTemplate, AltVision}
+
+// VisionService names the vision model in credentials and cost
+// estimates.
+const VisionService = "vision"
+
+// Defaults of Vision.
+const (
+	DefaultVisionURL   = "https://api.openai.com/v1/chat/completions"
+	DefaultVisionModel = "gpt-4o-mini"
+)
+
+// ParseAltTemplate parses an alt text template. Templates are executed
+// with the book record, as in "{{.FullTitle}} ({{.Year}})", and can
+// join names as "A, B and C" with names.
+func ParseAltTemplate(s string) (*template.Template, error) {
+	t, err := template.New("alt").Funcs(template.FuncMap{"names": book.Names}).Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("alt text template: %w", err)
+	}
+	return t, nil
+}
+
+// Alt writes alt text for covers, for the product images of web shops
+// and catalogs.
+type Alt struct {
+	// Template renders the alt text from the book record; nil uses
+	// DefaultAltTemplate.
+	Template *template.Template
+	// Vision describes downloaded covers; nil, or a failure, falls back
+	// to the template.
+	Vision *Vision
+}
+
+// Text returns the alt text of b's cover, whose downloaded image, if
+// any, is at file.
+func (a *Alt) Text(ctx context.Context, b *book.BookInfo, file string) (string, error) {
+	if a.Vision != nil && file != "" {
+		text, err := a.Vision.Describe(ctx, file, b)
+		if err == nil {
+			return text, nil
+		}
+		alt, terr := a.template(b)
+		if terr != nil {
+			return "", terr
+		}
+		return alt, err
+	}
+	return a.template(b)
+}
+
+func (a *Alt) template(b *book.BookInfo) (string, error) {
+	t := a.Template
+	if t == nil {
+		t = template.Must(ParseAltTemplate(DefaultAltTemplate))
+	}
+	var buf strings.Builder
+	if err := t.Execute(&buf, b); err != nil {
+		return "", fmt.Errorf("alt text template: %w", err)
+	}
+	return strings.Join(strings.Fields(buf.String()), " "), nil
+}
+
+// Vision describes covers with a vision model behind an OpenAI-style
+// chat completions endpoint. Descriptions are saved next to the covers
+// and reused while the image is unchanged, so each cover is only paid
+// for once.
+type Vision struct {
+	HTTPClient *http.Client
+	// URL is the chat completions endpoint; empty uses
+	// DefaultVisionURL.
+	URL    string
+	APIKey string
+	// Model is the model name; empty uses DefaultVisionModel.
+	Model string
+}
+
+// altExt is the extension of saved descriptions.
+const altExt = ".alt.txt"
+
+// prompt asks for the alt text of b's cover.
+func prompt(b *book.BookInfo) string {
+	var s strings.Builder
+	s.WriteString("Write the alt text of this book cover for a web shop: one sentence of at most 150 characters ")
+	s.WriteString("that names the book and describes the artwork, without starting with \"Image of\". ")
+	fmt.Fprintf(&s, "The book is %q", b.FullTitle())
+	if len(b.Authors) > 0 {
+		fmt.Fprintf(&s, " by %s", book.Names(b.Authors))
+	}
+	s.WriteString(". Reply with the alt text only.")
+	return s.String()
+}
+
+// Describe returns the description of the cover at file.
+func (v *Vision) Describe(ctx context.Context, file string, b *book.BookInfo) (string, error) {
+	saved := strings.TrimSuffix(file, filepath.Ext(file)) + altExt
+	if fresh(saved, file) {
+		if data, err := os.ReadFile(saved); err == nil {
+			return strings.TrimSpace(string(data)), nil
+		}
+	}
+	img, err := os.ReadFile(file)
+	if err != nil {
+		return "", err
+	}
+	dataURL := "data:" + http.DetectContentType(img) + ";base64," + base64.StdEncoding.EncodeToString(img)
+	model := v.Model
+	if model == "" {
+		model = DefaultVisionModel
+	}
+	body, err := json.Marshal(map[string]any{
+		"model":      model,
+		"max_tokens": 100,
+		"messages": []any{map[string]any{
+			"role": "user",
+			"content": []any{
+				map[string]any{"type": "text", "text": prompt(b)},
+				map[string]any{"type": "image_url", "image_url": map[string]string{"url": dataURL}},
+			},
+		}},
+	})
+	if err != nil {
+		return "", err
+	}
+	text, err := v.complete(ctx, body)
+	if err != nil {
+		return "", fmt.Errorf("describe cover: %w", err)
+	}
+	return text, writeAtomic(saved, []byte(text+"\n"))
+}
+
+func (v *Vision) complete(ctx context.Context, body []byte) (string, error) {
+	u := v.URL
+	if u == "" {
+		u = DefaultVisionURL
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", provider.UserAgent)
+	req.Header.Set("Content-Type", "application/json")
+	if v.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+v.APIKey)
+	}
+	c := v.HTTPClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+	var out struct {
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		return "", err
+	}
+	if len(out.Choices) == 0 {
+		return "", errors.New("empty response")
+	}
+	text := strings.Join(strings.Fields(out.Choices[0].Message.Content), " ")
+	if text == "" {
+		return "", errors.New("empty response")
+	}
+	return strings.Trim(text, `"`), nil
+}
@@ -0,0 +1,165 @@
+// Package cover downloads cover images and extracts their dominant
+// colors, which web shops use to theme product cards and to sort
+// displays by color, and writes alt text for them.
+package cover
+
+import (
+	"context"
+	"fmt"
+	_ "image/gif" // decoders for Palette
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// DefaultColors is the palette size used when Fetcher.Colors is zero.
+const DefaultColors = 5
+
+// maxSize caps the size of a downloaded cover.
+const maxSize = 20 << 20
+
+// Cover is a downloaded cover.
+type Cover struct {
+	// File is the path of the image, named by ISBN.
+	File string
+	// WebFile is the processed version of File, when processing is
+	// enabled.
+	WebFile string
+	// Palette lists the dominant colors as #rrggbb, most common first.
+	Palette []string
+	// Hash is the perceptual hash of the image, and SameAs the ISBN of
+	// an earlier book with a matching cover, when covers are indexed.
+	Hash   Hash
+	SameAs string
+	// Placeholder is set for images that look like a provider's generic
+	// stand-in rather than cover art: blank images, and covers shared
+	// by many books.
+	Placeholder bool
+}
+
+// Fetcher downloads covers into a directory, once per ISBN.
+type Fetcher struct {
+	HTTPClient *http.Client
+	Dir        string
+	// Colors is the palette size; negative skips the palette.
+	Colors int
+	// Process makes web versions of the covers; nil skips it.
+	Process *Processing
+	// Index finds matching covers; nil skips the comparison.
+	Index *Index
+}
+
+// extensions maps the image types sniffed by http.DetectContentType to
+// file extensions.
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Fetch downloads the cover at url for the book with ISBN-13 isbn13,
+// unless a cover for that ISBN is already in the directory, extracts its
+// palette and hash, and makes its web version.
+func (f *Fetcher) Fetch(ctx context.Context, isbn13, url string) (Cover, error) {
+	var c Cover
+	file, err := f.existing(isbn13)
+	if err != nil {
+		return c, err
+	}
+	if file == "" {
+		if file, err = f.download(ctx, isbn13, url); err != nil {
+			return c, err
+		}
+	}
+	c.File = file
+	img, err := decode(file)
+	if err != nil {
+		return c, err
+	}
+	n := f.Colors
+	if n == 0 {
+		n = DefaultColors
+	}
+	for _, col := range Palette(img, max(n, 0)) {
+		c.Palette = append(c.Palette, Hex(col))
+	}
+	c.Hash = PerceptualHash(img)
+	c.Placeholder = blank(img)
+	// Blank images all look alike; they are not the same cover.
+	if f.Index != nil && !c.Placeholder {
+		sameAs, shared := f.Index.Add(isbn13, c.Hash)
+		c.SameAs, c.Placeholder = sameAs, c.Placeholder || shared
+	}
+	if f.Process != nil {
+		if c.WebFile, err = f.Process.Process(ctx, file, isbn13); err != nil {
+			return c, err
+		}
+	}
+	return c, nil
+}
+
+// existing returns the cover already downloaded for isbn13, or "".
+func (f *Fetcher) existing(isbn13 string) (string, error) {
+	for _, ext := range extensions {
+		p := filepath.Join(f.Dir, isbn13+ext)
+		if _, err := os.Stat(p); err == nil {
+			return p, nil
+		}
+	}
+	return "", nil
+}
+
+func (f *Fetcher) download(ctx context.Context, isbn13, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("User-Agent", provider.UserAgent)
+	c := f.HTTPClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("cover %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize))
+	if err != nil {
+		return "", err
+	}
+	ext, ok := extensions[http.DetectContentType(data)]
+	if !ok {
+		return "", fmt.Errorf("cover %s: not an image", url)
+	}
+	if err := os.MkdirAll(f.Dir, 0o755); err != nil {
+		return "", err
+	}
+	p := filepath.Join(f.Dir, isbn13+ext)
+	return p, writeAtomic(p, data)
+}
+
+// PaletteFile decodes the image at path and returns its palette of n
+// colors.
+func PaletteFile(path string, n int) ([]string, error) {
+	img, err := decode(path)
+	if err != nil {
+		return nil, err
+	}
+	var hex []string
+	for _, c := range Palette(img, n) {
+		hex = append(hex, Hex(c))
+	}
+	return hex, nil
+}
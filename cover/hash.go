@@ -0,0 +1,120 @@
+package cover
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"sync"
+
+	"golang.org/x/image/draw"
+)
+
+// Hash is a perceptual hash of an image: 64 bits that change little when
+// the image is scaled, recompressed or slightly retouched, so that the
+// same cover art served by two providers hashes alike.
+type Hash uint64
+
+// DefaultPlaceholderCount is a PlaceholderCount for runs over varied
+// books, where five different books rarely share their cover art.
+const DefaultPlaceholderCount = 5
+
+// DefaultMaxDistance is the number of differing bits up to which two
+// hashes are taken for the same image.
+const DefaultMaxDistance = 6
+
+// PerceptualHash returns the difference hash of img: each bit tells
+// whether a pixel of a 9x8 grayscale thumbnail is brighter than its
+// right neighbor.
+func PerceptualHash(img image.Image) Hash {
+	g := thumbnail(img)
+	var h Hash
+	for y := range 8 {
+		for x := range 8 {
+			h <<= 1
+			if g.GrayAt(x, y).Y > g.GrayAt(x+1, y).Y {
+				h |= 1
+			}
+		}
+	}
+	return h
+}
+
+func thumbnail(img image.Image) *image.Gray {
+	g := image.NewGray(image.Rect(0, 0, 9, 8))
+	draw.ApproxBiLinear.Scale(g, g.Bounds(), img, img.Bounds(), draw.Src, nil)
+	return g
+}
+
+// Distance returns the number of bits that differ between h and o.
+func (h Hash) Distance(o Hash) int {
+	return bits.OnesCount64(uint64(h ^ o))
+}
+
+func (h Hash) String() string {
+	return fmt.Sprintf("%016x", uint64(h))
+}
+
+// blank reports whether img is too small or too uniform to be real
+// cover art, like the blank images some providers serve for books
+// without a cover.
+func blank(img image.Image) bool {
+	b := img.Bounds()
+	if b.Dx() < 16 || b.Dy() < 16 {
+		return true
+	}
+	g := thumbnail(img)
+	var sum, sq float64
+	for _, v := range g.Pix {
+		sum += float64(v)
+		sq += float64(v) * float64(v)
+	}
+	n := float64(len(g.Pix))
+	mean := sum / n
+	return math.Sqrt(sq/n-mean*mean) < 3
+}
+
+// Index finds covers that look alike across the books of a run. It is
+// safe for concurrent use.
+type Index struct {
+	// MaxDistance is the largest distance at which covers match; zero
+	// selects DefaultMaxDistance.
+	MaxDistance int
+	// PlaceholderCount is the number of books sharing a cover from which
+	// it is taken for a generic placeholder; zero disables the check.
+	PlaceholderCount int
+
+	mu      sync.Mutex
+	entries []indexEntry
+}
+
+type indexEntry struct {
+	hash  Hash
+	isbn  string
+	count int
+}
+
+// Add records the cover of isbn13 and returns the ISBN of an earlier
+// book with a matching cover, or "" for the first of its kind, and
+// whether the cover is shared by enough books to be a placeholder.
+func (x *Index) Add(isbn13 string, h Hash) (sameAs string, placeholder bool) {
+	limit := x.MaxDistance
+	if limit == 0 {
+		limit = DefaultMaxDistance
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	for i := range x.entries {
+		e := &x.entries[i]
+		if e.hash.Distance(h) > limit {
+			continue
+		}
+		if e.isbn == isbn13 {
+			return "", x.PlaceholderCount > 0 && e.count >= x.PlaceholderCount
+		}
+		e.count++
+		return e.isbn, x.PlaceholderCount > 0 && e.count >= x.PlaceholderCount
+	}
+	x.entries = append(x.entries, indexEntry{hash: h, isbn: isbn13, count: 1})
+	return "", false
+}
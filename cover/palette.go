@@ -0,0 +1,76 @@
+package cover
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"slices"
+)
+
+// sampleSize bounds the pixels examined on each axis; covers are
+// downsampled to about this size first.
+const sampleSize = 128
+
+// minDistance is the squared RGB distance under which two colors count
+// as the same one in a palette.
+const minDistance = 48 * 48
+
+// Palette returns up to n dominant colors of img, most common first.
+// Pixels are grouped into buckets of similar colors; each bucket is
+// represented by the mean of its pixels, and buckets too close to a more
+// common one are skipped so that the palette does not repeat a color
+// in several shades.
+func Palette(img image.Image, n int) []color.RGBA {
+	type bucket struct {
+		r, g, b, count int
+	}
+	buckets := make(map[int]*bucket)
+	bounds := img.Bounds()
+	step := max(1, max(bounds.Dx(), bounds.Dy())/sampleSize)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A < 128 {
+				continue
+			}
+			// Four bits per channel.
+			key := int(c.R>>4)<<8 | int(c.G>>4)<<4 | int(c.B>>4)
+			bk := buckets[key]
+			if bk == nil {
+				bk = new(bucket)
+				buckets[key] = bk
+			}
+			bk.r += int(c.R)
+			bk.g += int(c.G)
+			bk.b += int(c.B)
+			bk.count++
+		}
+	}
+	sorted := make([]*bucket, 0, len(buckets))
+	for _, bk := range buckets {
+		sorted = append(sorted, bk)
+	}
+	slices.SortFunc(sorted, func(a, b *bucket) int { return b.count - a.count })
+
+	var palette []color.RGBA
+	for _, bk := range sorted {
+		if len(palette) == n {
+			break
+		}
+		c := color.RGBA{uint8(bk.r / bk.count), uint8(bk.g / bk.count), uint8(bk.b / bk.count), 255}
+		if !slices.ContainsFunc(palette, func(p color.RGBA) bool { return distance(p, c) < minDistance }) {
+			palette = append(palette, c)
+		}
+	}
+	return palette
+}
+
+func distance(a, b color.RGBA) int {
+	dr, dg, db := int(a.R)-int(b.R), int(a.G)-int(b.G), int(a.B)-int(b.B)
+	return dr*dr + dg*dg + db*db
+}
+
+// Hex formats c as #rrggbb.
+func Hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
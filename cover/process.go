@@ -0,0 +1,211 @@
+package cover
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/draw"
+)
+
+// WebDir is the subdirectory of the cover directory holding processed
+// covers.
+const WebDir = "web"
+
+// DefaultQuality is the encoding quality used when Processing.Quality is
+// zero.
+const DefaultQuality = 80
+
+// The encoders run for the formats Go cannot write.
+const (
+	DefaultCWebP   = "cwebp"
+	DefaultAVIFEnc = "avifenc"
+)
+
+// Formats lists the formats covers can be converted to.
+var Formats = []string{"jpeg", "png", "webp", "avif"}
+
+// Processing turns downloaded covers into web-ready images: scaled down
+// to fit a box, optionally cropped to a square, and re-encoded.
+type Processing struct {
+	// MaxWidth and MaxHeight bound the size; zero leaves a side
+	// unbounded. Covers are never scaled up.
+	MaxWidth, MaxHeight int
+	// Square crops covers to their centered square before scaling.
+	Square bool
+	// Format is one of Formats; empty selects jpeg.
+	Format string
+	// Quality is the lossy encoding quality, 1 to 100.
+	Quality int
+	// CWebP and AVIFEnc override the encoder commands.
+	CWebP, AVIFEnc string
+}
+
+// Process writes the web version of the cover at src into the WebDir
+// subdirectory next to it, named by ISBN, and returns its path. A web
+// version newer than src is kept.
+func (p *Processing) Process(ctx context.Context, src, isbn13 string) (string, error) {
+	format := p.Format
+	if format == "" {
+		format = "jpeg"
+	}
+	ext := "." + format
+	if format == "jpeg" {
+		ext = ".jpg"
+	}
+	dst := filepath.Join(filepath.Dir(src), WebDir, isbn13+ext)
+	if fresh(dst, src) {
+		return dst, nil
+	}
+	img, err := decode(src)
+	if err != nil {
+		return "", err
+	}
+	if p.Square {
+		img = cropSquare(img)
+	}
+	img = p.fit(img)
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return "", err
+	}
+	quality := p.Quality
+	if quality == 0 {
+		quality = DefaultQuality
+	}
+	var buf bytes.Buffer
+	switch format {
+	case "jpeg":
+		if err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err == nil {
+			err = writeAtomic(dst, buf.Bytes())
+		}
+	case "png":
+		if err = png.Encode(&buf, img); err == nil {
+			err = writeAtomic(dst, buf.Bytes())
+		}
+	case "webp":
+		err = p.encode(ctx, img, dst, cmdOr(p.CWebP, DefaultCWebP), "-quiet", "-q", strconv.Itoa(quality), "{in}", "-o", "{out}")
+	case "avif":
+		err = p.encode(ctx, img, dst, cmdOr(p.AVIFEnc, DefaultAVIFEnc), "-q", strconv.Itoa(quality), "{in}", "{out}")
+	default:
+		err = fmt.Errorf("unknown cover format %q (want %s)", format, strings.Join(Formats, ", "))
+	}
+	if err != nil {
+		return "", err
+	}
+	return dst, nil
+}
+
+// fresh reports whether dst exists and is not older than src.
+func fresh(dst, src string) bool {
+	d, err := os.Stat(dst)
+	if err != nil {
+		return false
+	}
+	s, err := os.Stat(src)
+	return err == nil && !d.ModTime().Before(s.ModTime())
+}
+
+func decode(path string) (image.Image, error) {
+	fh, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+	img, _, err := image.Decode(fh)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", path, err)
+	}
+	return img, nil
+}
+
+func cropSquare(img image.Image) image.Image {
+	b := img.Bounds()
+	side := min(b.Dx(), b.Dy())
+	x, y := b.Min.X+(b.Dx()-side)/2, b.Min.Y+(b.Dy()-side)/2
+	dst := image.NewRGBA(image.Rect(0, 0, side, side))
+	draw.Draw(dst, dst.Bounds(), img, image.Pt(x, y), draw.Src)
+	return dst
+}
+
+// fit scales img down to fit MaxWidth by MaxHeight, keeping its aspect
+// ratio.
+func (p *Processing) fit(img image.Image) image.Image {
+	b := img.Bounds()
+	scale := 1.0
+	if p.MaxWidth > 0 && b.Dx() > p.MaxWidth {
+		scale = float64(p.MaxWidth) / float64(b.Dx())
+	}
+	if p.MaxHeight > 0 && float64(b.Dy())*scale > float64(p.MaxHeight) {
+		scale = float64(p.MaxHeight) / float64(b.Dy())
+	}
+	if scale == 1 {
+		return img
+	}
+	w, h := max(1, int(float64(b.Dx())*scale+0.5)), max(1, int(float64(b.Dy())*scale+0.5))
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, b, draw.Src, nil)
+	return dst
+}
+
+// encode writes img through an external encoder, passing it a PNG
+// file; "{in}" and "{out}" in args stand for the input and output
+// paths.
+func (p *Processing) encode(ctx context.Context, img image.Image, dst, command string, args ...string) error {
+	in, err := os.CreateTemp(filepath.Dir(dst), ".cover-*.png")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(in.Name())
+	if err := png.Encode(in, img); err != nil {
+		in.Close()
+		return err
+	}
+	if err := in.Close(); err != nil {
+		return err
+	}
+	tmp := dst + ".part"
+	for i, a := range args {
+		switch a {
+		case "{in}":
+			args[i] = in.Name()
+		case "{out}":
+			args[i] = tmp
+		}
+	}
+	var out bytes.Buffer
+	cmd := exec.CommandContext(ctx, command, args...)
+	cmd.Stdout, cmd.Stderr = &out, &out
+	if err := cmd.Run(); err != nil {
+		os.Remove(tmp)
+		if msg := strings.TrimSpace(out.String()); msg != "" {
+			return fmt.Errorf("%s: %w: %s", command, err, msg)
+		}
+		return fmt.Errorf("%s: %w", command, err)
+	}
+	return os.Rename(tmp, dst)
+}
+
+func cmdOr(cmd, def string) string {
+	if cmd == "" {
+		return def
+	}
+	return cmd
+}
+
+// writeAtomic writes data to path through a temporary file, so that a
+// crash leaves no truncated image to be mistaken for a complete one.
+func writeAtomic(path string, data []byte) error {
+	tmp := path + ".part"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,110 @@
+// Package customs derives the fields needed on export paperwork: country
+// of origin, a Harmonized System (HS) tariff code and a plain customs
+// description.
+package customs
+
+import (
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/tax"
+)
+
+// HS codes used for books and book-like goods.
+const (
+	HSPrintedBook   = "4901.99" // printed books, brochures and similar
+	HSDictionary    = "4901.91" // dictionaries and encyclopaedias
+	HSChildrensBook = "4903.00" // children's picture, drawing or colouring books
+	HSPrintedMusic  = "4904.00" // printed or manuscript music
+	HSRecordedMedia = "8523.49" // recorded optical media (audio CDs)
+)
+
+// maxDescription is the longest description most carrier forms accept.
+const maxDescription = 70
+
+// Options configures the derivation.
+type Options struct {
+	// DefaultOrigin is used when the provider does not report where the
+	// book was published, typically the seller's own country.
+	DefaultOrigin string
+}
+
+// Fields are the customs columns of one book.
+type Fields struct {
+	Origin      string
+	HSCode      string
+	Description string
+}
+
+// Derive computes the customs fields for b in the given edition format.
+// Providers report the country of publication, not of printing; it is
+// the best available proxy and is what most sellers declare.
+func (o Options) Derive(b *book.BookInfo, format string) Fields {
+	f := Fields{
+		Origin: strings.ToUpper(b.PublishCountry),
+		HSCode: HSCode(format, b.Subjects),
+	}
+	if f.Origin == "" {
+		f.Origin = strings.ToUpper(o.DefaultOrigin)
+	}
+	f.Description = Description(b, format)
+	return f
+}
+
+// HSCode picks the tariff code for a book of the given format and
+// subjects. Digital editions are not shipped and yield "".
+func HSCode(format string, subjects []string) string {
+	switch tax.CategoryFor(format) {
+	case tax.Digital:
+		return ""
+	case tax.Audio:
+		return HSRecordedMedia
+	}
+	for _, s := range subjects {
+		s = strings.ToLower(s)
+		switch {
+		case strings.Contains(s, "dictionar"), strings.Contains(s, "encyclop"):
+			return HSDictionary
+		case strings.Contains(s, "picture book"), strings.Contains(s, "coloring"),
+			strings.Contains(s, "colouring"), strings.Contains(s, "board book"):
+			return HSChildrensBook
+		case strings.Contains(s, "sheet music"), strings.Contains(s, "scores"):
+			return HSPrintedMusic
+		}
+	}
+	return HSPrintedBook
+}
+
+// Description builds a short, customs-friendly description such as
+// "Printed book - The Hobbit (ISBN 9780261103573)".
+func Description(b *book.BookInfo, format string) string {
+	kind := "Printed book"
+	switch tax.CategoryFor(format) {
+	case tax.Audio:
+		kind = "Audiobook on CD"
+	case tax.Digital:
+		kind = "Electronic book"
+	}
+	d := kind
+	if b.Title != "" {
+		d += " - " + b.Title
+	}
+	if isbn := b.ISBN(); isbn != "" {
+		suffix := " (ISBN " + isbn + ")"
+		if r := []rune(d); len(r)+len(suffix) > maxDescription {
+			d = string(r[:max(len(kind), maxDescription-len(suffix)-1)]) + "…"
+		}
+		d += suffix
+	}
+	return d
+}
+
+// Columns returns the header names of the customs columns.
+func Columns() []string {
+	return []string{"Country of Origin", "HS Code", "Customs Description"}
+}
+
+// Row formats f as the cells matching Columns.
+func Row(f Fields) []string {
+	return []string{f.Origin, f.HSCode, f.Description}
+}
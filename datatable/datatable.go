@@ -0,0 +1,120 @@
+// Package datatable keeps track of the versioned mapping tables built
+// into booktool, such as language codes, and of the versions published
+// online. Packages owning a table register it from an init function;
+// newer versions downloaded into a data directory replace the built-in
+// tables at startup, so taxonomy refreshes do not need a release.
+package datatable
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+)
+
+// DefaultIndexURL lists the latest published tables.
+const DefaultIndexURL = "https://raw.githubusercontent.com/SouadAli10/book_scrapping_tool/main/data/index.json"
+
+// Table describes a built-in mapping table.
+type Table struct {
+	Name    string
+	Summary string
+	// Version is the date the table was last refreshed, as YYYY-MM-DD,
+	// so versions compare as strings.
+	Version string
+	// Export encodes the built-in table as a published table file.
+	Export func() ([]byte, error)
+	// Load replaces the table with the contents of a table file. It is
+	// only called at startup, before the table is used.
+	Load func(data []byte) error
+	// Installed is set by LoadDir when a downloaded version replaced
+	// the built-in table.
+	Installed bool
+}
+
+var (
+	mu     sync.RWMutex
+	tables = make(map[string]Table)
+)
+
+// Register records a built-in table. It panics if the name is already
+// taken, like database/sql.Register.
+func Register(t Table) {
+	mu.Lock()
+	defer mu.Unlock()
+	if t.Export == nil || t.Load == nil {
+		panic("datatable: Register missing Export or Load for " + t.Name)
+	}
+	if _, dup := tables[t.Name]; dup {
+		panic("datatable: Register called twice for " + t.Name)
+	}
+	tables[t.Name] = t
+}
+
+// All returns the registered tables sorted by name.
+func All() []Table {
+	mu.RLock()
+	defer mu.RUnlock()
+	all := make([]Table, 0, len(tables))
+	for _, t := range tables {
+		all = append(all, t)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// Index is the list of published tables. Data directories hold one as
+// well, listing the installed tables.
+type Index struct {
+	Tables map[string]Entry `json:"tables"`
+}
+
+// Entry describes one published table.
+type Entry struct {
+	Version string `json:"version"`
+	// URL locates the table file, relative to the index. In a data
+	// directory it is the file name.
+	URL    string `json:"url"`
+	SHA256 string `json:"sha256"`
+}
+
+// FetchIndex downloads the index at rawURL and resolves the entry URLs
+// against it.
+func FetchIndex(ctx context.Context, c *http.Client, rawURL string) (*Index, error) {
+	base, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: %s", rawURL, resp.Status)
+	}
+	var idx Index
+	if err := json.NewDecoder(resp.Body).Decode(&idx); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", rawURL, err)
+	}
+	for name, e := range idx.Tables {
+		if u, err := base.Parse(e.URL); err == nil {
+			e.URL = u.String()
+			idx.Tables[name] = e
+		}
+	}
+	return &idx, nil
+}
+
+// Newer reports whether the index has a newer version of t.
+func (idx *Index) Newer(t Table) (Entry, bool) {
+	e, ok := idx.Tables[t.Name]
+	return e, ok && e.Version > t.Version
+}
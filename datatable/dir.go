@@ -0,0 +1,184 @@
+package datatable
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// IndexFile is the name of the index in data directories.
+const IndexFile = "index.json"
+
+// maxTableSize bounds downloaded table files.
+const maxTableSize = 16 << 20
+
+// readIndex reads the index of data directory dir; a missing index is
+// an empty one.
+func readIndex(dir string) (*Index, error) {
+	idx := &Index{Tables: make(map[string]Entry)}
+	data, err := os.ReadFile(filepath.Join(dir, IndexFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return idx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, idx); err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Join(dir, IndexFile), err)
+	}
+	if idx.Tables == nil {
+		idx.Tables = make(map[string]Entry)
+	}
+	return idx, nil
+}
+
+func writeIndex(dir string, idx *Index) error {
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFile(filepath.Join(dir, IndexFile), append(data, '\n'))
+}
+
+// writeFile replaces path atomically, so a failed update keeps the
+// previous file.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadDir replaces the built-in tables with the newer versions installed
+// in dir. Tables that fail to load keep their built-in version; the
+// errors are returned together.
+func LoadDir(dir string) error {
+	idx, err := readIndex(dir)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	var errs []error
+	for name, e := range idx.Tables {
+		t, ok := tables[name]
+		if !ok || e.Version <= t.Version {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, filepath.Base(e.URL)))
+		if err == nil {
+			err = t.Load(data)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("table %s: %w", name, err))
+			continue
+		}
+		t.Version, t.Installed = e.Version, true
+		tables[name] = t
+	}
+	return errors.Join(errs...)
+}
+
+// Update downloads the tables of the index at indexURL that are newer
+// than the ones in use into dir, and loads them. It returns the names of
+// the updated tables.
+func Update(ctx context.Context, c *http.Client, indexURL, dir string) ([]string, error) {
+	remote, err := FetchIndex(ctx, c, indexURL)
+	if err != nil {
+		return nil, err
+	}
+	local, err := readIndex(dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var updated []string
+	for _, t := range All() {
+		e, ok := remote.Newer(t)
+		if !ok {
+			continue
+		}
+		data, err := download(ctx, c, e)
+		if err != nil {
+			return updated, fmt.Errorf("table %s: %w", t.Name, err)
+		}
+		if err := t.Load(data); err != nil {
+			return updated, fmt.Errorf("table %s: %w", t.Name, err)
+		}
+		file := t.Name + ".json"
+		if err := writeFile(filepath.Join(dir, file), data); err != nil {
+			return updated, err
+		}
+		local.Tables[t.Name] = Entry{Version: e.Version, URL: file, SHA256: e.SHA256}
+		if err := writeIndex(dir, local); err != nil {
+			return updated, err
+		}
+		mu.Lock()
+		t.Version, t.Installed = e.Version, true
+		tables[t.Name] = t
+		mu.Unlock()
+		updated = append(updated, t.Name)
+	}
+	return updated, nil
+}
+
+// download fetches a table file and checks its digest.
+func download(ctx context.Context, c *http.Client, e Entry) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s: %s", e.URL, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxTableSize))
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != e.SHA256 {
+		return nil, fmt.Errorf("%s: checksum mismatch", e.URL)
+	}
+	return data, nil
+}
+
+// Export writes the built-in tables and their index to dir, in the
+// layout served at DefaultIndexURL.
+func Export(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	idx := &Index{Tables: make(map[string]Entry)}
+	for _, t := range All() {
+		data, err := t.Export()
+		if err != nil {
+			return fmt.Errorf("table %s: %w", t.Name, err)
+		}
+		if !bytes.HasSuffix(data, []byte("\n")) {
+			data = append(data, '\n')
+		}
+		file := t.Name + ".json"
+		if err := writeFile(filepath.Join(dir, file), data); err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		idx.Tables[t.Name] = Entry{Version: t.Version, URL: file, SHA256: hex.EncodeToString(sum[:])}
+	}
+	return writeIndex(dir, idx)
+}
@@ -0,0 +1,24 @@
+package enrich
+
+import (
+	"github.com/SouadAli10/book_scrapping_tool/gtin"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+)
+
+// DedupeKey returns the key under which duplicate rows are detected: the
+// ISBN-13 for rows with a valid ISBN, the EAN-13 for rows with the
+// barcode of another item, otherwise the normalized title and author.
+// Rows with none of them return "".
+func DedupeKey(row *input.Row) string {
+	if gtin.IsItem(row.ISBN) {
+		return "gtin:" + gtin.To13(row.ISBN)
+	}
+	if code := isbn.Normalize(row.ISBN); isbn.Valid(code) {
+		return "isbn:" + isbn.To13(code)
+	}
+	if t := normTitle(row.Title); t != "" {
+		return "title:" + t + "|" + normName(row.Author)
+	}
+	return ""
+}
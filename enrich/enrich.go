@@ -0,0 +1,588 @@
+// Package enrich runs input rows through the configured providers and
+// produces canonical book records. It is the library entry point used by
+// the booktool command and by applications embedding the enricher.
+package enrich
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/author"
+	"github.com/SouadAli10/book_scrapping_tool/authority"
+	"github.com/SouadAli10/book_scrapping_tool/bisac"
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/cover"
+	"github.com/SouadAli10/book_scrapping_tool/family"
+	"github.com/SouadAli10/book_scrapping_tool/gtin"
+	"github.com/SouadAli10/book_scrapping_tool/imprint"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/price"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/series"
+	"github.com/SouadAli10/book_scrapping_tool/subject"
+	"github.com/SouadAli10/book_scrapping_tool/translate"
+	"github.com/SouadAli10/book_scrapping_tool/validate"
+)
+
+// DefaultWorkers is the number of rows looked up concurrently when
+// Enricher.Workers is zero.
+const DefaultWorkers = 4
+
+// Enricher looks up books with a list of providers, in priority order.
+type Enricher struct {
+	Providers []provider.Provider
+	// Items look up rows whose code is the barcode of an item other
+	// than a book, such as a DVD or a board game, in priority order.
+	// Item rows skip Providers. When Items is empty such codes are
+	// reported as invalid ISBNs.
+	Items []provider.ItemProvider
+	// Subjects cleans up the subject headings after the lookup; nil
+	// keeps them as the providers report them.
+	Subjects *subject.Normalizer
+	// BISAC maps the subjects to BISAC codes after the lookup.
+	BISAC bool
+	// Imprints resolves the imprint and parent group of the publisher
+	// after the lookup.
+	Imprints bool
+	// Series resolves series information after the lookup; nil skips it.
+	Series *series.Resolver
+	// Authors looks up the authority data of the authors after the
+	// lookup; nil skips it.
+	Authors *authority.Resolver
+	// Family looks up the other editions of the book after the lookup;
+	// nil skips it.
+	Family *family.Resolver
+	// Prices looks up market prices after the lookup; nil skips it.
+	Prices *price.Lookup
+	// Covers downloads covers after the lookup; nil skips it.
+	Covers *cover.Fetcher
+	// Alt writes alt text for the covers; nil skips it.
+	Alt *cover.Alt
+	// Translate translates the title, subjects and description after
+	// the lookup; nil skips it.
+	Translate *translate.Translator
+	// Merge queries every provider concurrently for ISBN lookups and
+	// merges their records, the first provider taking precedence. When
+	// false the first provider that knows the ISBN wins.
+	Merge bool
+	// MinMatch is the lowest confidence at which a title/author search
+	// result is accepted; zero selects DefaultMinMatch.
+	MinMatch float64
+	// Dedupe looks up rows sharing a DedupeKey only once; later rows
+	// reuse the first row's result and are flagged by Result.DuplicateOf.
+	Dedupe  bool
+	Workers int
+	// RowTimeout bounds the lookup of one row by Run, across all its
+	// providers and extra lookups; rows taking longer fail with
+	// ErrRowTimeout and the run goes on. Zero means no limit.
+	RowTimeout time.Duration
+	// Order selects the order in which Run emits results.
+	Order Order
+	// Validation checks the records found against the rules of package
+	// validate, reporting or rejecting those breaking them.
+	Validation validate.Mode
+}
+
+// ErrRowTimeout is the error of rows whose lookup took longer than
+// Enricher.RowTimeout.
+var ErrRowTimeout = errors.New("lookup timed out")
+
+// Order is the order in which results are emitted.
+type Order int
+
+const (
+	// InputOrder emits results in input order, holding back rows that
+	// finish before their predecessors.
+	InputOrder Order = iota
+	// CompletionOrder emits each result as soon as its lookup finishes;
+	// Result.Row.Index tells where it belongs.
+	CompletionOrder
+)
+
+// ParseOrder parses "input" or "completion"; the empty string selects
+// InputOrder.
+func ParseOrder(s string) (Order, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "input":
+		return InputOrder, nil
+	case "completion":
+		return CompletionOrder, nil
+	}
+	return 0, fmt.Errorf("unknown order %q (want input or completion)", s)
+}
+
+func (o Order) String() string {
+	if o == CompletionOrder {
+		return "completion"
+	}
+	return "input"
+}
+
+// Result is the outcome of enriching one row. Book is nil when Err is
+// set.
+type Result struct {
+	Row  *input.Row
+	Book *book.BookInfo
+	Err  error
+	// DuplicateOf is the index of the first row with the same DedupeKey,
+	// or -1 when the row is not a duplicate or Dedupe is off.
+	DuplicateOf int
+	// seq is the position of the row among the rows read by Run, which
+	// may start at any Row.Index.
+	seq int
+}
+
+type job struct {
+	row         *input.Row
+	duplicateOf int
+	seq         int
+}
+
+// Lookup enriches a single row. Rows with a valid ISBN are looked up by
+// ISBN; otherwise, or when no provider knows the ISBN, the title and
+// author are searched. Rows holding the barcode of another item are
+// looked up with Items only, when it is set. The record found is then
+// validated as Validation selects. A rejected record is not returned:
+// its row fails with an error wrapping validate.ErrInvalid that lists
+// the violations, so that no writer takes it for a good record.
+func (e *Enricher) Lookup(ctx context.Context, row *input.Row) (*book.BookInfo, error) {
+	b, err := e.lookup(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	if err := validate.Check(e.Validation, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func (e *Enricher) lookup(ctx context.Context, row *input.Row) (*book.BookInfo, error) {
+	if len(e.Items) > 0 && gtin.IsItem(row.ISBN) {
+		return e.lookupItem(ctx, gtin.Normalize(row.ISBN))
+	}
+	var errs []error
+	if code := isbn.Normalize(row.ISBN); isbn.Valid(code) {
+		results := e.lookupEach(len(e.Providers), func(i int) (*book.BookInfo, error) {
+			return e.Providers[i].LookupISBN(ctx, code)
+		})
+		var merged *book.BookInfo
+		for i, res := range results {
+			if res.err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", e.Providers[i].Name(), res.err))
+				continue
+			}
+			if merged == nil {
+				merged = res.book
+			} else {
+				merged.Merge(res.book)
+			}
+		}
+		if merged != nil {
+			merged.MatchMethod, merged.MatchConfidence = "isbn", 1
+			return e.finish(ctx, merged), nil
+		}
+	} else if row.ISBN != "" {
+		errs = append(errs, fmt.Errorf("invalid ISBN %q", row.ISBN))
+	}
+	if row.Title != "" {
+		minMatch := e.MinMatch
+		if minMatch <= 0 {
+			minMatch = DefaultMinMatch
+		}
+		for _, p := range e.Providers {
+			bs, err := p.Search(ctx, row.Title, row.Author)
+			if err == nil && len(bs) == 0 {
+				err = provider.ErrNotFound
+			}
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s search: %w", p.Name(), err))
+				continue
+			}
+			b, score := bestMatch(row.Title, row.Author, bs)
+			if score < minMatch {
+				errs = append(errs, fmt.Errorf("%s search: best match %q scored %.2f, below %.2f",
+					p.Name(), b.FullTitle(), score, minMatch))
+				continue
+			}
+			b = edition(ctx, p, b)
+			b.MatchMethod, b.MatchConfidence = "search", score
+			return e.finish(ctx, b), nil
+		}
+	}
+	if len(errs) == 0 {
+		return nil, provider.ErrNotFound
+	}
+	return nil, errors.Join(errs...)
+}
+
+// edition returns the record of the edition of ISBN of b when b is a
+// work found by a search, such as an OpenLibrary search result, so that
+// the ISBNs, publisher and date of the match all describe that edition.
+// b is returned as found when p does not know the ISBN.
+func edition(ctx context.Context, p provider.Provider, b *book.BookInfo) *book.BookInfo {
+	if !b.WorkLevel || b.ISBN13 == "" {
+		return b
+	}
+	ed, err := p.LookupISBN(ctx, b.ISBN13)
+	if err != nil {
+		return b
+	}
+	if ed.OLWorkID == "" {
+		ed.OLWorkID = b.OLWorkID
+	}
+	return ed
+}
+
+type lookupResult struct {
+	book *book.BookInfo
+	err  error
+}
+
+// lookupEach asks n providers for a code through lookup, which queries
+// provider i. With Merge every provider is queried concurrently and the
+// results are returned in priority order; otherwise providers are tried
+// in turn until one knows the code, and the providers after it are left
+// out of the results.
+func (e *Enricher) lookupEach(n int, lookup func(i int) (*book.BookInfo, error)) []lookupResult {
+	results := make([]lookupResult, n)
+	if !e.Merge {
+		for i := range n {
+			b, err := lookup(i)
+			results[i] = lookupResult{b, err}
+			if err == nil {
+				return results[:i+1]
+			}
+		}
+		return results
+	}
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b, err := lookup(i)
+			results[i] = lookupResult{b, err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// lookupItem looks up the barcode code of an item other than a book with
+// the item providers. Items have no title search to fall back on.
+func (e *Enricher) lookupItem(ctx context.Context, code string) (*book.BookInfo, error) {
+	results := e.lookupEach(len(e.Items), func(i int) (*book.BookInfo, error) {
+		return e.Items[i].LookupBarcode(ctx, code)
+	})
+	var merged *book.BookInfo
+	var errs []error
+	for i, res := range results {
+		if res.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", e.Items[i].Name(), res.err))
+			continue
+		}
+		if merged == nil {
+			merged = res.book
+		} else {
+			merged.Merge(res.book)
+		}
+	}
+	if merged == nil {
+		return nil, errors.Join(errs...)
+	}
+	if merged.GTIN == "" {
+		merged.GTIN = code
+	}
+	if merged.Kind == "" {
+		merged.Kind = "item"
+	}
+	merged.MatchMethod, merged.MatchConfidence = "barcode", 1
+	return e.finish(ctx, merged), nil
+}
+
+func (e *Enricher) finish(ctx context.Context, b *book.BookInfo) *book.BookInfo {
+	b.Authors = author.Dedupe(b.Authors)
+	if e.Subjects != nil {
+		b.Subjects = e.Subjects.Normalize(b.Subjects)
+	}
+	if e.BISAC {
+		b.BISAC = bisac.Map(b.Subjects)
+	}
+	if e.Imprints {
+		if im, ok := imprint.Resolve(b.Publishers); ok {
+			b.Imprint, b.ParentPublisher = im.Name, im.Parent
+		}
+	}
+	// Series are a book matter.
+	if e.Series != nil && b.Kind == "" {
+		e.Series.Resolve(ctx, b).Apply(b)
+	}
+	if b.Series != "" && b.Kind == "" {
+		e.fillVolume(ctx, b)
+	}
+	if e.Authors != nil && len(b.Authors) > 0 {
+		ds, err := e.Authors.Resolve(ctx, b.Authors)
+		if err != nil {
+			b.Warn("author details: %v", err)
+		}
+		b.AuthorDetails = ds
+	}
+	if e.Family != nil && b.Kind == "" && b.ISBN() != "" {
+		f, err := e.Family.Resolve(ctx, b)
+		if err != nil {
+			b.Warn("isbn family: %v", err)
+		}
+		f.Apply(b)
+	}
+	if e.Translate != nil {
+		t, err := e.Translate.Translate(ctx, b)
+		if err != nil {
+			b.Warn("translation: %v", err)
+		}
+		b.Translation = t
+	}
+	if e.Prices != nil {
+		if code := isbn.To13(b.ISBN()); isbn.Valid13(code) {
+			p, err := e.Prices.Lookup(ctx, code)
+			if err != nil {
+				b.Warn("price lookup failed: %v", err)
+			}
+			b.Prices = p
+		}
+	}
+	if e.Covers != nil && b.CoverURL != "" {
+		if code := coverKey(b); code != "" {
+			c, err := e.Covers.Fetch(ctx, code, b.CoverURL)
+			if err != nil {
+				b.Warn("cover download failed: %v", err)
+			}
+			b.CoverFile, b.CoverWebFile, b.CoverPalette = c.File, c.WebFile, c.Palette
+			if c.File != "" {
+				b.CoverHash, b.CoverSameAs = c.Hash.String(), c.SameAs
+			}
+			if c.Placeholder {
+				b.Warn("cover looks like a generic placeholder")
+			}
+		}
+	}
+	if e.Alt != nil && b.CoverURL != "" {
+		file := b.CoverWebFile
+		if file == "" {
+			file = b.CoverFile
+		}
+		alt, err := e.Alt.Text(ctx, b, file)
+		if err != nil {
+			b.Warn("cover alt text: %v", err)
+		}
+		b.CoverAlt = alt
+	}
+	checkRecord(b)
+	return b
+}
+
+// fillVolume fills the gaps of b from the providers that know the
+// volumes of its series, such as manga databases, which have no ISBNs to
+// be asked by.
+func (e *Enricher) fillVolume(ctx context.Context, b *book.BookInfo) {
+	for _, p := range e.Providers {
+		vp, ok := p.(provider.VolumeProvider)
+		if !ok || p.Name() == b.Source {
+			continue
+		}
+		v, err := vp.LookupVolume(ctx, b.Series, b.SeriesPosition)
+		if errors.Is(err, provider.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			b.Warn("%s volume lookup failed: %v", p.Name(), err)
+			continue
+		}
+		b.Fill(v)
+	}
+}
+
+// coverKey returns the code the cover files of b are named by: its
+// ISBN-13, or the EAN-13 of an item. It returns "" when b has neither.
+func coverKey(b *book.BookInfo) string {
+	if code := isbn.To13(b.ISBN()); isbn.Valid13(code) {
+		return code
+	}
+	if gtin.Valid(b.GTIN) {
+		return gtin.To13(b.GTIN)
+	}
+	return ""
+}
+
+// lookupWithin looks a row up like Lookup, giving up after RowTimeout.
+// Lookups are expected to honor ctx, but one that does not is left
+// behind rather than holding the worker.
+func (e *Enricher) lookupWithin(ctx context.Context, row *input.Row) (*book.BookInfo, error) {
+	if e.RowTimeout <= 0 {
+		return e.Lookup(ctx, row)
+	}
+	parent := ctx
+	ctx, cancel := context.WithTimeout(ctx, e.RowTimeout)
+	defer cancel()
+	type answer struct {
+		b   *book.BookInfo
+		err error
+	}
+	done := make(chan answer, 1)
+	go func() {
+		b, err := e.Lookup(ctx, row)
+		done <- answer{b, err}
+	}()
+	select {
+	case a := <-done:
+		if a.err == nil || parent.Err() != nil || ctx.Err() == nil {
+			return a.b, a.err
+		}
+	case <-ctx.Done():
+		if parent.Err() != nil {
+			return nil, parent.Err()
+		}
+	}
+	return nil, fmt.Errorf("%w after %s", ErrRowTimeout, e.RowTimeout)
+}
+
+// Run reads every row from r, enriches rows concurrently and calls emit
+// with the results, in the order selected by e.Order. Failed lookups are reported through
+// Result.Err; Run itself only fails on read errors, context cancellation
+// or an error returned by emit. Run returns only once it has stopped
+// reading r, so r can be closed then; a Next call that blocks delays
+// the return until it does.
+func (e *Enricher) Run(ctx context.Context, r input.Reader, emit func(*Result) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := e.Workers
+	if workers <= 0 {
+		workers = DefaultWorkers
+	}
+	jobs := make(chan job)
+	results := make(chan *Result)
+	readErr := make(chan error, 1)
+
+	go func() {
+		defer close(jobs)
+		seen := make(map[string]int)
+		seq := 0
+		for {
+			row, err := r.Next()
+			if err == io.EOF {
+				readErr <- nil
+				return
+			}
+			if err != nil {
+				readErr <- err
+				return
+			}
+			j := job{row: row, duplicateOf: -1, seq: seq}
+			seq++
+			if e.Dedupe {
+				if key := DedupeKey(row); key != "" {
+					if first, ok := seen[key]; ok {
+						j.duplicateOf = first
+					} else {
+						seen[key] = row.Index
+					}
+				}
+			}
+			select {
+			case jobs <- j:
+			case <-ctx.Done():
+				readErr <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				res := &Result{Row: j.row, DuplicateOf: j.duplicateOf, seq: j.seq}
+				if j.duplicateOf < 0 {
+					res.Book, res.Err = e.lookupWithin(ctx, j.row)
+				}
+				select {
+				case results <- res:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Duplicates are emitted after their first row, whose result is kept
+	// in firsts to be copied.
+	firsts := make(map[int]*Result)
+	deliver := func(p *Result) error {
+		if e.Dedupe {
+			if p.DuplicateOf >= 0 {
+				f := firsts[p.DuplicateOf]
+				p.Book, p.Err = f.Book, f.Err
+			} else {
+				firsts[p.Row.Index] = p
+			}
+		}
+		return emit(p)
+	}
+	abort := func(err error) error {
+		cancel()
+		for range results {
+		}
+		<-readErr
+		return err
+	}
+	// In input order, rows that finish early are held back in pending
+	// until their predecessors have been emitted. In completion order,
+	// only duplicates whose first row is still running wait, in waiting.
+	pending := make(map[int]*Result)
+	waiting := make(map[int][]*Result)
+	next := 0
+	for res := range results {
+		if e.Order == CompletionOrder {
+			if e.Dedupe && res.DuplicateOf >= 0 && firsts[res.DuplicateOf] == nil {
+				waiting[res.DuplicateOf] = append(waiting[res.DuplicateOf], res)
+				continue
+			}
+			batch := append([]*Result{res}, waiting[res.Row.Index]...)
+			delete(waiting, res.Row.Index)
+			for _, p := range batch {
+				if err := deliver(p); err != nil {
+					return abort(err)
+				}
+			}
+			continue
+		}
+		pending[res.seq] = res
+		for {
+			p, ok := pending[next]
+			if !ok {
+				break
+			}
+			delete(pending, next)
+			next++
+			if err := deliver(p); err != nil {
+				return abort(err)
+			}
+		}
+	}
+	if err := <-readErr; err != nil {
+		return err
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,411 @@
+package enrich_test
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/validate"
+)
+
+// stub is a provider answering from a fixed set of records: books by
+// ISBN, after their delay, and found for every search.
+type stub struct {
+	books map[string]book.BookInfo
+	delay map[string]time.Duration
+	found []book.BookInfo
+}
+
+func (*stub) Name() string { return "stub" }
+
+func (s *stub) LookupISBN(ctx context.Context, code string) (*book.BookInfo, error) {
+	select {
+	case <-time.After(s.delay[code]):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	b, ok := s.books[code]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+	b.ISBN13, b.Source = code, "stub"
+	return &b, nil
+}
+
+func (s *stub) Search(ctx context.Context, title, author string) ([]*book.BookInfo, error) {
+	if len(s.found) == 0 {
+		return nil, provider.ErrNotFound
+	}
+	out := make([]*book.BookInfo, len(s.found))
+	for i := range s.found {
+		b := s.found[i]
+		out[i] = &b
+	}
+	return out, nil
+}
+
+// rows reads a CSV input listing ISBNs.
+func rows(t *testing.T, isbns ...string) input.Reader {
+	t.Helper()
+	r, err := input.NewReader(strings.NewReader("ISBN\n"+strings.Join(isbns, "\n")+"\n"), "csv", "", input.Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRejectedRecordsAreNotWritten(t *testing.T) {
+	const good, bad = "9780306406157", "9781861972712"
+	e := &enrich.Enricher{
+		Providers: []provider.Provider{&stub{books: map[string]book.BookInfo{
+			good: {Title: "Accepted Title", Pages: 200},
+			bad:  {Title: "Rejected Title", Pages: -5},
+		}}},
+		Validation: validate.Reject,
+	}
+	table := &columns.Table{Fields: columns.Default, Options: &columns.Options{}}
+
+	var csvOut bytes.Buffer
+	cw, err := output.NewCSV(&csvOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := sql.Open("recorder", t.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	sw, err := output.NewSQL(db, output.Postgres, "books")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []output.Writer{cw, sw} {
+		if err := w.WriteHeader(table.Header()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = e.Run(context.Background(), rows(t, good, bad), func(res *enrich.Result) error {
+		if res.Row.ISBN == bad && (!errors.Is(res.Err, validate.ErrInvalid) || res.Book != nil) {
+			t.Errorf("rejected row: book %v, error %v; want no book and ErrInvalid", res.Book, res.Err)
+		}
+		rec := &output.Record{Index: res.Row.Index, Book: res.Book, Cells: table.Row(res), Err: res.Err}
+		for _, w := range []output.Writer{cw, sw} {
+			if err := w.Write(rec); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range []output.Writer{cw, sw} {
+		if err := w.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if s := csvOut.String(); !strings.Contains(s, "Accepted Title") || strings.Contains(s, "Rejected Title") {
+		t.Errorf("CSV output:\n%s\nwant the accepted record only", s)
+	}
+	var upserted []string
+	for _, args := range recorded(t.Name()) {
+		upserted = append(upserted, fmt.Sprintln(args...))
+	}
+	all := strings.Join(upserted, "")
+	if !strings.Contains(all, "Accepted Title") || strings.Contains(all, "Rejected Title") {
+		t.Errorf("SQL upserts:\n%s\nwant the accepted record only", all)
+	}
+}
+
+func TestSearchMatchOfWork(t *testing.T) {
+	// A work with three editions, of which the search names the second.
+	work := book.BookInfo{
+		Title: "The Hobbit", Authors: []string{"J.R.R. Tolkien"},
+		Publishers:  []string{"Allen & Unwin", "Houghton Mifflin", "HarperCollins"},
+		PublishDate: "1937", ISBN13: "9780618260300", ISBN10: "0618260307",
+		WorkLevel: true, Source: "stub",
+	}
+	edition := book.BookInfo{
+		Title: "The Hobbit", Authors: []string{"J.R.R. Tolkien"},
+		Publishers: []string{"Houghton Mifflin"}, PublishDate: "2002", Binding: "Paperback",
+		ISBN10: "0618260307",
+	}
+	fields := []columns.Field{columns.Default[0]}
+	for _, f := range columns.Default {
+		if f.Header == columns.MatchedISBNHeader || f.Header == "Matched Edition" {
+			fields = append(fields, f)
+		}
+	}
+	table := &columns.Table{Fields: fields, Options: &columns.Options{}}
+	for _, tc := range []struct {
+		name  string
+		books map[string]book.BookInfo
+		// want are the input ISBN, Matched ISBN and Matched Edition
+		// cells.
+		want []string
+	}{
+		{"edition known", map[string]book.BookInfo{"9780618260300": edition}, []string{"9780618260300", "9780618260300", "Paperback, Houghton Mifflin, 2002"}},
+		{"edition unknown", nil, []string{"", "N/A", "N/A"}},
+	} {
+		e := &enrich.Enricher{Providers: []provider.Provider{&stub{books: tc.books, found: []book.BookInfo{work}}}}
+		row := &input.Row{Title: "The Hobbit", Author: "J.R.R. Tolkien"}
+		b, err := e.Lookup(context.Background(), row)
+		if err != nil {
+			t.Fatalf("%s: %v", tc.name, err)
+		}
+		cells := table.Row(&enrich.Result{Row: row, Book: b, DuplicateOf: -1})
+		n := len(input.Columns) + 1
+		got := []string{cells[0], cells[n+1], cells[n+2]}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s: ISBN, Matched ISBN, Matched Edition = %q, want %q", tc.name, got, tc.want)
+		}
+		if b.ISBN10 != "0618260307" {
+			t.Errorf("%s: isbn_10 = %q, want that of the matched edition", tc.name, b.ISBN10)
+		}
+	}
+}
+
+// Three books, of which the first takes longest to look up.
+const slow, fast1, fast2 = "9780306406157", "9781861972712", "9780261103573"
+
+func books() *stub {
+	return &stub{
+		books: map[string]book.BookInfo{slow: {Title: "Slow"}, fast1: {Title: "Fast One"}, fast2: {Title: "Fast Two"}},
+		delay: map[string]time.Duration{slow: 50 * time.Millisecond},
+	}
+}
+
+// collect runs e over isbns and returns the results in emitted order.
+func collect(t *testing.T, e *enrich.Enricher, isbns ...string) []*enrich.Result {
+	t.Helper()
+	var got []*enrich.Result
+	if err := e.Run(context.Background(), rows(t, isbns...), func(res *enrich.Result) error {
+		got = append(got, res)
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	return got
+}
+
+func TestRunOrder(t *testing.T) {
+	for _, tc := range []struct {
+		order enrich.Order
+		want  []int
+	}{
+		{enrich.InputOrder, []int{0, 1, 2}},
+		{enrich.CompletionOrder, []int{1, 2, 0}},
+	} {
+		e := &enrich.Enricher{Providers: []provider.Provider{books()}, Order: tc.order}
+		var got []int
+		for _, res := range collect(t, e, slow, fast1, fast2) {
+			if res.Err != nil {
+				t.Errorf("%s order: row %d: %v", tc.order, res.Row.Index, res.Err)
+			}
+			got = append(got, res.Row.Index)
+		}
+		// Completion order only settles the slow row as last; the two
+		// fast ones may come in either order.
+		if tc.order == enrich.CompletionOrder && len(got) == 3 && got[0] == 2 {
+			got[0], got[1] = got[1], got[0]
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("%s order: rows emitted as %v, want %v", tc.order, got, tc.want)
+		}
+	}
+}
+
+func TestRunDuplicates(t *testing.T) {
+	for _, order := range []enrich.Order{enrich.InputOrder, enrich.CompletionOrder} {
+		e := &enrich.Enricher{Providers: []provider.Provider{books()}, Order: order, Dedupe: true}
+		results := collect(t, e, slow, fast1, "978-0-306-40615-7", fast1)
+		if len(results) != 4 {
+			t.Fatalf("%s order: %d results, want 4", order, len(results))
+		}
+		emitted := map[int]int{}
+		dups := map[int]int{}
+		for i, res := range results {
+			emitted[res.Row.Index] = i
+			dups[res.Row.Index] = res.DuplicateOf
+		}
+		for row, first := range map[int]int{0: -1, 1: -1, 2: 0, 3: 1} {
+			if dups[row] != first {
+				t.Errorf("%s order: row %d duplicates %d, want %d", order, row, dups[row], first)
+			}
+			if first >= 0 && emitted[row] < emitted[first] {
+				t.Errorf("%s order: row %d emitted before its first row %d", order, row, first)
+			}
+		}
+		if b := results[emitted[2]].Book; b == nil || b.Title != "Slow" {
+			t.Errorf("%s order: duplicate row 2 has record %v, want that of row 0", order, b)
+		}
+	}
+}
+
+// gatedReader yields a row per ISBN, but its Next call for row gate
+// closes blocked and waits until open is closed. inNext counts the calls
+// in progress.
+type gatedReader struct {
+	isbns   []string
+	gate    int
+	blocked chan struct{}
+	open    chan struct{}
+	n       int
+	inNext  atomic.Int32
+}
+
+func (r *gatedReader) Next() (*input.Row, error) {
+	r.inNext.Add(1)
+	defer r.inNext.Add(-1)
+	if r.n == r.gate {
+		close(r.blocked)
+		<-r.open
+	}
+	if r.n == len(r.isbns) {
+		return nil, io.EOF
+	}
+	row := &input.Row{ISBN: r.isbns[r.n], Index: r.n}
+	r.n++
+	return row, nil
+}
+
+func (*gatedReader) Close() error { return nil }
+
+// A stopCase stops a run while the reader is blocked in Next on row gate:
+// by emitErr, or by cancelling once the reader is blocked.
+type stopCase struct {
+	name    string
+	workers int
+	isbns   []string
+	gate    int
+	emitErr error
+	cancel  bool
+	want    error
+}
+
+// TestRunStops checks that stopped runs only return once the reader is
+// done. Run with -race, this also checks that the reader is never used
+// once Run has returned.
+func TestRunStops(t *testing.T) {
+	for _, tc := range []stopCase{
+		{name: "emit error", workers: 2, isbns: []string{slow, fast1, fast2}, gate: 2, emitErr: errStop, want: errStop},
+		// The only worker is in the middle of its lookup when the run
+		// is cancelled, so nothing but the reader holds Run back.
+		{name: "cancel", workers: 1, isbns: []string{slow, fast1}, gate: 1, cancel: true, want: context.Canceled},
+	} {
+		// A cancelled worker may still hand its result over, and then
+		// waits for the reader like an idle one; repeat to see both.
+		for range 10 {
+			tc.run(t)
+		}
+	}
+}
+
+var errStop = errors.New("stop")
+
+func (tc stopCase) run(t *testing.T) {
+	t.Helper()
+	r := &gatedReader{
+		isbns:   tc.isbns,
+		gate:    tc.gate,
+		blocked: make(chan struct{}),
+		open:    make(chan struct{}),
+	}
+	defer r.Close()
+	e := &enrich.Enricher{Providers: []provider.Provider{books()}, Workers: tc.workers}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if tc.cancel {
+		go func() {
+			<-r.blocked
+			cancel()
+		}()
+	}
+	returned := make(chan error, 1)
+	var emits atomic.Int32
+	go func() {
+		returned <- e.Run(ctx, r, func(*enrich.Result) error {
+			emits.Add(1)
+			<-r.blocked
+			return tc.emitErr
+		})
+	}()
+	select {
+	case err := <-returned:
+		t.Fatalf("%s: Run returned %v while the reader was blocked", tc.name, err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(r.open)
+	if err := <-returned; !errors.Is(err, tc.want) {
+		t.Errorf("%s: Run returned %v, want %v", tc.name, err, tc.want)
+	}
+	if n := r.inNext.Load(); n != 0 {
+		t.Errorf("%s: %d Next calls still running after Run returned", tc.name, n)
+	}
+	if n := emits.Load(); tc.emitErr != nil && n != 1 {
+		t.Errorf("%s: emit called %d times, want once", tc.name, n)
+	}
+}
+
+// The recorder database/sql driver keeps the arguments of the statements
+// executed on each database name, so that the SQL writer can be tested
+// without a database server.
+func init() { sql.Register("recorder", recorder{}) }
+
+var (
+	recordedMu sync.Mutex
+	recordings = map[string][][]any{}
+)
+
+func recorded(name string) [][]any {
+	recordedMu.Lock()
+	defer recordedMu.Unlock()
+	return recordings[name]
+}
+
+type recorder struct{}
+
+func (recorder) Open(name string) (driver.Conn, error) { return recorderConn(name), nil }
+
+type recorderConn string
+
+func (c recorderConn) Prepare(query string) (driver.Stmt, error) { return recorderStmt(c), nil }
+func (recorderConn) Close() error                                { return nil }
+func (recorderConn) Begin() (driver.Tx, error)                   { return nil, errors.New("recorder: no transactions") }
+
+type recorderStmt string
+
+func (recorderStmt) Close() error  { return nil }
+func (recorderStmt) NumInput() int { return -1 }
+
+func (s recorderStmt) Exec(args []driver.Value) (driver.Result, error) {
+	recordedMu.Lock()
+	defer recordedMu.Unlock()
+	vs := make([]any, len(args))
+	for i, a := range args {
+		vs[i] = a
+	}
+	recordings[string(s)] = append(recordings[string(s)], vs)
+	return driver.RowsAffected(1), nil
+}
+
+func (recorderStmt) Query([]driver.Value) (driver.Rows, error) {
+	return nil, errors.New("recorder: no queries")
+}
@@ -0,0 +1,102 @@
+package enrich
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/fuzzy"
+)
+
+// DefaultMinMatch is the lowest confidence at which a title/author search
+// result is accepted when Enricher.MinMatch is zero.
+const DefaultMinMatch = 0.8
+
+// articles are dropped from the start of titles before comparing.
+var articles = map[string]bool{
+	"the": true, "a": true, "an": true, "le": true, "la": true, "les": true,
+	"l": true, "der": true, "die": true, "das": true, "el": true, "los": true,
+}
+
+// arabicForms folds the spellings of Arabic letters that catalogs use
+// interchangeably: the alef with and without hamza or madda, the final
+// ya with and without dots, and the ta marbuta written as ha.
+var arabicForms = strings.NewReplacer("أ", "ا", "إ", "ا", "آ", "ا", "ٱ", "ا", "ى", "ي", "ة", "ه")
+
+// foldMarks drops combining marks, such as the vowels of voweled Arabic,
+// and the tatweel, a stretch with no sound, which would otherwise split
+// words, and folds arabicForms, so that voweled and unvoweled spellings
+// compare equal.
+func foldMarks(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if unicode.Is(unicode.Mn, r) || r == 'ـ' {
+			return -1
+		}
+		return r
+	}, s)
+	return arabicForms.Replace(s)
+}
+
+// normTitle lower-cases s, replaces punctuation with spaces and drops a
+// leading article.
+func normTitle(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(foldMarks(s)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) > 1 && articles[words[0]] {
+		words = words[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+// normName reduces a person's name to its sorted lower-case tokens, so
+// "Tolkien, J. R. R." and "J.R.R. Tolkien" compare equal.
+func normName(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(foldMarks(s)), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// titleScore compares the wanted title with the candidate's title, with
+// and without its subtitle, and keeps the better score.
+func titleScore(want string, b *book.BookInfo) float64 {
+	w := normTitle(want)
+	return max(fuzzy.JaroWinkler(w, normTitle(b.Title)), fuzzy.JaroWinkler(w, normTitle(b.FullTitle())))
+}
+
+// authorScore compares the wanted author with the candidate's best
+// matching author.
+func authorScore(want string, b *book.BookInfo) float64 {
+	w := normName(want)
+	best := 0.0
+	for _, a := range b.Authors {
+		best = max(best, fuzzy.JaroWinkler(w, normName(a)))
+	}
+	return best
+}
+
+// MatchScore rates how well candidate b matches a title and optional
+// author, between 0 and 1. The title weighs more than the author, since
+// author spellings vary more between sources.
+func MatchScore(title, author string, b *book.BookInfo) float64 {
+	t := titleScore(title, b)
+	if author == "" {
+		return t
+	}
+	return 0.7*t + 0.3*authorScore(author, b)
+}
+
+// bestMatch returns the highest-scoring candidate and its score.
+func bestMatch(title, author string, cands []*book.BookInfo) (*book.BookInfo, float64) {
+	var best *book.BookInfo
+	bestScore := -1.0
+	for _, c := range cands {
+		if s := MatchScore(title, author, c); s > bestScore {
+			best, bestScore = c, s
+		}
+	}
+	return best, bestScore
+}
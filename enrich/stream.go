@@ -0,0 +1,87 @@
+package enrich
+
+import (
+	"context"
+	"errors"
+	"io"
+	"iter"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/input"
+)
+
+// RowResult is a result delivered by Stream, with the progress of the
+// stream when it was produced.
+type RowResult struct {
+	*Result
+	// Done counts the rows delivered so far, this one included.
+	Done int
+	// Elapsed is the time since the stream started.
+	Elapsed time.Duration
+}
+
+// Stream enriches rows like Run and delivers the results on the returned
+// channel, in the order selected by e.Order, as soon as they are ready.
+// Stream sets Row.Index to each row's position in rows.
+//
+// The channel is closed after the last row, or early when ctx is
+// cancelled; consumers that stop reading must cancel ctx. A sequence
+// that blocks, waiting for its next row, delays the close until it
+// yields or ends, since rows is only stopped once no row is being
+// pulled. Stream fails only when the Enricher cannot run at all.
+func (e *Enricher) Stream(ctx context.Context, rows iter.Seq[*input.Row]) (<-chan RowResult, error) {
+	if len(e.Providers) == 0 {
+		return nil, errors.New("enrich: no providers")
+	}
+	if rows == nil {
+		return nil, errors.New("enrich: nil rows")
+	}
+	out := make(chan RowResult)
+	next, stop := iter.Pull(rows)
+	r := &seqReader{next: next, stop: stop}
+	start := time.Now()
+	go func() {
+		defer close(out)
+		defer r.Close()
+		done := 0
+		// Run only fails here on cancellation, which the consumer
+		// observes through ctx.
+		e.Run(ctx, r, func(res *Result) error {
+			done++
+			select {
+			case out <- RowResult{Result: res, Done: done, Elapsed: time.Since(start)}:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		})
+	}()
+	return out, nil
+}
+
+// seqReader adapts a pulled iterator to input.Reader, numbering rows.
+type seqReader struct {
+	next func() (*input.Row, bool)
+	stop func()
+	n    int
+}
+
+func (s *seqReader) Next() (*input.Row, error) {
+	for {
+		row, ok := s.next()
+		if !ok {
+			return nil, io.EOF
+		}
+		if row == nil {
+			continue
+		}
+		row.Index = s.n
+		s.n++
+		return row, nil
+	}
+}
+
+func (s *seqReader) Close() error {
+	s.stop()
+	return nil
+}
@@ -0,0 +1,15 @@
+package enrich
+
+import (
+	"github.com/SouadAli10/book_scrapping_tool/book"
+)
+
+// checkRecord adds warnings about gaps in b that leave the row usable.
+func checkRecord(b *book.BookInfo) {
+	if b.PublishDate != "" && b.Year() == "" {
+		b.Warn("publish date %q not understood", b.PublishDate)
+	}
+	if b.CoverURL == "" {
+		b.Warn("cover missing")
+	}
+}
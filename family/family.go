@@ -0,0 +1,205 @@
+// Package family resolves the ISBN family of a book: the other editions
+// of its work, which for textbooks include the next and previous
+// editions, the international edition, the loose-leaf version and
+// bundles with an access code. Resellers need them to tell which ISBN a
+// buyback or a listing refers to, and international editions must be
+// flagged because they cannot be sold in every market.
+//
+// Families are read from the editions OpenLibrary groups under a work.
+package family
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/provider/openlibrary"
+)
+
+// MaxEditions is the number of editions of a work fetched; works with
+// more, mostly classics, are not textbook families.
+const MaxEditions = 100
+
+var (
+	// international matches the wording of international editions,
+	// e.g. "International Student Edition" or "Not for sale in the USA".
+	international = regexp.MustCompile(`(?i)\b(?:international|global|asian?|indian|eastern economy)(?:\s+\w+)?\s+ed(?:ition|n?\.)|not for sale in (?:the )?(?:usa|u\.s\.a?\.?|united states|north america|canada)`)
+	// looseLeaf matches unbound, three-hole-punched editions.
+	looseLeaf = regexp.MustCompile(`(?i)loose[- ]?leaf|binder[- ]ready|unbound`)
+	// bundle matches editions packaged with an access code to an online
+	// course platform.
+	bundle = regexp.MustCompile(`(?i)access (?:code|card|kit)|\bbundle\b|\b(?:mylab|mastering|connect|webassign|mindtap|wileyplus)\b`)
+	// number matches edition numbers, e.g. "5th ed." or "Fifth edition".
+	number = regexp.MustCompile(`(?i)\b(\d{1,2}(?:st|nd|rd|th)|first|second|third|fourth|fifth|sixth|seventh|eighth|ninth|tenth|eleventh|twelfth)\.?\s+ed(?:ition|n?\.|\b)`)
+)
+
+var ordinals = map[string]int{
+	"first": 1, "second": 2, "third": 3, "fourth": 4, "fifth": 5, "sixth": 6,
+	"seventh": 7, "eighth": 8, "ninth": 9, "tenth": 10, "eleventh": 11, "twelfth": 12,
+}
+
+// ordinal writes n as "1st", "2nd", "11th".
+func ordinal(n int) string {
+	suffix := "th"
+	if n%100 < 11 || n%100 > 13 {
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return strconv.Itoa(n) + suffix
+}
+
+// Describe labels an edition from its edition statement, title and
+// binding, e.g. "5th edition, international, paperback", and reports
+// whether it is an international edition.
+func Describe(edition, title, binding string) (label string, intl bool) {
+	text := edition + " " + title + " " + binding
+	var parts []string
+	if m := number.FindStringSubmatch(text); m != nil {
+		n, ok := ordinals[strings.ToLower(m[1])]
+		if !ok {
+			n, _ = strconv.Atoi(strings.TrimRight(strings.ToLower(m[1]), "stndrh"))
+		}
+		if n > 0 {
+			parts = append(parts, ordinal(n)+" edition")
+		}
+	}
+	intl = international.MatchString(text)
+	if intl {
+		parts = append(parts, "international")
+	}
+	loose := looseLeaf.MatchString(text)
+	if loose {
+		parts = append(parts, "loose-leaf")
+	}
+	if bundle.MatchString(text) {
+		parts = append(parts, "with access code")
+	}
+	if f := book.ParseFormat(binding); !loose && f != "" && f != book.OtherFormat {
+		parts = append(parts, string(f))
+	}
+	label = strings.Join(parts, ", ")
+	if label != "" {
+		label = strings.ToUpper(label[:1]) + label[1:]
+	}
+	return label, intl
+}
+
+// Family is the ISBN family of one book.
+type Family struct {
+	// International is set when the book is an international edition.
+	International bool
+	// Siblings are the other editions of its work.
+	Siblings []book.Sibling
+	// Truncated is set when the work has more editions than were
+	// fetched.
+	Truncated bool
+}
+
+// Apply records f on b.
+func (f Family) Apply(b *book.BookInfo) {
+	b.Siblings = f.Siblings
+	b.International = b.International || f.International
+	if f.Truncated {
+		b.Warn("only the first %d editions of the work are listed", openlibrary.MaxWorkEditions)
+	}
+}
+
+// Resolver looks up ISBN families in OpenLibrary.
+type Resolver struct {
+	OpenLibrary *openlibrary.Client
+	// All lists the editions of the work on every page, up to
+	// openlibrary.MaxWorkEditions, rather than the first MaxEditions.
+	All bool
+}
+
+// Resolve returns the family of b. A book OpenLibrary does not know has
+// no siblings; whether it is an international edition is then told from
+// its own title and binding.
+func (r *Resolver) Resolve(ctx context.Context, b *book.BookInfo) (Family, error) {
+	own := isbn.To13(b.ISBN())
+	_, intl := Describe("", b.FullTitle(), b.Binding)
+	f := Family{International: intl}
+	work := b.OLWorkID
+	if work == "" && isbn.Valid13(own) {
+		e, err := r.OpenLibrary.Edition(ctx, own)
+		if errors.Is(err, provider.ErrNotFound) {
+			return f, nil
+		}
+		if err != nil {
+			return f, err
+		}
+		if len(e.Works) > 0 {
+			work = e.Works[0].ID()
+		}
+	}
+	if work == "" {
+		return f, nil
+	}
+	limit := MaxEditions
+	if r.All {
+		limit = openlibrary.MaxWorkEditions
+	}
+	eds, err := r.OpenLibrary.WorkEditions(ctx, work, limit)
+	if errors.Is(err, provider.ErrNotFound) {
+		return f, nil
+	}
+	if err != nil {
+		return f, fmt.Errorf("editions of %s: %w", work, err)
+	}
+	f.Truncated = r.All && len(eds) >= limit
+	seen := map[string]bool{own: true}
+	for _, e := range eds {
+		code := editionISBN(&e)
+		label, intl := Describe(e.EditionName, strings.TrimSpace(string(e.Title)+" "+e.Subtitle), e.PhysicalFormat)
+		if code != "" && code == own {
+			f.International = f.International || intl
+		}
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+		sib := book.Sibling{
+			ISBN13:        code,
+			Label:         label,
+			International: intl,
+			Title:         strings.TrimSpace(string(e.Title)),
+			PublishDate:   string(e.PublishDate),
+			Binding:       e.PhysicalFormat,
+			Pages:         int(e.Pages),
+			Languages:     e.LanguageCodes(),
+		}
+		if len(e.Publishers) > 0 {
+			sib.Publisher = e.Publishers[0]
+		}
+		f.Siblings = append(f.Siblings, sib)
+	}
+	return f, nil
+}
+
+// editionISBN returns the ISBN-13 of e, converting its ISBN-10 when it
+// has no other.
+func editionISBN(e *openlibrary.Edition) string {
+	for _, c := range e.ISBN13 {
+		if c = isbn.Normalize(c); isbn.Valid13(c) {
+			return c
+		}
+	}
+	for _, c := range e.ISBN10 {
+		if c = isbn.To13(c); isbn.Valid13(c) {
+			return c
+		}
+	}
+	return ""
+}
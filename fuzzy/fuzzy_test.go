@@ -0,0 +1,79 @@
+package fuzzy_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/SouadAli10/book_scrapping_tool/fuzzy"
+)
+
+func TestLevenshtein(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"sitting", "kitten", 3},
+		{"flaw", "lawn", 2},
+		{"Misérables", "Miserables", 1},
+		{"東京", "京都", 2},
+	} {
+		if got := fuzzy.Levenshtein(tc.a, tc.b); got != tc.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
+
+func TestClosest(t *testing.T) {
+	candidates := []string{"isbn", "title", "author", "quantity"}
+	for _, tc := range []struct {
+		s    string
+		want string
+		ok   bool
+	}{
+		{"title", "title", true},
+		{"tilte", "title", true},
+		{"autor", "author", true},
+		{"quantitiy", "quantity", true},
+		{"publisher", "", false},
+	} {
+		got, ok := fuzzy.Closest(tc.s, candidates)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("Closest(%q) = %q, %v, want %q, %v", tc.s, got, ok, tc.want, tc.ok)
+		}
+	}
+	if got, ok := fuzzy.Closest("title", nil); ok {
+		t.Errorf("Closest among none = %q, want none", got)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	for _, tc := range []struct {
+		a, b                     string
+		jaro, jaroWinkler, ratio float64
+	}{
+		{"", "", 1, 1, 1},
+		{"abc", "", 0, 0, 0},
+		{"same", "same", 1, 1, 1},
+		{"abc", "xyz", 0, 0, 0},
+		{"MARTHA", "MARHTA", 0.9444, 0.9611, 0.6667},
+		{"DIXON", "DICKSONX", 0.7667, 0.8133, 0.5},
+	} {
+		for _, f := range []struct {
+			name string
+			fn   func(a, b string) float64
+			want float64
+		}{
+			{"Jaro", fuzzy.Jaro, tc.jaro},
+			{"JaroWinkler", fuzzy.JaroWinkler, tc.jaroWinkler},
+			{"Ratio", fuzzy.Ratio, tc.ratio},
+		} {
+			if got := f.fn(tc.a, tc.b); math.Abs(got-f.want) > 0.0001 {
+				t.Errorf("%s(%q, %q) = %.4f, want %.4f", f.name, tc.a, tc.b, got, f.want)
+			}
+		}
+	}
+}
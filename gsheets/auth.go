@@ -0,0 +1,178 @@
+package gsheets
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Scope grants read and write access to spreadsheets.
+const Scope = "https://www.googleapis.com/auth/spreadsheets"
+
+// DefaultTokenURL is the OAuth token endpoint used when a service account
+// key does not name one.
+const DefaultTokenURL = "https://oauth2.googleapis.com/token"
+
+// TokenSource supplies OAuth 2.0 access tokens.
+type TokenSource interface {
+	Token(ctx context.Context) (string, error)
+}
+
+// StaticToken is an access token obtained elsewhere, e.g. with
+// "gcloud auth print-access-token".
+type StaticToken string
+
+// Token implements TokenSource.
+func (t StaticToken) Token(context.Context) (string, error) { return string(t), nil }
+
+// ServiceAccount exchanges a service account key for access tokens. The
+// spreadsheets must be shared with the account's email address.
+type ServiceAccount struct {
+	Email      string
+	PrivateKey *rsa.PrivateKey
+	TokenURL   string
+	HTTPClient *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// keyFile is the JSON key downloaded from the Google Cloud console.
+type keyFile struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// LoadServiceAccount reads a service account JSON key file.
+func LoadServiceAccount(path string) (*ServiceAccount, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("gsheets: %w", err)
+	}
+	sa, err := ParseServiceAccount(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w in %s", err, path)
+	}
+	return sa, nil
+}
+
+// ParseServiceAccount parses a service account JSON key.
+func ParseServiceAccount(data []byte) (*ServiceAccount, error) {
+	var k keyFile
+	if err := json.Unmarshal(data, &k); err != nil {
+		return nil, fmt.Errorf("gsheets: parse service account key: %w", err)
+	}
+	if k.Type != "service_account" {
+		return nil, fmt.Errorf("gsheets: not a service account key (type %q)", k.Type)
+	}
+	block, _ := pem.Decode([]byte(k.PrivateKey))
+	if block == nil {
+		return nil, errors.New("gsheets: no PEM private key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("gsheets: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("gsheets: private key is not RSA")
+	}
+	if k.TokenURI == "" {
+		k.TokenURI = DefaultTokenURL
+	}
+	return &ServiceAccount{Email: k.ClientEmail, PrivateKey: rsaKey, TokenURL: k.TokenURI}, nil
+}
+
+// Token implements TokenSource. Tokens are reused until shortly before
+// they expire.
+func (s *ServiceAccount) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Until(s.expires) > time.Minute {
+		return s.token, nil
+	}
+	assertion, err := s.assertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	c := s.HTTPClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("gsheets: token: %w", err)
+	}
+	defer resp.Body.Close()
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tok); err != nil {
+		return "", fmt.Errorf("gsheets: token: %w", err)
+	}
+	if tok.AccessToken == "" {
+		if tok.Error == "" {
+			tok.Error = resp.Status
+		}
+		return "", fmt.Errorf("gsheets: token: %s %s", tok.Error, tok.Description)
+	}
+	s.token = tok.AccessToken
+	s.expires = time.Now().Add(time.Duration(tok.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// assertion builds the signed JWT exchanged for an access token.
+func (s *ServiceAccount) assertion(now time.Time) (string, error) {
+	if s.PrivateKey == nil {
+		return "", errors.New("gsheets: service account has no private key")
+	}
+	enc := base64.RawURLEncoding
+	header := enc.EncodeToString([]byte(`{"alg":"RS256","typ":"JWT"}`))
+	claims, err := json.Marshal(map[string]any{
+		"iss":   s.Email,
+		"scope": Scope,
+		"aud":   s.TokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", err
+	}
+	signed := header + "." + enc.EncodeToString(claims)
+	sum := sha256.Sum256([]byte(signed))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, s.PrivateKey, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", err
+	}
+	return signed + "." + enc.EncodeToString(sig), nil
+}
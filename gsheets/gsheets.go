@@ -0,0 +1,260 @@
+// Package gsheets reads input rows from and writes enriched rows to
+// Google Sheets through the Sheets API v4.
+package gsheets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/output"
+)
+
+// DefaultBaseURL is the Sheets API endpoint.
+const DefaultBaseURL = "https://sheets.googleapis.com/v4/spreadsheets"
+
+// Hosts lists the hosts the client connects to with the default
+// endpoints, for network allowlists.
+func Hosts() []string {
+	return []string{"sheets.googleapis.com", "oauth2.googleapis.com"}
+}
+
+// Ref identifies a tab of a spreadsheet.
+type Ref struct {
+	SpreadsheetID string
+	// Sheet is the tab title; empty selects the tab with GID, or the
+	// first tab.
+	Sheet string
+	GID   string
+}
+
+func (r Ref) String() string {
+	s := "gsheet:" + r.SpreadsheetID
+	if r.Sheet != "" {
+		s += "/" + r.Sheet
+	}
+	return s
+}
+
+var urlRe = regexp.MustCompile(`^https://docs\.google\.com/spreadsheets/d/([A-Za-z0-9_-]+)`)
+var gidRe = regexp.MustCompile(`[#?&]gid=([0-9]+)`)
+
+// ParseRef parses a spreadsheet reference: a browser URL such as
+// "https://docs.google.com/spreadsheets/d/ID/edit#gid=0", or
+// "gsheet:ID" and "gsheet:ID/Tab name". ok is false for anything else,
+// such as a file path.
+func ParseRef(s string) (ref Ref, ok bool) {
+	if m := urlRe.FindStringSubmatch(s); m != nil {
+		ref.SpreadsheetID = m[1]
+		if g := gidRe.FindStringSubmatch(s); g != nil {
+			ref.GID = g[1]
+		}
+		return ref, true
+	}
+	rest, ok := strings.CutPrefix(s, "gsheet:")
+	if !ok || rest == "" {
+		return Ref{}, false
+	}
+	ref.SpreadsheetID, ref.Sheet, _ = strings.Cut(rest, "/")
+	return ref, true
+}
+
+// Client calls the Sheets API.
+type Client struct {
+	Tokens     TokenSource
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+func (c *Client) do(ctx context.Context, method, u string, body, v any) error {
+	var rd io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		rd = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, rd)
+	if err != nil {
+		return err
+	}
+	tok, err := c.Tokens.Token(ctx)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+tok)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	resp, err := hc.Do(req)
+	if err != nil {
+		return fmt.Errorf("gsheets: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		var e struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&e)
+		if e.Error.Message == "" {
+			e.Error.Message = resp.Status
+		}
+		return fmt.Errorf("gsheets: %s %s: %s", method, req.URL.Path, e.Error.Message)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+func (c *Client) url(id, path string, q url.Values) string {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	u := base + "/" + url.PathEscape(id) + path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	return u
+}
+
+type sheetProps struct {
+	SheetID int    `json:"sheetId"`
+	Title   string `json:"title"`
+}
+
+func (c *Client) tabs(ctx context.Context, id string) ([]sheetProps, error) {
+	var resp struct {
+		Sheets []struct {
+			Properties sheetProps `json:"properties"`
+		} `json:"sheets"`
+	}
+	if err := c.do(ctx, http.MethodGet, c.url(id, "", url.Values{"fields": {"sheets.properties"}}), nil, &resp); err != nil {
+		return nil, err
+	}
+	tabs := make([]sheetProps, len(resp.Sheets))
+	for i, s := range resp.Sheets {
+		tabs[i] = s.Properties
+	}
+	return tabs, nil
+}
+
+// Resolve fills in the tab title of ref from its GID or, failing that,
+// the first tab.
+func (c *Client) Resolve(ctx context.Context, ref Ref) (Ref, error) {
+	if ref.Sheet != "" {
+		return ref, nil
+	}
+	tabs, err := c.tabs(ctx, ref.SpreadsheetID)
+	if err != nil {
+		return ref, err
+	}
+	if len(tabs) == 0 {
+		return ref, fmt.Errorf("gsheets: spreadsheet %s has no tabs", ref.SpreadsheetID)
+	}
+	ref.Sheet = tabs[0].Title
+	for _, t := range tabs {
+		if ref.GID != "" && strconv.Itoa(t.SheetID) == ref.GID {
+			ref.Sheet = t.Title
+		}
+	}
+	return ref, nil
+}
+
+// a1 quotes a tab title for use as an A1 range.
+func a1(sheet string) string {
+	return "'" + strings.ReplaceAll(sheet, "'", "''") + "'"
+}
+
+// Read returns the values of the tab, one slice per row.
+func (c *Client) Read(ctx context.Context, ref Ref) ([][]string, error) {
+	ref, err := c.Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	var resp struct {
+		Values [][]string `json:"values"`
+	}
+	u := c.url(ref.SpreadsheetID, "/values/"+url.PathEscape(a1(ref.Sheet)), url.Values{"valueRenderOption": {"FORMATTED_VALUE"}})
+	if err := c.do(ctx, http.MethodGet, u, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Values, nil
+}
+
+// Write replaces the contents of the tab with rows, creating the tab if
+// it does not exist.
+func (c *Client) Write(ctx context.Context, ref Ref, rows [][]string) error {
+	tabs, err := c.tabs(ctx, ref.SpreadsheetID)
+	if err != nil {
+		return err
+	}
+	exists := false
+	for _, t := range tabs {
+		exists = exists || t.Title == ref.Sheet
+	}
+	if !exists {
+		add := map[string]any{"requests": []any{
+			map[string]any{"addSheet": map[string]any{"properties": map[string]any{"title": ref.Sheet}}},
+		}}
+		if err := c.do(ctx, http.MethodPost, c.url(ref.SpreadsheetID, ":batchUpdate", nil), add, nil); err != nil {
+			return err
+		}
+	}
+	rng := url.PathEscape(a1(ref.Sheet))
+	if err := c.do(ctx, http.MethodPost, c.url(ref.SpreadsheetID, "/values/"+rng+":clear", nil), map[string]any{}, nil); err != nil {
+		return err
+	}
+	body := map[string]any{"values": rows}
+	return c.do(ctx, http.MethodPut, c.url(ref.SpreadsheetID, "/values/"+rng, url.Values{"valueInputOption": {"RAW"}}), body, nil)
+}
+
+// Writer collects records and writes them to a tab on Close.
+type Writer struct {
+	c    *Client
+	ref  Ref
+	rows [][]string
+}
+
+var _ output.Writer = (*Writer)(nil)
+
+// NewWriter returns an output.Writer for the tab ref.Sheet, which must
+// be set.
+func NewWriter(c *Client, ref Ref) (*Writer, error) {
+	if ref.Sheet == "" {
+		return nil, fmt.Errorf("gsheets: %s: no tab given for the output", ref)
+	}
+	return &Writer{c: c, ref: ref}, nil
+}
+
+// WriteHeader implements output.Writer.
+func (w *Writer) WriteHeader(columns []string) error {
+	w.rows = append(w.rows, columns)
+	return nil
+}
+
+// Write implements output.Writer.
+func (w *Writer) Write(r *output.Record) error {
+	w.rows = append(w.rows, r.Cells)
+	return nil
+}
+
+// Close implements output.Writer by uploading the rows.
+func (w *Writer) Close() error {
+	return w.c.Write(context.Background(), w.ref, w.rows)
+}
@@ -0,0 +1,70 @@
+// Package gtin validates the product barcodes printed on non-book items:
+// UPC-A, EAN-8, EAN-13 and GTIN-14. Book barcodes are EAN-13s in the
+// 978 and 979 ranges, which package isbn handles.
+package gtin
+
+import "github.com/SouadAli10/book_scrapping_tool/isbn"
+
+// Normalize strips everything but digits from s. It does not validate
+// the result.
+func Normalize(s string) string {
+	b := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c >= '0' && c <= '9' {
+			b = append(b, c)
+		}
+	}
+	return string(b)
+}
+
+// Valid reports whether s is a GTIN-8, -12, -13 or -14 with a correct
+// check digit once normalized.
+func Valid(s string) bool {
+	s = Normalize(s)
+	switch len(s) {
+	case 8, 12, 13, 14:
+	default:
+		return false
+	}
+	return check(s[:len(s)-1]) == s[len(s)-1]
+}
+
+// check returns the mod-10 check digit of body, weighting digits 3 and 1
+// alternately from the right.
+func check(body string) byte {
+	sum := 0
+	for i := range len(body) {
+		d := int(body[len(body)-1-i] - '0')
+		if i%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// To13 returns the EAN-13 form of a UPC-A or EAN-13, and s normalized
+// otherwise.
+func To13(s string) string {
+	s = Normalize(s)
+	if len(s) == 12 {
+		return "0" + s
+	}
+	return s
+}
+
+// IsBook reports whether s is a book barcode: an ISBN-10, or an EAN-13
+// in the 978 or 979 "Bookland" ranges.
+func IsBook(s string) bool {
+	if n := isbn.Normalize(s); len(n) == 10 {
+		return isbn.Valid10(n)
+	}
+	s = To13(s)
+	return len(s) == 13 && (s[:3] == "978" || s[:3] == "979") && Valid(s)
+}
+
+// IsItem reports whether s is the valid barcode of an item other than a
+// book.
+func IsItem(s string) bool {
+	return Valid(s) && !IsBook(s)
+}
@@ -0,0 +1,62 @@
+package httpx
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrHostNotAllowed is returned for requests to hosts outside the
+// allowlist given to AllowHosts.
+var ErrHostNotAllowed = errors.New("host not in allowlist")
+
+// HostAllowed reports whether host matches one of patterns. A pattern is
+// a host name, or "*.example.org" for every subdomain of example.org.
+// Ports are ignored and matching is case-insensitive.
+func HostAllowed(patterns []string, host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(hostOnly(host), "."))
+	for _, p := range patterns {
+		p = strings.ToLower(p)
+		if suffix, ok := strings.CutPrefix(p, "*"); ok {
+			if strings.HasSuffix(host, suffix) && strings.HasPrefix(suffix, ".") {
+				return true
+			}
+			continue
+		}
+		if host == p {
+			return true
+		}
+	}
+	return false
+}
+
+// hostOnly strips the port from host, keeping IPv6 literals intact.
+func hostOnly(host string) string {
+	if strings.HasPrefix(host, "[") {
+		if i := strings.Index(host, "]"); i > 0 {
+			return host[1:i]
+		}
+	}
+	if i := strings.LastIndex(host, ":"); i >= 0 && strings.Count(host, ":") == 1 {
+		return host[:i]
+	}
+	return host
+}
+
+// AllowHosts refuses requests to hosts not matched by patterns, before
+// any connection is made. Redirects go through the transport again, so
+// they cannot leave the allowlist either.
+func AllowHosts(patterns []string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if !HostAllowed(patterns, req.URL.Host) {
+				if req.Body != nil {
+					req.Body.Close()
+				}
+				return nil, fmt.Errorf("%w: %s", ErrHostNotAllowed, req.URL.Hostname())
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
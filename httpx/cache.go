@@ -0,0 +1,170 @@
+package httpx
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// Cache stores raw HTTP responses by key.
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, data []byte) error
+}
+
+// MemoryCache is an in-process Cache.
+type MemoryCache struct {
+	mu sync.RWMutex
+	m  map[string][]byte
+}
+
+// Get implements Cache.
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	v, ok := c.m[key]
+	return v, ok
+}
+
+// Set implements Cache.
+func (c *MemoryCache) Set(key string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.m == nil {
+		c.m = make(map[string][]byte)
+	}
+	c.m[key] = data
+	return nil
+}
+
+// DiskCache stores responses as files in a directory, one file per key.
+type DiskCache struct {
+	Dir string
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(c.Dir, name[:2], name+".http")
+}
+
+// Get implements Cache.
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+	data, err := os.ReadFile(c.path(key))
+	return data, err == nil
+}
+
+// Set implements Cache. The file is written atomically so concurrent
+// readers never see a partial response.
+func (c *DiskCache) Set(key string, data []byte) error {
+	p := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(p), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), p)
+}
+
+// CacheKey returns the cache key of a request.
+func CacheKey(req *http.Request) string {
+	return req.Method + " " + req.URL.String()
+}
+
+// Caching answers GET requests from c when possible and stores successful
+// and not-found responses in it. Not-found answers are cached too: a
+// book missing from a provider today is almost always missing tomorrow,
+// and re-asking wastes quota.
+func Caching(c Cache) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet {
+				return next.RoundTrip(req)
+			}
+			key := CacheKey(req)
+			if data, ok := c.Get(key); ok {
+				if resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req); err == nil {
+					resp.Header.Set("X-Booktool-Cache", "hit")
+					return resp, nil
+				}
+			}
+			resp, err := next.RoundTrip(req)
+			if err != nil || (resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound) {
+				return resp, err
+			}
+			data, err := httputil.DumpResponse(resp, true)
+			if err != nil {
+				return nil, err
+			}
+			// DumpResponse leaves resp.Body readable; caching failures are
+			// not fatal to the request.
+			_ = c.Set(key, data)
+			return resp, nil
+		})
+	}
+}
+
+// Ping checks that the cache directory exists and is writable.
+func (c *DiskCache) Ping() error {
+	if err := os.MkdirAll(c.Dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(c.Dir, ".ping-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// Cipher encrypts cache entries at rest; seal.Sealer implements it.
+type Cipher interface {
+	Seal(plaintext []byte) ([]byte, error)
+	Open(data []byte) ([]byte, error)
+}
+
+type encryptedCache struct {
+	c  Cache
+	ci Cipher
+}
+
+// Encrypted returns a Cache storing entries in c encrypted with ci.
+// Entries that fail to decrypt, such as plain entries from before
+// encryption was turned on, are treated as misses.
+func Encrypted(c Cache, ci Cipher) Cache {
+	return encryptedCache{c: c, ci: ci}
+}
+
+func (e encryptedCache) Get(key string) ([]byte, bool) {
+	data, ok := e.c.Get(key)
+	if !ok {
+		return nil, false
+	}
+	plain, err := e.ci.Open(data)
+	return plain, err == nil
+}
+
+func (e encryptedCache) Set(key string, data []byte) error {
+	sealed, err := e.ci.Seal(data)
+	if err != nil {
+		return err
+	}
+	return e.c.Set(key, sealed)
+}
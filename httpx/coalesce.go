@@ -0,0 +1,74 @@
+package httpx
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httputil"
+	"sync"
+)
+
+// call is a request in flight that identical requests wait for.
+type call struct {
+	done chan struct{}
+	// data is the dumped response, or err the failure of the request.
+	data []byte
+	err  error
+}
+
+// Coalescing shares identical GET requests in flight: the first one
+// goes to the network and those made before it answers, by workers
+// looking up the same ISBN, get a copy of its response instead of
+// sending their own. Place it inside Caching, so that the shared
+// response is cached once and later requests are answered from the
+// cache. A request whose context ends while waiting returns its
+// context's error; one whose leader was canceled sends its own.
+func Coalescing() Middleware {
+	var mu sync.Mutex
+	calls := make(map[string]*call)
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet {
+				return next.RoundTrip(req)
+			}
+			key := CacheKey(req)
+			mu.Lock()
+			if c, ok := calls[key]; ok {
+				mu.Unlock()
+				select {
+				case <-c.done:
+				case <-req.Context().Done():
+					return nil, req.Context().Err()
+				}
+				if c.err != nil {
+					if errors.Is(c.err, context.Canceled) || errors.Is(c.err, context.DeadlineExceeded) {
+						return next.RoundTrip(req)
+					}
+					return nil, c.err
+				}
+				return http.ReadResponse(bufio.NewReader(bytes.NewReader(c.data)), req)
+			}
+			c := &call{done: make(chan struct{})}
+			calls[key] = c
+			mu.Unlock()
+
+			resp, err := next.RoundTrip(req)
+			if err == nil {
+				// DumpResponse leaves resp.Body readable for the leader.
+				c.data, err = httputil.DumpResponse(resp, true)
+				if err != nil {
+					resp.Body.Close()
+					resp = nil
+				}
+			}
+			c.err = err
+			mu.Lock()
+			delete(calls, key)
+			mu.Unlock()
+			close(c.done)
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+)
+
+// ErrLimit is returned by the Counting middleware for requests over the
+// limit set with WithLimit.
+var ErrLimit = errors.New("httpx: request limit reached")
+
+// Counter counts the network requests made on behalf of a context.
+type Counter struct {
+	n atomic.Int64
+}
+
+// Load returns the current count.
+func (c *Counter) Load() int64 { return c.n.Load() }
+
+// reserve counts one request unless the count would exceed max, which is
+// ignored when zero. A refused request is taken back out of the count.
+func (c *Counter) reserve(max int64) bool {
+	if n := c.n.Add(1); max > 0 && n > max {
+		c.n.Add(-1)
+		return false
+	}
+	return true
+}
+
+type counterKey struct{}
+
+// counted is the Counter of a context and its limit.
+type counted struct {
+	c   *Counter
+	max int64
+}
+
+// WithCounter returns a context whose requests are counted in c by the
+// Counting middleware.
+func WithCounter(ctx context.Context, c *Counter) context.Context {
+	return WithLimit(ctx, c, 0)
+}
+
+// WithLimit is like WithCounter, but Counting refuses the requests that
+// would take c over max with ErrLimit. Each request is counted before
+// it is sent, so concurrent requests cannot overshoot max.
+func WithLimit(ctx context.Context, c *Counter, max int64) context.Context {
+	return context.WithValue(ctx, counterKey{}, counted{c, max})
+}
+
+// Counting increments the Counter attached to each request's context.
+// Place it inside Caching so cache hits are not counted.
+func Counting() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if v, ok := req.Context().Value(counterKey{}).(counted); ok && !v.c.reserve(v.max) {
+				return nil, ErrLimit
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
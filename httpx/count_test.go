@@ -0,0 +1,44 @@
+package httpx
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCountingLimit(t *testing.T) {
+	var sent atomic.Int64
+	rt := Chain(RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		sent.Add(1)
+		return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+	}), Counting())
+	var c Counter
+	ctx := WithLimit(context.Background(), &c, 10)
+	var refused atomic.Int64
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req, _ := http.NewRequestWithContext(ctx, http.MethodGet, "http://example.com/", nil)
+			resp, err := rt.RoundTrip(req)
+			switch {
+			case errors.Is(err, ErrLimit):
+				refused.Add(1)
+			case err != nil:
+				t.Error(err)
+			default:
+				resp.Body.Close()
+			}
+		}()
+	}
+	wg.Wait()
+	if sent.Load() != 10 || refused.Load() != 40 || c.Load() != 10 {
+		t.Errorf("sent %d, refused %d, counted %d; want 10, 40, 10", sent.Load(), refused.Load(), c.Load())
+	}
+}
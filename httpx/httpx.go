@@ -0,0 +1,36 @@
+// Package httpx provides composable middleware for the HTTP clients used
+// by providers. Each cross-cutting concern (logging, caching, rate
+// limiting, retries, metrics, recording) is an http.RoundTripper wrapper;
+// Chain stacks them around a base transport.
+package httpx
+
+import (
+	"net/http"
+)
+
+// Middleware wraps a RoundTripper with additional behavior.
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc adapts a function to http.RoundTripper.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper.
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Chain wraps base with mws. The first middleware is the outermost: it
+// sees the request first and the response last. A nil base selects
+// http.DefaultTransport.
+func Chain(base http.RoundTripper, mws ...Middleware) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	rt := base
+	for i := len(mws) - 1; i >= 0; i-- {
+		if mws[i] != nil {
+			rt = mws[i](rt)
+		}
+	}
+	return rt
+}
@@ -0,0 +1,75 @@
+// Package httpxtest replays recorded provider responses in tests.
+//
+// Tests get an HTTP client for a cassette file, usually under testdata.
+// By default the client answers from the cassette and fails requests it
+// has no recording for, so tests never reach the network. Setting
+// BOOKTOOL_VCR=record re-records the cassettes from the live APIs, and
+// BOOKTOOL_VCR=auto records only the requests that are missing:
+//
+//	BOOKTOOL_VCR=record go test ./provider/...
+package httpxtest
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+)
+
+// Client returns an HTTP client backed by the cassette at path, in the
+// mode selected by $BOOKTOOL_VCR. A recording cassette is saved when
+// the test ends.
+func Client(t testing.TB, path string) *http.Client {
+	t.Helper()
+	mode, err := httpx.ParseMode(os.Getenv(httpx.EnvVCR))
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := httpx.LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode != httpx.ModeReplay {
+		t.Cleanup(func() {
+			if err := c.Save(path); err != nil {
+				t.Errorf("save cassette: %v", err)
+			}
+		})
+	}
+	return &http.Client{
+		Timeout:   30 * time.Second,
+		Transport: c.Middleware(mode)(http.DefaultTransport),
+	}
+}
+
+// Requests returns an HTTP client that reaches no server: it answers
+// every request with 404 Not Found, and urls returns the URLs it was
+// asked for, in order. Tests use it to check the requests a provider
+// builds.
+func Requests() (c *http.Client, urls func() []string) {
+	var (
+		mu   sync.Mutex
+		seen []string
+	)
+	c = &http.Client{Transport: httpx.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		mu.Lock()
+		seen = append(seen, req.URL.String())
+		mu.Unlock()
+		return &http.Response{
+			StatusCode: http.StatusNotFound,
+			Header:     make(http.Header),
+			Body:       io.NopCloser(strings.NewReader("")),
+			Request:    req,
+		}, nil
+	})}
+	return c, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), seen...)
+	}
+}
@@ -0,0 +1,24 @@
+package httpx
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// Logging logs every request with its status and duration.
+func Logging(l *log.Logger) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			d := time.Since(start).Round(time.Millisecond)
+			if err != nil {
+				l.Printf("%s %s: %v (%s)", req.Method, req.URL.Redacted(), err, d)
+				return nil, err
+			}
+			l.Printf("%s %s: %d (%s)", req.Method, req.URL.Redacted(), resp.StatusCode, d)
+			return resp, nil
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package httpx
+
+import (
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// LatencyBuckets are the upper bounds of the buckets of latency
+// histograms.
+var LatencyBuckets = []time.Duration{
+	50 * time.Millisecond, 100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second, 30 * time.Second,
+}
+
+// Histogram counts durations in LatencyBuckets.
+type Histogram struct {
+	// Buckets counts the durations up to each of LatencyBuckets; a
+	// duration is counted in every bucket it fits, as Prometheus does.
+	Buckets []int
+	Count   int
+	Sum     time.Duration
+}
+
+// Observe adds d to the histogram.
+func (h *Histogram) Observe(d time.Duration) {
+	if h.Buckets == nil {
+		h.Buckets = make([]int, len(LatencyBuckets))
+	}
+	for i, b := range LatencyBuckets {
+		if d <= b {
+			h.Buckets[i]++
+		}
+	}
+	h.Count++
+	h.Sum += d
+}
+
+// Clone returns a copy of h that does not share its buckets.
+func (h Histogram) Clone() Histogram {
+	h.Buckets = append([]int(nil), h.Buckets...)
+	return h
+}
+
+// HostStats are the counters kept per host by Metrics.
+type HostStats struct {
+	Host      string
+	Requests  int
+	Errors    int
+	CacheHits int
+	// Latency is the time spent on the requests answered by the network
+	// rather than the cache, and Network their latency histogram.
+	Latency time.Duration
+	Network Histogram
+}
+
+// Metrics collects request counters per host.
+type Metrics struct {
+	mu    sync.Mutex
+	hosts map[string]*HostStats
+}
+
+// Middleware returns the middleware that feeds m. Place it outside
+// Caching to count cache hits.
+func (m *Metrics) Middleware() Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next.RoundTrip(req)
+			d := time.Since(start)
+
+			m.mu.Lock()
+			defer m.mu.Unlock()
+			if m.hosts == nil {
+				m.hosts = make(map[string]*HostStats)
+			}
+			s := m.hosts[req.URL.Host]
+			if s == nil {
+				s = &HostStats{Host: req.URL.Host}
+				m.hosts[req.URL.Host] = s
+			}
+			s.Requests++
+			switch {
+			case err != nil || resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+				s.Errors++
+			case resp.Header.Get("X-Booktool-Cache") == "hit":
+				s.CacheHits++
+				return resp, err
+			}
+			s.Latency += d
+			s.Network.Observe(d)
+			return resp, err
+		})
+	}
+}
+
+// Snapshot returns a copy of the counters, sorted by host.
+func (m *Metrics) Snapshot() []HostStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]HostStats, 0, len(m.hosts))
+	for _, s := range m.hosts {
+		c := *s
+		c.Network = s.Network.Clone()
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Host < out[j].Host })
+	return out
+}
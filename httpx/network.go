@@ -0,0 +1,55 @@
+package httpx
+
+import (
+	"fmt"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+)
+
+// Headers sets the headers in h on every request, replacing those the
+// caller set: a User-Agent in h replaces the providers' own.
+func Headers(h http.Header) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			for k, v := range h {
+				req.Header[k] = v
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
+
+// ParseHeader parses a "Name: value" header line, returning the name in
+// canonical form.
+func ParseHeader(s string) (name, value string, err error) {
+	name, value, ok := strings.Cut(s, ":")
+	name = strings.TrimSpace(name)
+	if !ok || name == "" || strings.ContainsAny(name, " \t\r\n") {
+		return "", "", fmt.Errorf("want Name: value, got %q", s)
+	}
+	if strings.ContainsAny(value, "\r\n") {
+		return "", "", fmt.Errorf("header %s: the value spans several lines", name)
+	}
+	return textproto.CanonicalMIMEHeaderKey(name), strings.TrimSpace(value), nil
+}
+
+// ParseProxy parses the URL of an HTTP, HTTPS or SOCKS5 proxy, such as
+// "http://proxy.example.org:3128".
+func ParseProxy(s string) (*url.URL, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("proxy %q: want an http, https or socks5 URL", u.Redacted())
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("proxy %q: missing host", u.Redacted())
+	}
+	return u, nil
+}
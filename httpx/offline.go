@@ -0,0 +1,23 @@
+package httpx
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrOffline is returned for requests that need the network in offline
+// mode.
+var ErrOffline = errors.New("pending: not in the cache (offline)")
+
+// Offline answers every request with ErrOffline instead of sending it,
+// so that only the middleware before it, such as the cache, can answer.
+func Offline() Middleware {
+	return func(http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, ErrOffline
+		})
+	}
+}
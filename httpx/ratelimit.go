@@ -0,0 +1,45 @@
+package httpx
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimit spaces requests to the same host at least interval apart.
+// Providers publish per-host limits (OpenLibrary asks for about one
+// request per second), so hosts are throttled independently.
+func RateLimit(interval time.Duration) Middleware {
+	return ScheduledRateLimit(Schedule{Default: interval})
+}
+
+// ScheduledRateLimit is RateLimit with an interval that follows s.
+func ScheduledRateLimit(s Schedule) Middleware {
+	var (
+		mu   sync.Mutex
+		next = make(map[string]time.Time)
+	)
+	return func(rt http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			mu.Lock()
+			now := time.Now()
+			at := next[req.URL.Host]
+			if at.Before(now) {
+				at = now
+			}
+			next[req.URL.Host] = at.Add(s.Interval(at))
+			mu.Unlock()
+
+			if wait := time.Until(at); wait > 0 {
+				t := time.NewTimer(wait)
+				select {
+				case <-t.C:
+				case <-req.Context().Done():
+					t.Stop()
+					return nil, req.Context().Err()
+				}
+			}
+			return rt.RoundTrip(req)
+		})
+	}
+}
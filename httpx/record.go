@@ -0,0 +1,24 @@
+package httpx
+
+import (
+	"net/http"
+	"net/http/httputil"
+)
+
+// Record stores every response that passes through it in c, regardless
+// of status, keyed like Caching. Pointing it at a DiskCache in a fixture
+// directory captures a run's raw provider traffic.
+func Record(c Cache) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			resp, err := next.RoundTrip(req)
+			if err != nil {
+				return nil, err
+			}
+			if data, err := httputil.DumpResponse(resp, true); err == nil {
+				_ = c.Set(CacheKey(req), data)
+			}
+			return resp, nil
+		})
+	}
+}
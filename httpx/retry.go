@@ -0,0 +1,74 @@
+package httpx
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxRetryAfter caps how long a Retry-After header can make us wait.
+const maxRetryAfter = 2 * time.Minute
+
+// Retry retries idempotent requests that fail with a network error, 429
+// or a 5xx status, up to attempts times in total, with exponential
+// backoff starting at backoff. A Retry-After header overrides the
+// backoff.
+func Retry(attempts int, backoff time.Duration) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if req.Method != http.MethodGet && req.Method != http.MethodHead {
+				return next.RoundTrip(req)
+			}
+			wait := backoff
+			for i := 1; ; i++ {
+				resp, err := next.RoundTrip(req)
+				if i >= attempts || !retryable(resp, err) {
+					return resp, err
+				}
+				if d, ok := retryAfter(resp); ok {
+					wait = d
+				}
+				if resp != nil {
+					io.Copy(io.Discard, resp.Body)
+					resp.Body.Close()
+				}
+				t := time.NewTimer(wait)
+				select {
+				case <-t.C:
+				case <-req.Context().Done():
+					t.Stop()
+					return nil, req.Context().Err()
+				}
+				wait *= 2
+			}
+		})
+	}
+}
+
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, ErrHostNotAllowed)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	var d time.Duration
+	if secs, err := strconv.Atoi(v); err == nil {
+		d = time.Duration(secs) * time.Second
+	} else if t, err := http.ParseTime(v); err == nil {
+		d = time.Until(t)
+	} else {
+		return 0, false
+	}
+	return min(max(d, 0), maxRetryAfter), true
+}
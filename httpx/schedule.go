@@ -0,0 +1,150 @@
+package httpx
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Window is a recurring period of the week with its own request
+// interval, such as business hours.
+type Window struct {
+	// Days selects the weekdays the window starts on, indexed by
+	// time.Weekday; a window with no days set applies every day.
+	Days [7]bool
+	// Start and End are offsets from midnight. A window ending at or
+	// before its start runs past midnight into the next day.
+	Start, End time.Duration
+	Interval   time.Duration
+}
+
+// contains reports whether t, in its own location, falls in w.
+func (w Window) contains(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	off := t.Sub(midnight)
+	day := t.Weekday()
+	if w.Start < w.End {
+		return w.on(day) && off >= w.Start && off < w.End
+	}
+	// Past midnight, the window belongs to the day before.
+	return (w.on(day) && off >= w.Start) || (w.on((day+6)%7) && off < w.End)
+}
+
+func (w Window) on(d time.Weekday) bool {
+	return w.Days == [7]bool{} || w.Days[d]
+}
+
+// Schedule varies the interval between requests to a host with the time
+// of day, so that long runs go faster at night and stay polite during
+// business hours.
+type Schedule struct {
+	// Default applies outside every window.
+	Default time.Duration
+	// Windows are tried in order; the first one containing the time
+	// wins.
+	Windows []Window
+}
+
+// Interval returns the interval in force at t.
+func (s Schedule) Interval(t time.Time) time.Duration {
+	for _, w := range s.Windows {
+		if w.contains(t) {
+			return w.Interval
+		}
+	}
+	return s.Default
+}
+
+var weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// ParseWindow parses a window written as "[days ]HH:MM-HH:MM=interval",
+// where days is a day or a range of days: "mon-fri 09:00-18:00=1s",
+// "sat 00:00-24:00=100ms" or "22:00-06:00=100ms" for every night.
+func ParseWindow(s string) (Window, error) {
+	var w Window
+	spec, interval, ok := strings.Cut(strings.TrimSpace(s), "=")
+	if !ok {
+		return w, fmt.Errorf("rate window %q: want [days ]HH:MM-HH:MM=interval", s)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(interval))
+	if err != nil || d < 0 {
+		return w, fmt.Errorf("rate window %q: bad interval %q", s, interval)
+	}
+	w.Interval = d
+	fields := strings.Fields(spec)
+	switch len(fields) {
+	case 1:
+	case 2:
+		if w.Days, err = parseDays(fields[0]); err != nil {
+			return w, fmt.Errorf("rate window %q: %w", s, err)
+		}
+		fields = fields[1:]
+	default:
+		return w, fmt.Errorf("rate window %q: want [days ]HH:MM-HH:MM=interval", s)
+	}
+	from, to, ok := strings.Cut(fields[0], "-")
+	if !ok {
+		return w, fmt.Errorf("rate window %q: want a time range such as 09:00-18:00", s)
+	}
+	if w.Start, err = parseClock(from); err != nil {
+		return w, fmt.Errorf("rate window %q: %w", s, err)
+	}
+	if w.End, err = parseClock(to); err != nil {
+		return w, fmt.Errorf("rate window %q: %w", s, err)
+	}
+	return w, nil
+}
+
+// ParseSchedule parses comma-separated windows, as read by ParseWindow.
+func ParseSchedule(def time.Duration, s string) (Schedule, error) {
+	sched := Schedule{Default: def}
+	for _, part := range strings.Split(s, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		w, err := ParseWindow(part)
+		if err != nil {
+			return sched, err
+		}
+		sched.Windows = append(sched.Windows, w)
+	}
+	return sched, nil
+}
+
+func parseDays(s string) ([7]bool, error) {
+	var days [7]bool
+	from, to, isRange := strings.Cut(strings.ToLower(s), "-")
+	if !isRange {
+		to = from
+	}
+	i, j := dayIndex(from), dayIndex(to)
+	if i < 0 || j < 0 {
+		return days, fmt.Errorf("unknown days %q (want e.g. mon or mon-fri)", s)
+	}
+	for d := i; ; d = (d + 1) % 7 {
+		days[d] = true
+		if d == j {
+			return days, nil
+		}
+	}
+}
+
+func dayIndex(s string) int {
+	for i, d := range weekdays {
+		if s == d {
+			return i
+		}
+	}
+	return -1
+}
+
+// parseClock parses HH:MM as an offset from midnight; 24:00 ends the
+// day.
+func parseClock(s string) (time.Duration, error) {
+	var h, m int
+	if n, err := fmt.Sscanf(s, "%d:%d", &h, &m); err != nil || n != 2 || len(s) != 5 ||
+		h < 0 || m < 0 || m > 59 || h > 24 || (h == 24 && m > 0) {
+		return 0, fmt.Errorf("bad time %q (want HH:MM)", s)
+	}
+	return time.Duration(h)*time.Hour + time.Duration(m)*time.Minute, nil
+}
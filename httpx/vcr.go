@@ -0,0 +1,194 @@
+package httpx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// EnvVCR names the environment variable selecting the Mode of cassettes
+// opened by tests: "replay" (the default), "record" or "auto".
+const EnvVCR = "BOOKTOOL_VCR"
+
+// ErrNotRecorded is returned when replaying a request the cassette has
+// no response for.
+var ErrNotRecorded = errors.New("no recorded response")
+
+// Mode selects what a Cassette does with requests.
+type Mode int
+
+const (
+	// ModeReplay answers requests from the cassette and fails the
+	// others, so that nothing reaches the network.
+	ModeReplay Mode = iota
+	// ModeRecord sends every request and records the responses.
+	ModeRecord
+	// ModeAuto answers recorded requests and records the others.
+	ModeAuto
+)
+
+// ParseMode parses "replay", "record" or "auto"; the empty string
+// selects ModeReplay.
+func ParseMode(s string) (Mode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "replay":
+		return ModeReplay, nil
+	case "record":
+		return ModeRecord, nil
+	case "auto":
+		return ModeAuto, nil
+	}
+	return 0, fmt.Errorf("unknown cassette mode %q (want replay, record or auto)", s)
+}
+
+// Interaction is a recorded HTTP exchange. Body holds JSON bodies as is,
+// so fixture files stay readable, and any other body as a JSON string.
+type Interaction struct {
+	Method string          `json:"method,omitempty"`
+	URL    string          `json:"url"`
+	Status int             `json:"status"`
+	Body   json.RawMessage `json:"body,omitempty"`
+}
+
+// RawBody returns the response body as sent.
+func (in *Interaction) RawBody() []byte {
+	var s string
+	if json.Unmarshal(in.Body, &s) == nil {
+		return []byte(s)
+	}
+	return in.Body
+}
+
+// SetBody stores a response body.
+func (in *Interaction) SetBody(data []byte) {
+	var v any
+	if len(data) > 0 && json.Unmarshal(data, &v) == nil {
+		var buf bytes.Buffer
+		if json.Compact(&buf, data) == nil {
+			in.Body = buf.Bytes()
+			return
+		}
+	}
+	in.Body, _ = json.Marshal(string(data))
+}
+
+func (in *Interaction) matches(req *http.Request) bool {
+	method := in.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	return method == req.Method && in.URL == req.URL.String()
+}
+
+// response rebuilds the recorded response to req.
+func (in *Interaction) response(req *http.Request) *http.Response {
+	return &http.Response{
+		StatusCode: in.Status,
+		Status:     fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+		Proto:      "HTTP/1.1", ProtoMajor: 1, ProtoMinor: 1,
+		Header:  make(http.Header),
+		Body:    io.NopCloser(bytes.NewReader(in.RawBody())),
+		Request: req,
+	}
+}
+
+// Cassette is a set of recorded interactions that can stand in for the
+// network, VCR style: record a provider's responses once, then replay
+// them in tests and CI. Cassette files are JSON. It is safe for
+// concurrent use.
+type Cassette struct {
+	mu           sync.Mutex
+	Interactions []Interaction `json:"interactions"`
+}
+
+// LoadCassette reads the cassette at path; a missing file is an empty
+// cassette, ready for recording.
+func LoadCassette(path string) (*Cassette, error) {
+	c := new(Cassette)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("cassette %s: %w", path, err)
+	}
+	return c, nil
+}
+
+// Save writes the cassette to path, with the interactions sorted by URL
+// since concurrent requests are recorded in any order.
+func (c *Cassette) Save(path string) error {
+	c.mu.Lock()
+	slices.SortStableFunc(c.Interactions, func(a, b Interaction) int { return strings.Compare(a.URL, b.URL) })
+	data, err := json.MarshalIndent(c, "", "  ")
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// find returns the response recorded for req.
+func (c *Cassette) find(req *http.Request) (*http.Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i := range c.Interactions {
+		if in := &c.Interactions[i]; in.matches(req) {
+			return in.response(req), true
+		}
+	}
+	return nil, false
+}
+
+// record sends req through next and adds the response to the cassette,
+// replacing an earlier recording of the same request.
+func (c *Cassette) record(next http.RoundTripper, req *http.Request) (*http.Response, error) {
+	resp, err := next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	in := Interaction{URL: req.URL.String(), Status: resp.StatusCode}
+	if req.Method != http.MethodGet {
+		in.Method = req.Method
+	}
+	in.SetBody(data)
+	c.mu.Lock()
+	c.Interactions = slices.DeleteFunc(c.Interactions, func(o Interaction) bool { return o.matches(req) })
+	c.Interactions = append(c.Interactions, in)
+	c.mu.Unlock()
+	return resp, nil
+}
+
+// Middleware returns the cassette as middleware working in mode.
+func (c *Cassette) Middleware(mode Mode) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if mode != ModeRecord {
+				if resp, ok := c.find(req); ok {
+					return resp, nil
+				}
+			}
+			if mode == ModeReplay {
+				return nil, fmt.Errorf("%w for %s %s", ErrNotRecorded, req.Method, req.URL)
+			}
+			return c.record(next, req)
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package httpx
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func get(t *testing.T, rt http.RoundTripper, url string) (int, string, error) {
+	t.Helper()
+	resp, err := (&http.Client{Transport: rt}).Get(url)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp.StatusCode, string(data), nil
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	hits := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, `{"title": "The Hobbit"}`)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	rec, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rt := rec.Middleware(ModeRecord)(http.DefaultTransport)
+	if _, body, err := get(t, rt, srv.URL+"/book"); err != nil || body != `{"title": "The Hobbit"}` {
+		t.Fatalf("record: got %q, %v", body, err)
+	}
+	if status, _, err := get(t, rt, srv.URL+"/missing"); err != nil || status != http.StatusNotFound {
+		t.Fatalf("record: got status %d, %v", status, err)
+	}
+	if err := rec.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err := LoadCassette(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(c.Interactions) != 2 {
+		t.Fatalf("saved %d interactions, want 2", len(c.Interactions))
+	}
+	srv.Close()
+	rt = c.Middleware(ModeReplay)(http.DefaultTransport)
+	if status, body, err := get(t, rt, srv.URL+"/book"); err != nil || status != http.StatusOK || !strings.Contains(body, `"The Hobbit"`) {
+		t.Errorf("replay: got %d %q, %v", status, body, err)
+	}
+	if status, _, err := get(t, rt, srv.URL+"/missing"); err != nil || status != http.StatusNotFound {
+		t.Errorf("replay: got status %d, %v", status, err)
+	}
+	if _, _, err := get(t, rt, srv.URL+"/other"); !errors.Is(err, ErrNotRecorded) {
+		t.Errorf("replay of an unrecorded request: got %v, want ErrNotRecorded", err)
+	}
+	if hits != 2 {
+		t.Errorf("server saw %d requests, want 2", hits)
+	}
+}
+
+func TestInteractionBody(t *testing.T) {
+	for _, body := range []string{"<xml/>", `{"a": 1}`, ""} {
+		var in Interaction
+		in.SetBody([]byte(body))
+		want := body
+		if body == `{"a": 1}` {
+			want = `{"a":1}`
+		}
+		if got := string(in.RawBody()); got != want {
+			t.Errorf("SetBody(%q): RawBody() = %q, want %q", body, got, want)
+		}
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for s, want := range map[string]Mode{"": ModeReplay, "replay": ModeReplay, "Record": ModeRecord, "auto": ModeAuto} {
+		if got, err := ParseMode(s); err != nil || got != want {
+			t.Errorf("ParseMode(%q) = %v, %v; want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseMode("rewind"); err == nil {
+		t.Error("ParseMode(\"rewind\") succeeded")
+	}
+}
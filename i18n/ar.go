@@ -0,0 +1,149 @@
+package i18n
+
+func init() {
+	Register("ar", &Catalog{
+		Name: "العربية",
+		Yes:  []string{"ن", "نعم"},
+		No:   []string{"ل", "لا"},
+		Messages: map[string]string{
+			// Commands
+			"usage: booktool [command] [flags]\n\ncommands:":              "الاستخدام: booktool [أمر] [خيارات]\n\nالأوامر:",
+			"\nRun \"booktool <command> -h\" for the flags of a command.": "\nشغّل \"booktool <أمر> -h\" لعرض خيارات الأمر.",
+			"booktool: unknown command %q\n\n":                            "booktool: أمر غير معروف %q\n\n",
+			"enrich an input file (default command)":                      "إثراء ملف إدخال (الأمر الافتراضي)",
+			"enrich a Calibre library and fill in missing fields":         "إثراء مكتبة Calibre وإكمال الحقول الناقصة",
+			"suggest editions to buy for wishlist items not in stock":     "اقتراح طبعات للشراء لعناصر قائمة الرغبات غير المتوفرة في المخزون",
+			"reprice a catalog from market prices":                        "إعادة تسعير فهرس وفق أسعار السوق",
+			"reproduce a recorded run and compare":                        "إعادة تشغيل مُسجَّل ومقارنته",
+			"report low-stock fast sellers and stale stock":               "الإبلاغ عن الكتب الأكثر مبيعًا قليلة المخزون والمخزون الراكد",
+			"validate the configuration file (config check)":              "التحقق من ملف الإعدادات (config check)",
+			"create a configuration interactively":                        "إنشاء الإعدادات خطوة بخطوة",
+			"list the supported input and output formats":                 "عرض صيغ الإدخال والإخراج المدعومة",
+			"run the HTTP service":                                        "تشغيل خدمة HTTP",
+			"show or clean the state directory":                           "عرض مجلد الحالة أو تنظيفه",
+			"encrypt or decrypt configuration and credential files":       "تشفير ملفات الإعدادات وبيانات الاعتماد أو فك تشفيرها",
+			"check the provider mappings against recorded responses":      "التحقق من تحويل بيانات المزوّدين باستخدام ردود مسجّلة",
+			"download newer data tables":                                  "تنزيل أحدث جداول البيانات",
+			"print the version and data table versions":                   "عرض الإصدار وإصدارات جداول البيانات",
+			"enrich ISBNs read from a barcode scanner or standard input":  "إثراء أرقام ISBN المقروءة بقارئ الباركود أو من الإدخال القياسي",
+
+			// Prompts
+			"y/N":      "ن/[ل]",
+			"Y/n":      "[ن]/ل",
+			"aborted":  "أُلغي",
+			"warning:": "تحذير:",
+			"Please answer with a number or one of the names above.":                          "يُرجى الإجابة برقم أو بأحد الأسماء أعلاه.",
+			"This will create a booktool configuration. Press Enter to accept the [default].": "سيتم إنشاء إعدادات booktool. اضغط Enter لقبول [القيمة الافتراضية].",
+			"%s exists. Overwrite it?":                                                        "الملف %s موجود. هل تريد استبداله؟",
+			"Providers are queried in the order you enable them.":                             "يُستعلم من المزوّدين بالترتيب الذي تفعّلهم به.",
+			"Use %s?": "استخدام %s؟",
+			"  Keys can be stored in the file or read from an environment variable.": "  يمكن حفظ المفاتيح في الملف أو قراءتها من متغير بيئة.",
+			"  Paste the %s API key (leave empty to use $%s)":                        "  الصق مفتاح API لـ %s (اتركه فارغًا لاستخدام $%s)",
+			"at least one provider is needed":                                        "يلزم مزوّد واحد على الأقل",
+			"Path to one of your spreadsheets, to map its columns (optional)":        "مسار أحد جداولك لربط أعمدته (اختياري)",
+			"Could not read %s: %v\nColumns will be detected automatically.\n":       "تعذّرت قراءة %s: %v\nسيتم اكتشاف الأعمدة تلقائيًا.\n",
+			"Output format for enriched files:":                                      "صيغة إخراج الملفات المُثراة:",
+			"Format":                                                                 "الصيغة",
+			"Languages as":                                                           "اللغات بصيغة",
+			"Units":                                                                  "الوحدات",
+			"\nWrote %s. Run \"booktool -input yourfile.xlsx\" to enrich a file.\n":  "\nتمت كتابة %s. شغّل \"booktool -input yourfile.xlsx\" لإثراء ملف.\n",
+			"this format has no header row to map":                                   "لا يحتوي هذا التنسيق على صف عناوين لربطه",
+			"Columns found:":                                                         "الأعمدة الموجودة:",
+			"(none)":                                                                 "(لا شيء)",
+			"Which column holds the %s?\n":                                           "أي عمود يحتوي الحقل %s؟\n",
+			"This run uses paid services (%d rows):\n":                               "يستخدم هذا التشغيل خدمات مدفوعة (%d صفًا):\n",
+			"estimated cost $%.2f exceeds the $%.2f threshold; rerun with -yes to accept":                             "التكلفة المقدّرة %.2f$ تتجاوز الحد %.2f$؛ أعد التشغيل مع -yes للموافقة",
+			"paid services are enabled and the cost of standard input cannot be estimated; rerun with -yes to accept": "الخدمات المدفوعة مفعّلة ولا يمكن تقدير تكلفة الإدخال القياسي؛ أعد التشغيل مع -yes للموافقة",
+			"Continue? [y/N] ": "متابعة؟ [ن/[ل]] ",
+
+			// Reports and errors
+			"no input file given":                                             "لم يُحدَّد ملف إدخال",
+			"open input: %w":                                                  "فتح الإدخال: %w",
+			"%d rows enriched, %d failed":                                     "تم إثراء %d صفًا، وفشل %d",
+			"cannot record the run in the history: %v\n":                      "تعذّر تسجيل التشغيل في السجل: %v\n",
+			"wrote %s (%d rows, %d failed)\n":                                 "تمت كتابة %s (%d صفًا، فشل %d)\n",
+			"%d duplicate rows reused an earlier lookup; see the %q column\n": "أعاد %d صفًا مكررًا استخدام بحث سابق؛ راجع العمود %q\n",
+			"%d rows have warnings; see the %q column\n":                      "لدى %d صفًا تحذيرات؛ راجع العمود %q\n",
+			"upserted into %s table %q":                                       "أُدرجت أو حُدّثت في الجدول %[2]q (%[1]s)",
+			" (%d rows without ISBN skipped)":                                 " (تم تخطي %d صفًا بلا ISBN)",
+			"sample of %d rows written to %s\ncheck it, then run again without -sample for the full file\n": "تمت كتابة عيّنة من %d صفًا في %s\nراجعها ثم أعد التشغيل دون -sample لمعالجة الملف كاملًا\n",
+			"cannot open %s: %v\n":                                               "تعذّر فتح %s: %v\n",
+			"  %-14s %d of %d calls%s\n":                                         "  %-14s %d من %d استدعاء%s\n",
+			" (exhausted; later rows skipped this provider)":                     " (نفد؛ تخطّت الصفوف اللاحقة هذا المزوّد)",
+			"  %-28s %5d requests, %5d cached, %3d errors, avg %s\n":             "  %-28s %5d طلبًا، %5d من الذاكرة المؤقتة، %3d خطأ، المتوسط %s\n",
+			"no Calibre library given":                                           "لم تُحدَّد مكتبة Calibre",
+			"reading a Calibre library needs a booktool built with -tags sqlite": "تتطلب قراءة مكتبة Calibre نسخة booktool مبنية مع -tags sqlite",
+			"%d of %d books enriched, %d failed":                                 "تم إثراء %d من %d كتابًا، وفشل %d",
+			"%d of %d books have missing fields to fill (%d changes)\n":          "لدى %d من %d كتابًا حقول ناقصة لإكمالها (%d تعديلًا)\n",
+			"%w (%d books updated before the failure)":                           "%w (تم تحديث %d كتابًا قبل الفشل)",
+			"updated %d books in %s\n":                                           "تم تحديث %d كتابًا في %s\n",
+			"wrote %s\n":                                                         "تمت كتابة %s\n",
+			"acquire needs a -wishlist and a -stock file":                        "يتطلب acquire ملف -wishlist وملف -stock",
+			"acquire needs at least one price source (-prices)":                  "يتطلب acquire مصدر أسعار واحدًا على الأقل (-prices)",
+			"%d items checked, %d in stock":                                      "تم فحص %d عنصرًا، %d منها في المخزون",
+			"%d of %d items are not in stock\n":                                  "%d من أصل %d عنصرًا غير متوفرة في المخزون\n",
+			"Scan barcodes or type ISBNs, one per line. Press Ctrl-D when done.": "امسح الباركود أو اكتب أرقام ISBN، رقمًا في كل سطر. اضغط Ctrl-D عند الانتهاء.",
+			"%d. %s: not found (%v)\n":                                           "%d. %s: غير موجود (%v)\n",
+			"%d. %s: %s (again)\n":                                               "%d. %s: %s (مكرر)\n",
+			"cannot remove the checkpoint: %v\n":                                 "تعذّر حذف نقطة الاستئناف: %v\n",
+			"cannot save the checkpoint: %v\n":                                   "تعذّر حفظ نقطة الاستئناف: %v\n",
+			"stopped at the %s limit after %d rows; continue with:\n  %s\n":      "توقّف عند الحد %s بعد %d صفًا؛ للمتابعة شغّل:\n  %s\n",
+			"-update needs a file with a header row, such as xlsx or csv":        "يتطلب -update ملفًا يحتوي صف عناوين، مثل xlsx أو csv",
+			"%s has none of the enrichment columns":                              "لا يحتوي %s أيًا من أعمدة الإثراء",
+			"updated %s (%d rows enriched again, %d failed)\n":                   "تم تحديث %s (أُعيد إثراء %d صفًا، وفشل %d)\n",
+			"-offline answers from the response cache, which -no-cache disables": "يجيب -offline من ذاكرة الردود المؤقتة، وهو ما يعطّله -no-cache",
+			"%d rows are pending because their lookups are not in the cache; to fill them, run online with -update %s\n": "%d صفًا معلّقًا لأن عمليات البحث عنها ليست في الذاكرة المؤقتة؛ لإكمالها شغّل الأداة متصلًا مع -update %s\n",
+			"-cover-alt vision describes the downloaded covers; set -covers":                                             "يصف -cover-alt vision الأغلفة المنزّلة؛ حدّد -covers",
+			"wrote the sitemap and SEO metadata to %s":                                                                   "تمت كتابة خريطة الموقع وبيانات SEO في %s",
+			"-site-dir needs -site-url, the template of the books' canonical URLs":                                       "يتطلب -site-dir الخيار -site-url، وهو قالب عناوين URL الأساسية للكتب",
+			"%d rows scored below -min-confidence %.2f; they are in %s for review\n":                                     "حصل %d صفًا على درجة أقل من -min-confidence %.2f؛ وهي في %s للمراجعة\n",
+			"no catalog given":                                                              "لم يُحدَّد فهرس",
+			"-refresh needs price sources (-prices)":                                        "يتطلب -refresh مصادر أسعار (-prices)",
+			"reprice needs a catalog with a header row, such as xlsx or csv":                "يتطلب reprice فهرسًا بسطر عناوين، مثل xlsx أو csv",
+			"%s has no price column to write to":                                            "لا يحتوي %s على عمود سعر للكتابة فيه",
+			"%s has no market price columns in %s; enrich it with -prices, or use -refresh": "لا يحتوي %s على أعمدة أسعار السوق بعملة %s؛ أثرِه باستخدام -prices أو استخدم -refresh",
+			"%d rows priced, %d changes":                                                    "تم تسعير %d صفًا، %d تغييرات",
+			"%d of %d prices change\n":                                                      "يتغير %d من أصل %d سعرًا\n",
+			"updated %d prices in %s\n":                                                     "تم تحديث %d سعرًا في %s\n",
+			"%s: skipped %d sales rows without an ISBN, a title or a readable date\n":       "%s: تم تخطي %d صفًا من المبيعات بلا ISBN أو عنوان أو تاريخ مقروء\n",
+			"%d low-stock and %d stale items\n":                                             "%d عنصرًا قليل المخزون و%d عنصرًا راكدًا\n",
+			"%s: %d low-stock and %d stale items":                                           "%s: %d عنصرًا قليل المخزون و%d عنصرًا راكدًا",
+			"posted the alerts to the -notify webhook\n":                                    "أُرسلت التنبيهات إلى خطاف -notify\n",
+			"-columns does not apply to export profiles, which have their own columns":      "لا ينطبق -columns على ملفات التصدير، فلها أعمدتها الخاصة",
+			"wrote the manifest %s; replay the run with \"booktool replay %s\"\n":           "كُتب البيان %s؛ أعد التشغيل بالأمر \"booktool replay %s\"\n",
+			"no manifest given":                                                             "لم يُحدَّد أي بيان",
+			"%s changed since the run; a replay needs the same input":                       "تغيّر %s منذ التشغيل؛ تتطلب الإعادة المدخلات نفسها",
+			"response archive: %w":                                                          "أرشيف الاستجابات: %w",
+			"warning: the configuration %s changed since the run\n":                         "تحذير: تغيّر ملف الإعداد %s منذ التشغيل\n",
+			"the run was made with booktool %s; replaying with %s\n":                        "جرى التشغيل بالإصدار %s من booktool؛ وتجري الإعادة بالإصدار %s\n",
+			"the recorded output %s changed since the run; nothing to compare with":         "تغيّر الناتج المسجَّل %s منذ التشغيل؛ فلا شيء للمقارنة",
+			"the replay differs from %s in %d cells":                                        "تختلف الإعادة عن %s في %d خلية",
+			"reproduced %s exactly (%d rows)\n":                                             "أُعيد إنتاج %s بدقة (%d صفًا)\n",
+			"%s differs from the replay (compared as a whole, not cell by cell)\n":          "يختلف %s عن الإعادة (قورن كاملًا، لا خلية بخلية)\n",
+			"%sonly in the recorded output\n":                                               "%sفي الناتج المسجَّل فقط\n",
+			"%sonly in the replay\n":                                                        "%sفي الإعادة فقط\n",
+			"%s%d rows only in the recorded output: %s\n":                                   "%s%d صفًا في الناتج المسجَّل فقط: %s\n",
+			"%s%d rows only in the replay: %s\n":                                            "%s%d صفًا في الإعادة فقط: %s\n",
+			"%scolumn %q only in the recorded output\n":                                     "%sالعمود %q في الناتج المسجَّل فقط\n",
+			"%scolumn %q only in the replay\n":                                              "%sالعمود %q في الإعادة فقط\n",
+			"%scolumn %q: %d of %d rows differ\n":                                           "%sالعمود %q: يختلف %d من %d صفًا\n",
+			"interrupted; finishing the rows in progress (interrupt again to quit)\n":       "تمت المقاطعة؛ جارٍ إنهاء الصفوف قيد المعالجة (قاطع مرة أخرى للخروج)\n",
+			"interrupted after %d rows; continue with:\n  %s\n":                             "تمت المقاطعة بعد %d صفًا؛ تابع باستخدام:\n  %s\n",
+			"or with:\n  %s\n":                                                              "أو باستخدام:\n  %s\n",
+			"read the checkpoint: %w":                                                       "قراءة نقطة الاستئناف: %w",
+			"no stopped run of %s to resume":                                                "لا يوجد تشغيل متوقف لـ %s لاستئنافه",
+			"resuming %s after its first %d rows, writing to %s\n":                          "استئناف %s بعد أول %d صفًا، والكتابة إلى %s\n",
+			"%d rows timed out after -row-timeout %s and are reported as failed\n":          "تجاوز %d صفًا المهلة -row-timeout %s وأُبلغ عنها كفاشلة\n",
+			"the OpenLibrary dump index needs a booktool built with -tags sqlite":           "يتطلب فهرس تفريغات OpenLibrary إصدارًا من booktool مبنيًا مع -tags sqlite",
+			"no index at %s; build it from the OpenLibrary dumps with \"booktool oldump\"":  "لا يوجد فهرس في %s؛ أنشئه من تفريغات OpenLibrary باستخدام \"booktool oldump\"",
+			"no dump file given":                                                            "لم يُحدَّد أي ملف تفريغ",
+			"%s: %d editions, %d works, %d authors":                                         "%s: %d طبعة، %d عملًا، %d مؤلفًا",
+			"%s: %d editions (%d ISBNs), %d works, %d authors, %d skipped\n":                "%s: %d طبعة (%d ردمك)، %d عملًا، %d مؤلفًا، %d متجاهَلًا\n",
+			"indexed in %s; look books up in it with -providers %s\n":                       "تمت الفهرسة في %s؛ ابحث فيه عن الكتب باستخدام -providers %s\n",
+			"index OpenLibrary data dumps for offline lookups":                              "فهرسة تفريغات OpenLibrary للبحث دون اتصال",
+			"%d other editions listed in %s\n":                                              "%d طبعة أخرى مدرجة في %s\n",
+			"%d rows break validation rules; see the %q column\n":                           "%d صفًا تخالف قواعد التحقق؛ انظر العمود %q\n",
+			"%d rows broke validation rules and are reported as failed\n":                   "%d صفًا خالفت قواعد التحقق وتم الإبلاغ عنها كفاشلة\n",
+		},
+	})
+}
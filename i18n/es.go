@@ -0,0 +1,148 @@
+package i18n
+
+func init() {
+	Register("es", &Catalog{
+		Name: "Español",
+		Yes:  []string{"s", "sí", "si"},
+		Messages: map[string]string{
+			// Commands
+			"usage: booktool [command] [flags]\n\ncommands:":              "uso: booktool [comando] [opciones]\n\ncomandos:",
+			"\nRun \"booktool <command> -h\" for the flags of a command.": "\nEjecute «booktool <comando> -h» para ver las opciones de un comando.",
+			"booktool: unknown command %q\n\n":                            "booktool: comando desconocido %q\n\n",
+			"enrich an input file (default command)":                      "enriquecer un archivo de entrada (comando predeterminado)",
+			"enrich a Calibre library and fill in missing fields":         "enriquecer una biblioteca de Calibre y completar los campos que faltan",
+			"suggest editions to buy for wishlist items not in stock":     "sugerir ediciones que comprar para los artículos de la lista de deseos sin existencias",
+			"reprice a catalog from market prices":                        "recalcular los precios de un catálogo según el mercado",
+			"reproduce a recorded run and compare":                        "reproducir una ejecución registrada y comparar",
+			"report low-stock fast sellers and stale stock":               "informar de los más vendidos con poco stock y del stock inmovilizado",
+			"validate the configuration file (config check)":              "validar el archivo de configuración (config check)",
+			"create a configuration interactively":                        "crear una configuración paso a paso",
+			"list the supported input and output formats":                 "listar los formatos de entrada y salida admitidos",
+			"run the HTTP service":                                        "iniciar el servicio HTTP",
+			"show or clean the state directory":                           "mostrar o limpiar el directorio de estado",
+			"encrypt or decrypt configuration and credential files":       "cifrar o descifrar los archivos de configuración y credenciales",
+			"check the provider mappings against recorded responses":      "comprobar las correspondencias de los proveedores con respuestas grabadas",
+			"download newer data tables":                                  "descargar tablas de datos más recientes",
+			"print the version and data table versions":                   "mostrar la versión y las de las tablas de datos",
+			"enrich ISBNs read from a barcode scanner or standard input":  "enriquecer ISBN leídos con un lector de códigos de barras o desde la entrada estándar",
+
+			// Prompts
+			"y/N":      "s/N",
+			"Y/n":      "S/n",
+			"aborted":  "cancelado",
+			"warning:": "aviso:",
+			"Please answer with a number or one of the names above.":                          "Responda con un número o uno de los nombres anteriores.",
+			"This will create a booktool configuration. Press Enter to accept the [default].": "Se creará una configuración de booktool. Pulse Intro para aceptar el [valor predeterminado].",
+			"%s exists. Overwrite it?":                                                        "%s ya existe. ¿Sobrescribirlo?",
+			"Providers are queried in the order you enable them.":                             "Los proveedores se consultan en el orden en que los active.",
+			"Use %s?": "¿Usar %s?",
+			"  Keys can be stored in the file or read from an environment variable.": "  Las claves pueden guardarse en el archivo o leerse de una variable de entorno.",
+			"  Paste the %s API key (leave empty to use $%s)":                        "  Pegue la clave de API de %s (déjela vacía para usar $%s)",
+			"at least one provider is needed":                                        "se necesita al menos un proveedor",
+			"Path to one of your spreadsheets, to map its columns (optional)":        "Ruta de una de sus hojas de cálculo, para asignar sus columnas (opcional)",
+			"Could not read %s: %v\nColumns will be detected automatically.\n":       "No se pudo leer %s: %v\nLas columnas se detectarán automáticamente.\n",
+			"Output format for enriched files:":                                      "Formato de salida de los archivos enriquecidos:",
+			"Format":                                                                 "Formato",
+			"Languages as":                                                           "Idiomas como",
+			"Units":                                                                  "Unidades",
+			"\nWrote %s. Run \"booktool -input yourfile.xlsx\" to enrich a file.\n":  "\nSe escribió %s. Ejecute «booktool -input suarchivo.xlsx» para enriquecer un archivo.\n",
+			"this format has no header row to map":                                   "este formato no tiene fila de encabezado que asignar",
+			"Columns found:":                                                         "Columnas encontradas:",
+			"(none)":                                                                 "(ninguna)",
+			"Which column holds the %s?\n":                                           "¿Qué columna contiene el campo %s?\n",
+			"This run uses paid services (%d rows):\n":                               "Esta ejecución usa servicios de pago (%d filas):\n",
+			"estimated cost $%.2f exceeds the $%.2f threshold; rerun with -yes to accept":                             "el coste estimado de %.2f $ supera el umbral de %.2f $; vuelva a ejecutar con -yes para aceptarlo",
+			"paid services are enabled and the cost of standard input cannot be estimated; rerun with -yes to accept": "hay servicios de pago activados y no se puede estimar el coste de la entrada estándar; vuelva a ejecutar con -yes para aceptarlo",
+			"Continue? [y/N] ": "¿Continuar? [s/N] ",
+
+			// Reports and errors
+			"no input file given":                                             "no se indicó ningún archivo de entrada",
+			"open input: %w":                                                  "abrir la entrada: %w",
+			"%d rows enriched, %d failed":                                     "%d filas enriquecidas, %d con error",
+			"cannot record the run in the history: %v\n":                      "no se pudo registrar la ejecución en el historial: %v\n",
+			"wrote %s (%d rows, %d failed)\n":                                 "se escribió %s (%d filas, %d con error)\n",
+			"%d duplicate rows reused an earlier lookup; see the %q column\n": "%d filas duplicadas reutilizaron una búsqueda anterior; vea la columna %q\n",
+			"%d rows have warnings; see the %q column\n":                      "%d filas tienen avisos; vea la columna %q\n",
+			"upserted into %s table %q":                                       "insertadas o actualizadas en la tabla %[2]q (%[1]s)",
+			" (%d rows without ISBN skipped)":                                 " (%d filas sin ISBN omitidas)",
+			"sample of %d rows written to %s\ncheck it, then run again without -sample for the full file\n": "muestra de %d filas escrita en %s\nrevísela y vuelva a ejecutar sin -sample para el archivo completo\n",
+			"cannot open %s: %v\n":                                               "no se pudo abrir %s: %v\n",
+			"  %-14s %d of %d calls%s\n":                                         "  %-14s %d de %d llamadas%s\n",
+			" (exhausted; later rows skipped this provider)":                     " (agotado; las filas siguientes omitieron este proveedor)",
+			"  %-28s %5d requests, %5d cached, %3d errors, avg %s\n":             "  %-28s %5d solicitudes, %5d en caché, %3d errores, media %s\n",
+			"no Calibre library given":                                           "no se indicó ninguna biblioteca de Calibre",
+			"reading a Calibre library needs a booktool built with -tags sqlite": "leer una biblioteca de Calibre requiere un booktool compilado con -tags sqlite",
+			"%d of %d books enriched, %d failed":                                 "%d de %d libros enriquecidos, %d con error",
+			"%d of %d books have missing fields to fill (%d changes)\n":          "%d de %d libros tienen campos que completar (%d cambios)\n",
+			"%w (%d books updated before the failure)":                           "%w (%d libros actualizados antes del error)",
+			"updated %d books in %s\n":                                           "%d libros actualizados en %s\n",
+			"wrote %s\n":                                                         "se escribió %s\n",
+			"acquire needs a -wishlist and a -stock file":                        "acquire necesita un archivo -wishlist y un archivo -stock",
+			"acquire needs at least one price source (-prices)":                  "acquire necesita al menos una fuente de precios (-prices)",
+			"%d items checked, %d in stock":                                      "%d artículos comprobados, %d en existencias",
+			"%d of %d items are not in stock\n":                                  "%d de %d artículos no están en existencias\n",
+			"Scan barcodes or type ISBNs, one per line. Press Ctrl-D when done.": "Escanee los códigos de barras o escriba los ISBN, uno por línea. Pulse Ctrl-D al terminar.",
+			"%d. %s: not found (%v)\n":                                           "%d. %s: no encontrado (%v)\n",
+			"%d. %s: %s (again)\n":                                               "%d. %s: %s (repetido)\n",
+			"cannot remove the checkpoint: %v\n":                                 "no se pudo eliminar el punto de control: %v\n",
+			"cannot save the checkpoint: %v\n":                                   "no se pudo guardar el punto de control: %v\n",
+			"stopped at the %s limit after %d rows; continue with:\n  %s\n":      "detenido en el límite %s tras %d filas; para continuar:\n  %s\n",
+			"-update needs a file with a header row, such as xlsx or csv":        "-update necesita un archivo con fila de encabezado, como xlsx o csv",
+			"%s has none of the enrichment columns":                              "%s no tiene ninguna de las columnas de enriquecimiento",
+			"updated %s (%d rows enriched again, %d failed)\n":                   "se actualizó %s (%d filas enriquecidas de nuevo, %d con error)\n",
+			"-offline answers from the response cache, which -no-cache disables": "-offline responde desde la caché de respuestas, que -no-cache desactiva",
+			"%d rows are pending because their lookups are not in the cache; to fill them, run online with -update %s\n": "%d filas están pendientes porque sus búsquedas no están en la caché; para completarlas, ejecute en línea con -update %s\n",
+			"-cover-alt vision describes the downloaded covers; set -covers":                                             "-cover-alt vision describe las portadas descargadas; indique -covers",
+			"wrote the sitemap and SEO metadata to %s":                                                                   "se escribieron el sitemap y los metadatos SEO en %s",
+			"-site-dir needs -site-url, the template of the books' canonical URLs":                                       "-site-dir necesita -site-url, la plantilla de las URL canónicas de los libros",
+			"%d rows scored below -min-confidence %.2f; they are in %s for review\n":                                     "%d filas puntuaron por debajo de -min-confidence %.2f; están en %s para revisión\n",
+			"no catalog given":                                                              "no se indicó ningún catálogo",
+			"-refresh needs price sources (-prices)":                                        "-refresh necesita fuentes de precios (-prices)",
+			"reprice needs a catalog with a header row, such as xlsx or csv":                "reprice necesita un catálogo con una fila de encabezado, como xlsx o csv",
+			"%s has no price column to write to":                                            "%s no tiene una columna de precio donde escribir",
+			"%s has no market price columns in %s; enrich it with -prices, or use -refresh": "%s no tiene columnas de precios de mercado en %s; enriquézcalo con -prices o use -refresh",
+			"%d rows priced, %d changes":                                                    "%d filas con precio, %d cambios",
+			"%d of %d prices change\n":                                                      "%d de %d precios cambian\n",
+			"updated %d prices in %s\n":                                                     "se actualizaron %d precios en %s\n",
+			"%s: skipped %d sales rows without an ISBN, a title or a readable date\n":       "%s: se omitieron %d filas de ventas sin ISBN, título o fecha legible\n",
+			"%d low-stock and %d stale items\n":                                             "%d artículos con poco stock y %d artículos inmovilizados\n",
+			"%s: %d low-stock and %d stale items":                                           "%s: %d artículos con poco stock y %d artículos inmovilizados",
+			"posted the alerts to the -notify webhook\n":                                    "alertas enviadas al webhook de -notify\n",
+			"-columns does not apply to export profiles, which have their own columns":      "-columns no se aplica a los perfiles de exportación, que tienen sus propias columnas",
+			"wrote the manifest %s; replay the run with \"booktool replay %s\"\n":           "manifiesto %s escrito; reproduzca la ejecución con \"booktool replay %s\"\n",
+			"no manifest given":                                                             "no se indicó ningún manifiesto",
+			"%s changed since the run; a replay needs the same input":                       "%s cambió desde la ejecución; reproducirla requiere la misma entrada",
+			"response archive: %w":                                                          "archivo de respuestas: %w",
+			"warning: the configuration %s changed since the run\n":                         "aviso: la configuración %s cambió desde la ejecución\n",
+			"the run was made with booktool %s; replaying with %s\n":                        "la ejecución se hizo con booktool %s; se reproduce con %s\n",
+			"the recorded output %s changed since the run; nothing to compare with":         "la salida registrada %s cambió desde la ejecución; no hay con qué comparar",
+			"the replay differs from %s in %d cells":                                        "la reproducción difiere de %s en %d celdas",
+			"reproduced %s exactly (%d rows)\n":                                             "%s reproducido exactamente (%d filas)\n",
+			"%s differs from the replay (compared as a whole, not cell by cell)\n":          "%s difiere de la reproducción (comparado entero, no celda a celda)\n",
+			"%sonly in the recorded output\n":                                               "%ssolo en la salida registrada\n",
+			"%sonly in the replay\n":                                                        "%ssolo en la reproducción\n",
+			"%s%d rows only in the recorded output: %s\n":                                   "%s%d filas solo en la salida registrada: %s\n",
+			"%s%d rows only in the replay: %s\n":                                            "%s%d filas solo en la reproducción: %s\n",
+			"%scolumn %q only in the recorded output\n":                                     "%scolumna %q solo en la salida registrada\n",
+			"%scolumn %q only in the replay\n":                                              "%scolumna %q solo en la reproducción\n",
+			"%scolumn %q: %d of %d rows differ\n":                                           "%scolumna %q: %d de %d filas difieren\n",
+			"interrupted; finishing the rows in progress (interrupt again to quit)\n":       "interrumpido; terminando las filas en curso (interrumpa de nuevo para salir)\n",
+			"interrupted after %d rows; continue with:\n  %s\n":                             "interrumpido tras %d filas; continúe con:\n  %s\n",
+			"or with:\n  %s\n":                                                              "o con:\n  %s\n",
+			"read the checkpoint: %w":                                                       "leer el punto de control: %w",
+			"no stopped run of %s to resume":                                                "no hay ninguna ejecución detenida de %s que reanudar",
+			"resuming %s after its first %d rows, writing to %s\n":                          "reanudando %s tras sus primeras %d filas, escribiendo en %s\n",
+			"%d rows timed out after -row-timeout %s and are reported as failed\n":          "%d filas superaron -row-timeout %s y figuran como fallidas\n",
+			"the OpenLibrary dump index needs a booktool built with -tags sqlite":           "el índice de los volcados de OpenLibrary necesita un booktool compilado con -tags sqlite",
+			"no index at %s; build it from the OpenLibrary dumps with \"booktool oldump\"":  "no hay índice en %s; constrúyalo a partir de los volcados de OpenLibrary con \"booktool oldump\"",
+			"no dump file given":                                                            "no se indicó ningún archivo de volcado",
+			"%s: %d editions, %d works, %d authors":                                         "%s: %d ediciones, %d obras, %d autores",
+			"%s: %d editions (%d ISBNs), %d works, %d authors, %d skipped\n":                "%s: %d ediciones (%d ISBN), %d obras, %d autores, %d omitidos\n",
+			"indexed in %s; look books up in it with -providers %s\n":                       "indexado en %s; busque libros en él con -providers %s\n",
+			"index OpenLibrary data dumps for offline lookups":                              "indexar los volcados de OpenLibrary para búsquedas sin conexión",
+			"%d other editions listed in %s\n":                                              "%d otras ediciones listadas en %s\n",
+			"%d rows break validation rules; see the %q column\n":                           "%d filas incumplen las reglas de validación; vea la columna %q\n",
+			"%d rows broke validation rules and are reported as failed\n":                   "%d filas incumplen las reglas de validación y se informan como fallidas\n",
+		},
+	})
+}
@@ -0,0 +1,150 @@
+package i18n
+
+func init() {
+	Register("fr", &Catalog{
+		Name: "Français",
+		Yes:  []string{"o", "oui"},
+		No:   []string{"non"},
+		Messages: map[string]string{
+			// Commands
+			"usage: booktool [command] [flags]\n\ncommands:":              "utilisation : booktool [commande] [options]\n\ncommandes :",
+			"\nRun \"booktool <command> -h\" for the flags of a command.": "\nLancez « booktool <commande> -h » pour les options d'une commande.",
+			"booktool: unknown command %q\n\n":                            "booktool : commande inconnue %q\n\n",
+			"enrich an input file (default command)":                      "enrichir un fichier d'entrée (commande par défaut)",
+			"enrich a Calibre library and fill in missing fields":         "enrichir une bibliothèque Calibre et compléter les champs manquants",
+			"suggest editions to buy for wishlist items not in stock":     "suggérer les éditions à acheter pour les articles de la liste d'envies absents du stock",
+			"reprice a catalog from market prices":                        "recalculer les prix d'un catalogue d'après le marché",
+			"reproduce a recorded run and compare":                        "reproduire une exécution enregistrée et comparer",
+			"report low-stock fast sellers and stale stock":               "rapporter les meilleures ventes en rupture proche et le stock dormant",
+			"validate the configuration file (config check)":              "valider le fichier de configuration (config check)",
+			"create a configuration interactively":                        "créer une configuration pas à pas",
+			"list the supported input and output formats":                 "lister les formats d'entrée et de sortie pris en charge",
+			"run the HTTP service":                                        "lancer le service HTTP",
+			"show or clean the state directory":                           "afficher ou nettoyer le répertoire d'état",
+			"encrypt or decrypt configuration and credential files":       "chiffrer ou déchiffrer les fichiers de configuration et d'identifiants",
+			"check the provider mappings against recorded responses":      "vérifier les correspondances des fournisseurs sur des réponses enregistrées",
+			"download newer data tables":                                  "télécharger les tables de données plus récentes",
+			"print the version and data table versions":                   "afficher la version et celles des tables de données",
+			"enrich ISBNs read from a barcode scanner or standard input":  "enrichir des ISBN lus par un lecteur de codes-barres ou sur l'entrée standard",
+
+			// Prompts
+			"y/N":      "o/N",
+			"Y/n":      "O/n",
+			"aborted":  "abandon",
+			"warning:": "avertissement :",
+			"Please answer with a number or one of the names above.":                          "Répondez par un numéro ou l'un des noms ci-dessus.",
+			"This will create a booktool configuration. Press Enter to accept the [default].": "Création d'une configuration booktool. Appuyez sur Entrée pour accepter la [valeur par défaut].",
+			"%s exists. Overwrite it?":                                                        "%s existe déjà. L'écraser ?",
+			"Providers are queried in the order you enable them.":                             "Les fournisseurs sont interrogés dans l'ordre où vous les activez.",
+			"Use %s?": "Utiliser %s ?",
+			"  Keys can be stored in the file or read from an environment variable.": "  Les clés peuvent être enregistrées dans le fichier ou lues depuis une variable d'environnement.",
+			"  Paste the %s API key (leave empty to use $%s)":                        "  Collez la clé d'API %s (laissez vide pour utiliser $%s)",
+			"at least one provider is needed":                                        "il faut au moins un fournisseur",
+			"Path to one of your spreadsheets, to map its columns (optional)":        "Chemin d'un de vos tableurs, pour associer ses colonnes (facultatif)",
+			"Could not read %s: %v\nColumns will be detected automatically.\n":       "Impossible de lire %s : %v\nLes colonnes seront détectées automatiquement.\n",
+			"Output format for enriched files:":                                      "Format de sortie des fichiers enrichis :",
+			"Format":                                                                 "Format",
+			"Languages as":                                                           "Langues en",
+			"Units":                                                                  "Unités",
+			"\nWrote %s. Run \"booktool -input yourfile.xlsx\" to enrich a file.\n":  "\n%s écrit. Lancez « booktool -input votrefichier.xlsx » pour enrichir un fichier.\n",
+			"this format has no header row to map":                                   "ce format n'a pas de ligne d'en-tête à associer",
+			"Columns found:":                                                         "Colonnes trouvées :",
+			"(none)":                                                                 "(aucune)",
+			"Which column holds the %s?\n":                                           "Quelle colonne contient le champ %s ?\n",
+			"This run uses paid services (%d rows):\n":                               "Cette exécution utilise des services payants (%d lignes) :\n",
+			"estimated cost $%.2f exceeds the $%.2f threshold; rerun with -yes to accept":                             "le coût estimé de %.2f $ dépasse le seuil de %.2f $ ; relancez avec -yes pour l'accepter",
+			"paid services are enabled and the cost of standard input cannot be estimated; rerun with -yes to accept": "des services payants sont activés et le coût de l'entrée standard ne peut pas être estimé ; relancez avec -yes pour l'accepter",
+			"Continue? [y/N] ": "Continuer ? [o/N] ",
+
+			// Reports and errors
+			"no input file given":                                             "aucun fichier d'entrée indiqué",
+			"open input: %w":                                                  "ouverture de l'entrée : %w",
+			"%d rows enriched, %d failed":                                     "%d lignes enrichies, %d en échec",
+			"cannot record the run in the history: %v\n":                      "impossible d'enregistrer l'exécution dans l'historique : %v\n",
+			"wrote %s (%d rows, %d failed)\n":                                 "%s écrit (%d lignes, %d en échec)\n",
+			"%d duplicate rows reused an earlier lookup; see the %q column\n": "%d lignes en double ont repris une recherche précédente ; voir la colonne %q\n",
+			"%d rows have warnings; see the %q column\n":                      "%d lignes ont des avertissements ; voir la colonne %q\n",
+			"upserted into %s table %q":                                       "insérées ou mises à jour dans la table %[2]q (%[1]s)",
+			" (%d rows without ISBN skipped)":                                 " (%d lignes sans ISBN ignorées)",
+			"sample of %d rows written to %s\ncheck it, then run again without -sample for the full file\n": "échantillon de %d lignes écrit dans %s\nvérifiez-le, puis relancez sans -sample pour le fichier complet\n",
+			"cannot open %s: %v\n":                                               "impossible d'ouvrir %s : %v\n",
+			"  %-14s %d of %d calls%s\n":                                         "  %-14s %d appels sur %d%s\n",
+			" (exhausted; later rows skipped this provider)":                     " (épuisé ; les lignes suivantes se sont passées de ce fournisseur)",
+			"  %-28s %5d requests, %5d cached, %3d errors, avg %s\n":             "  %-28s %5d requêtes, %5d en cache, %3d erreurs, moy. %s\n",
+			"no Calibre library given":                                           "aucune bibliothèque Calibre indiquée",
+			"reading a Calibre library needs a booktool built with -tags sqlite": "la lecture d'une bibliothèque Calibre nécessite un booktool compilé avec -tags sqlite",
+			"%d of %d books enriched, %d failed":                                 "%d livres enrichis sur %d, %d en échec",
+			"%d of %d books have missing fields to fill (%d changes)\n":          "%d livres sur %d ont des champs manquants à compléter (%d modifications)\n",
+			"%w (%d books updated before the failure)":                           "%w (%d livres mis à jour avant l'échec)",
+			"updated %d books in %s\n":                                           "%d livres mis à jour dans %s\n",
+			"wrote %s\n":                                                         "%s écrit\n",
+			"acquire needs a -wishlist and a -stock file":                        "acquire nécessite un fichier -wishlist et un fichier -stock",
+			"acquire needs at least one price source (-prices)":                  "acquire nécessite au moins une source de prix (-prices)",
+			"%d items checked, %d in stock":                                      "%d articles vérifiés, %d en stock",
+			"%d of %d items are not in stock\n":                                  "%d articles sur %d ne sont pas en stock\n",
+			"Scan barcodes or type ISBNs, one per line. Press Ctrl-D when done.": "Scannez les codes-barres ou tapez les ISBN, un par ligne. Appuyez sur Ctrl-D pour terminer.",
+			"%d. %s: not found (%v)\n":                                           "%d. %s : introuvable (%v)\n",
+			"%d. %s: %s\n":                                                       "%d. %s : %s\n",
+			"%d. %s: %s (again)\n":                                               "%d. %s : %s (déjà scanné)\n",
+			"cannot remove the checkpoint: %v\n":                                 "impossible de supprimer le point de reprise : %v\n",
+			"cannot save the checkpoint: %v\n":                                   "impossible d'enregistrer le point de reprise : %v\n",
+			"stopped at the %s limit after %d rows; continue with:\n  %s\n":      "arrêt à la limite %s après %d lignes ; pour continuer :\n  %s\n",
+			"-update needs a file with a header row, such as xlsx or csv":        "-update nécessite un fichier avec une ligne d'en-tête, comme xlsx ou csv",
+			"%s has none of the enrichment columns":                              "%s ne contient aucune des colonnes d'enrichissement",
+			"updated %s (%d rows enriched again, %d failed)\n":                   "%s mis à jour (%d lignes enrichies à nouveau, %d en échec)\n",
+			"-offline answers from the response cache, which -no-cache disables": "-offline répond depuis le cache des réponses, que -no-cache désactive",
+			"%d rows are pending because their lookups are not in the cache; to fill them, run online with -update %s\n": "%d lignes sont en attente car leurs recherches ne sont pas en cache ; pour les compléter, relancez en ligne avec -update %s\n",
+			"-cover-alt vision describes the downloaded covers; set -covers":                                             "-cover-alt vision décrit les couvertures téléchargées ; indiquez -covers",
+			"wrote the sitemap and SEO metadata to %s":                                                                   "sitemap et métadonnées SEO écrits dans %s",
+			"-site-dir needs -site-url, the template of the books' canonical URLs":                                       "-site-dir nécessite -site-url, le modèle des URL canoniques des livres",
+			"%d rows scored below -min-confidence %.2f; they are in %s for review\n":                                     "%d lignes ont un score inférieur à -min-confidence %.2f ; elles sont dans %s pour vérification\n",
+			"no catalog given":                                                              "aucun catalogue indiqué",
+			"-refresh needs price sources (-prices)":                                        "-refresh nécessite des sources de prix (-prices)",
+			"reprice needs a catalog with a header row, such as xlsx or csv":                "reprice nécessite un catalogue avec une ligne d'en-tête, comme xlsx ou csv",
+			"%s has no price column to write to":                                            "%s n'a pas de colonne de prix où écrire",
+			"%s has no market price columns in %s; enrich it with -prices, or use -refresh": "%s n'a pas de colonnes de prix du marché en %s ; enrichissez-le avec -prices ou utilisez -refresh",
+			"%d rows priced, %d changes":                                                    "%d lignes tarifées, %d modifications",
+			"%d of %d prices change\n":                                                      "%d prix sur %d changent\n",
+			"updated %d prices in %s\n":                                                     "%d prix mis à jour dans %s\n",
+			"%s: skipped %d sales rows without an ISBN, a title or a readable date\n":       "%s : %d lignes de ventes ignorées, sans ISBN, titre ou date lisible\n",
+			"%d low-stock and %d stale items\n":                                             "%d articles en stock faible et %d articles dormants\n",
+			"%s: %d low-stock and %d stale items":                                           "%s : %d articles en stock faible et %d articles dormants",
+			"posted the alerts to the -notify webhook\n":                                    "alertes envoyées au webhook -notify\n",
+			"-columns does not apply to export profiles, which have their own columns":      "-columns ne s'applique pas aux profils d'export, qui ont leurs propres colonnes",
+			"wrote the manifest %s; replay the run with \"booktool replay %s\"\n":           "manifeste %s écrit ; rejouez l'exécution avec \"booktool replay %s\"\n",
+			"no manifest given":                                                             "aucun manifeste indiqué",
+			"%s changed since the run; a replay needs the same input":                       "%s a changé depuis l'exécution ; rejouer demande la même entrée",
+			"response archive: %w":                                                          "archive des réponses : %w",
+			"warning: the configuration %s changed since the run\n":                         "attention : la configuration %s a changé depuis l'exécution\n",
+			"the run was made with booktool %s; replaying with %s\n":                        "l'exécution a été faite avec booktool %s ; rejouée avec %s\n",
+			"the recorded output %s changed since the run; nothing to compare with":         "la sortie enregistrée %s a changé depuis l'exécution ; rien à quoi comparer",
+			"the replay differs from %s in %d cells":                                        "la réexécution diffère de %s dans %d cellules",
+			"reproduced %s exactly (%d rows)\n":                                             "%s reproduit à l'identique (%d lignes)\n",
+			"%s differs from the replay (compared as a whole, not cell by cell)\n":          "%s diffère de la réexécution (comparé en entier, pas cellule par cellule)\n",
+			"%sonly in the recorded output\n":                                               "%sseulement dans la sortie enregistrée\n",
+			"%sonly in the replay\n":                                                        "%sseulement dans la réexécution\n",
+			"%s%d rows only in the recorded output: %s\n":                                   "%s%d lignes seulement dans la sortie enregistrée : %s\n",
+			"%s%d rows only in the replay: %s\n":                                            "%s%d lignes seulement dans la réexécution : %s\n",
+			"%scolumn %q only in the recorded output\n":                                     "%scolonne %q seulement dans la sortie enregistrée\n",
+			"%scolumn %q only in the replay\n":                                              "%scolonne %q seulement dans la réexécution\n",
+			"%scolumn %q: %d of %d rows differ\n":                                           "%scolonne %q : %d lignes sur %d diffèrent\n",
+			"interrupted; finishing the rows in progress (interrupt again to quit)\n":       "interrompu ; fin des lignes en cours (interrompez encore pour quitter)\n",
+			"interrupted after %d rows; continue with:\n  %s\n":                             "interrompu après %d lignes ; continuez avec :\n  %s\n",
+			"or with:\n  %s\n":                                                              "ou avec :\n  %s\n",
+			"read the checkpoint: %w":                                                       "lecture du point de reprise : %w",
+			"no stopped run of %s to resume":                                                "aucune exécution interrompue de %s à reprendre",
+			"resuming %s after its first %d rows, writing to %s\n":                          "reprise de %s après ses %d premières lignes, écriture dans %s\n",
+			"%d rows timed out after -row-timeout %s and are reported as failed\n":          "%d lignes ont dépassé -row-timeout %s et sont signalées en échec\n",
+			"the OpenLibrary dump index needs a booktool built with -tags sqlite":           "l'index des exports OpenLibrary nécessite un booktool compilé avec -tags sqlite",
+			"no index at %s; build it from the OpenLibrary dumps with \"booktool oldump\"":  "aucun index dans %s ; construisez-le à partir des exports OpenLibrary avec « booktool oldump »",
+			"no dump file given":                                                            "aucun fichier d'export indiqué",
+			"%s: %d editions, %d works, %d authors":                                         "%s : %d éditions, %d œuvres, %d auteurs",
+			"%s: %d editions (%d ISBNs), %d works, %d authors, %d skipped\n":                "%s : %d éditions (%d ISBN), %d œuvres, %d auteurs, %d ignorés\n",
+			"indexed in %s; look books up in it with -providers %s\n":                       "indexé dans %s ; recherchez-y les livres avec -providers %s\n",
+			"index OpenLibrary data dumps for offline lookups":                              "indexer les exports OpenLibrary pour des recherches hors ligne",
+			"%d other editions listed in %s\n":                                              "%d autres éditions listées dans %s\n",
+			"%d rows break validation rules; see the %q column\n":                           "%d lignes enfreignent les règles de validation ; voir la colonne %q\n",
+			"%d rows broke validation rules and are reported as failed\n":                   "%d lignes enfreignent les règles de validation et sont signalées en échec\n",
+		},
+	})
+}
@@ -0,0 +1,146 @@
+// Package i18n translates the messages booktool prints for people:
+// prompts, errors raised by the commands and run reports.
+//
+// Messages are looked up by their English text, which is also the
+// fallback, so untranslated messages still read well. Translations must
+// keep the verbs of the English format; %[n]s can reorder them.
+package i18n
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// English is the language of the messages in the source.
+const English = "en"
+
+// Catalog holds the translations of one language.
+type Catalog struct {
+	// Name is the language's own name, e.g. "Français".
+	Name string
+	// Yes and No are the answers accepted at yes/no prompts besides
+	// "y", "yes", "n" and "no".
+	Yes, No []string
+	// Messages maps English messages to translations.
+	Messages map[string]string
+}
+
+var (
+	catalogs = make(map[string]*Catalog)
+	current  = &Catalog{Name: "English"}
+	lang     = English
+)
+
+// Register makes a catalog available under an ISO 639-1 code. It panics
+// if the code is registered twice.
+func Register(code string, c *Catalog) {
+	if _, dup := catalogs[code]; dup || code == English {
+		panic("i18n: Register called twice for " + code)
+	}
+	catalogs[code] = c
+}
+
+// Languages returns the codes of the available languages, English
+// included, sorted.
+func Languages() []string {
+	codes := append(slices.Collect(maps.Keys(catalogs)), English)
+	slices.Sort(codes)
+	return codes
+}
+
+// Set selects the language of messages. Regional forms such as "fr-CA"
+// or "fr_FR.UTF-8" select their base language.
+func Set(code string) error {
+	base := baseLanguage(code)
+	if base == English || base == "" {
+		current, lang = &Catalog{Name: "English"}, English
+		return nil
+	}
+	c, ok := catalogs[base]
+	if !ok {
+		return fmt.Errorf("unsupported language %q (available: %s)", code, strings.Join(Languages(), ", "))
+	}
+	current, lang = c, base
+	return nil
+}
+
+// Lang returns the code of the selected language.
+func Lang() string { return lang }
+
+// EnvLang is the environment variable selecting the language of
+// booktool's messages; it takes precedence over the locale.
+const EnvLang = "BOOKTOOL_LANG"
+
+// FromEnv returns the language selected by $BOOKTOOL_LANG or the locale
+// ($LC_ALL, $LC_MESSAGES, $LANG), or English when none names an
+// available language.
+func FromEnv() string {
+	for _, v := range []string{EnvLang, "LC_ALL", "LC_MESSAGES", "LANG"} {
+		s := os.Getenv(v)
+		if s == "" {
+			continue
+		}
+		// The first variable set decides, as with the C library.
+		if base := baseLanguage(s); catalogs[base] != nil {
+			return base
+		}
+		return English
+	}
+	return English
+}
+
+// baseLanguage reduces a locale name to its language code: "fr_FR.UTF-8"
+// and "fr-CA" become "fr"; "C" and "POSIX" become English.
+func baseLanguage(s string) string {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(s, "_-.@"); i >= 0 {
+		s = s[:i]
+	}
+	if s == "c" || s == "posix" {
+		return English
+	}
+	return s
+}
+
+// T returns the translation of msg, or msg itself.
+func T(msg string) string {
+	if t, ok := current.Messages[msg]; ok {
+		return t
+	}
+	return msg
+}
+
+// Sprintf formats the translation of format.
+func Sprintf(format string, args ...any) string {
+	return fmt.Sprintf(T(format), args...)
+}
+
+// Fprintf writes the translation of format to w.
+func Fprintf(w io.Writer, format string, args ...any) (int, error) {
+	return fmt.Fprintf(w, T(format), args...)
+}
+
+// Errorf returns an error with the translation of format; %w wraps as
+// with fmt.Errorf.
+func Errorf(format string, args ...any) error {
+	return fmt.Errorf(T(format), args...)
+}
+
+// IsYes reports whether an answer to a yes/no prompt means yes.
+func IsYes(answer string) bool {
+	return isAnswer(answer, []string{"y", "yes"}, current.Yes)
+}
+
+// IsNo reports whether an answer to a yes/no prompt means no.
+func IsNo(answer string) bool {
+	return isAnswer(answer, []string{"n", "no"}, current.No)
+}
+
+func isAnswer(answer string, english, local []string) bool {
+	a := strings.ToLower(strings.TrimSpace(answer))
+	return slices.Contains(english, a) || slices.Contains(local, a)
+}
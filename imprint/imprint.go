@@ -0,0 +1,272 @@
+// Package imprint maps the publisher names providers report, such as
+// "Penguin Books Ltd" or "PENGUIN CLASSICS", to a canonical imprint and
+// the publishing group that owns it, for reports by publisher.
+//
+// The mapping is a data table, refreshed with "booktool update-data" as
+// groups buy and sell imprints.
+package imprint
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"unicode"
+
+	"github.com/SouadAli10/book_scrapping_tool/datatable"
+)
+
+// TableVersion is the date the imprint table was last refreshed.
+const TableVersion = "2026-10-17"
+
+// Group is a publishing group and its imprints.
+type Group struct {
+	Parent string `json:"parent"`
+	// Imprints maps imprint names to their other spellings.
+	Imprints map[string][]string `json:"imprints"`
+}
+
+var table = []Group{
+	{"Penguin Random House", map[string][]string{
+		"Penguin Books":       {"Penguin", "Penguin UK", "Penguin USA"},
+		"Penguin Classics":    nil,
+		"Penguin Press":       nil,
+		"Puffin Books":        {"Puffin"},
+		"Viking":              {"Viking Press", "Viking Penguin"},
+		"Allen Lane":          nil,
+		"Hamish Hamilton":     nil,
+		"Michael Joseph":      nil,
+		"Ladybird Books":      {"Ladybird"},
+		"Random House":        nil,
+		"Alfred A. Knopf":     {"Knopf", "A. A. Knopf"},
+		"Vintage":             {"Vintage Books", "Vintage International", "Vintage Classics"},
+		"Anchor Books":        {"Anchor"},
+		"Pantheon Books":      {"Pantheon"},
+		"Doubleday":           nil,
+		"Crown":               {"Crown Publishers"},
+		"Bantam Books":        {"Bantam"},
+		"Dell":                {"Dell Publishing"},
+		"Ballantine Books":    {"Ballantine"},
+		"Del Rey":             {"Del Rey Books"},
+		"Riverhead Books":     {"Riverhead"},
+		"G. P. Putnam's Sons": {"Putnam", "G.P. Putnam's Sons"},
+		"Berkley":             {"Berkley Books"},
+		"Ace Books":           {"Ace"},
+		"Dutton":              {"E. P. Dutton"},
+		"Plume":               nil,
+		"Signet":              {"Signet Classics", "Signet Classic", "New American Library", "NAL"},
+		"Portfolio":           nil,
+		"Jonathan Cape":       {"Cape"},
+		"Chatto & Windus":     nil,
+		"The Bodley Head":     {"Bodley Head"},
+		"Century":             nil,
+		"Ebury Press":         {"Ebury"},
+		"Transworld":          {"Transworld Publishers"},
+		"Corgi Books":         {"Corgi"},
+	}},
+	{"HarperCollins", map[string][]string{
+		"HarperCollins":             {"Harper Collins", "HarperCollins Publishers"},
+		"Harper":                    {"Harper & Row", "Harper & Brothers", "Harper Paperbacks"},
+		"Harper Perennial":          {"Perennial"},
+		"Harper Voyager":            {"Voyager", "HarperVoyager"},
+		"William Morrow":            {"Morrow"},
+		"Avon":                      {"Avon Books"},
+		"Ecco":                      {"Ecco Press"},
+		"Fourth Estate":             {"4th Estate"},
+		"Collins":                   {"William Collins"},
+		"Harlequin":                 nil,
+		"Mills & Boon":              nil,
+		"Thomas Nelson":             nil,
+		"Zondervan":                 nil,
+		"Houghton Mifflin Harcourt": {"Houghton Mifflin", "Harcourt", "Harcourt Brace"},
+	}},
+	{"Simon & Schuster", map[string][]string{
+		"Simon & Schuster": {"Simon and Schuster"},
+		"Scribner":         {"Charles Scribner's Sons", "Scribner's"},
+		"Atria Books":      {"Atria"},
+		"Gallery Books":    {"Gallery"},
+		"Pocket Books":     {"Pocket"},
+		"Touchstone":       nil,
+		"Free Press":       {"The Free Press"},
+		"Saga Press":       nil,
+		"Aladdin":          {"Aladdin Paperbacks"},
+		"Atheneum":         {"Atheneum Books"},
+	}},
+	{"Hachette Book Group", map[string][]string{
+		"Little, Brown and Company": {"Little Brown", "Little, Brown"},
+		"Grand Central Publishing":  {"Grand Central", "Warner Books"},
+		"Orbit":                     {"Orbit Books"},
+		"Hachette Books":            nil,
+		"Basic Books":               nil,
+		"Mulholland Books":          nil,
+		"Hodder & Stoughton":        {"Hodder"},
+		"Headline":                  {"Headline Publishing"},
+		"Orion":                     {"Orion Books", "Orion Publishing"},
+		"Gollancz":                  {"Victor Gollancz"},
+		"Weidenfeld & Nicolson":     nil,
+		"Quercus":                   nil,
+		"John Murray":               nil,
+		"Sceptre":                   nil,
+		"Sphere":                    nil,
+		"Abacus":                    nil,
+		"Virago":                    {"Virago Press"},
+		"Octopus":                   {"Octopus Publishing"},
+	}},
+	{"Macmillan Publishers", map[string][]string{
+		"Macmillan":                 {"Macmillan Publishers", "Pan Macmillan"},
+		"Pan Books":                 {"Pan"},
+		"Picador":                   nil,
+		"Tor Books":                 {"Tor", "Tom Doherty Associates"},
+		"Forge":                     {"Forge Books"},
+		"St. Martin's Press":        {"St. Martin's", "St Martins Press"},
+		"Minotaur Books":            {"Minotaur"},
+		"Farrar, Straus and Giroux": {"FSG", "Farrar Straus Giroux"},
+		"Henry Holt":                {"Henry Holt and Company", "Holt"},
+		"Flatiron Books":            {"Flatiron"},
+		"Celadon Books":             {"Celadon"},
+		"Feiwel & Friends":          nil,
+	}},
+	{"Bloomsbury Publishing", map[string][]string{"Bloomsbury": {"Bloomsbury Publishing"}}},
+	{"Scholastic", map[string][]string{"Scholastic": {"Scholastic Press", "Scholastic Inc"}}},
+	{"W. W. Norton & Company", map[string][]string{"W. W. Norton": {"Norton", "W.W. Norton"}}},
+	{"Faber & Faber", map[string][]string{"Faber & Faber": {"Faber and Faber", "Faber"}}},
+	{"Dover Publications", map[string][]string{"Dover": {"Dover Publications"}}},
+	{"Oxford University Press", map[string][]string{"Oxford University Press": {"OUP", "Oxford University Press, USA"}}},
+	{"Cambridge University Press", map[string][]string{"Cambridge University Press": {"CUP"}}},
+	{"Pearson", map[string][]string{
+		"Pearson":        {"Pearson Education"},
+		"Prentice Hall":  {"Prentice-Hall"},
+		"Addison-Wesley": {"Addison Wesley"},
+		"Longman":        nil,
+	}},
+	{"Cengage", map[string][]string{
+		"Cengage Learning": {"Cengage"},
+		"Brooks/Cole":      {"Brooks Cole"},
+		"Wadsworth":        nil,
+	}},
+	{"McGraw Hill", map[string][]string{"McGraw Hill": {"McGraw-Hill", "McGraw-Hill Education", "McGraw Hill Education"}}},
+	{"John Wiley & Sons", map[string][]string{
+		"Wiley":       {"John Wiley & Sons", "John Wiley", "Wiley-Blackwell"},
+		"Jossey-Bass": nil,
+	}},
+	{"Springer Nature", map[string][]string{
+		"Springer":           {"Springer-Verlag", "Springer Nature"},
+		"Palgrave Macmillan": {"Palgrave"},
+	}},
+	{"Taylor & Francis", map[string][]string{
+		"Routledge":        nil,
+		"CRC Press":        nil,
+		"Taylor & Francis": {"Taylor and Francis"},
+	}},
+	{"Elsevier", map[string][]string{"Elsevier": nil}},
+	{"Viz Media", map[string][]string{"Viz Media": {"VIZ", "Viz Media LLC"}}},
+	{"Kodansha", map[string][]string{"Kodansha": {"Kodansha USA", "Kodansha Comics"}}},
+}
+
+// Imprint is the canonical imprint of a publisher name and the group
+// that owns it.
+type Imprint struct {
+	Name   string
+	Parent string
+}
+
+var index map[string]Imprint
+
+func init() {
+	datatable.Register(datatable.Table{
+		Name:    "imprints",
+		Summary: "publisher imprints and their parent groups",
+		Version: TableVersion,
+		Export:  func() ([]byte, error) { return json.MarshalIndent(table, "", "  ") },
+		Load:    loadTable,
+	})
+	buildIndex()
+}
+
+// loadTable replaces table with a downloaded table.
+func loadTable(data []byte) error {
+	var t []Group
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+	if len(t) == 0 {
+		return errors.New("no publishing groups")
+	}
+	table = t
+	buildIndex()
+	return nil
+}
+
+// buildIndex indexes the imprints by the keys of their names and other
+// spellings. A group's own name maps to the group, unless it is also the
+// name of one of its imprints.
+func buildIndex() {
+	index = make(map[string]Imprint)
+	for _, g := range table {
+		index[key(g.Parent)] = Imprint{Name: g.Parent, Parent: g.Parent}
+	}
+	for _, g := range table {
+		for name, aliases := range g.Imprints {
+			im := Imprint{Name: name, Parent: g.Parent}
+			index[key(name)] = im
+			for _, a := range aliases {
+				index[key(a)] = im
+			}
+		}
+	}
+}
+
+// noise are the words left out when publisher names are compared, such
+// as the legal form of the company.
+var noise = map[string]bool{
+	"the": true, "and": true, "inc": true, "incorporated": true, "ltd": true,
+	"limited": true, "llc": true, "plc": true, "gmbh": true, "co": true,
+	"company": true, "corp": true, "corporation": true, "group": true,
+	"publisher": true, "publishers": true, "publishing": true, "pub": true,
+	"usa": true, "uk": true,
+}
+
+// key reduces a publisher name to the words that tell publishers apart,
+// so "Penguin Books Ltd." and "PENGUIN BOOKS" compare equal.
+func key(s string) string {
+	s = strings.NewReplacer("'", "", "’", "", ".", " ").Replace(strings.ToLower(s))
+	var ws []string
+	for _, w := range strings.FieldsFunc(s, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+		if !noise[w] {
+			ws = append(ws, w)
+		}
+	}
+	return strings.Join(ws, " ")
+}
+
+// Lookup returns the imprint of a publisher name. A name that extends a
+// known one, such as "Penguin Classics Deluxe Edition", maps to the
+// longest known name it starts with.
+func Lookup(publisher string) (Imprint, bool) {
+	k := key(publisher)
+	if k == "" {
+		return Imprint{}, false
+	}
+	if im, ok := index[k]; ok {
+		return im, true
+	}
+	for {
+		i := strings.LastIndexByte(k, ' ')
+		if i < 0 {
+			return Imprint{}, false
+		}
+		k = k[:i]
+		if im, ok := index[k]; ok {
+			return im, true
+		}
+	}
+}
+
+// Resolve returns the imprint of the first of publishers that is known.
+func Resolve(publishers []string) (Imprint, bool) {
+	for _, p := range publishers {
+		if im, ok := Lookup(p); ok {
+			return im, true
+		}
+	}
+	return Imprint{}, false
+}
@@ -0,0 +1,42 @@
+package input
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+)
+
+func init() {
+	// Site exports are registered first so their header is recognized
+	// before the generic dialects.
+	for _, x := range exports {
+		Register(Format{Name: x.name, Sniff: x.sniff, New: x.open})
+	}
+	Register(Format{Name: "tsv", Extensions: []string{".tsv", ".tab"}, Sniff: sniffDelim('\t'), New: newDelimited('\t')})
+	Register(Format{Name: "csv", Extensions: []string{".csv", ".txt"}, Sniff: sniffDelim(','), New: newDelimited(',')})
+}
+
+// sniffDelim matches text whose first line contains delim.
+func sniffDelim(delim byte) func([]byte) bool {
+	return func(head []byte) bool {
+		head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+		if len(head) == 0 || bytes.IndexByte(head, 0) >= 0 || head[0] == '{' {
+			return false
+		}
+		line := head
+		if i := bytes.IndexByte(head, '\n'); i >= 0 {
+			line = head[:i]
+		}
+		return bytes.IndexByte(line, delim) >= 0
+	}
+}
+
+func newDelimited(delim rune) func(io.Reader, Options) (Reader, error) {
+	return func(r io.Reader, opts Options) (Reader, error) {
+		cr := csv.NewReader(r)
+		cr.Comma = delim
+		cr.FieldsPerRecord = -1
+		cr.LazyQuotes = true
+		return newTableReader(cr.Read, nil, opts)
+	}
+}
@@ -0,0 +1,142 @@
+package input
+
+import (
+	"bytes"
+	"encoding/csv"
+	"io"
+	"maps"
+	"regexp"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+)
+
+// export describes the library export of a cataloguing site: a CSV or
+// TSV file with a recognizable header whose columns need some cleaning
+// before they map to Row fields.
+type export struct {
+	name   string
+	delim  rune
+	header string // start of the header line
+	// columns maps Row fields to the export's columns; Options.Columns
+	// override them.
+	columns map[string]string
+	// fix cleans a data record in place. col returns the index of a
+	// column, or -1.
+	fix func(rec []string, col func(name string) int)
+}
+
+var exports = []export{
+	{
+		// Goodreads writes identifiers as ="0451526538" so spreadsheets
+		// keep leading zeros, and appends the series to titles.
+		name: "goodreads", delim: ',',
+		header:  "Book Id,Title,Author,Author l-f",
+		columns: map[string]string{"isbn": "ISBN13", "title": "Title", "author": "Author"},
+		fix: func(rec []string, col func(string) int) {
+			for i, v := range rec {
+				rec[i] = unwrapFormula(v)
+			}
+			fallback(rec, col("ISBN13"), col("ISBN"))
+			update(rec, col("Title"), func(s string) string { return seriesSuffix.ReplaceAllString(s, "") })
+		},
+	},
+	{
+		// LibraryThing writes the ISBN in brackets, "[0618260307]", and
+		// every known ISBN of the work in "ISBNs".
+		name: "librarything", delim: '\t',
+		header: "Book Id\tTitle\tSort Character\tPrimary Author",
+		columns: map[string]string{
+			"isbn": "ISBN", "title": "Title", "author": "Primary Author",
+			"quantity": "Copies", "condition": "Condition",
+		},
+		fix: func(rec []string, col func(string) int) {
+			update(rec, col("ISBN"), func(s string) string { return strings.Trim(s, "[] ") })
+			update(rec, col("ISBNs"), func(s string) string { s, _, _ = strings.Cut(s, ","); return s })
+			fallback(rec, col("ISBN"), col("ISBNs"))
+		},
+	},
+	{
+		// StoryGraph mixes ISBNs with its own identifiers in
+		// "ISBN/UID" and lists all authors in one column.
+		name: "storygraph", delim: ',',
+		header:  "Title,Authors,Contributors,ISBN/UID",
+		columns: map[string]string{"isbn": "ISBN/UID", "title": "Title", "author": "Authors"},
+		fix: func(rec []string, col func(string) int) {
+			update(rec, col("ISBN/UID"), func(s string) string {
+				if !isbn.Valid(isbn.Normalize(s)) {
+					return ""
+				}
+				return s
+			})
+			update(rec, col("Authors"), func(s string) string { s, _, _ = strings.Cut(s, ","); return s })
+		},
+	},
+}
+
+// seriesSuffix matches the series suffix appended to titles, as in "The
+// Hobbit (Middle-earth #0)". It would lower the title match of rows
+// looked up by title.
+var seriesSuffix = regexp.MustCompile(`\s*\([^()]*#\s*[\d.]+\)$`)
+
+func (x *export) sniff(head []byte) bool {
+	return bytes.HasPrefix(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), []byte(x.header))
+}
+
+func (x *export) open(r io.Reader, opts Options) (Reader, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = x.delim
+	cr.FieldsPerRecord = -1
+	cr.LazyQuotes = true
+	var cols map[string]int
+	col := func(name string) int {
+		if i, ok := cols[normHeader(name)]; ok {
+			return i
+		}
+		return -1
+	}
+	next := func() ([]string, error) {
+		rec, err := cr.Read()
+		if err != nil {
+			return nil, err
+		}
+		if cols == nil {
+			cols = make(map[string]int)
+			for i, h := range rec {
+				cols[normHeader(h)] = i
+			}
+			return rec, nil
+		}
+		x.fix(rec, col)
+		return rec, nil
+	}
+	c := maps.Clone(x.columns)
+	maps.Copy(c, opts.Columns)
+	opts.Columns = c
+	return newTableReader(next, nil, opts)
+}
+
+// unwrapFormula turns the ="value" text formulas of spreadsheet exports
+// into value.
+func unwrapFormula(v string) string {
+	if s, ok := strings.CutPrefix(v, `="`); ok {
+		if s, ok := strings.CutSuffix(s, `"`); ok {
+			return s
+		}
+	}
+	return v
+}
+
+// update replaces the value of column i, if the record has it.
+func update(rec []string, i int, f func(string) string) {
+	if i >= 0 && i < len(rec) {
+		rec[i] = strings.TrimSpace(f(rec[i]))
+	}
+}
+
+// fallback copies column from into column to when to is empty.
+func fallback(rec []string, to, from int) {
+	if to >= 0 && from >= 0 && to < len(rec) && from < len(rec) && rec[to] == "" {
+		rec[to] = rec[from]
+	}
+}
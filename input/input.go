@@ -0,0 +1,329 @@
+// Package input defines the Reader interface implemented by every input
+// format and a registry that picks a format by name, by content sniffing
+// (magic bytes) or by file extension.
+package input
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Row is one book to enrich, as read from the input.
+type Row struct {
+	// Index is the zero-based position of the row among data rows.
+	Index    int
+	ISBN     string
+	Title    string
+	Author   string
+	Quantity string
+	// Condition and Price are optional listing details used by the
+	// marketplace profiles. They are not part of Columns.
+	Condition string
+	Price     string
+	// Date is an optional date of the row: when the copy was acquired,
+	// in a catalog, or sold, in a sales import. It is kept as written.
+	Date string
+	// Record holds the cells of the row as read, for inputs with a
+	// header row; see Tabular.
+	Record []string
+	// Sheet is the worksheet the row was read from, for inputs read
+	// from several worksheets, and SheetIndex its zero-based position
+	// among the data rows of that sheet. Index runs on across sheets.
+	Sheet      string
+	SheetIndex int
+}
+
+// Line returns the spreadsheet row number of r, counting the header
+// row: within its worksheet for inputs read from several.
+func (r *Row) Line() int {
+	if r.Sheet != "" {
+		return r.SheetIndex + 2
+	}
+	return r.Index + 2
+}
+
+// Columns are the output headers of the input fields, in the order
+// Row.Cells returns them.
+var Columns = []string{"ISBN", "Title", "Author", "Quantity"}
+
+// Cells returns the input fields of r in Columns order.
+func (r *Row) Cells() []string {
+	return []string{r.ISBN, r.Title, r.Author, r.Quantity}
+}
+
+// Reader yields input rows. Next returns io.EOF after the last row.
+type Reader interface {
+	Next() (*Row, error)
+	Close() error
+}
+
+// Tabular is implemented by readers of inputs with a header row.
+type Tabular interface {
+	Reader
+	// Header returns the input's header row.
+	Header() []string
+	// Mapping returns the header matched to each Row field.
+	Mapping() map[string]string
+}
+
+// AsTabular returns the Tabular view of r, if it has one, also through
+// Skip and Limit.
+func AsTabular(r Reader) (Tabular, bool) {
+	for {
+		switch w := r.(type) {
+		case *fileReader:
+			r = w.Reader
+		case *skipReader:
+			r = w.Reader
+		case *limitReader:
+			r = w.Reader
+		default:
+			t, ok := r.(Tabular)
+			return t, ok
+		}
+	}
+}
+
+// Fields lists the Row fields that can be mapped to input columns.
+var Fields = []string{"isbn", "title", "author", "quantity", "condition", "price", "date"}
+
+// Options configures readers.
+type Options struct {
+	// Columns maps Row fields ("isbn", "title", "author", "quantity"...) to
+	// the input header that holds them. Unmapped fields are located by
+	// matching common header names.
+	Columns map[string]string
+	// Sheet selects the worksheet of spreadsheet inputs; empty selects
+	// the first one.
+	Sheet string
+	// Sheets selects several worksheets, read one after the other, and
+	// AllSheets every worksheet; Row.Sheet tells their rows apart. With
+	// AllSheets, worksheets that are empty or have neither an ISBN nor
+	// a title column, such as notes, are skipped.
+	Sheets    []string
+	AllSheets bool
+}
+
+// multiSheet reports whether opts reads several worksheets.
+func (o Options) multiSheet() bool {
+	return o.AllSheets || len(o.Sheets) > 0
+}
+
+// Format describes a registered input format.
+type Format struct {
+	Name       string
+	Extensions []string
+	// Sniff reports whether head, the first bytes of the input, looks
+	// like this format. It may be nil for formats that can only be
+	// selected by name or extension.
+	Sniff func(head []byte) bool
+	New   func(r io.Reader, opts Options) (Reader, error)
+	// Sheets is set for workbook formats, which honor Options.Sheets
+	// and AllSheets.
+	Sheets bool
+}
+
+var (
+	mu      sync.RWMutex
+	formats = make(map[string]*Format)
+	// order keeps registration order so sniffing is deterministic and
+	// specific formats registered first win over generic ones.
+	order []*Format
+)
+
+// Register makes a format available. It panics on duplicate names.
+func Register(f Format) {
+	mu.Lock()
+	defer mu.Unlock()
+	name := strings.ToLower(f.Name)
+	if f.New == nil {
+		panic("input: Register factory is nil for " + name)
+	}
+	if _, dup := formats[name]; dup {
+		panic("input: Register called twice for " + name)
+	}
+	formats[name] = &f
+	order = append(order, &f)
+}
+
+// Names returns the registered format names, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(formats))
+	for n := range formats {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// sniffLen is how many bytes are inspected for magic numbers.
+const sniffLen = 512
+
+// Detect picks the format of an input from an explicit name, its content
+// or its path, in that order of preference.
+func Detect(name, path string, head []byte) (*Format, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if name != "" {
+		f, ok := formats[strings.ToLower(name)]
+		if !ok {
+			return nil, fmt.Errorf("unknown input format %q (available: %s)", name, strings.Join(namesLocked(), ", "))
+		}
+		return f, nil
+	}
+	ext := strings.ToLower(filepath.Ext(path))
+	// Extensions disambiguate formats that sniff alike (CSV dialects), so
+	// prefer a format that matches both. Formats without extensions,
+	// such as site exports, are recognized by content alone.
+	for _, f := range order {
+		if f.Sniff != nil && f.Sniff(head) && (len(f.Extensions) == 0 || hasExt(f, ext)) {
+			return f, nil
+		}
+	}
+	for _, f := range order {
+		if f.Sniff != nil && f.Sniff(head) {
+			return f, nil
+		}
+	}
+	for _, f := range order {
+		if hasExt(f, ext) {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("cannot detect the format of %q; use -input-format (available: %s)",
+		path, strings.Join(namesLocked(), ", "))
+}
+
+func namesLocked() []string {
+	names := make([]string, 0, len(order))
+	for _, f := range order {
+		names = append(names, f.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func hasExt(f *Format, ext string) bool {
+	for _, e := range f.Extensions {
+		if e == ext {
+			return true
+		}
+	}
+	return false
+}
+
+// NewReader detects the format of r and returns a Reader for it. path is
+// only used for extension matching and may be empty.
+func NewReader(r io.Reader, name, path string, opts Options) (Reader, error) {
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, _ := br.Peek(sniffLen)
+	f, err := Detect(name, path, head)
+	if err != nil {
+		return nil, err
+	}
+	if opts.multiSheet() && !f.Sheets {
+		return nil, fmt.Errorf("%s input has no worksheets to select", f.Name)
+	}
+	return f.New(br, opts)
+}
+
+// Stdin is the path naming standard input.
+const Stdin = "-"
+
+// Open opens the file at path and returns a Reader for it. Closing the
+// Reader closes the file. Open reads standard input when path is Stdin;
+// its format can then only be detected from the content.
+func Open(path, name string, opts Options) (Reader, error) {
+	if path == Stdin {
+		return NewReader(os.Stdin, name, "", opts)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	r, err := NewReader(f, name, path, opts)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &fileReader{Reader: r, f: f}, nil
+}
+
+type fileReader struct {
+	Reader
+	f *os.File
+}
+
+func (r *fileReader) Close() error {
+	err := r.Reader.Close()
+	if ferr := r.f.Close(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// Limit returns a Reader that stops after n rows of r.
+func Limit(r Reader, n int) Reader {
+	return &limitReader{Reader: r, left: n}
+}
+
+type limitReader struct {
+	Reader
+	left int
+}
+
+func (l *limitReader) Next() (*Row, error) {
+	if l.left <= 0 {
+		return nil, io.EOF
+	}
+	l.left--
+	return l.Reader.Next()
+}
+
+// Skip returns a Reader yielding the rows of r from index n on, for
+// continuing a run that stopped early. Rows keep their index.
+func Skip(r Reader, n int) Reader {
+	return &skipReader{Reader: r, from: n}
+}
+
+type skipReader struct {
+	Reader
+	from int
+}
+
+func (s *skipReader) Next() (*Row, error) {
+	for {
+		row, err := s.Reader.Next()
+		if err != nil || row.Index >= s.from {
+			return row, err
+		}
+	}
+}
+
+// Slice returns a Reader yielding rows, for inputs that are not files.
+func Slice(rows []*Row) Reader {
+	return &sliceReader{rows: rows}
+}
+
+type sliceReader struct {
+	rows []*Row
+}
+
+func (s *sliceReader) Next() (*Row, error) {
+	if len(s.rows) == 0 {
+		return nil, io.EOF
+	}
+	r := s.rows[0]
+	s.rows = s.rows[1:]
+	return r, nil
+}
+
+func (s *sliceReader) Close() error { return nil }
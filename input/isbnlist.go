@@ -0,0 +1,75 @@
+package input
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+	"regexp"
+	"strings"
+)
+
+func init() {
+	// Recognized by content alone: a .txt list would otherwise be read
+	// as a one-column CSV without header.
+	Register(Format{Name: "isbns", Sniff: sniffISBNList, New: NewISBNList})
+}
+
+// isbnLine matches a line holding one ISBN, as typed or produced by a
+// barcode scanner.
+var isbnLine = regexp.MustCompile(`^[0-9][0-9Xx\- ]{8,16}$`)
+
+// sniffISBNList matches text whose lines, apart from an optional "ISBN"
+// header, blank lines and # comments, all hold one ISBN.
+func sniffISBNList(head []byte) bool {
+	head = bytes.TrimPrefix(head, []byte("\xef\xbb\xbf"))
+	if len(head) == sniffLen {
+		// The last line may be cut off.
+		if i := bytes.LastIndexByte(head, '\n'); i >= 0 {
+			head = head[:i]
+		}
+	}
+	n := 0
+	for i, line := range strings.Split(string(head), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "#"):
+		case i == 0 && strings.EqualFold(line, "isbn"):
+		case isbnLine.MatchString(line):
+			n++
+		default:
+			return false
+		}
+	}
+	return n > 0
+}
+
+type isbnListReader struct {
+	sc *bufio.Scanner
+	n  int
+}
+
+// NewISBNList returns a Reader over text with one ISBN per line, the
+// output of a USB barcode scanner. Blank lines, # comments and an "ISBN"
+// header are skipped. Rows are returned as soon as their line is read,
+// so r may be a terminal.
+func NewISBNList(r io.Reader, _ Options) (Reader, error) {
+	return &isbnListReader{sc: bufio.NewScanner(r)}, nil
+}
+
+func (l *isbnListReader) Next() (*Row, error) {
+	for l.sc.Scan() {
+		line := strings.TrimSpace(strings.TrimPrefix(l.sc.Text(), "\uFEFF"))
+		if line == "" || strings.HasPrefix(line, "#") || strings.EqualFold(line, "isbn") {
+			continue
+		}
+		row := &Row{Index: l.n, ISBN: line}
+		l.n++
+		return row, nil
+	}
+	if err := l.sc.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+func (l *isbnListReader) Close() error { return nil }
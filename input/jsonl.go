@@ -0,0 +1,56 @@
+package input
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+)
+
+func init() {
+	Register(Format{Name: "jsonl", Extensions: []string{".jsonl", ".ndjson"}, Sniff: sniffJSON, New: newJSONL})
+}
+
+func sniffJSON(head []byte) bool {
+	head = bytes.TrimLeft(head, " \t\r\n\xef\xbb\xbf")
+	return len(head) > 0 && head[0] == '{'
+}
+
+type jsonlReader struct {
+	dec *json.Decoder
+	n   int
+}
+
+func newJSONL(r io.Reader, _ Options) (Reader, error) {
+	return &jsonlReader{dec: json.NewDecoder(r)}, nil
+}
+
+func (j *jsonlReader) Next() (*Row, error) {
+	var rec struct {
+		ISBN      string `json:"isbn"`
+		Title     string `json:"title"`
+		Author    string `json:"author"`
+		Quantity  any    `json:"quantity"`
+		Condition string `json:"condition"`
+		Price     any    `json:"price"`
+		Date      string `json:"date"`
+	}
+	if err := j.dec.Decode(&rec); err != nil {
+		return nil, err
+	}
+	row := &Row{Index: j.n, ISBN: rec.ISBN, Title: rec.Title, Author: rec.Author, Condition: rec.Condition, Date: rec.Date}
+	row.Quantity = scalar(rec.Quantity)
+	row.Price = scalar(rec.Price)
+	j.n++
+	return row, nil
+}
+
+func (j *jsonlReader) Close() error { return nil }
+
+// scalar renders a JSON string or number as text.
+func scalar(v any) string {
+	if v == nil {
+		return ""
+	}
+	b, _ := json.Marshal(v)
+	return string(bytes.Trim(b, `"`))
+}
@@ -0,0 +1,166 @@
+package input
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// headerAliases lists the header names recognized for each Row field,
+// compared case-insensitively with spaces, dashes and underscores removed.
+var headerAliases = map[string][]string{
+	"isbn":      {"isbn", "isbn13", "isbn10", "ean", "isbnean", "upc", "barcode", "gtin"},
+	"title":     {"title", "booktitle", "name"},
+	"author":    {"author", "authors", "writer", "byline"},
+	"quantity":  {"quantity", "qty", "stock", "copies", "count", "qtysold", "quantitysold", "unitssold"},
+	"condition": {"condition", "bookcondition", "cond", "grade"},
+	"price":     {"price", "listprice", "sellingprice", "askingprice"},
+	"date": {"date", "acquired", "dateacquired", "acquisitiondate", "dateadded", "added", "received",
+		"datereceived", "purchasedate", "saledate", "solddate", "dateofsale", "orderdate"},
+}
+
+func normHeader(h string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '_', '.', '\uFEFF':
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(h)))
+}
+
+// columnIndex locates the Row fields in header.
+func columnIndex(header []string, mapping map[string]string) (map[string]int, error) {
+	idx := make(map[string]int)
+	for field, col := range mapping {
+		found := false
+		for i, h := range header {
+			if normHeader(h) == normHeader(col) {
+				idx[field], found = i, true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("column %q for %s not found in header %q", col, field, header)
+		}
+	}
+	for field, aliases := range headerAliases {
+		if _, ok := idx[field]; ok {
+			continue
+		}
+	search:
+		for _, a := range aliases {
+			for i, h := range header {
+				if normHeader(h) == a {
+					idx[field] = i
+					break search
+				}
+			}
+		}
+	}
+	if _, ok := idx["isbn"]; !ok {
+		if _, ok := idx["title"]; !ok {
+			return nil, fmt.Errorf("input has neither an ISBN nor a title column (header: %q)", header)
+		}
+	}
+	return idx, nil
+}
+
+// errEmpty is returned for inputs without even a header row.
+var errEmpty = errors.New("input is empty")
+
+// tableReader adapts a record source with a header row, such as a CSV
+// file or a worksheet, to Reader.
+type tableReader struct {
+	next   func() ([]string, error)
+	close  func() error
+	header []string
+	idx    map[string]int
+	// err is a column mapping problem. It is reported by Next rather
+	// than by the constructor so Header stays usable, e.g. for the setup
+	// wizard to show what the file contains.
+	err error
+	n   int
+}
+
+func newTableReader(next func() ([]string, error), close func() error, opts Options) (*tableReader, error) {
+	header, err := next()
+	if err == io.EOF {
+		return nil, errEmpty
+	}
+	if err != nil {
+		return nil, err
+	}
+	idx, err := columnIndex(header, opts.Columns)
+	return &tableReader{next: next, close: close, header: header, idx: idx, err: err}, nil
+}
+
+// Header implements Tabular.
+func (t *tableReader) Header() []string { return t.header }
+
+// Mapping implements Tabular.
+func (t *tableReader) Mapping() map[string]string {
+	m := make(map[string]string, len(t.idx))
+	for field, i := range t.idx {
+		m[field] = t.header[i]
+	}
+	return m
+}
+
+func (t *tableReader) field(rec []string, name string) string {
+	i, ok := t.idx[name]
+	if !ok || i >= len(rec) {
+		return ""
+	}
+	return strings.TrimSpace(rec[i])
+}
+
+func (t *tableReader) Next() (*Row, error) {
+	if t.err != nil {
+		return nil, t.err
+	}
+	for {
+		rec, err := t.next()
+		if err != nil {
+			return nil, err
+		}
+		row := &Row{
+			ISBN:      t.field(rec, "isbn"),
+			Title:     t.field(rec, "title"),
+			Author:    t.field(rec, "author"),
+			Quantity:  t.field(rec, "quantity"),
+			Condition: t.field(rec, "condition"),
+			Price:     t.field(rec, "price"),
+			Date:      t.field(rec, "date"),
+			Record:    rec,
+		}
+		if row.ISBN == "" && row.Title == "" {
+			continue // blank line
+		}
+		row.Index = t.n
+		t.n++
+		return row, nil
+	}
+}
+
+func (t *tableReader) Close() error {
+	if t.close != nil {
+		return t.close()
+	}
+	return nil
+}
+
+// FromRecords returns a Reader over in-memory records whose first record
+// is the header, e.g. the values of an online spreadsheet.
+func FromRecords(records [][]string, opts Options) (Reader, error) {
+	next := func() ([]string, error) {
+		if len(records) == 0 {
+			return nil, io.EOF
+		}
+		rec := records[0]
+		records = records[1:]
+		return rec, nil
+	}
+	return newTableReader(next, nil, opts)
+}
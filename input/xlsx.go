@@ -0,0 +1,136 @@
+package input
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func init() {
+	Register(Format{Name: "xlsx", Extensions: []string{".xlsx", ".xlsm"}, Sniff: sniffZip, New: newXLSX, Sheets: true})
+}
+
+// sniffZip matches the local file header every OOXML package starts with.
+func sniffZip(head []byte) bool {
+	return bytes.HasPrefix(head, []byte("PK\x03\x04"))
+}
+
+func newXLSX(r io.Reader, opts Options) (Reader, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("open workbook: %w", err)
+	}
+	if opts.multiSheet() {
+		return newSheetsReader(f, opts)
+	}
+	sheet := opts.Sheet
+	if sheet == "" {
+		sheet = f.GetSheetName(0)
+	}
+	tr, err := openSheet(f, sheet, opts)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	closeSheet := tr.close
+	tr.close = func() error {
+		closeSheet()
+		return f.Close()
+	}
+	return tr, nil
+}
+
+// openSheet returns a reader over the rows of a worksheet of f. Closing
+// it leaves f open.
+func openSheet(f *excelize.File, sheet string, opts Options) (*tableReader, error) {
+	rows, err := f.Rows(sheet)
+	if err != nil {
+		return nil, fmt.Errorf("sheet %q: %w", sheet, err)
+	}
+	next := func() ([]string, error) {
+		if !rows.Next() {
+			if err := rows.Error(); err != nil {
+				return nil, err
+			}
+			return nil, io.EOF
+		}
+		return rows.Columns()
+	}
+	tr, err := newTableReader(next, rows.Close, opts)
+	if err != nil {
+		rows.Close()
+		return nil, err
+	}
+	return tr, nil
+}
+
+// sheetsReader reads several worksheets of a workbook in turn, as one
+// input whose rows carry their sheet.
+type sheetsReader struct {
+	f      *excelize.File
+	sheets []string
+	opts   Options
+	cur    *tableReader
+	name   string
+	n      int
+}
+
+func newSheetsReader(f *excelize.File, opts Options) (*sheetsReader, error) {
+	sheets := opts.Sheets
+	if opts.AllSheets {
+		sheets = f.GetSheetList()
+	}
+	for _, s := range sheets {
+		if i, err := f.GetSheetIndex(s); err != nil || i < 0 {
+			f.Close()
+			return nil, fmt.Errorf("workbook has no sheet %q (sheets: %s)", s, strings.Join(f.GetSheetList(), ", "))
+		}
+	}
+	return &sheetsReader{f: f, sheets: sheets, opts: opts}, nil
+}
+
+func (s *sheetsReader) Next() (*Row, error) {
+	for {
+		if s.cur == nil {
+			if len(s.sheets) == 0 {
+				return nil, io.EOF
+			}
+			s.name, s.sheets = s.sheets[0], s.sheets[1:]
+			tr, err := openSheet(s.f, s.name, s.opts)
+			if err != nil {
+				if errors.Is(err, errEmpty) {
+					continue
+				}
+				return nil, err
+			}
+			if tr.err != nil && s.opts.AllSheets {
+				tr.Close()
+				continue
+			}
+			s.cur = tr
+		}
+		row, err := s.cur.Next()
+		if err == io.EOF {
+			s.cur.Close()
+			s.cur = nil
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sheet %q: %w", s.name, err)
+		}
+		row.Sheet, row.SheetIndex, row.Index = s.name, row.Index, s.n
+		s.n++
+		return row, nil
+	}
+}
+
+func (s *sheetsReader) Close() error {
+	if s.cur != nil {
+		s.cur.Close()
+	}
+	return s.f.Close()
+}
@@ -0,0 +1,116 @@
+// Package isbn normalizes, validates and converts ISBNs.
+package isbn
+
+import "strings"
+
+// Normalize strips separators and whitespace from s and upper-cases a
+// trailing X check digit. It does not validate the result.
+func Normalize(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == 'x' || r == 'X':
+			b.WriteByte('X')
+		}
+	}
+	return b.String()
+}
+
+// Valid reports whether s is a valid ISBN-10 or ISBN-13 once normalized.
+func Valid(s string) bool {
+	s = Normalize(s)
+	switch len(s) {
+	case 10:
+		return Valid10(s)
+	case 13:
+		return Valid13(s)
+	}
+	return false
+}
+
+// Valid10 reports whether s is a normalized ISBN-10 with a correct check
+// digit.
+func Valid10(s string) bool {
+	if len(s) != 10 {
+		return false
+	}
+	sum := 0
+	for i := 0; i < 10; i++ {
+		c := s[i]
+		var d int
+		switch {
+		case c >= '0' && c <= '9':
+			d = int(c - '0')
+		case c == 'X' && i == 9:
+			d = 10
+		default:
+			return false
+		}
+		sum += d * (10 - i)
+	}
+	return sum%11 == 0
+}
+
+// Valid13 reports whether s is a normalized ISBN-13 with a correct check
+// digit.
+func Valid13(s string) bool {
+	if len(s) != 13 {
+		return false
+	}
+	for i := 0; i < 13; i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return check13(s[:12]) == s[12]
+}
+
+func check13(s string) byte {
+	sum := 0
+	for i := 0; i < 12; i++ {
+		d := int(s[i] - '0')
+		if i%2 == 1 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// To13 converts an ISBN-10 to its ISBN-13 form. ISBN-13 input is returned
+// normalized; anything else is returned unchanged.
+func To13(s string) string {
+	n := Normalize(s)
+	switch len(n) {
+	case 13:
+		return n
+	case 10:
+		body := "978" + n[:9]
+		return body + string(check13(body))
+	}
+	return s
+}
+
+// To10 converts a 978-prefixed ISBN-13 to its ISBN-10 form. It returns ""
+// when no ISBN-10 exists (979 prefixes) or the input is malformed.
+func To10(s string) string {
+	n := Normalize(s)
+	if len(n) == 10 {
+		return n
+	}
+	if len(n) != 13 || !strings.HasPrefix(n, "978") {
+		return ""
+	}
+	body := n[3:12]
+	sum := 0
+	for i := 0; i < 9; i++ {
+		sum += int(body[i]-'0') * (10 - i)
+	}
+	c := (11 - sum%11) % 11
+	if c == 10 {
+		return body + "X"
+	}
+	return body + string(byte('0'+c))
+}
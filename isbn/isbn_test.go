@@ -0,0 +1,68 @@
+package isbn_test
+
+import (
+	"testing"
+
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+)
+
+func TestNormalize(t *testing.T) {
+	for in, want := range map[string]string{
+		"978-0-306-40615-7":  "9780306406157",
+		" 0 306 40615 2 ":    "0306406152",
+		"0-8044-2957-x":      "080442957X",
+		"ISBN 0-8044-2957-X": "080442957X",
+		"":                   "",
+		"n/a":                "",
+	} {
+		if got := isbn.Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want bool
+	}{
+		{"9780306406157", true},
+		{"978-0-306-40615-7", true},
+		{"0306406152", true},
+		{"080442957X", true},
+		{"080442957x", true},
+		{"9791032305690", true},
+		{"9780306406158", false}, // check digit
+		{"0306406153", false},    // check digit
+		{"X804429570", false},    // X only as the check digit
+		{"978030640615", false},  // length
+		{"97803064061577", false},
+		{"", false},
+	} {
+		if got := isbn.Valid(tc.in); got != tc.want {
+			t.Errorf("Valid(%q) = %v, want %v", tc.in, got, tc.want)
+		}
+	}
+}
+
+func TestConvert(t *testing.T) {
+	for _, tc := range []struct {
+		in, to13, to10 string
+	}{
+		{"0306406152", "9780306406157", "0306406152"},
+		{"9780306406157", "9780306406157", "0306406152"},
+		{"0-8044-2957-X", "9780804429573", "080442957X"},
+		{"9780804429573", "9780804429573", "080442957X"},
+		// 979 ISBNs have no ISBN-10.
+		{"979-10-323-0569-0", "9791032305690", ""},
+		// Malformed input is left to the caller.
+		{"12345", "12345", ""},
+	} {
+		if got := isbn.To13(tc.in); got != tc.to13 {
+			t.Errorf("To13(%q) = %q, want %q", tc.in, got, tc.to13)
+		}
+		if got := isbn.To10(tc.in); got != tc.to10 {
+			t.Errorf("To10(%q) = %q, want %q", tc.in, got, tc.to10)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// Package jobs records the enrichment jobs run by the server: who ran
+// what, when, with which settings and with what outcome.
+package jobs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned for an unknown job ID.
+var ErrNotFound = errors.New("job not found")
+
+// Status is the state of a job.
+type Status string
+
+const (
+	Running Status = "running"
+	Done    Status = "done"
+	Failed  Status = "failed"
+)
+
+// Job is one entry of the job history.
+type Job struct {
+	ID   int64  `json:"id"`
+	User string `json:"user,omitempty"`
+	// Kind is the operation, e.g. "enrich" or "lookup".
+	Kind string `json:"kind"`
+	// Input names what was enriched: an uploaded file name or an ISBN.
+	Input string `json:"input,omitempty"`
+	// Config is the effective configuration of the job, as JSON.
+	Config   string    `json:"config,omitempty"`
+	Status   Status    `json:"status"`
+	Rows     int       `json:"rows"`
+	Failed   int       `json:"failed"`
+	Error    string    `json:"error,omitempty"`
+	Started  time.Time `json:"started"`
+	Finished time.Time `json:"finished,omitzero"`
+}
+
+// Filter selects jobs from the history. Zero fields match everything.
+type Filter struct {
+	User   string
+	Kind   string
+	Status Status
+	Since  time.Time
+	Until  time.Time
+	// Limit caps the number of jobs returned, newest first.
+	Limit int
+}
+
+// Match reports whether j passes f.
+func (f *Filter) Match(j *Job) bool {
+	return (f.User == "" || j.User == f.User) &&
+		(f.Kind == "" || j.Kind == f.Kind) &&
+		(f.Status == "" || j.Status == f.Status) &&
+		(f.Since.IsZero() || !j.Started.Before(f.Since)) &&
+		(f.Until.IsZero() || j.Started.Before(f.Until))
+}
+
+// Store persists the job history.
+type Store interface {
+	// Create records a new job and sets its ID.
+	Create(ctx context.Context, j *Job) error
+	// Update saves the status and results of an existing job.
+	Update(ctx context.Context, j *Job) error
+	// Get returns the job with the given ID.
+	Get(ctx context.Context, id int64) (*Job, error)
+	// List returns the jobs matching f, newest first.
+	List(ctx context.Context, f Filter) ([]*Job, error)
+	Close() error
+}
+
+// MemoryStore keeps the history in memory; it is lost on restart.
+type MemoryStore struct {
+	mu   sync.Mutex
+	jobs []*Job
+}
+
+// Create implements Store.
+func (s *MemoryStore) Create(_ context.Context, j *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	j.ID = int64(len(s.jobs) + 1)
+	c := *j
+	s.jobs = append(s.jobs, &c)
+	return nil
+}
+
+// Update implements Store.
+func (s *MemoryStore) Update(_ context.Context, j *Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if j.ID < 1 || j.ID > int64(len(s.jobs)) {
+		return ErrNotFound
+	}
+	c := *j
+	s.jobs[j.ID-1] = &c
+	return nil
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, id int64) (*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if id < 1 || id > int64(len(s.jobs)) {
+		return nil, ErrNotFound
+	}
+	c := *s.jobs[id-1]
+	return &c, nil
+}
+
+// List implements Store.
+func (s *MemoryStore) List(_ context.Context, f Filter) ([]*Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []*Job
+	for _, j := range slices.Backward(s.jobs) {
+		if f.Limit > 0 && len(out) == f.Limit {
+			break
+		}
+		if f.Match(j) {
+			c := *j
+			out = append(out, &c)
+		}
+	}
+	return out, nil
+}
+
+// Close implements Store.
+func (s *MemoryStore) Close() error { return nil }
@@ -0,0 +1,164 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const schema = `CREATE TABLE IF NOT EXISTS jobs (
+	id       INTEGER PRIMARY KEY AUTOINCREMENT,
+	user     TEXT NOT NULL DEFAULT '',
+	kind     TEXT NOT NULL,
+	input    TEXT NOT NULL DEFAULT '',
+	config   TEXT NOT NULL DEFAULT '',
+	status   TEXT NOT NULL,
+	rows     INTEGER NOT NULL DEFAULT 0,
+	failed   INTEGER NOT NULL DEFAULT 0,
+	error    TEXT NOT NULL DEFAULT '',
+	started  TEXT NOT NULL,
+	finished TEXT NOT NULL DEFAULT ''
+)`
+
+// SQLStore keeps the history in an SQLite database through database/sql.
+// The driver must be linked into the binary; booktool includes one when
+// built with -tags sqlite.
+type SQLStore struct {
+	db *sql.DB
+}
+
+// OpenSQL opens the database at dsn with the named driver and creates
+// the jobs table if needed.
+func OpenSQL(driver, dsn string) (*SQLStore, error) {
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("jobs: %w", err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("jobs: create table: %w", err)
+	}
+	return &SQLStore{db: db}, nil
+}
+
+// Times are stored as RFC 3339 text in UTC, which sorts chronologically.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func parseTime(s string) time.Time {
+	t, _ := time.Parse(time.RFC3339Nano, s)
+	return t
+}
+
+// Create implements Store.
+func (s *SQLStore) Create(ctx context.Context, j *Job) error {
+	res, err := s.db.ExecContext(ctx,
+		`INSERT INTO jobs (user, kind, input, config, status, rows, failed, error, started, finished)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		j.User, j.Kind, j.Input, j.Config, string(j.Status), j.Rows, j.Failed, j.Error,
+		formatTime(j.Started), formatTime(j.Finished))
+	if err != nil {
+		return fmt.Errorf("jobs: %w", err)
+	}
+	j.ID, err = res.LastInsertId()
+	return err
+}
+
+// Update implements Store.
+func (s *SQLStore) Update(ctx context.Context, j *Job) error {
+	res, err := s.db.ExecContext(ctx,
+		`UPDATE jobs SET status = ?, rows = ?, failed = ?, error = ?, finished = ? WHERE id = ?`,
+		string(j.Status), j.Rows, j.Failed, j.Error, formatTime(j.Finished), j.ID)
+	if err != nil {
+		return fmt.Errorf("jobs: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+const columns = `id, user, kind, input, config, status, rows, failed, error, started, finished`
+
+func scan(row interface{ Scan(...any) error }) (*Job, error) {
+	var j Job
+	var status, started, finished string
+	if err := row.Scan(&j.ID, &j.User, &j.Kind, &j.Input, &j.Config, &status,
+		&j.Rows, &j.Failed, &j.Error, &started, &finished); err != nil {
+		return nil, err
+	}
+	j.Status, j.Started, j.Finished = Status(status), parseTime(started), parseTime(finished)
+	return &j, nil
+}
+
+// Get implements Store.
+func (s *SQLStore) Get(ctx context.Context, id int64) (*Job, error) {
+	j, err := scan(s.db.QueryRowContext(ctx, `SELECT `+columns+` FROM jobs WHERE id = ?`, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("jobs: %w", err)
+	}
+	return j, nil
+}
+
+// List implements Store.
+func (s *SQLStore) List(ctx context.Context, f Filter) ([]*Job, error) {
+	var where []string
+	var args []any
+	add := func(cond string, v any) {
+		where = append(where, cond)
+		args = append(args, v)
+	}
+	if f.User != "" {
+		add("user = ?", f.User)
+	}
+	if f.Kind != "" {
+		add("kind = ?", f.Kind)
+	}
+	if f.Status != "" {
+		add("status = ?", string(f.Status))
+	}
+	if !f.Since.IsZero() {
+		add("started >= ?", formatTime(f.Since))
+	}
+	if !f.Until.IsZero() {
+		add("started < ?", formatTime(f.Until))
+	}
+	q := `SELECT ` + columns + ` FROM jobs`
+	if len(where) > 0 {
+		q += " WHERE " + strings.Join(where, " AND ")
+	}
+	q += " ORDER BY id DESC"
+	if f.Limit > 0 {
+		q += fmt.Sprintf(" LIMIT %d", f.Limit)
+	}
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return nil, fmt.Errorf("jobs: %w", err)
+	}
+	defer rows.Close()
+	var out []*Job
+	for rows.Next() {
+		j, err := scan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("jobs: %w", err)
+		}
+		out = append(out, j)
+	}
+	return out, rows.Err()
+}
+
+// Ping checks the database connection, for readiness probes.
+func (s *SQLStore) Ping(ctx context.Context) error { return s.db.PingContext(ctx) }
+
+// Close implements Store.
+func (s *SQLStore) Close() error { return s.db.Close() }
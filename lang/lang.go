@@ -0,0 +1,231 @@
+// Package lang normalizes the language codes reported by providers.
+// OpenLibrary uses ISO 639-2 references ("/languages/eng"), Google Books
+// uses ISO 639-1 ("en"); both are mapped to a common entry so output can
+// show either a consistent code or an English language name.
+package lang
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/datatable"
+)
+
+// TableVersion is the date the language table was last refreshed.
+const TableVersion = "2025-03-01"
+
+// Language is one entry of the ISO 639 table.
+type Language struct {
+	Alpha2 string // ISO 639-1, e.g. "de"
+	Alpha3 string // ISO 639-2/B as used by library catalogs, e.g. "ger"
+	Name   string // English name, e.g. "German"
+}
+
+// table lists ISO 639-2/B codes first; terminology (/T) variants are
+// added to the index as aliases.
+var table = []struct {
+	Language
+	alias string
+}{
+	{Language{"af", "afr", "Afrikaans"}, ""},
+	{Language{"sq", "alb", "Albanian"}, "sqi"},
+	{Language{"ar", "ara", "Arabic"}, ""},
+	{Language{"hy", "arm", "Armenian"}, "hye"},
+	{Language{"eu", "baq", "Basque"}, "eus"},
+	{Language{"be", "bel", "Belarusian"}, ""},
+	{Language{"bn", "ben", "Bengali"}, ""},
+	{Language{"bs", "bos", "Bosnian"}, ""},
+	{Language{"bg", "bul", "Bulgarian"}, ""},
+	{Language{"my", "bur", "Burmese"}, "mya"},
+	{Language{"ca", "cat", "Catalan"}, ""},
+	{Language{"zh", "chi", "Chinese"}, "zho"},
+	{Language{"hr", "hrv", "Croatian"}, ""},
+	{Language{"cs", "cze", "Czech"}, "ces"},
+	{Language{"da", "dan", "Danish"}, ""},
+	{Language{"nl", "dut", "Dutch"}, "nld"},
+	{Language{"en", "eng", "English"}, ""},
+	{Language{"eo", "epo", "Esperanto"}, ""},
+	{Language{"et", "est", "Estonian"}, ""},
+	{Language{"fi", "fin", "Finnish"}, ""},
+	{Language{"fr", "fre", "French"}, "fra"},
+	{Language{"gl", "glg", "Galician"}, ""},
+	{Language{"ka", "geo", "Georgian"}, "kat"},
+	{Language{"de", "ger", "German"}, "deu"},
+	{Language{"el", "gre", "Greek"}, "ell"},
+	{Language{"he", "heb", "Hebrew"}, ""},
+	{Language{"hi", "hin", "Hindi"}, ""},
+	{Language{"hu", "hun", "Hungarian"}, ""},
+	{Language{"is", "ice", "Icelandic"}, "isl"},
+	{Language{"id", "ind", "Indonesian"}, ""},
+	{Language{"ga", "gle", "Irish"}, ""},
+	{Language{"it", "ita", "Italian"}, ""},
+	{Language{"ja", "jpn", "Japanese"}, ""},
+	{Language{"kk", "kaz", "Kazakh"}, ""},
+	{Language{"ko", "kor", "Korean"}, ""},
+	{Language{"ku", "kur", "Kurdish"}, ""},
+	{Language{"la", "lat", "Latin"}, ""},
+	{Language{"lv", "lav", "Latvian"}, ""},
+	{Language{"lt", "lit", "Lithuanian"}, ""},
+	{Language{"mk", "mac", "Macedonian"}, "mkd"},
+	{Language{"ms", "may", "Malay"}, "msa"},
+	{Language{"mt", "mlt", "Maltese"}, ""},
+	{Language{"no", "nor", "Norwegian"}, ""},
+	{Language{"fa", "per", "Persian"}, "fas"},
+	{Language{"pl", "pol", "Polish"}, ""},
+	{Language{"pt", "por", "Portuguese"}, ""},
+	{Language{"pa", "pan", "Punjabi"}, ""},
+	{Language{"ro", "rum", "Romanian"}, "ron"},
+	{Language{"ru", "rus", "Russian"}, ""},
+	{Language{"sa", "san", "Sanskrit"}, ""},
+	{Language{"sr", "srp", "Serbian"}, ""},
+	{Language{"sk", "slo", "Slovak"}, "slk"},
+	{Language{"sl", "slv", "Slovenian"}, ""},
+	{Language{"es", "spa", "Spanish"}, ""},
+	{Language{"sw", "swa", "Swahili"}, ""},
+	{Language{"sv", "swe", "Swedish"}, ""},
+	{Language{"tl", "tgl", "Tagalog"}, ""},
+	{Language{"ta", "tam", "Tamil"}, ""},
+	{Language{"th", "tha", "Thai"}, ""},
+	{Language{"bo", "tib", "Tibetan"}, "bod"},
+	{Language{"tr", "tur", "Turkish"}, ""},
+	{Language{"uk", "ukr", "Ukrainian"}, ""},
+	{Language{"ur", "urd", "Urdu"}, ""},
+	{Language{"uz", "uzb", "Uzbek"}, ""},
+	{Language{"vi", "vie", "Vietnamese"}, ""},
+	{Language{"cy", "wel", "Welsh"}, "cym"},
+	{Language{"yi", "yid", "Yiddish"}, ""},
+}
+
+var index = make(map[string]*Language)
+
+func init() {
+	datatable.Register(datatable.Table{
+		Name:    "languages",
+		Summary: "ISO 639 language codes and names",
+		Version: TableVersion,
+		Export:  exportTable,
+		Load:    loadTable,
+	})
+	for i := range table {
+		add(&table[i].Language, table[i].alias)
+	}
+}
+
+func add(l *Language, alias string) {
+	index[l.Alpha2] = l
+	index[l.Alpha3] = l
+	index[strings.ToLower(l.Name)] = l
+	if alias != "" {
+		index[alias] = l
+	}
+}
+
+// fileEntry is one language in the published table file.
+type fileEntry struct {
+	Alpha2 string `json:"alpha2,omitempty"`
+	Alpha3 string `json:"alpha3"`
+	Name   string `json:"name"`
+	Alias  string `json:"alias,omitempty"`
+}
+
+func exportTable() ([]byte, error) {
+	entries := make([]fileEntry, len(table))
+	for i, e := range table {
+		entries[i] = fileEntry{e.Alpha2, e.Alpha3, e.Name, e.alias}
+	}
+	return json.MarshalIndent(entries, "", "  ")
+}
+
+// loadTable replaces the built-in table with a downloaded one.
+func loadTable(data []byte) error {
+	var entries []fileEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return errors.New("no languages")
+	}
+	index = make(map[string]*Language)
+	for _, e := range entries {
+		if e.Alpha3 == "" || e.Name == "" {
+			return fmt.Errorf("language %q: missing alpha3 code or name", e.Alpha2)
+		}
+		add(&Language{Alpha2: e.Alpha2, Alpha3: e.Alpha3, Name: e.Name}, e.Alias)
+	}
+	return nil
+}
+
+// Lookup resolves a language code in any of the forms providers use:
+// "en", "eng", "deu", "/languages/eng", "en-GB" or an English name.
+func Lookup(code string) (Language, bool) {
+	c := strings.ToLower(strings.TrimSpace(code))
+	c = c[strings.LastIndex(c, "/")+1:]
+	if i := strings.IndexAny(c, "-_"); i > 0 {
+		c = c[:i]
+	}
+	if l, ok := index[c]; ok {
+		return *l, true
+	}
+	return Language{}, false
+}
+
+// Mode selects how languages are written to output.
+type Mode string
+
+const (
+	// Name writes English names such as "English".
+	Name Mode = "name"
+	// Code writes ISO 639-2/B codes such as "eng", as used by MARC.
+	Code Mode = "code"
+	// Code2 writes ISO 639-1 codes such as "en".
+	Code2 Mode = "code2"
+)
+
+// ParseMode parses a -language-format flag value. The empty string
+// selects Name.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(s)); m {
+	case "":
+		return Name, nil
+	case Name, Code, Code2:
+		return m, nil
+	}
+	return "", fmt.Errorf("unknown language format %q (want name, code or code2)", s)
+}
+
+// Format renders one language code in mode m. Unknown codes are passed
+// through unchanged so no information is lost.
+func (m Mode) Format(code string) string {
+	l, ok := Lookup(code)
+	if !ok {
+		return code
+	}
+	switch m {
+	case Code:
+		return l.Alpha3
+	case Code2:
+		if l.Alpha2 != "" {
+			return l.Alpha2
+		}
+		return l.Alpha3
+	}
+	return l.Name
+}
+
+// FormatAll renders codes in mode m, dropping duplicates that result
+// from providers reporting the same language in different forms.
+func (m Mode) FormatAll(codes []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, c := range codes {
+		v := m.Format(c)
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		out = append(out, v)
+	}
+	return out
+}
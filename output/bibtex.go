@@ -0,0 +1,122 @@
+package output
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/SouadAli10/book_scrapping_tool/author"
+	"github.com/SouadAli10/book_scrapping_tool/book"
+)
+
+func init() {
+	Register(Format{Name: "bibtex", Extensions: []string{".bib"}, New: NewBibTeX})
+}
+
+type bibtexWriter struct {
+	w *bufio.Writer
+	// keys counts the citation keys used so far, to make them unique.
+	keys map[string]int
+}
+
+// NewBibTeX returns a Writer producing one @book entry per enriched
+// record, for LaTeX and reference managers such as Zotero. Rows without
+// a result are written as comments.
+func NewBibTeX(w io.Writer) (Writer, error) {
+	return &bibtexWriter{w: bufio.NewWriter(w), keys: make(map[string]int)}, nil
+}
+
+func (b *bibtexWriter) WriteHeader([]string) error { return nil }
+
+func (b *bibtexWriter) Write(r *Record) error {
+	if r.Book == nil {
+		msg := "no result"
+		if r.Err != nil {
+			msg = strings.Join(strings.Fields(r.Err.Error()), " ")
+		}
+		_, err := fmt.Fprintf(b.w, "%% row %d: %s\n\n", r.Index+2, msg)
+		return err
+	}
+	bk := r.Book
+	year := bk.Year()
+	fmt.Fprintf(b.w, "@book{%s,\n", b.key(bk, year))
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(b.w, "  %s = {%s},\n", name, bibEscape(value))
+		}
+	}
+	field("author", strings.Join(bk.Authors, " and "))
+	field("title", bk.FullTitle())
+	field("publisher", first(bk.Publishers))
+	field("address", first(bk.PublishPlaces))
+	field("year", year)
+	field("isbn", bk.ISBN())
+	if bk.Series != "" {
+		field("series", bk.Series)
+		field("number", bk.SeriesPosition)
+	}
+	_, err := b.w.WriteString("}\n\n")
+	return err
+}
+
+func (b *bibtexWriter) Close() error { return b.w.Flush() }
+
+// key builds a citation key such as "tolkien1937hobbit", adding a
+// letter suffix to repeats.
+func (b *bibtexWriter) key(bk *book.BookInfo, year string) string {
+	var last string
+	if len(bk.Authors) > 0 {
+		last = author.Surname(bk.Authors[0])
+	}
+	var word string
+	for _, w := range strings.Fields(bk.Title) {
+		if w = keyPart(w); w != "" && !keyStopWords[w] {
+			word = w
+			break
+		}
+	}
+	k := keyPart(last) + year + word
+	if k == "" {
+		k = "isbn" + bk.ISBN()
+	}
+	n := b.keys[k]
+	b.keys[k]++
+	if n > 0 {
+		k += string(rune('a' + (n-1)%26))
+	}
+	return k
+}
+
+var keyStopWords = map[string]bool{"the": true, "a": true, "an": true, "of": true}
+
+// keyPart keeps the lower-case ASCII letters and digits of s.
+func keyPart(s string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if r < 128 && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+var bibEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	`{`, `\{`, `}`, `\}`,
+	`&`, `\&`, `%`, `\%`, `$`, `\$`, `#`, `\#`, `_`, `\_`,
+	`~`, `\textasciitilde{}`, `^`, `\textasciicircum{}`,
+)
+
+// bibEscape escapes the characters LaTeX treats specially.
+func bibEscape(s string) string {
+	return bibEscaper.Replace(strings.Join(strings.Fields(s), " "))
+}
+
+func first(s []string) string {
+	if len(s) > 0 {
+		return s[0]
+	}
+	return ""
+}
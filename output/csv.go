@@ -0,0 +1,40 @@
+package output
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+func init() {
+	Register(Format{Name: "csv", Extensions: []string{".csv"}, New: NewCSV})
+	Register(Format{Name: "tsv", Extensions: []string{".tsv", ".tab"}, New: NewTSV})
+}
+
+type csvWriter struct {
+	w *csv.Writer
+}
+
+// NewCSV returns a Writer producing comma-separated values.
+func NewCSV(w io.Writer) (Writer, error) {
+	return &csvWriter{w: csv.NewWriter(w)}, nil
+}
+
+// NewTSV returns a Writer producing tab-separated values.
+func NewTSV(w io.Writer) (Writer, error) {
+	cw := csv.NewWriter(w)
+	cw.Comma = '\t'
+	return &csvWriter{w: cw}, nil
+}
+
+func (c *csvWriter) WriteHeader(columns []string) error {
+	return c.w.Write(columns)
+}
+
+func (c *csvWriter) Write(r *Record) error {
+	return c.w.Write(r.Cells)
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
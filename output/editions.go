@@ -0,0 +1,82 @@
+package output
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// EditionsHeader is the header of the Editions sheet and files, which
+// list the other editions of each book's work, one per row. Row and
+// ISBN name the input row and the book it is an edition of.
+var EditionsHeader = []string{"Row", "ISBN", "Edition ISBN", "Edition", "Title", "Publisher", "Publish Date", "Binding", "Pages", "Languages", "International"}
+
+// EditionRows returns the rows listing the other editions of r's book,
+// aligned with EditionsHeader, or nil when it has none.
+func EditionRows(r *Record) [][]string {
+	if r.Book == nil {
+		return nil
+	}
+	rows := make([][]string, 0, len(r.Book.Siblings))
+	for _, s := range r.Book.Siblings {
+		pages, intl := "", ""
+		if s.Pages > 0 {
+			pages = strconv.Itoa(s.Pages)
+		}
+		if s.International {
+			intl = "Yes"
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(r.row()), r.Book.ISBN(), s.ISBN13, s.Label, s.Title, s.Publisher,
+			s.PublishDate, s.Binding, pages, strings.Join(s.Languages, ", "), intl,
+		})
+	}
+	return rows
+}
+
+// row returns the spreadsheet row number of r in its input, counting
+// the header row.
+func (r *Record) row() int {
+	if r.Sheet != "" {
+		return r.Line
+	}
+	return r.Index + 2
+}
+
+type editionsWriter struct {
+	main, editions Writer
+}
+
+// SplitEditions returns a Writer that writes records to main and the
+// other editions of those marked with Editions to editions, for formats
+// that cannot hold them in a sheet of their own. editions gets
+// EditionsHeader.
+func SplitEditions(main, editions Writer) Writer {
+	return editionsWriter{main, editions}
+}
+
+func (s editionsWriter) WriteHeader(columns []string) error {
+	if err := s.main.WriteHeader(columns); err != nil {
+		return err
+	}
+	return s.editions.WriteHeader(EditionsHeader)
+}
+
+func (s editionsWriter) Write(r *Record) error {
+	if err := s.main.Write(r); err != nil {
+		return err
+	}
+	if !r.Editions {
+		return nil
+	}
+	for _, row := range EditionRows(r) {
+		if err := s.editions.Write(&Record{Index: r.Index, Cells: row}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s editionsWriter) Close() error {
+	return errors.Join(s.main.Close(), s.editions.Close())
+}
@@ -0,0 +1,41 @@
+package output
+
+import (
+	"encoding/json"
+	"io"
+)
+
+func init() {
+	Register(Format{Name: "jsonl", Extensions: []string{".jsonl", ".ndjson"}, New: NewJSONL})
+}
+
+type jsonlWriter struct {
+	enc     *json.Encoder
+	columns []string
+}
+
+// NewJSONL returns a Writer producing one JSON object per record, keyed
+// by column header.
+func NewJSONL(w io.Writer) (Writer, error) {
+	return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+}
+
+func (j *jsonlWriter) WriteHeader(columns []string) error {
+	j.columns = columns
+	return nil
+}
+
+func (j *jsonlWriter) Write(r *Record) error {
+	obj := make(map[string]string, len(r.Cells))
+	for i, v := range r.Cells {
+		if i < len(j.columns) {
+			obj[j.columns[i]] = v
+		}
+	}
+	if r.Err != nil {
+		obj["error"] = r.Err.Error()
+	}
+	return j.enc.Encode(obj)
+}
+
+func (j *jsonlWriter) Close() error { return nil }
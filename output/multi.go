@@ -0,0 +1,65 @@
+package output
+
+import "errors"
+
+type multiWriter []Writer
+
+// Multi returns a Writer that writes every record to each of ws.
+func Multi(ws ...Writer) Writer {
+	return multiWriter(ws)
+}
+
+func (m multiWriter) WriteHeader(columns []string) error {
+	for _, w := range m {
+		if err := w.WriteHeader(columns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multiWriter) Write(r *Record) error {
+	for _, w := range m {
+		if err := w.Write(r); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m multiWriter) Close() error {
+	var errs []error
+	for _, w := range m {
+		errs = append(errs, w.Close())
+	}
+	return errors.Join(errs...)
+}
+
+type splitWriter struct {
+	main, review Writer
+}
+
+// Split returns a Writer that writes records marked for review to
+// review and the others to main, for formats that cannot hold them
+// apart themselves. Both get the header.
+func Split(main, review Writer) Writer {
+	return splitWriter{main, review}
+}
+
+func (s splitWriter) WriteHeader(columns []string) error {
+	if err := s.main.WriteHeader(columns); err != nil {
+		return err
+	}
+	return s.review.WriteHeader(columns)
+}
+
+func (s splitWriter) Write(r *Record) error {
+	if r.Review {
+		return s.review.Write(r)
+	}
+	return s.main.Write(r)
+}
+
+func (s splitWriter) Close() error {
+	return errors.Join(s.main.Close(), s.review.Close())
+}
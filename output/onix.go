@@ -0,0 +1,334 @@
+package output
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/author"
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/lang"
+)
+
+func init() {
+	Register(Format{Name: "onix", Extensions: []string{".onix"}, New: NewONIX})
+}
+
+// ONIXSender is the sender name written to the ONIX message header.
+var ONIXSender = "booktool"
+
+type onixWriter struct {
+	w   *bufio.Writer
+	enc *xml.Encoder
+}
+
+// NewONIX returns a Writer producing an ONIX for Books 3.0 message
+// (reference tags) with one Product per enriched record, as required by
+// distributors and retailers. Rows without a result or an ISBN are
+// written as comments.
+func NewONIX(w io.Writer) (Writer, error) {
+	bw := bufio.NewWriter(w)
+	enc := xml.NewEncoder(bw)
+	enc.Indent("", "  ")
+	return &onixWriter{w: bw, enc: enc}, nil
+}
+
+func (o *onixWriter) WriteHeader([]string) error {
+	o.w.WriteString(xml.Header)
+	o.w.WriteString(`<ONIXMessage release="3.0" xmlns="http://ns.editeur.org/onix/3.0/reference">` + "\n")
+	h := onixHeader{SenderName: ONIXSender, SentDateTime: time.Now().UTC().Format("20060102T1504Z")}
+	return o.enc.Encode(h)
+}
+
+func (o *onixWriter) Write(r *Record) error {
+	if r.Book == nil || r.Book.ISBN() == "" {
+		msg := "no ISBN"
+		switch {
+		case r.Err != nil:
+			msg = r.Err.Error()
+		case r.Book == nil:
+			msg = "no result"
+		}
+		return o.comment(fmt.Sprintf("row %d: %s", r.Index+2, msg))
+	}
+	return o.enc.Encode(onixProduct(r.Book))
+}
+
+func (o *onixWriter) comment(s string) error {
+	// "--" may not appear inside an XML comment.
+	s = strings.ReplaceAll(strings.Join(strings.Fields(s), " "), "--", "- -")
+	return o.enc.EncodeToken(xml.Comment(" " + s + " "))
+}
+
+func (o *onixWriter) Close() error {
+	if err := o.enc.Flush(); err != nil {
+		return err
+	}
+	o.w.WriteString("\n</ONIXMessage>\n")
+	return o.w.Flush()
+}
+
+type onixHeader struct {
+	XMLName      xml.Name `xml:"Header"`
+	SenderName   string   `xml:"Sender>SenderName"`
+	SentDateTime string   `xml:"SentDateTime"`
+}
+
+// The types below follow the ONIX 3.0 reference tag names; the element
+// order matters, as the schema defines sequences. Code values refer to
+// the ONIX code lists.
+
+type product struct {
+	XMLName           xml.Name            `xml:"Product"`
+	RecordReference   string              `xml:"RecordReference"`
+	NotificationType  string              `xml:"NotificationType"`
+	Identifiers       []productIdentifier `xml:"ProductIdentifier"`
+	DescriptiveDetail descriptiveDetail   `xml:"DescriptiveDetail"`
+	CollateralDetail  *collateralDetail   `xml:"CollateralDetail"`
+	PublishingDetail  *publishingDetail   `xml:"PublishingDetail"`
+}
+
+type productIdentifier struct {
+	Type  string `xml:"ProductIDType"`
+	Value string `xml:"IDValue"`
+}
+
+type descriptiveDetail struct {
+	ProductComposition string        `xml:"ProductComposition"`
+	ProductForm        string        `xml:"ProductForm"`
+	Measures           []measure     `xml:"Measure"`
+	Collection         *collection   `xml:"Collection"`
+	TitleDetail        titleDetail   `xml:"TitleDetail"`
+	Contributors       []contributor `xml:"Contributor"`
+	NoContributor      *struct{}     `xml:"NoContributor"`
+	Languages          []language    `xml:"Language"`
+	Extent             *extent       `xml:"Extent"`
+	Subjects           []subject     `xml:"Subject"`
+}
+
+type measure struct {
+	Type  string `xml:"MeasureType"`
+	Value string `xml:"Measurement"`
+	Unit  string `xml:"MeasureUnitCode"`
+}
+
+type collection struct {
+	Type        string      `xml:"CollectionType"`
+	TitleDetail titleDetail `xml:"TitleDetail"`
+}
+
+type titleDetail struct {
+	Type    string       `xml:"TitleType"`
+	Element titleElement `xml:"TitleElement"`
+}
+
+type titleElement struct {
+	Level      string `xml:"TitleElementLevel"`
+	PartNumber string `xml:"PartNumber,omitempty"`
+	Text       string `xml:"TitleText"`
+	Subtitle   string `xml:"Subtitle,omitempty"`
+}
+
+type contributor struct {
+	Sequence int    `xml:"SequenceNumber"`
+	Role     string `xml:"ContributorRole"`
+	Name     string `xml:"PersonName,omitempty"`
+	Inverted string `xml:"PersonNameInverted,omitempty"`
+}
+
+type language struct {
+	Role string `xml:"LanguageRole"`
+	Code string `xml:"LanguageCode"`
+}
+
+type extent struct {
+	Type  string `xml:"ExtentType"`
+	Value int    `xml:"ExtentValue"`
+	Unit  string `xml:"ExtentUnit"`
+}
+
+type subject struct {
+	Main    *struct{} `xml:"MainSubject"`
+	Scheme  string    `xml:"SubjectSchemeIdentifier"`
+	Code    string    `xml:"SubjectCode,omitempty"`
+	Heading string    `xml:"SubjectHeadingText,omitempty"`
+}
+
+type collateralDetail struct {
+	Text     *textContent        `xml:"TextContent"`
+	Resource *supportingResource `xml:"SupportingResource"`
+}
+
+type textContent struct {
+	Type     string `xml:"TextType"`
+	Audience string `xml:"ContentAudience"`
+	Text     string `xml:"Text"`
+}
+
+type supportingResource struct {
+	ContentType string `xml:"ResourceContentType"`
+	Audience    string `xml:"ContentAudience"`
+	Mode        string `xml:"ResourceMode"`
+	Form        string `xml:"ResourceVersion>ResourceForm"`
+	Link        string `xml:"ResourceVersion>ResourceLink"`
+}
+
+type publishingDetail struct {
+	Publishers []publisher     `xml:"Publisher"`
+	City       string          `xml:"CityOfPublication,omitempty"`
+	Country    string          `xml:"CountryOfPublication,omitempty"`
+	Date       *publishingDate `xml:"PublishingDate"`
+}
+
+type publisher struct {
+	Role string `xml:"PublishingRole"`
+	Name string `xml:"PublisherName"`
+}
+
+type publishingDate struct {
+	Role string        `xml:"PublishingDateRole"`
+	Date onixDateValue `xml:"Date"`
+}
+
+type onixDateValue struct {
+	Format string `xml:"dateformat,attr,omitempty"`
+	Value  string `xml:",chardata"`
+}
+
+// productForms maps canonical formats to ONIX List 150 codes.
+var productForms = map[book.Format]string{
+	book.Hardcover:   "BB",
+	book.Paperback:   "BC",
+	book.Ebook:       "ED",
+	book.Audio:       "AA",
+	book.OtherFormat: "BZ",
+}
+
+func onixProduct(b *book.BookInfo) *product {
+	code13 := b.ISBN13
+	if code13 == "" {
+		code13 = isbn.To13(b.ISBN10)
+	}
+	p := &product{
+		RecordReference:  "isbn:" + code13,
+		NotificationType: "03", // confirmed record
+		Identifiers:      []productIdentifier{{Type: "15", Value: code13}},
+	}
+	if b.LCCN != "" {
+		p.Identifiers = append(p.Identifiers, productIdentifier{Type: "13", Value: b.LCCN})
+	}
+	if b.OCLC != "" {
+		p.Identifiers = append(p.Identifiers, productIdentifier{Type: "23", Value: b.OCLC})
+	}
+	d := &p.DescriptiveDetail
+	d.ProductComposition = "00" // single-item retail product
+	d.ProductForm = "00"        // undefined
+	if f, ok := productForms[b.Format]; ok {
+		d.ProductForm = f
+	}
+	for _, m := range []struct {
+		typ string
+		v   float64
+	}{{"01", float64(b.Dimensions.Height)}, {"02", float64(b.Dimensions.Width)}, {"03", float64(b.Dimensions.Thickness)}} {
+		if m.v > 0 {
+			d.Measures = append(d.Measures, measure{Type: m.typ, Value: onixNumber(m.v), Unit: "cm"})
+		}
+	}
+	if b.Weight > 0 {
+		d.Measures = append(d.Measures, measure{Type: "08", Value: onixNumber(float64(b.Weight)), Unit: "gr"})
+	}
+	if b.Series != "" {
+		d.Collection = &collection{Type: "10", TitleDetail: titleDetail{Type: "01", Element: titleElement{
+			Level: "02", PartNumber: b.SeriesPosition, Text: b.Series,
+		}}}
+	}
+	d.TitleDetail = titleDetail{Type: "01", Element: titleElement{Level: "01", Text: b.Title, Subtitle: b.Subtitle}}
+	for i, a := range b.Authors {
+		c := contributor{Sequence: i + 1, Role: "A01", Name: author.Normalize(a), Inverted: author.Invert(a)}
+		d.Contributors = append(d.Contributors, c)
+	}
+	if len(d.Contributors) == 0 {
+		d.NoContributor = &struct{}{}
+	}
+	for _, code := range lang.Code.FormatAll(b.Languages) {
+		if len(code) == 3 {
+			d.Languages = append(d.Languages, language{Role: "01", Code: code})
+		}
+	}
+	if b.Pages > 0 {
+		d.Extent = &extent{Type: "00", Value: b.Pages, Unit: "03"} // main content, pages
+	}
+	for i, code := range b.BISAC {
+		s := subject{Scheme: "10", Code: code} // BISAC
+		if i == 0 {
+			s.Main = &struct{}{}
+		}
+		d.Subjects = append(d.Subjects, s)
+	}
+	if b.DeweyDecimal != "" {
+		d.Subjects = append(d.Subjects, subject{Scheme: "01", Code: b.DeweyDecimal})
+	}
+	if b.LCC != "" {
+		d.Subjects = append(d.Subjects, subject{Scheme: "03", Code: b.LCC})
+	}
+	if len(b.Subjects) > 0 {
+		d.Subjects = append(d.Subjects, subject{Scheme: "20", Heading: strings.Join(b.Subjects, ";")}) // keywords
+	}
+
+	if b.Description != "" || b.CoverURL != "" {
+		c := &collateralDetail{}
+		if b.Description != "" {
+			c.Text = &textContent{Type: "03", Audience: "00", Text: b.Description}
+		}
+		if b.CoverURL != "" {
+			c.Resource = &supportingResource{ContentType: "01", Audience: "00", Mode: "03", Form: "02", Link: b.CoverURL}
+		}
+		p.CollateralDetail = c
+	}
+
+	pd := &publishingDetail{City: first(b.PublishPlaces), Country: b.PublishCountry}
+	for _, name := range b.Publishers {
+		pd.Publishers = append(pd.Publishers, publisher{Role: "01", Name: name})
+	}
+	if v, f := onixDate(b.PublishDate); v != "" {
+		pd.Date = &publishingDate{Role: "01", Date: onixDateValue{Format: f, Value: v}}
+	}
+	if len(pd.Publishers) > 0 || pd.City != "" || pd.Country != "" || pd.Date != nil {
+		p.PublishingDetail = pd
+	}
+	return p
+}
+
+// onixNumber formats a measurement to one decimal place.
+func onixNumber(v float64) string {
+	return strconv.FormatFloat(math.Round(v*10)/10, 'f', -1, 64)
+}
+
+// onixDate converts a provider's publish date to an ONIX date and its
+// List 55 dateformat: "" (YYYYMMDD), "01" (YYYYMM) or "05" (YYYY).
+func onixDate(s string) (value, format string) {
+	s = strings.TrimSpace(s)
+	for _, l := range []struct{ layout, out, format string }{
+		{"2006-01-02", "20060102", ""},
+		{"January 2, 2006", "20060102", ""},
+		{"Jan 2, 2006", "20060102", ""},
+		{"2 January 2006", "20060102", ""},
+		{"2006-01", "200601", "01"},
+		{"January 2006", "200601", "01"},
+		{"Jan 2006", "200601", "01"},
+	} {
+		if t, err := time.Parse(l.layout, s); err == nil {
+			return t.Format(l.out), l.format
+		}
+	}
+	if y := (&book.BookInfo{PublishDate: s}).Year(); y != "" {
+		return y, "05"
+	}
+	return "", ""
+}
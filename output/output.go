@@ -0,0 +1,137 @@
+// Package output defines the Writer interface implemented by every
+// output format and a registry through which formats are selected by
+// name or file extension. Formats register themselves from init
+// functions; applications embedding the enricher can register their own.
+package output
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+)
+
+// Record is one enriched input row.
+type Record struct {
+	// Index is the zero-based position of the row in the input.
+	Index int
+	// Book is the merged enrichment result, nil when the lookup failed.
+	Book *book.BookInfo
+	// Cells holds the tabular representation of the row, aligned with
+	// the header passed to WriteHeader.
+	Cells []string
+	// Err is the lookup error for failed rows.
+	Err error
+	// Review marks rows whose data quality is below the run's minimum
+	// confidence. The xlsx format moves them to a Review sheet; see
+	// Split for the others.
+	Review bool
+	// Editions asks for the other editions of the book to be listed. The
+	// xlsx format lists them on an Editions sheet; see SplitEditions for
+	// the others.
+	Editions bool
+	// Sheet is the input worksheet of the row, for inputs read from
+	// several, and Line its row number there. The xlsx format writes
+	// the rows of each input sheet to a sheet of the same name.
+	Sheet string
+	Line  int
+}
+
+// Writer writes enriched records in one output format. Tabular formats
+// use the header and cells; structured formats (MARC, ONIX, BibTeX...)
+// may ignore them and work from Record.Book instead.
+type Writer interface {
+	WriteHeader(columns []string) error
+	Write(r *Record) error
+	// Close flushes buffered output. It does not close the underlying
+	// io.Writer.
+	Close() error
+}
+
+// Factory creates a Writer that writes to w.
+type Factory func(w io.Writer) (Writer, error)
+
+// Format describes a registered output format.
+type Format struct {
+	Name string
+	// Extensions lists the file extensions (with dot) that select this
+	// format when no format is given explicitly.
+	Extensions []string
+	New        Factory
+}
+
+var (
+	mu      sync.RWMutex
+	formats = make(map[string]*Format)
+)
+
+// Register makes a format available by name. It panics if the name is
+// already taken, like database/sql.Register.
+func Register(f Format) {
+	mu.Lock()
+	defer mu.Unlock()
+	name := strings.ToLower(f.Name)
+	if f.New == nil {
+		panic("output: Register factory is nil for " + name)
+	}
+	if _, dup := formats[name]; dup {
+		panic("output: Register called twice for " + name)
+	}
+	formats[name] = &f
+}
+
+// Lookup returns the format registered under name.
+func Lookup(name string) (*Format, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	f, ok := formats[strings.ToLower(name)]
+	return f, ok
+}
+
+// ForPath returns the format whose extensions match path.
+func ForPath(path string) (*Format, bool) {
+	ext := strings.ToLower(filepath.Ext(path))
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, f := range formats {
+		for _, e := range f.Extensions {
+			if e == ext {
+				return f, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// Names returns the registered format names, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(formats))
+	for n := range formats {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Resolve picks a format by explicit name, falling back to the extension
+// of path.
+func Resolve(name, path string) (*Format, error) {
+	if name != "" {
+		f, ok := Lookup(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown output format %q (available: %s)", name, strings.Join(Names(), ", "))
+		}
+		return f, nil
+	}
+	if f, ok := ForPath(path); ok {
+		return f, nil
+	}
+	return nil, fmt.Errorf("cannot infer output format from %q; use -output-format (available: %s)",
+		path, strings.Join(Names(), ", "))
+}
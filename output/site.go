@@ -0,0 +1,283 @@
+package output
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+)
+
+// SEO limits: search engines cut title tags and meta descriptions
+// beyond about these many characters.
+const (
+	MaxTitleLength       = 60
+	MaxDescriptionLength = 160
+)
+
+// DefaultSEOTitle is the title tag template used when none is given.
+const DefaultSEOTitle = `{{.FullTitle}}{{with .Authors}} by {{names .}}{{end}}`
+
+// SEODir is the subdirectory of the site directory holding the per-book
+// metadata files.
+const SEODir = "seo"
+
+// maxSitemapURLs is the most URLs a sitemap file may list; larger
+// catalogs get a sitemap index.
+const maxSitemapURLs = 50000
+
+// ParseSiteTemplate parses a canonical URL or title tag template.
+// Templates are executed with the book record, as in
+// "https://shop.example/books/{{.ISBN13}}/{{slug .Title}}"; slug turns
+// text into a URL path segment and names joins names as "A, B and C".
+func ParseSiteTemplate(name, s string) (*template.Template, error) {
+	t, err := template.New(name).Funcs(template.FuncMap{"slug": slug, "names": book.Names}).Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("%s template: %w", name, err)
+	}
+	return t, nil
+}
+
+// SEO is the metadata of one book's page, as written to
+// <dir>/seo/<isbn13>.json.
+type SEO struct {
+	ISBN        string   `json:"isbn"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Canonical   string   `json:"canonical"`
+	Image       string   `json:"image,omitempty"`
+	ImageAlt    string   `json:"image_alt,omitempty"`
+	Authors     []string `json:"authors,omitempty"`
+	Publisher   string   `json:"publisher,omitempty"`
+	Published   string   `json:"published,omitempty"`
+}
+
+// SiteWriter writes the files a static site builder needs for a
+// catalog: a sitemap.xml listing every book's canonical URL and, per
+// book, a JSON file of SEO metadata. Failed lookups and books without
+// an ISBN-13 have no page and are skipped.
+type SiteWriter struct {
+	dir       string
+	url       *template.Template
+	title     *template.Template
+	lastMod   string
+	pages     []sitePage
+	seen      map[string]bool
+	skipped   int
+	mkdirDone bool
+}
+
+type sitePage struct {
+	index int
+	loc   string
+}
+
+// NewSite returns a Writer building the site files in dir. canonical
+// renders each book's URL; title renders its title tag, nil selecting
+// DefaultSEOTitle.
+func NewSite(dir string, canonical, title *template.Template) (*SiteWriter, error) {
+	if canonical == nil {
+		return nil, errors.New("site: no canonical URL template")
+	}
+	if title == nil {
+		title = template.Must(ParseSiteTemplate("title", DefaultSEOTitle))
+	}
+	return &SiteWriter{
+		dir:     dir,
+		url:     canonical,
+		title:   title,
+		lastMod: time.Now().Format(time.DateOnly),
+		seen:    make(map[string]bool),
+	}, nil
+}
+
+// Skipped returns the number of records without a page.
+func (s *SiteWriter) Skipped() int { return s.skipped }
+
+// Dir returns the site directory.
+func (s *SiteWriter) Dir() string { return s.dir }
+
+func (s *SiteWriter) WriteHeader([]string) error { return nil }
+
+func (s *SiteWriter) Write(r *Record) error {
+	b := r.Book
+	if b == nil || !isbn.Valid13(isbn.To13(b.ISBN())) {
+		s.skipped++
+		return nil
+	}
+	m, err := s.seo(b)
+	if err != nil {
+		return err
+	}
+	if !s.mkdirDone {
+		if err := os.MkdirAll(filepath.Join(s.dir, SEODir), 0o755); err != nil {
+			return err
+		}
+		s.mkdirDone = true
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeFileAtomic(filepath.Join(s.dir, SEODir, m.ISBN+".json"), append(data, '\n')); err != nil {
+		return err
+	}
+	// Repeated ISBNs share a page.
+	if !s.seen[m.Canonical] {
+		s.seen[m.Canonical] = true
+		s.pages = append(s.pages, sitePage{r.Index, m.Canonical})
+	}
+	return nil
+}
+
+// seo builds the metadata of b's page.
+func (s *SiteWriter) seo(b *book.BookInfo) (*SEO, error) {
+	code := isbn.To13(b.ISBN())
+	loc, err := execute(s.url, b)
+	if err != nil {
+		return nil, err
+	}
+	if u, err := url.Parse(loc); err != nil || !u.IsAbs() {
+		return nil, fmt.Errorf("site: canonical URL %q of %s is not absolute", loc, code)
+	}
+	title, err := execute(s.title, b)
+	if err != nil {
+		return nil, err
+	}
+	m := &SEO{
+		ISBN:        code,
+		Title:       book.Truncate(title, MaxTitleLength),
+		Description: book.Truncate(metaDescription(b), MaxDescriptionLength),
+		Canonical:   loc,
+		Image:       b.CoverURL,
+		ImageAlt:    b.CoverAlt,
+		Authors:     b.Authors,
+		Published:   b.PublishDate,
+	}
+	if len(b.Publishers) > 0 {
+		m.Publisher = b.Publishers[0]
+	}
+	return m, nil
+}
+
+// metaDescription returns the first paragraph of the blurb, or a
+// sentence built from the record for books without one.
+func metaDescription(b *book.BookInfo) string {
+	if d, _, _ := strings.Cut(strings.TrimSpace(b.Description), "\n\n"); d != "" {
+		return strings.Join(strings.Fields(d), " ")
+	}
+	var d strings.Builder
+	d.WriteString(b.FullTitle())
+	if len(b.Authors) > 0 {
+		d.WriteString(" by " + book.Names(b.Authors))
+	}
+	if len(b.Publishers) > 0 {
+		d.WriteString(", published by " + b.Publishers[0])
+	}
+	if y := b.Year(); y != "" {
+		d.WriteString(" in " + y)
+	}
+	d.WriteString(".")
+	return d.String()
+}
+
+func execute(t *template.Template, b *book.BookInfo) (string, error) {
+	var buf strings.Builder
+	if err := t.Execute(&buf, b); err != nil {
+		return "", fmt.Errorf("site: %w", err)
+	}
+	return strings.Join(strings.Fields(buf.String()), " "), nil
+}
+
+// Close writes the sitemap, listing the pages in input order. Catalogs
+// of more than 50,000 books get numbered sitemaps and a sitemap.xml
+// index pointing to them, next to the first page's URL.
+func (s *SiteWriter) Close() error {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	slices.SortStableFunc(s.pages, func(a, b sitePage) int { return a.index - b.index })
+	if len(s.pages) <= maxSitemapURLs {
+		return s.writeSitemap("sitemap.xml", s.pages)
+	}
+	base, err := url.Parse(s.pages[0].loc)
+	if err != nil {
+		return err
+	}
+	index := sitemapIndex{XMLNS: sitemapNS}
+	for i := 0; i*maxSitemapURLs < len(s.pages); i++ {
+		name := "sitemap-" + strconv.Itoa(i+1) + ".xml"
+		part := s.pages[i*maxSitemapURLs : min((i+1)*maxSitemapURLs, len(s.pages))]
+		if err := s.writeSitemap(name, part); err != nil {
+			return err
+		}
+		loc := base.ResolveReference(&url.URL{Path: "/" + name})
+		index.Sitemaps = append(index.Sitemaps, sitemapURL{Loc: loc.String(), LastMod: s.lastMod})
+	}
+	return s.writeXML("sitemap.xml", index)
+}
+
+const sitemapNS = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type urlSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapURL `xml:"sitemap"`
+}
+
+func (s *SiteWriter) writeSitemap(name string, pages []sitePage) error {
+	set := urlSet{XMLNS: sitemapNS}
+	for _, p := range pages {
+		set.URLs = append(set.URLs, sitemapURL{Loc: p.loc, LastMod: s.lastMod})
+	}
+	return s.writeXML(name, set)
+}
+
+func (s *SiteWriter) writeXML(name string, v any) error {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append([]byte(xml.Header), append(data, '\n')...)
+	return writeFileAtomic(filepath.Join(s.dir, name), data)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".part"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// slug lower-cases s and joins its words with hyphens, escaped for use
+// as a URL path segment. Marks, such as Arabic vowels, stay in their
+// words.
+func slug(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && !unicode.Is(unicode.Mn, r)
+	})
+	return url.PathEscape(strings.Join(words, "-"))
+}
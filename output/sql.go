@@ -0,0 +1,182 @@
+package output
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+)
+
+// Dialect is an SQL flavour supported by SQLWriter.
+type Dialect string
+
+const (
+	Postgres Dialect = "postgres"
+	MySQL    Dialect = "mysql"
+)
+
+// DialectFor returns the dialect of a database/sql driver name.
+func DialectFor(driver string) (Dialect, error) {
+	switch driver {
+	case "postgres", "pgx":
+		return Postgres, nil
+	case "mysql":
+		return MySQL, nil
+	}
+	return "", fmt.Errorf("unsupported database driver %q (want pgx, postgres or mysql)", driver)
+}
+
+// KeyColumn is the primary key of exported tables: the ISBN-13 of the
+// enriched book, or of the input row when the lookup failed.
+const KeyColumn = "isbn13"
+
+// SQLWriter upserts records into a database table keyed by ISBN-13, one
+// text column per header. Rows without a valid ISBN cannot be keyed and
+// are skipped.
+type SQLWriter struct {
+	db      *sql.DB
+	dialect Dialect
+	table   string
+	columns []string
+	// isbnCol is the index of the input ISBN column, or -1.
+	isbnCol int
+	stmt    *sql.Stmt
+	skipped int
+}
+
+var identRe = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// NewSQL returns a Writer upserting into table. The table is created on
+// WriteHeader if it does not exist; an existing table must have the
+// columns ColumnName derives from the header.
+func NewSQL(db *sql.DB, d Dialect, table string) (*SQLWriter, error) {
+	if !identRe.MatchString(table) {
+		return nil, fmt.Errorf("invalid table name %q", table)
+	}
+	return &SQLWriter{db: db, dialect: d, table: table, isbnCol: -1}, nil
+}
+
+var nonIdentRe = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ColumnName derives an SQL column name from a header, e.g.
+// "Height (cm)" becomes "height_cm".
+func ColumnName(header string) string {
+	return strings.Trim(nonIdentRe.ReplaceAllString(strings.ToLower(header), "_"), "_")
+}
+
+func (s *SQLWriter) quote(ident string) string {
+	if s.dialect == MySQL {
+		return "`" + ident + "`"
+	}
+	return `"` + ident + `"`
+}
+
+func (s *SQLWriter) placeholder(i int) string {
+	if s.dialect == Postgres {
+		return "$" + strconv.Itoa(i)
+	}
+	return "?"
+}
+
+func (s *SQLWriter) WriteHeader(columns []string) error {
+	seen := map[string]bool{KeyColumn: true}
+	cols := []string{KeyColumn}
+	for i, h := range columns {
+		name := ColumnName(h)
+		if name == "isbn" {
+			s.isbnCol = i
+		}
+		for base, n := name, 2; seen[name]; n++ {
+			name = base + "_" + strconv.Itoa(n)
+		}
+		seen[name] = true
+		cols = append(cols, name)
+	}
+	s.columns = cols
+
+	ctx := context.Background()
+	keyType := "TEXT"
+	if s.dialect == MySQL {
+		keyType = "VARCHAR(13)"
+	}
+	defs := []string{s.quote(KeyColumn) + " " + keyType + " PRIMARY KEY"}
+	for _, c := range cols[1:] {
+		defs = append(defs, s.quote(c)+" TEXT")
+	}
+	create := "CREATE TABLE IF NOT EXISTS " + s.table + " (" + strings.Join(defs, ", ") + ")"
+	if _, err := s.db.ExecContext(ctx, create); err != nil {
+		return fmt.Errorf("create table %s: %w", s.table, err)
+	}
+
+	quoted := make([]string, len(cols))
+	params := make([]string, len(cols))
+	var updates []string
+	for i, c := range cols {
+		quoted[i], params[i] = s.quote(c), s.placeholder(i+1)
+		if i == 0 {
+			continue
+		}
+		if s.dialect == MySQL {
+			updates = append(updates, quoted[i]+" = VALUES("+quoted[i]+")")
+		} else {
+			updates = append(updates, quoted[i]+" = EXCLUDED."+quoted[i])
+		}
+	}
+	q := "INSERT INTO " + s.table + " (" + strings.Join(quoted, ", ") + ") VALUES (" + strings.Join(params, ", ") + ")"
+	if s.dialect == MySQL {
+		q += " ON DUPLICATE KEY UPDATE " + strings.Join(updates, ", ")
+	} else {
+		q += " ON CONFLICT (" + quoted[0] + ") DO UPDATE SET " + strings.Join(updates, ", ")
+	}
+	var err error
+	s.stmt, err = s.db.PrepareContext(ctx, q)
+	return err
+}
+
+func (s *SQLWriter) key(r *Record) string {
+	if r.Book != nil && r.Book.ISBN13 != "" {
+		return r.Book.ISBN13
+	}
+	if s.isbnCol >= 0 && s.isbnCol < len(r.Cells) {
+		if code := isbn.Normalize(r.Cells[s.isbnCol]); isbn.Valid(code) {
+			return isbn.To13(code)
+		}
+	}
+	return ""
+}
+
+func (s *SQLWriter) Write(r *Record) error {
+	key := s.key(r)
+	if key == "" {
+		s.skipped++
+		return nil
+	}
+	args := make([]any, len(s.columns))
+	args[0] = key
+	for i := 1; i < len(args); i++ {
+		if i-1 < len(r.Cells) {
+			args[i] = r.Cells[i-1]
+		} else {
+			args[i] = ""
+		}
+	}
+	if _, err := s.stmt.Exec(args...); err != nil {
+		return fmt.Errorf("upsert %s into %s: %w", key, s.table, err)
+	}
+	return nil
+}
+
+// Skipped returns the number of records left out for lack of an ISBN.
+func (s *SQLWriter) Skipped() int { return s.skipped }
+
+// Close releases the prepared statement. It does not close the database.
+func (s *SQLWriter) Close() error {
+	if s.stmt == nil {
+		return nil
+	}
+	return s.stmt.Close()
+}
@@ -0,0 +1,251 @@
+package output
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+func init() {
+	Register(Format{Name: "xlsx", Extensions: []string{".xlsx"}, New: NewXLSX})
+}
+
+// Sheet names used by the xlsx writer.
+const (
+	SheetBooks    = "Books"
+	SheetErrors   = "Errors"
+	SheetReview   = "Review"
+	SheetEditions = "Editions"
+)
+
+type xlsxWriter struct {
+	out    io.Writer
+	f      *excelize.File
+	header []any
+	// data holds the streams of the sheets rows go to: Books, or the
+	// input sheets of multi-sheet inputs, in the order they came.
+	data     map[string]*sheetStream
+	order    []string
+	errs     *sheetStream
+	review   *sheetStream
+	editions *sheetStream
+	// used is set once the workbook's initial sheet has been named.
+	used bool
+	// aux is the first Errors, Review or Editions sheet, which data sheets
+	// created after it are moved in front of.
+	aux    string
+	styles *xlsxStyles
+}
+
+// NewXLSX returns a Writer producing an Excel workbook. Successful rows go
+// to the Books sheet, or to a Review sheet when marked for review; failed
+// lookups are also listed on an Errors sheet with the reason, and the
+// other editions of books marked with Editions on an Editions sheet. Rows read
+// from several input sheets go to sheets of the same names instead, so
+// the workbook keeps the input's structure. Rows are streamed to
+// temporary storage as they are written rather than kept in memory, so
+// large files stay cheap. Sheets are styled to be shared as they are;
+// see sheetStream.
+func NewXLSX(w io.Writer) (Writer, error) {
+	f := excelize.NewFile()
+	styles, err := newXLSXStyles(f)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &xlsxWriter{out: w, f: f, data: make(map[string]*sheetStream), styles: styles}, nil
+}
+
+func (x *xlsxWriter) WriteHeader(columns []string) error {
+	x.header = values(columns)
+	return nil
+}
+
+// sheet adds a sheet and returns its stream, with header as first row.
+// The first sheet takes the place of the new workbook's empty one.
+func (x *xlsxWriter) sheet(name string, header []any) (*sheetStream, error) {
+	if !x.used {
+		if err := x.f.SetSheetName("Sheet1", name); err != nil {
+			return nil, err
+		}
+		x.used = true
+	} else {
+		if i, _ := x.f.GetSheetIndex(name); i >= 0 {
+			return nil, fmt.Errorf("workbook already has a sheet %q", name)
+		}
+		if _, err := x.f.NewSheet(name); err != nil {
+			return nil, err
+		}
+	}
+	sw, err := x.f.NewStreamWriter(name)
+	if err != nil {
+		return nil, err
+	}
+	return newSheetStream(x.f, sw, header, x.styles), nil
+}
+
+// dataSheet returns the stream of a data sheet, adding it on first use.
+func (x *xlsxWriter) dataSheet(name string) (*sheetStream, error) {
+	if s, ok := x.data[name]; ok {
+		return s, nil
+	}
+	s, err := x.sheet(name, x.header)
+	if err != nil {
+		return nil, err
+	}
+	x.data[name] = s
+	x.order = append(x.order, name)
+	return s, nil
+}
+
+// auxSheet adds the Errors, Review or Editions sheet.
+func (x *xlsxWriter) auxSheet(name string, header []any) (*sheetStream, error) {
+	s, err := x.sheet(name, header)
+	if err == nil && x.aux == "" {
+		x.aux = name
+	}
+	return s, err
+}
+
+func values(cells []string) []any {
+	vals := make([]any, len(cells))
+	for i, c := range cells {
+		vals[i] = c
+	}
+	return vals
+}
+
+func (x *xlsxWriter) Write(r *Record) error {
+	if err := x.writeRow(r); err != nil {
+		return err
+	}
+	return x.writeEditions(r)
+}
+
+// writeRow writes r to its data sheet or the Review sheet, and failed
+// lookups to the Errors sheet.
+func (x *xlsxWriter) writeRow(r *Record) error {
+	// Rows of several input sheets are told apart by a leading Sheet
+	// column on the Review and Errors sheets. Their sheet is added even
+	// when all its rows need review, to keep the input's structure.
+	var sheet []any
+	if r.Sheet != "" {
+		sheet = []any{r.Sheet}
+		if _, err := x.dataSheet(r.Sheet); err != nil {
+			return err
+		}
+	}
+	if r.Review {
+		if x.review == nil {
+			s, err := x.auxSheet(SheetReview, append(sheetHeader(sheet), x.header...))
+			if err != nil {
+				return err
+			}
+			x.review = s
+		}
+		return x.review.append(append(sheet, values(r.Cells)...))
+	}
+	name := SheetBooks
+	if r.Sheet != "" {
+		name = r.Sheet
+	}
+	s, err := x.dataSheet(name)
+	if err != nil {
+		return err
+	}
+	if err := s.append(values(r.Cells)); err != nil {
+		return err
+	}
+	if r.Err == nil {
+		return nil
+	}
+	// Report the spreadsheet row number, counting the header row.
+	line := r.Index + 2
+	if r.Sheet != "" {
+		line = r.Line
+	}
+	if x.errs == nil {
+		s, err := x.auxSheet(SheetErrors, append(sheetHeader(sheet), "Row", "Error"))
+		if err != nil {
+			return err
+		}
+		x.errs = s
+	}
+	return x.errs.append(append(sheet, line, r.Err.Error()))
+}
+
+// writeEditions lists the other editions of r's book on the Editions
+// sheet, when asked to.
+func (x *xlsxWriter) writeEditions(r *Record) error {
+	if !r.Editions {
+		return nil
+	}
+	rows := EditionRows(r)
+	if len(rows) == 0 {
+		return nil
+	}
+	var sheet []any
+	if r.Sheet != "" {
+		sheet = []any{r.Sheet}
+	}
+	if x.editions == nil {
+		s, err := x.auxSheet(SheetEditions, append(sheetHeader(sheet), values(EditionsHeader)...))
+		if err != nil {
+			return err
+		}
+		x.editions = s
+	}
+	for _, row := range rows {
+		if err := x.editions.append(append(sheet, values(row)...)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sheetHeader returns the header of the leading Sheet column, if any.
+func sheetHeader(sheet []any) []any {
+	if sheet == nil {
+		return nil
+	}
+	return []any{"Sheet"}
+}
+
+func (x *xlsxWriter) Close() error {
+	defer x.f.Close()
+	// A run without rows still gets a Books sheet with the header.
+	if len(x.data) == 0 {
+		if _, err := x.dataSheet(SheetBooks); err != nil {
+			return err
+		}
+	}
+	streams := make([]*sheetStream, 0, len(x.order)+3)
+	for _, name := range x.order {
+		streams = append(streams, x.data[name])
+	}
+	for _, s := range append(streams, x.errs, x.review, x.editions) {
+		if s == nil {
+			continue
+		}
+		if err := s.flush(); err != nil {
+			return err
+		}
+	}
+	// Keep the data sheets first, in input order.
+	if x.aux != "" {
+		for _, name := range x.order {
+			i, _ := x.f.GetSheetIndex(name)
+			j, _ := x.f.GetSheetIndex(x.aux)
+			if i > j {
+				if err := x.f.MoveSheet(name, x.aux); err != nil {
+					return err
+				}
+			}
+		}
+		i, _ := x.f.GetSheetIndex(x.order[0])
+		x.f.SetActiveSheet(i)
+	}
+	_, err := x.f.WriteTo(x.out)
+	return err
+}
@@ -0,0 +1,266 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/xuri/excelize/v2"
+
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/translit"
+)
+
+// sampleRows is how many rows are held back to fit the column widths
+// to before a sheet is streamed.
+const sampleRows = 100
+
+// Column widths, in characters.
+const (
+	minWidth  = 8
+	maxWidth  = 60
+	wrapWidth = 60
+)
+
+// rightToLeft is the reading order of cells in right-to-left scripts.
+const rightToLeft = 2
+
+// maxLinkLength is the longest URL a HYPERLINK formula can hold; longer
+// ones are written as text.
+const maxLinkLength = 255
+
+// xlsxStyles are the cell styles of the xlsx writer.
+type xlsxStyles struct {
+	header, wrap, link int
+	// rtl and rtlWrap align text in right-to-left scripts, such as
+	// Arabic titles, to the right and set its reading order.
+	rtl, rtlWrap int
+	// missing is the conditional style of fields no provider filled.
+	missing int
+}
+
+func newXLSXStyles(f *excelize.File) (*xlsxStyles, error) {
+	var s xlsxStyles
+	var err error
+	if s.header, err = f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true},
+		Fill:      excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"D9E1F2"}},
+		Border:    []excelize.Border{{Type: "bottom", Color: "8EA9DB", Style: 1}},
+		Alignment: &excelize.Alignment{Vertical: "center"},
+	}); err != nil {
+		return nil, err
+	}
+	if s.wrap, err = f.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{WrapText: true, Vertical: "top"},
+	}); err != nil {
+		return nil, err
+	}
+	if s.rtl, err = f.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{Horizontal: "right", ReadingOrder: rightToLeft},
+	}); err != nil {
+		return nil, err
+	}
+	if s.rtlWrap, err = f.NewStyle(&excelize.Style{
+		Alignment: &excelize.Alignment{Horizontal: "right", ReadingOrder: rightToLeft, WrapText: true, Vertical: "top"},
+	}); err != nil {
+		return nil, err
+	}
+	if s.link, err = f.NewStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "0563C1", Underline: "single"},
+	}); err != nil {
+		return nil, err
+	}
+	if s.missing, err = f.NewConditionalStyle(&excelize.Style{
+		Font: &excelize.Font{Color: "9C0006"},
+		Fill: excelize.Fill{Type: "pattern", Pattern: 1, Color: []string{"FFC7CE"}},
+	}); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// sheetStream writes a sheet ready to share: a bold header row frozen
+// above the data, an auto-filter, columns as wide as their contents,
+// wrapped descriptions, clickable URLs, right-to-left text aligned to
+// the right and missing fields in red. The streaming writer needs
+// the column widths before the first row, so the first sampleRows rows
+// are held back to measure.
+type sheetStream struct {
+	f       *excelize.File
+	sw      *excelize.StreamWriter
+	styles  *xlsxStyles
+	header  []any
+	wrap    []bool
+	pending [][]any
+	started bool
+	// row is the last row written or held back.
+	row int
+}
+
+func newSheetStream(f *excelize.File, sw *excelize.StreamWriter, header []any, styles *xlsxStyles) *sheetStream {
+	wrap := make([]bool, len(header))
+	for i, h := range header {
+		wrap[i] = wrapColumn(fmt.Sprint(h))
+	}
+	return &sheetStream{f: f, sw: sw, styles: styles, header: header, wrap: wrap, row: 1}
+}
+
+// wrapColumn reports whether the cells of a column hold running text,
+// such as descriptions and their translations, to wrap.
+func wrapColumn(header string) bool {
+	return strings.HasPrefix(header, "Description")
+}
+
+// append writes vals to the next row of s.
+func (s *sheetStream) append(vals []any) error {
+	s.row++
+	if s.started {
+		return s.write(s.row, vals)
+	}
+	s.pending = append(s.pending, vals)
+	if len(s.pending) < sampleRows {
+		return nil
+	}
+	return s.start()
+}
+
+// start sets the column widths and panes from the rows held back, then
+// writes them after the header.
+func (s *sheetStream) start() error {
+	s.started = true
+	// The stream writer lists columns in the reverse order they are
+	// set, and spreadsheets want them in order.
+	widths := s.widths()
+	for i := len(widths) - 1; i >= 0; i-- {
+		if err := s.sw.SetColWidth(i+1, i+1, widths[i]); err != nil {
+			return err
+		}
+	}
+	if err := s.sw.SetPanes(&excelize.Panes{
+		Freeze:      true,
+		YSplit:      1,
+		TopLeftCell: "A2",
+		ActivePane:  "bottomLeft",
+	}); err != nil {
+		return err
+	}
+	header := make([]any, len(s.header))
+	for i, h := range s.header {
+		header[i] = excelize.Cell{StyleID: s.styles.header, Value: h}
+	}
+	if err := setRow(s.sw, 1, header); err != nil {
+		return err
+	}
+	for i, vals := range s.pending {
+		if err := s.write(i+2, vals); err != nil {
+			return err
+		}
+	}
+	s.pending = nil
+	return nil
+}
+
+// widths fits the column widths to the header and the rows held back.
+func (s *sheetStream) widths() []float64 {
+	widths := make([]float64, len(s.header))
+	measure := func(i int, v any) {
+		if i >= len(widths) {
+			return
+		}
+		// The filter button takes about two characters.
+		w := float64(utf8.RuneCountInString(fmt.Sprint(v))) + 2
+		widths[i] = max(widths[i], w)
+	}
+	for i, h := range s.header {
+		measure(i, h)
+	}
+	for _, vals := range s.pending {
+		for i, v := range vals {
+			measure(i, v)
+		}
+	}
+	for i := range widths {
+		widths[i] = min(max(widths[i], minWidth), maxWidth)
+		if s.wrap[i] {
+			widths[i] = wrapWidth
+		}
+	}
+	return widths
+}
+
+// write writes a data row, styling its cells.
+func (s *sheetStream) write(row int, vals []any) error {
+	cells := make([]any, len(vals))
+	for i, v := range vals {
+		cells[i] = v
+		str, ok := v.(string)
+		switch {
+		case !ok || str == "":
+		case i < len(s.wrap) && s.wrap[i]:
+			style := s.styles.wrap
+			if translit.IsRTL(str) {
+				style = s.styles.rtlWrap
+			}
+			cells[i] = excelize.Cell{StyleID: style, Value: str}
+		case isLink(str):
+			cells[i] = excelize.Cell{
+				StyleID: s.styles.link,
+				Formula: `HYPERLINK("` + strings.ReplaceAll(str, `"`, `""`) + `")`,
+				Value:   str,
+			}
+		case translit.IsRTL(str):
+			cells[i] = excelize.Cell{StyleID: s.styles.rtl, Value: str}
+		}
+	}
+	return setRow(s.sw, row, cells)
+}
+
+// isLink reports whether a cell holds only a web address short enough
+// for a HYPERLINK formula.
+func isLink(s string) bool {
+	return (strings.HasPrefix(s, "https://") || strings.HasPrefix(s, "http://")) &&
+		len(s) <= maxLinkLength && !strings.ContainsAny(s, " \n\t")
+}
+
+// flush writes the rows still held back, adds the auto-filter over the
+// header and the rows, and ends the sheet.
+//
+// Fields no provider could fill are written as columns.NA, so a rule
+// filling those cells in red shows the gaps of a large inventory at a
+// glance. Being conditional, the highlight goes away as cells are
+// filled in by hand, and it leaves blank input cells alone.
+func (s *sheetStream) flush() error {
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+	last, err := excelize.CoordinatesToCellName(max(len(s.header), 1), s.row)
+	if err != nil {
+		return err
+	}
+	// The filter is kept on the sheet the stream writes out on Flush.
+	if err := s.f.AutoFilter(s.sw.Sheet, "A1:"+last, nil); err != nil {
+		return err
+	}
+	if s.row > 1 {
+		if err := s.f.SetConditionalFormat(s.sw.Sheet, "A2:"+last, []excelize.ConditionalFormatOptions{{
+			Type:     "cell",
+			Criteria: "==",
+			Value:    `"` + columns.NA + `"`,
+			Format:   &s.styles.missing,
+		}}); err != nil {
+			return err
+		}
+	}
+	return s.sw.Flush()
+}
+
+// setRow writes vals to a row of a stream; rows must come in order.
+func setRow(sw *excelize.StreamWriter, row int, vals []any) error {
+	cell, err := excelize.CoordinatesToCellName(1, row)
+	if err != nil {
+		return err
+	}
+	return sw.SetRow(cell, vals)
+}
@@ -0,0 +1,84 @@
+package price
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// AbeBooksURL is the pricing service behind the AbeBooks price widgets.
+const AbeBooksURL = "https://www.abebooks.com/servlet/DWRestService/pricingservice"
+
+// AbeBooks reports the cheapest new and used copies listed on
+// AbeBooks.com, in US dollars. It needs no key.
+type AbeBooks struct {
+	HTTPClient *http.Client
+	// BaseURL overrides AbeBooksURL.
+	BaseURL string
+}
+
+// Name implements Source.
+func (*AbeBooks) Name() string { return "abebooks" }
+
+type abePricing struct {
+	Success bool         `json:"success"`
+	New     *abeBestInfo `json:"pricingInfoForBestNew"`
+	Used    *abeBestInfo `json:"pricingInfoForBestUsed"`
+}
+
+type abeBestInfo struct {
+	Price provider.String `json:"bestPriceInPurchaseCurrencyValueOnly"`
+}
+
+// Offers implements Source.
+func (a *AbeBooks) Offers(ctx context.Context, isbn13 string) ([]Offer, error) {
+	u := a.BaseURL
+	if u == "" {
+		u = AbeBooksURL
+	}
+	form := url.Values{
+		"action":    {"getPricingDataByISBN"},
+		"isbn":      {isbn13},
+		"container": {"pricingService-" + isbn13},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("User-Agent", provider.UserAgent)
+	c := a.HTTPClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, &provider.StatusError{URL: u, StatusCode: resp.StatusCode}
+	}
+	var p abePricing
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		return nil, fmt.Errorf("decode %s: %w", u, err)
+	}
+	var offers []Offer
+	for cond, info := range map[Condition]*abeBestInfo{New: p.New, Used: p.Used} {
+		if info == nil {
+			continue
+		}
+		if v, err := strconv.ParseFloat(string(info.Price), 64); err == nil {
+			offers = append(offers, Offer{Condition: cond, Amount: v, Currency: "USD"})
+		}
+	}
+	return offers, nil
+}
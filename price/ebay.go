@@ -0,0 +1,81 @@
+package price
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// EbayURL is the eBay Browse API.
+const EbayURL = "https://api.ebay.com/buy/browse/v1"
+
+// Ebay reports the fixed-price listings of an ISBN on eBay through the
+// Browse API, which needs an OAuth application token.
+type Ebay struct {
+	HTTPClient *http.Client
+	Token      string
+	// Marketplace is the eBay site searched, "EBAY_US" by default.
+	Marketplace string
+	// BaseURL overrides EbayURL.
+	BaseURL string
+}
+
+// Name implements Source.
+func (*Ebay) Name() string { return "ebay" }
+
+type ebaySearch struct {
+	Items []struct {
+		ConditionID string `json:"conditionId"`
+		Price       struct {
+			Value    provider.String `json:"value"`
+			Currency string          `json:"currency"`
+		} `json:"price"`
+	} `json:"itemSummaries"`
+}
+
+// Offers implements Source.
+func (e *Ebay) Offers(ctx context.Context, isbn13 string) ([]Offer, error) {
+	base := e.BaseURL
+	if base == "" {
+		base = EbayURL
+	}
+	market := e.Marketplace
+	if market == "" {
+		market = "EBAY_US"
+	}
+	c := e.HTTPClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+	c2 := *c
+	c2.Transport = provider.WithHeader(provider.WithHeader(c.Transport, "Authorization", "Bearer "+e.Token),
+		"X-EBAY-C-MARKETPLACE-ID", market)
+	q := url.Values{
+		"gtin":   {isbn13},
+		"filter": {"buyingOptions:{FIXED_PRICE}"},
+		"limit":  {"50"},
+	}
+	var res ebaySearch
+	if err := provider.GetJSON(ctx, &c2, base+"/item_summary/search?"+q.Encode(), &res); err != nil {
+		return nil, err
+	}
+	var offers []Offer
+	for _, it := range res.Items {
+		v, err := strconv.ParseFloat(string(it.Price.Value), 64)
+		if err != nil {
+			continue
+		}
+		cond := Used
+		// 1000 is "New"; 1500 "New other" and 1750 "New with defects"
+		// are sold as new too.
+		switch it.ConditionID {
+		case "1000", "1500", "1750":
+			cond = New
+		}
+		offers = append(offers, Offer{Condition: cond, Amount: v, Currency: it.Price.Currency})
+	}
+	return offers, nil
+}
@@ -0,0 +1,132 @@
+// Package price looks up current market prices of an edition from one or
+// more price sources and summarizes them as minimum and median prices for
+// new and used copies, to help price a collection.
+package price
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+)
+
+// DefaultCurrency is the currency prices are summarized in when
+// Lookup.Currency is empty.
+const DefaultCurrency = "USD"
+
+// Condition tells new copies from used ones.
+type Condition string
+
+const (
+	New  Condition = "new"
+	Used Condition = "used"
+)
+
+// Offer is one copy for sale, or the best offer of a kind when a source
+// only reports that.
+type Offer struct {
+	Condition Condition
+	// Amount includes the item price only, not shipping.
+	Amount   float64
+	Currency string
+	// Source names the price source listing the offer. Lookup sets it.
+	Source string
+}
+
+// Source reports the offers for an ISBN-13.
+type Source interface {
+	Name() string
+	Offers(ctx context.Context, isbn13 string) ([]Offer, error)
+}
+
+// Lookup queries its sources concurrently and summarizes their offers.
+type Lookup struct {
+	Sources []Source
+	// Currency selects the offers summarized; offers in other currencies
+	// are ignored, since there is no exchange rate to convert them.
+	Currency string
+}
+
+// Lookup returns the price summary for isbn13. It fails only when every
+// source fails; an ISBN without offers yields a zero summary.
+func (l *Lookup) Lookup(ctx context.Context, isbn13 string) (book.Prices, error) {
+	offers, err := l.Offers(ctx, isbn13)
+	if err != nil {
+		return book.Prices{}, err
+	}
+	var newAmounts, usedAmounts []float64
+	for _, o := range offers {
+		if o.Condition == New {
+			newAmounts = append(newAmounts, o.Amount)
+		} else {
+			usedAmounts = append(usedAmounts, o.Amount)
+		}
+	}
+	p := book.Prices{Offers: len(offers)}
+	if p.Offers > 0 {
+		p.Currency = l.currency()
+	}
+	p.NewMin, p.NewMedian = stats(newAmounts)
+	p.UsedMin, p.UsedMedian = stats(usedAmounts)
+	return p, nil
+}
+
+func (l *Lookup) currency() string {
+	if l.Currency == "" {
+		return DefaultCurrency
+	}
+	return strings.ToUpper(l.Currency)
+}
+
+// Offers returns the offers of every source for isbn13 in the lookup
+// currency, with their Source set. Like Lookup, it fails only when every
+// source fails.
+func (l *Lookup) Offers(ctx context.Context, isbn13 string) ([]Offer, error) {
+	cur := l.currency()
+	offers := make([][]Offer, len(l.Sources))
+	errs := make([]error, len(l.Sources))
+	var wg sync.WaitGroup
+	for i, s := range l.Sources {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			offers[i], errs[i] = s.Offers(ctx, isbn13)
+			if errs[i] != nil {
+				errs[i] = fmt.Errorf("%s: %w", s.Name(), errs[i])
+			}
+		}()
+	}
+	wg.Wait()
+	if len(l.Sources) > 0 && !slices.ContainsFunc(errs, func(err error) bool { return err == nil }) {
+		return nil, errors.Join(errs...)
+	}
+	var out []Offer
+	for i, list := range offers {
+		for _, o := range list {
+			if o.Amount <= 0 || !strings.EqualFold(o.Currency, cur) {
+				continue
+			}
+			o.Source = l.Sources[i].Name()
+			out = append(out, o)
+		}
+	}
+	return out, nil
+}
+
+// stats returns the minimum and median of amounts, or zeros when empty.
+func stats(amounts []float64) (lowest, median float64) {
+	if len(amounts) == 0 {
+		return 0, 0
+	}
+	slices.Sort(amounts)
+	n := len(amounts)
+	median = amounts[n/2]
+	if n%2 == 0 {
+		median = (amounts[n/2-1] + amounts[n/2]) / 2
+	}
+	return amounts[0], median
+}
@@ -0,0 +1,50 @@
+package profile
+
+import (
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+)
+
+func init() {
+	Register(&Profile{
+		Name:    "abebooks",
+		Summary: "AbeBooks HomeBase inventory upload (use a .tsv output)",
+		Fields:  abebooksFields,
+	})
+}
+
+// abebooksConditions maps conditions to the HomeBase condition terms.
+var abebooksConditions = map[Condition]string{
+	New:        "New",
+	LikeNew:    "As New",
+	VeryGood:   "Very Good",
+	Good:       "Good",
+	Acceptable: "Fair",
+	Poor:       "Poor",
+}
+
+// abebooksFields follow the HomeBase tab-delimited upload layout.
+var abebooksFields = []Field{
+	{"ListingID", isbn13},
+	{"Title", title},
+	{"Author", func(res *enrich.Result) string {
+		if res.Book != nil && len(res.Book.Authors) > 0 {
+			return strings.Join(res.Book.Authors, "; ")
+		}
+		return res.Row.Author
+	}},
+	{"Publisher", fromBook(func(b *book.BookInfo) string { return first(b.Publishers) })},
+	{"PlacePublished", fromBook(func(b *book.BookInfo) string { return first(b.PublishPlaces) })},
+	{"DatePublished", fromBook(func(b *book.BookInfo) string { return b.Year() })},
+	{"ISBN", isbn13},
+	{"Binding", fromBook(binding)},
+	{"BookCondition", func(res *enrich.Result) string { return abebooksConditions[condition(res)] }},
+	{"Price", price},
+	{"Quantity", listingQuantity},
+	{"ProductType", constant("Book")},
+	{"Pages", fromBook(pages)},
+	{"Keywords", fromBook(func(b *book.BookInfo) string { return strings.Join(b.Subjects, ", ") })},
+	{"Description", fromBook(func(b *book.BookInfo) string { return strings.Join(strings.Fields(b.Description), " ") })},
+}
@@ -0,0 +1,64 @@
+package profile
+
+import (
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+)
+
+// Condition is a book condition on the common scale used by the
+// marketplaces.
+type Condition int
+
+const (
+	UnknownCondition Condition = iota
+	New
+	LikeNew
+	VeryGood
+	Good
+	Acceptable
+	Poor
+)
+
+var conditionNames = [...]string{"unknown", "new", "like new", "very good", "good", "acceptable", "poor"}
+
+// String returns the name of c in lower case, e.g. "very good".
+func (c Condition) String() string {
+	if c < 0 || int(c) >= len(conditionNames) {
+		return conditionNames[UnknownCondition]
+	}
+	return conditionNames[c]
+}
+
+// conditionWords maps the wording of input files to conditions. Longer
+// phrases are matched first, so "very good" is not read as "good".
+var conditionWords = []struct {
+	word string
+	c    Condition
+}{
+	{"like new", LikeNew}, {"as new", LikeNew}, {"fine", LikeNew}, {"mint", LikeNew},
+	{"very good", VeryGood}, {"vg", VeryGood},
+	{"brand new", New}, {"new", New},
+	{"good", Good}, {"g", Good},
+	{"acceptable", Acceptable}, {"fair", Acceptable}, {"reading copy", Acceptable},
+	{"poor", Poor},
+}
+
+// ParseCondition reads a condition such as "Very Good", "VG+" or
+// "Like new (unread)". It returns UnknownCondition for empty or
+// unrecognized text.
+func ParseCondition(s string) Condition {
+	s = strings.ToLower(strings.TrimSpace(s))
+	s = strings.Trim(s, "+-")
+	for _, w := range conditionWords {
+		if s == w.word || strings.HasPrefix(s, w.word+" ") || (len(w.word) > 2 && strings.Contains(s, w.word)) {
+			return w.c
+		}
+	}
+	return UnknownCondition
+}
+
+// condition returns the input row's condition.
+func condition(res *enrich.Result) Condition {
+	return ParseCondition(res.Row.Condition)
+}
@@ -0,0 +1,57 @@
+package profile
+
+import (
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+)
+
+func init() {
+	Register(&Profile{
+		Name:    "ebay",
+		Summary: "eBay File Exchange listing upload",
+		Fields:  ebayFields,
+	})
+}
+
+// ebayCategory is eBay's "Books & Magazines > Books" category.
+const ebayCategory = "261186"
+
+// ebayConditions maps conditions to eBay's ConditionID values for books.
+var ebayConditions = map[Condition]string{
+	New:        "1000",
+	LikeNew:    "2750",
+	VeryGood:   "4000",
+	Good:       "5000",
+	Acceptable: "6000",
+	Poor:       "6000",
+}
+
+// ebayFields follow the File Exchange "Add" template for fixed-price
+// listings; item specifics use the C: prefix. Shipping and returns are
+// left to the seller's business policies.
+var ebayFields = []Field{
+	{"*Action(SiteID=US|Country=US|Currency=USD|Version=1193|CC=UTF-8)", constant("Add")},
+	{"CustomLabel", isbn13},
+	{"*Category", constant(ebayCategory)},
+	{"Product:ISBN", isbn13},
+	{"*Title", func(res *enrich.Result) string { return truncate(title(res), 80) }},
+	{"*ConditionID", func(res *enrich.Result) string { return ebayConditions[condition(res)] }},
+	{"ConditionDescription", func(res *enrich.Result) string { return res.Row.Condition }},
+	{"C:Book Title", fromBook(func(b *book.BookInfo) string { return truncate(b.Title, 65) })},
+	{"C:Author", fromBook(func(b *book.BookInfo) string { return first(b.Authors) })},
+	{"C:Publisher", fromBook(func(b *book.BookInfo) string { return first(b.Publishers) })},
+	{"C:Publication Year", fromBook(func(b *book.BookInfo) string { return b.Year() })},
+	{"C:Format", fromBook(binding)},
+	{"C:Number of Pages", fromBook(pages)},
+	{"PicURL", fromBook(func(b *book.BookInfo) string { return b.CoverURL })},
+	{"*Description", func(res *enrich.Result) string {
+		if res.Book != nil && res.Book.Description != "" {
+			return bodyHTML(res.Book.Description)
+		}
+		return bodyHTML(title(res))
+	}},
+	{"*Format", constant("FixedPrice")},
+	{"*Duration", constant("GTC")},
+	{"*StartPrice", price},
+	{"*Quantity", listingQuantity},
+}
@@ -0,0 +1,91 @@
+// Package profile defines export profiles: column layouts that match the
+// bulk-import templates of marketplaces and catalog tools, used instead
+// of the standard enrichment columns. Profiles register themselves from
+// init functions, like output formats.
+package profile
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+)
+
+// Field is one column of a profile. Value is called for failed rows too,
+// with res.Book nil.
+type Field struct {
+	Header string
+	Value  func(res *enrich.Result) string
+}
+
+// Profile is a named column layout. Unlike the standard layout, empty
+// values stay empty: import templates treat blank cells as "not set".
+type Profile struct {
+	Name    string
+	Summary string
+	Fields  []Field
+}
+
+// Header returns the column headers.
+func (p *Profile) Header() []string {
+	h := make([]string, len(p.Fields))
+	for i, f := range p.Fields {
+		h[i] = f.Header
+	}
+	return h
+}
+
+// Row returns the cells of an enrichment result.
+func (p *Profile) Row(res *enrich.Result) []string {
+	cells := make([]string, len(p.Fields))
+	for i, f := range p.Fields {
+		cells[i] = f.Value(res)
+	}
+	return cells
+}
+
+var (
+	mu       sync.RWMutex
+	profiles = make(map[string]*Profile)
+)
+
+// Register makes a profile available by name. It panics if the name is
+// already taken, like database/sql.Register.
+func Register(p *Profile) {
+	mu.Lock()
+	defer mu.Unlock()
+	name := strings.ToLower(p.Name)
+	if _, dup := profiles[name]; dup {
+		panic("profile: Register called twice for " + name)
+	}
+	profiles[name] = p
+}
+
+// Lookup returns the profile registered under name.
+func Lookup(name string) (*Profile, error) {
+	mu.RLock()
+	defer mu.RUnlock()
+	p, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(names(), ", "))
+	}
+	return p, nil
+}
+
+// Names returns the registered profile names, sorted.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	return names()
+}
+
+func names() []string {
+	names := make([]string, 0, len(profiles))
+	for n := range profiles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
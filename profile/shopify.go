@@ -0,0 +1,114 @@
+package profile
+
+import (
+	"html"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+)
+
+func init() {
+	Register(&Profile{
+		Name:    "shopify",
+		Summary: "Shopify product CSV import",
+		Fields:  shopifyFields,
+	})
+}
+
+// shopifyFields follow Shopify's product CSV template. Products are
+// created as drafts so they can be reviewed and priced before going on
+// sale.
+var shopifyFields = []Field{
+	{"Handle", handle},
+	{"Title", title},
+	{"Body (HTML)", fromBook(func(b *book.BookInfo) string { return bodyHTML(b.Description) })},
+	{"Vendor", fromBook(func(b *book.BookInfo) string { return first(b.Publishers) })},
+	{"Product Category", constant("Media > Books")},
+	{"Type", fromBook(func(b *book.BookInfo) string {
+		if b.Format == book.Ebook {
+			return "eBook"
+		}
+		return "Book"
+	})},
+	{"Tags", fromBook(func(b *book.BookInfo) string { return strings.Join(b.Subjects, ", ") })},
+	{"Published", constant("FALSE")},
+	{"Option1 Name", constant("Title")},
+	{"Option1 Value", constant("Default Title")},
+	{"Variant SKU", isbn13},
+	{"Variant Grams", fromBook(func(b *book.BookInfo) string {
+		if b.Weight <= 0 {
+			return ""
+		}
+		return strconv.Itoa(int(math.Round(float64(b.Weight))))
+	})},
+	{"Variant Inventory Tracker", func(res *enrich.Result) string {
+		if quantity(res) == "" {
+			return ""
+		}
+		return "shopify"
+	}},
+	{"Variant Inventory Qty", quantity},
+	{"Variant Inventory Policy", constant("deny")},
+	{"Variant Fulfillment Service", constant("manual")},
+	{"Variant Price", constant("")},
+	{"Variant Requires Shipping", fromBook(func(b *book.BookInfo) string {
+		return strings.ToUpper(strconv.FormatBool(b.Format != book.Ebook))
+	})},
+	{"Variant Taxable", constant("TRUE")},
+	{"Variant Barcode", isbn13},
+	{"Image Src", fromBook(func(b *book.BookInfo) string { return b.CoverURL })},
+	{"Image Position", fromBook(func(b *book.BookInfo) string {
+		if b.CoverURL == "" {
+			return ""
+		}
+		return "1"
+	})},
+	{"Image Alt Text", fromBook(func(b *book.BookInfo) string {
+		if b.CoverURL == "" {
+			return ""
+		}
+		return "Cover of " + b.FullTitle()
+	})},
+	{"Status", constant("draft")},
+}
+
+// handle builds a unique URL handle from the title and ISBN, e.g.
+// "the-hobbit-9780261103573".
+func handle(res *enrich.Result) string {
+	h := slug(title(res))
+	if code := isbn13(res); code != "" {
+		if h != "" {
+			h += "-"
+		}
+		h += code
+	}
+	if h == "" {
+		h = "row-" + strconv.Itoa(res.Row.Index+2)
+	}
+	return h
+}
+
+// slug lower-cases s and joins its words with hyphens.
+func slug(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return strings.Join(words, "-")
+}
+
+// bodyHTML renders a plain-text description as HTML paragraphs.
+func bodyHTML(s string) string {
+	var b strings.Builder
+	for _, p := range strings.Split(s, "\n\n") {
+		if p = strings.TrimSpace(p); p != "" {
+			b.WriteString("<p>")
+			b.WriteString(strings.ReplaceAll(html.EscapeString(p), "\n", "<br>"))
+			b.WriteString("</p>")
+		}
+	}
+	return b.String()
+}
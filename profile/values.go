@@ -0,0 +1,130 @@
+package profile
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+)
+
+// Value helpers shared by the profiles.
+
+// fromBook adapts a record value to a Field; failed rows yield "".
+func fromBook(f func(b *book.BookInfo) string) func(*enrich.Result) string {
+	return func(res *enrich.Result) string {
+		if res.Book == nil {
+			return ""
+		}
+		return f(res.Book)
+	}
+}
+
+// constant returns a Field value that is the same on every row.
+func constant(v string) func(*enrich.Result) string {
+	return func(*enrich.Result) string { return v }
+}
+
+// title returns the record's full title, or the input title of failed
+// rows.
+func title(res *enrich.Result) string {
+	if res.Book != nil && res.Book.Title != "" {
+		return res.Book.FullTitle()
+	}
+	return res.Row.Title
+}
+
+// isbn13 returns the record's ISBN-13, falling back to the input ISBN.
+func isbn13(res *enrich.Result) string {
+	if res.Book != nil && res.Book.ISBN13 != "" {
+		return res.Book.ISBN13
+	}
+	code := res.Row.ISBN
+	if res.Book != nil && res.Book.ISBN10 != "" {
+		code = res.Book.ISBN10
+	}
+	if code = isbn.Normalize(code); isbn.Valid(code) {
+		return isbn.To13(code)
+	}
+	return ""
+}
+
+// quantity returns the input quantity as a whole number, or "" when it
+// is missing or not a number.
+func quantity(res *enrich.Result) string {
+	n, err := strconv.Atoi(strings.TrimSpace(res.Row.Quantity))
+	if err != nil || n < 0 {
+		return ""
+	}
+	return strconv.Itoa(n)
+}
+
+func first(s []string) string {
+	if len(s) > 0 {
+		return s[0]
+	}
+	return ""
+}
+
+// listingQuantity returns the input quantity, defaulting to a single
+// copy.
+func listingQuantity(res *enrich.Result) string {
+	if q := quantity(res); q != "" {
+		return q
+	}
+	return "1"
+}
+
+// pages returns the page count, or "" when it is unknown.
+func pages(b *book.BookInfo) string {
+	if b.Pages <= 0 {
+		return ""
+	}
+	return strconv.Itoa(b.Pages)
+}
+
+// price returns the input price as a decimal number such as "12.50",
+// accepting currency symbols and decimal commas; "" when there is none.
+func price(res *enrich.Result) string {
+	s := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' || r == '.' || r == ',' {
+			return r
+		}
+		return -1
+	}, res.Row.Price)
+	if strings.Contains(s, ".") {
+		s = strings.ReplaceAll(s, ",", "") // thousands separators
+	} else {
+		s = strings.ReplaceAll(s, ",", ".")
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v < 0 {
+		return ""
+	}
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+// binding returns the record's format in words, e.g. "Hardcover".
+func binding(b *book.BookInfo) string {
+	switch b.Format {
+	case book.Hardcover:
+		return "Hardcover"
+	case book.Paperback:
+		return "Paperback"
+	}
+	return b.Binding
+}
+
+// truncate shortens s to at most n runes on a word boundary.
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	cut := string(r[:n])
+	if i := strings.LastIndex(cut, " "); i > n/2 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,;:-")
+}
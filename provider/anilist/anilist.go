@@ -0,0 +1,235 @@
+// Package anilist implements a provider for the AniList database of
+// manga, manhwa and light novels (https://anilist.co), free and without
+// a key. OpenLibrary knows little about manga beyond the title of each
+// volume; AniList adds the creators, genres and synopsis of the series.
+//
+// AniList describes series, not editions, and has no ISBNs. The provider
+// answers searches by series and volume, e.g. "Naruto, Vol. 3", and
+// implements provider.VolumeProvider so the enricher can ask it about
+// the series of books another provider found by ISBN.
+package anilist
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/series"
+)
+
+// Name is the provider name used in configuration and provenance.
+const Name = "anilist"
+
+// DefaultBaseURL is the AniList GraphQL endpoint.
+const DefaultBaseURL = "https://graphql.anilist.co"
+
+// searchQuery finds the manga and light novels matching $search. It is
+// sent as a GET request so responses are cached like those of the other
+// providers.
+const searchQuery = `query($search:String){Page(perPage:5){media(search:$search,type:MANGA,sort:SEARCH_MATCH){` +
+	`id title{romaji english native} synonyms format countryOfOrigin startDate{year} genres ` +
+	`description(asHtml:false) coverImage{extraLarge} ` +
+	`staff(sort:RELEVANCE,perPage:8){edges{role node{name{full}}}}}}}`
+
+// Media is a series of the search response.
+type Media struct {
+	ID    int `json:"id"`
+	Title struct {
+		Romaji  string `json:"romaji"`
+		English string `json:"english"`
+		Native  string `json:"native"`
+	} `json:"title"`
+	Synonyms        []string `json:"synonyms"`
+	Format          string   `json:"format"`
+	CountryOfOrigin string   `json:"countryOfOrigin"`
+	StartDate       struct {
+		Year int `json:"year"`
+	} `json:"startDate"`
+	Genres      []string `json:"genres"`
+	Description string   `json:"description"`
+	CoverImage  struct {
+		ExtraLarge string `json:"extraLarge"`
+	} `json:"coverImage"`
+	Staff struct {
+		Edges []struct {
+			Role string `json:"role"`
+			Node struct {
+				Name struct {
+					Full string `json:"full"`
+				} `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"staff"`
+}
+
+type searchResponse struct {
+	Data struct {
+		Page struct {
+			Media []Media `json:"media"`
+		} `json:"Page"`
+	} `json:"data"`
+}
+
+// creators are the staff roles credited as authors. Roles qualified by
+// a language, such as "Translator (English)", belong to one edition.
+var creators = map[string]bool{
+	"Story & Art":      true,
+	"Story":            true,
+	"Art":              true,
+	"Original Story":   true,
+	"Original Creator": true,
+}
+
+// forms names the kind of comic by the country it comes from.
+var forms = map[string]string{
+	"JP": "Manga",
+	"KR": "Manhwa",
+	"CN": "Manhua",
+	"TW": "Manhua",
+}
+
+// Name returns the series title, in English when AniList has one.
+func (m *Media) Name() string {
+	return cmp.Or(m.Title.English, m.Title.Romaji, m.Title.Native)
+}
+
+// Titles returns every title the series is known by.
+func (m *Media) Titles() []string {
+	return append([]string{m.Title.English, m.Title.Romaji, m.Title.Native}, m.Synonyms...)
+}
+
+// BookInfo maps volume of the series into the canonical record; volume
+// is "" for the series as a whole. The cover and start year of the
+// series are only kept for the series as a whole, since they are not
+// those of a volume.
+func (m *Media) BookInfo(volume string) *book.BookInfo {
+	name := m.Name()
+	out := &book.BookInfo{
+		Title:          name,
+		Series:         name,
+		SeriesPosition: volume,
+		Description:    book.PlainText(m.Description),
+		Source:         Name,
+	}
+	if volume != "" {
+		out.Title = name + ", Vol. " + volume
+	} else {
+		out.CoverURL = m.CoverImage.ExtraLarge
+		if m.StartDate.Year > 0 {
+			out.PublishDate = strconv.Itoa(m.StartDate.Year)
+		}
+	}
+	for _, e := range m.Staff.Edges {
+		if creators[strings.TrimSpace(e.Role)] && e.Node.Name.Full != "" {
+			out.Authors = append(out.Authors, e.Node.Name.Full)
+		}
+	}
+	switch {
+	case m.Format == "NOVEL":
+		out.Subjects = append(out.Subjects, "Light novels")
+	case forms[m.CountryOfOrigin] != "":
+		out.Subjects = append(out.Subjects, forms[m.CountryOfOrigin])
+	}
+	out.Subjects = append(out.Subjects, m.Genres...)
+	return out
+}
+
+// Client queries AniList. The zero value is ready to use.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+var (
+	_ provider.Provider       = (*Client)(nil)
+	_ provider.VolumeProvider = (*Client)(nil)
+)
+
+// Name implements provider.Provider.
+func (c *Client) Name() string { return Name }
+
+// LookupISBN implements provider.Provider. AniList has no ISBNs, so it
+// always returns provider.ErrNotFound; books are found by their series
+// instead, through Search and LookupVolume.
+func (c *Client) LookupISBN(context.Context, string) (*book.BookInfo, error) {
+	return nil, provider.ErrNotFound
+}
+
+// Search implements provider.Provider. The series and volume are parsed
+// from title, e.g. "Naruto, Vol. 3"; a title without a volume is taken
+// as the name of a series. AniList cannot search by author.
+func (c *Client) Search(ctx context.Context, title, author string) ([]*book.BookInfo, error) {
+	name, volume := strings.TrimSpace(title), ""
+	if in := series.FromTitle(title); in.Name != "" {
+		name, volume = in.Name, in.Position
+	}
+	media, err := c.search(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	var out []*book.BookInfo
+	for i := range media {
+		out = append(out, media[i].BookInfo(volume))
+	}
+	return out, nil
+}
+
+// LookupVolume implements provider.VolumeProvider. Only a series whose
+// title, in any language, matches name is taken, so books of series
+// AniList does not know are not matched to a similarly named manga.
+func (c *Client) LookupVolume(ctx context.Context, name, volume string) (*book.BookInfo, error) {
+	media, err := c.search(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	want := key(name)
+	for i := range media {
+		for _, t := range media[i].Titles() {
+			if t != "" && key(t) == want {
+				return media[i].BookInfo(volume), nil
+			}
+		}
+	}
+	return nil, provider.ErrNotFound
+}
+
+func (c *Client) search(ctx context.Context, name string) ([]Media, error) {
+	if name == "" {
+		return nil, provider.ErrNotFound
+	}
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	vars, err := json.Marshal(map[string]string{"search": name})
+	if err != nil {
+		return nil, err
+	}
+	q := url.Values{"query": {searchQuery}, "variables": {string(vars)}}
+	var resp searchResponse
+	if err := provider.GetJSON(ctx, c.HTTPClient, base+"?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Data.Page.Media) == 0 {
+		return nil, provider.ErrNotFound
+	}
+	return resp.Data.Page.Media, nil
+}
+
+// key reduces a title to its lower-case letters and digits, so "Fullmetal
+// Alchemist" matches "FullMetal Alchemist!".
+func key(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
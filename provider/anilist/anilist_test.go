@@ -0,0 +1,65 @@
+package anilist_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/SouadAli10/book_scrapping_tool/httpx/httpxtest"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/provider/anilist"
+)
+
+func TestSearch(t *testing.T) {
+	c := &anilist.Client{HTTPClient: httpxtest.Client(t, "testdata/search.json")}
+	bs, err := c.Search(context.Background(), "Naruto, Vol. 3", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bs) != 2 {
+		t.Fatalf("got %d results, want 2", len(bs))
+	}
+	b := bs[0]
+	for _, tc := range []struct{ field, got, want string }{
+		{"title", b.Title, "Naruto, Vol. 3"},
+		{"series", b.Series, "Naruto"},
+		{"series_position", b.SeriesPosition, "3"},
+		{"description", b.Description, "Before Naruto's birth, a great demon fox had attacked the Hidden Leaf Village.\n\n(Source: VIZ Media)"},
+		// The cover and start year are the series', not the volume's.
+		{"cover_url", b.CoverURL, ""},
+		{"publish_date", b.PublishDate, ""},
+		{"source", b.Source, anilist.Name},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.field, tc.got, tc.want)
+		}
+	}
+	if want := []string{"Masashi Kishimoto"}; !slices.Equal(b.Authors, want) {
+		t.Errorf("authors = %q, want %q", b.Authors, want)
+	}
+	if want := []string{"Manga", "Action", "Adventure"}; !slices.Equal(b.Subjects, want) {
+		t.Errorf("subjects = %q, want %q", b.Subjects, want)
+	}
+}
+
+func TestLookupVolume(t *testing.T) {
+	c := &anilist.Client{HTTPClient: httpxtest.Client(t, "testdata/search.json")}
+	b, err := c.LookupVolume(context.Background(), "Naruto", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Series != "Naruto" || b.PublishDate != "1999" || b.CoverURL == "" {
+		t.Errorf("got series %q, date %q, cover %q; want Naruto, 1999 and the cover", b.Series, b.PublishDate, b.CoverURL)
+	}
+	if _, err := c.LookupVolume(context.Background(), "Discworld", "4"); !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestLookupISBN(t *testing.T) {
+	c := &anilist.Client{HTTPClient: httpxtest.Client(t, "testdata/search.json")}
+	if _, err := c.LookupISBN(context.Background(), "9781569319406"); !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
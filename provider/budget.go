@@ -0,0 +1,91 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+)
+
+// ErrBudgetExhausted is returned by a budgeted provider once it has used
+// its allowance of calls.
+var ErrBudgetExhausted = errors.New("call budget exhausted")
+
+// Budgeted limits the network calls a provider makes in a run. Calls are
+// counted by the httpx.Counting middleware, so cached answers are free.
+// Each call takes its place in the budget before it is sent, so
+// concurrent lookups never make more than Max calls between them; a
+// lookup whose call is refused fails with ErrBudgetExhausted.
+type Budgeted struct {
+	Provider
+	Max     int64
+	counter httpx.Counter
+}
+
+// WithBudget wraps p so it stops making calls after max network requests.
+func WithBudget(p Provider, max int64) *Budgeted {
+	return &Budgeted{Provider: p, Max: max}
+}
+
+// Calls returns the network requests made so far.
+func (b *Budgeted) Calls() int64 { return b.counter.Load() }
+
+// Exhausted reports whether the budget is used up.
+func (b *Budgeted) Exhausted() bool { return b.counter.Load() >= b.Max }
+
+func (b *Budgeted) check(ctx context.Context) (context.Context, error) {
+	if b.Exhausted() {
+		return nil, b.exhausted()
+	}
+	return httpx.WithLimit(ctx, &b.counter, b.Max), nil
+}
+
+func (b *Budgeted) exhausted() error {
+	return fmt.Errorf("%w after %d calls", ErrBudgetExhausted, b.Max)
+}
+
+// done reports the calls refused by the counting middleware as an
+// exhausted budget.
+func (b *Budgeted) done(err error) error {
+	if errors.Is(err, httpx.ErrLimit) {
+		return b.exhausted()
+	}
+	return err
+}
+
+// LookupISBN implements Provider.
+func (b *Budgeted) LookupISBN(ctx context.Context, isbn string) (*book.BookInfo, error) {
+	ctx, err := b.check(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bi, err := b.Provider.LookupISBN(ctx, isbn)
+	return bi, b.done(err)
+}
+
+// Search implements Provider.
+func (b *Budgeted) Search(ctx context.Context, title, author string) ([]*book.BookInfo, error) {
+	ctx, err := b.check(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bs, err := b.Provider.Search(ctx, title, author)
+	return bs, b.done(err)
+}
+
+// LookupVolume implements VolumeProvider. It returns ErrNotFound when the
+// wrapped provider does not know series volumes.
+func (b *Budgeted) LookupVolume(ctx context.Context, series, volume string) (*book.BookInfo, error) {
+	vp, ok := b.Provider.(VolumeProvider)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	ctx, err := b.check(ctx)
+	if err != nil {
+		return nil, err
+	}
+	bi, err := vp.LookupVolume(ctx, series, volume)
+	return bi, b.done(err)
+}
@@ -0,0 +1,123 @@
+package provider
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// The flexible types below decode fields that providers serve with
+// inconsistent JSON types. They never fail: a value that cannot be
+// interpreted decodes as the zero value, so one odd field does not lose
+// the rest of the record.
+
+// String is a text field that may also arrive as a number or boolean,
+// e.g. "publish_date": 1997.
+type String string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *String) UnmarshalJSON(data []byte) error {
+	*s = String(scalarString(data))
+	return nil
+}
+
+// Int is a count that may also arrive as a float or a string such as
+// "320" or "320 p.".
+type Int int
+
+var digitsRe = regexp.MustCompile(`\d+`)
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *Int) UnmarshalJSON(data []byte) error {
+	*n = 0
+	var f float64
+	if err := json.Unmarshal(data, &f); err == nil {
+		if !math.IsInf(f, 0) && !math.IsNaN(f) && math.Abs(f) < math.MaxInt32 {
+			*n = Int(f)
+		}
+		return nil
+	}
+	if d := digitsRe.FindString(scalarString(data)); d != "" {
+		v, _ := strconv.Atoi(d)
+		*n = Int(v)
+	}
+	return nil
+}
+
+// Strings is a list of text values that may also arrive as a single
+// value. Elements that are not scalars are dropped.
+type Strings []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (s *Strings) UnmarshalJSON(data []byte) error {
+	*s = nil
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		if v := scalarString(data); v != "" {
+			*s = Strings{v}
+		}
+		return nil
+	}
+	for _, r := range raw {
+		if v := scalarString(r); v != "" {
+			*s = append(*s, v)
+		}
+	}
+	return nil
+}
+
+// List is a JSON array whose elements are decoded one by one; elements
+// that fail to decode are dropped instead of failing the whole array.
+type List[T any] []T
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (l *List[T]) UnmarshalJSON(data []byte) error {
+	*l = nil
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil
+	}
+	for _, r := range raw {
+		var v T
+		if err := json.Unmarshal(r, &v); tolerateTypes(err) == nil {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+// tolerateTypes drops JSON type mismatches inside a value. encoding/json
+// skips a field of the wrong type and still decodes the rest, so the
+// result is usable with that field left empty. A mismatch of the value
+// itself, such as an array where an object was expected, is kept.
+func tolerateTypes(err error) error {
+	var te *json.UnmarshalTypeError
+	if errors.As(err, &te) && te.Field != "" {
+		return nil
+	}
+	return err
+}
+
+// scalarString returns a JSON string, number or boolean as text, and ""
+// for anything else.
+func scalarString(data []byte) string {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return ""
+	}
+	switch data[0] {
+	case '"':
+		var s string
+		json.Unmarshal(data, &s)
+		return strings.TrimSpace(s)
+	case 't', 'f':
+		return string(data)
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return string(data)
+	}
+	return ""
+}
@@ -0,0 +1,81 @@
+package googlebooks
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// DefaultBaseURL is the public Google Books endpoint.
+const DefaultBaseURL = "https://www.googleapis.com/books/v1"
+
+// Client queries the Google Books volumes API. The zero value is usable;
+// an APIKey raises the anonymous quota.
+type Client struct {
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+var _ provider.Provider = (*Client)(nil)
+
+// Name implements provider.Provider.
+func (c *Client) Name() string { return Name }
+
+// Volumes runs a volumes query such as "isbn:9780261103573".
+func (c *Client) Volumes(ctx context.Context, query string) ([]Volume, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	q := url.Values{"q": {query}, "maxResults": {"10"}}
+	if c.APIKey != "" {
+		q.Set("key", c.APIKey)
+	}
+	var resp VolumesResponse
+	if err := provider.GetJSON(ctx, c.HTTPClient, base+"/volumes?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Items) == 0 {
+		return nil, provider.ErrNotFound
+	}
+	return resp.Items, nil
+}
+
+// LookupISBN implements provider.Provider.
+func (c *Client) LookupISBN(ctx context.Context, code string) (*book.BookInfo, error) {
+	vs, err := c.Volumes(ctx, "isbn:"+isbn.Normalize(code))
+	if err != nil {
+		return nil, err
+	}
+	return vs[0].BookInfo(), nil
+}
+
+// phrase quotes s as a phrase of a volumes query, so that a field
+// operator applies to all its words. Quotes inside s would end the
+// phrase and are dropped.
+func phrase(s string) string {
+	return `"` + strings.Join(strings.Fields(strings.ReplaceAll(s, `"`, " ")), " ") + `"`
+}
+
+// Search implements provider.Provider.
+func (c *Client) Search(ctx context.Context, title, author string) ([]*book.BookInfo, error) {
+	query := "intitle:" + phrase(title)
+	if author != "" {
+		query += " inauthor:" + phrase(author)
+	}
+	vs, err := c.Volumes(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*book.BookInfo, len(vs))
+	for i := range vs {
+		out[i] = vs[i].BookInfo()
+	}
+	return out, nil
+}
@@ -0,0 +1,62 @@
+package googlebooks_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/SouadAli10/book_scrapping_tool/httpx/httpxtest"
+	"github.com/SouadAli10/book_scrapping_tool/provider/googlebooks"
+)
+
+func TestLookupISBN(t *testing.T) {
+	c := &googlebooks.Client{HTTPClient: httpxtest.Client(t, "testdata/isbn.json")}
+	b, err := c.LookupISBN(context.Background(), "9780261103573")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct{ field, got, want string }{
+		{"isbn_13", b.ISBN13, "9780261103573"},
+		{"isbn_10", b.ISBN10, "0261103571"},
+		{"title", b.Title, "The Hobbit"},
+		{"subtitle", b.Subtitle, "Or There and Back Again"},
+		{"publish_date", b.PublishDate, "2012-02-15"},
+		{"google_volume_id", b.GoogleVolumeID, "pD6arNyKyi8C"},
+		{"source", b.Source, googlebooks.Name},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.field, tc.got, tc.want)
+		}
+	}
+	if want := []string{"J.R.R. Tolkien"}; !slices.Equal(b.Authors, want) {
+		t.Errorf("authors = %q, want %q", b.Authors, want)
+	}
+	if want := []string{"HarperCollins UK"}; !slices.Equal(b.Publishers, want) {
+		t.Errorf("publishers = %q, want %q", b.Publishers, want)
+	}
+	if b.Pages != 310 {
+		t.Errorf("pages = %d, want 310", b.Pages)
+	}
+}
+
+// TestSearchRequest checks the query sent for titles and authors with
+// query syntax, quotes, accents and other scripts.
+func TestSearchRequest(t *testing.T) {
+	hc, urls := httpxtest.Requests()
+	c := &googlebooks.Client{HTTPClient: hc}
+	for _, tc := range []struct{ title, author, want string }{
+		{"Tintin & Milou #1", "", "maxResults=10&q=intitle%3A%22Tintin+%26+Milou+%231%22"},
+		{"Les Misérables", "Victor Hugo", "maxResults=10&q=intitle%3A%22Les+Mis%C3%A9rables%22+inauthor%3A%22Victor+Hugo%22"},
+		{"ノルウェイの森", "村上春樹", "maxResults=10&q=intitle%3A%22%E3%83%8E%E3%83%AB%E3%82%A6%E3%82%A7%E3%82%A4%E3%81%AE%E6%A3%AE%22+inauthor%3A%22%E6%9D%91%E4%B8%8A%E6%98%A5%E6%A8%B9%22"},
+		{"مدن الملح", "عبد الرحمن منيف", "maxResults=10&q=intitle%3A%22%D9%85%D8%AF%D9%86+%D8%A7%D9%84%D9%85%D9%84%D8%AD%22+inauthor%3A%22%D8%B9%D8%A8%D8%AF+%D8%A7%D9%84%D8%B1%D8%AD%D9%85%D9%86+%D9%85%D9%86%D9%8A%D9%81%22"},
+		{"C++ Primer / 5th edition", "", "maxResults=10&q=intitle%3A%22C%2B%2B+Primer+%2F+5th+edition%22"},
+		{`The "Lord" of the Rings`, "", "maxResults=10&q=intitle%3A%22The+Lord+of+the+Rings%22"},
+		{"Who's Afraid? *^", "", "maxResults=10&q=intitle%3A%22Who%27s+Afraid%3F+%2A%5E%22"},
+	} {
+		c.Search(context.Background(), tc.title, tc.author)
+		sent := urls()
+		if got := sent[len(sent)-1]; got != "https://www.googleapis.com/books/v1/volumes?"+tc.want {
+			t.Errorf("search %q, %q: requested\n%s\nwant\n%s", tc.title, tc.author, got, "https://www.googleapis.com/books/v1/volumes?"+tc.want)
+		}
+	}
+}
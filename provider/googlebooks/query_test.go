@@ -0,0 +1,18 @@
+package googlebooks
+
+import "testing"
+
+func TestPhrase(t *testing.T) {
+	for _, tc := range []struct{ s, want string }{
+		{"Tintin & Milou #1", `"Tintin & Milou #1"`},
+		{"  Les   Misérables ", `"Les Misérables"`},
+		{"ノルウェイの森", `"ノルウェイの森"`},
+		{"C++ Primer / 5th edition", `"C++ Primer / 5th edition"`},
+		{`The "Lord" of the Rings`, `"The Lord of the Rings"`},
+		{`"`, `""`},
+	} {
+		if got := phrase(tc.s); got != tc.want {
+			t.Errorf("phrase(%q) = %s, want %s", tc.s, got, tc.want)
+		}
+	}
+}
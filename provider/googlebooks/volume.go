@@ -0,0 +1,104 @@
+// Package googlebooks decodes Google Books API volume responses and maps
+// them into the canonical book.BookInfo.
+package googlebooks
+
+import (
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/units"
+)
+
+// Name is the provider name used in configuration and provenance.
+const Name = "googlebooks"
+
+// VolumesResponse is the body of /books/v1/volumes.
+type VolumesResponse struct {
+	TotalItems int                   `json:"totalItems"`
+	Items      provider.List[Volume] `json:"items"`
+}
+
+// Volume is one Google Books volume.
+type Volume struct {
+	ID         string     `json:"id"`
+	VolumeInfo VolumeInfo `json:"volumeInfo"`
+	SaleInfo   struct {
+		IsEbook bool `json:"isEbook"`
+	} `json:"saleInfo"`
+}
+
+// Identifier is an entry of VolumeInfo.IndustryIdentifiers.
+type Identifier struct {
+	Type       string `json:"type"`
+	Identifier string `json:"identifier"`
+}
+
+// VolumeInfo holds the bibliographic part of a volume. Languages are ISO
+// 639-1 codes such as "en".
+type VolumeInfo struct {
+	Title               provider.String `json:"title"`
+	Subtitle            string          `json:"subtitle"`
+	Authors             []string        `json:"authors"`
+	Publisher           string          `json:"publisher"`
+	PublishedDate       provider.String `json:"publishedDate"`
+	IndustryIdentifiers []Identifier    `json:"industryIdentifiers"`
+	PageCount           provider.Int    `json:"pageCount"`
+	// Dimensions are strings such as "24.00 cm".
+	Dimensions struct {
+		Height    string `json:"height"`
+		Width     string `json:"width"`
+		Thickness string `json:"thickness"`
+	} `json:"dimensions"`
+	Categories []string `json:"categories"`
+	// Description may contain simple HTML markup.
+	Description string `json:"description"`
+	Language    string `json:"language"`
+	ImageLinks  struct {
+		SmallThumbnail string `json:"smallThumbnail"`
+		Thumbnail      string `json:"thumbnail"`
+	} `json:"imageLinks"`
+}
+
+// BookInfo maps v into the canonical record.
+func (v *Volume) BookInfo() *book.BookInfo {
+	vi := &v.VolumeInfo
+	b := &book.BookInfo{
+		Title:          string(vi.Title),
+		Subtitle:       vi.Subtitle,
+		Authors:        vi.Authors,
+		PublishDate:    string(vi.PublishedDate),
+		Pages:          int(vi.PageCount),
+		Subjects:       vi.Categories,
+		Description:    book.PlainText(vi.Description),
+		GoogleVolumeID: v.ID,
+		Source:         Name,
+	}
+	b.Dimensions.Height, _ = units.ParseLength(vi.Dimensions.Height)
+	b.Dimensions.Width, _ = units.ParseLength(vi.Dimensions.Width)
+	b.Dimensions.Thickness, _ = units.ParseLength(vi.Dimensions.Thickness)
+	// Google only distinguishes e-books; print bindings are unknown.
+	if v.SaleInfo.IsEbook {
+		b.Format, b.Binding = book.Ebook, "eBook"
+	}
+	if vi.Publisher != "" {
+		b.Publishers = []string{vi.Publisher}
+	}
+	if vi.Language != "" {
+		b.Languages = []string{vi.Language}
+	}
+	for _, id := range vi.IndustryIdentifiers {
+		switch id.Type {
+		case "ISBN_13":
+			b.ISBN13 = id.Identifier
+		case "ISBN_10":
+			b.ISBN10 = id.Identifier
+		}
+	}
+	thumb := vi.ImageLinks.Thumbnail
+	if thumb == "" {
+		thumb = vi.ImageLinks.SmallThumbnail
+	}
+	b.CoverURL = strings.Replace(thumb, "http://", "https://", 1)
+	return b
+}
@@ -0,0 +1,181 @@
+// Package isbndb implements a provider for the ISBNdb API
+// (https://isbndb.com/apidocs/v2). It requires a paid API key.
+package isbndb
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/units"
+)
+
+// Name is the provider name used in configuration and provenance.
+const Name = "isbndb"
+
+// DefaultBaseURL is the ISBNdb API endpoint.
+const DefaultBaseURL = "https://api2.isbndb.com"
+
+// Measure is a value with a unit, as in dimensions_structured.
+type Measure struct {
+	Unit  string  `json:"unit"`
+	Value float64 `json:"value"`
+}
+
+// Book is the book object of /book/{isbn} and /books/{query}.
+type Book struct {
+	Title         provider.String `json:"title"`
+	TitleLong     string          `json:"title_long"`
+	ISBN          string          `json:"isbn"`
+	ISBN13        string          `json:"isbn13"`
+	Publisher     string          `json:"publisher"`
+	Language      string          `json:"language"`
+	DatePublished provider.String `json:"date_published"`
+	Pages         provider.Int    `json:"pages"`
+	Binding       string          `json:"binding"`
+	Authors       []string        `json:"authors"`
+	Subjects      []string        `json:"subjects"`
+	Synopsis      string          `json:"synopsis"`
+	Image         string          `json:"image"`
+	// DimensionsStructured is present on most records; Dimensions is
+	// the older free-text form such as "Height: 9.21 Inches, ...".
+	DimensionsStructured struct {
+		Length Measure `json:"length"`
+		Width  Measure `json:"width"`
+		Height Measure `json:"height"`
+		Weight Measure `json:"weight"`
+	} `json:"dimensions_structured"`
+}
+
+type bookResponse struct {
+	Book Book `json:"book"`
+}
+
+type searchResponse struct {
+	Total int                 `json:"total"`
+	Books provider.List[Book] `json:"books"`
+}
+
+func length(m Measure) units.Length {
+	if m.Value <= 0 {
+		return 0
+	}
+	l, _ := units.ParseLength(formatMeasure(m))
+	return l
+}
+
+func weight(m Measure) units.Weight {
+	if m.Value <= 0 {
+		return 0
+	}
+	w, _ := units.ParseWeight(formatMeasure(m))
+	return w
+}
+
+func formatMeasure(m Measure) string {
+	return strings.TrimSpace(strconv.FormatFloat(m.Value, 'f', -1, 64) + " " + m.Unit)
+}
+
+// BookInfo maps b into the canonical record. ISBNdb's "length" is the
+// longest side of the book, i.e. its height; its "height" is the
+// thickness.
+func (b *Book) BookInfo() *book.BookInfo {
+	ds := b.DimensionsStructured
+	out := &book.BookInfo{
+		ISBN13:      b.ISBN13,
+		ISBN10:      b.ISBN,
+		Title:       string(b.Title),
+		Authors:     b.Authors,
+		PublishDate: string(b.DatePublished),
+		Pages:       int(b.Pages),
+		Subjects:    b.Subjects,
+		Description: book.PlainText(b.Synopsis),
+		CoverURL:    b.Image,
+		Dimensions: units.Dimensions{
+			Height:    length(ds.Length),
+			Width:     length(ds.Width),
+			Thickness: length(ds.Height),
+		},
+		Weight:  weight(ds.Weight),
+		Binding: b.Binding,
+		Format:  book.ParseFormat(b.Binding),
+		Source:  Name,
+	}
+	if b.Publisher != "" {
+		out.Publishers = []string{b.Publisher}
+	}
+	if b.Language != "" {
+		out.Languages = []string{b.Language}
+	}
+	return out
+}
+
+// Client queries ISBNdb.
+type Client struct {
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+var _ provider.Provider = (*Client)(nil)
+
+// Name implements provider.Provider.
+func (c *Client) Name() string { return Name }
+
+func (c *Client) get(ctx context.Context, path string, v any) error {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	// ISBNdb authenticates with a bare key in the Authorization header.
+	authed := *hc
+	authed.Transport = provider.WithHeader(hc.Transport, "Authorization", c.APIKey)
+	return provider.GetJSON(ctx, &authed, base+path, v)
+}
+
+// LookupISBN implements provider.Provider.
+func (c *Client) LookupISBN(ctx context.Context, code string) (*book.BookInfo, error) {
+	var resp bookResponse
+	if err := c.get(ctx, "/book/"+url.PathEscape(isbn.Normalize(code)), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Book.BookInfo(), nil
+}
+
+// pathQuery escapes a search query for the path of the search endpoint.
+// Slashes would split the path even escaped, as some proxies decode
+// them, so they become spaces; plus signs, which PathEscape keeps and
+// servers may read as spaces, are escaped.
+func pathQuery(query string) string {
+	return strings.ReplaceAll(url.PathEscape(strings.ReplaceAll(query, "/", " ")), "+", "%2B")
+}
+
+// Search implements provider.Provider.
+func (c *Client) Search(ctx context.Context, title, author string) ([]*book.BookInfo, error) {
+	q := url.Values{"pageSize": {"10"}, "column": {"title"}}
+	query := title
+	if author != "" {
+		query += " " + author
+	}
+	var resp searchResponse
+	if err := c.get(ctx, "/books/"+pathQuery(query)+"?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Books) == 0 {
+		return nil, provider.ErrNotFound
+	}
+	out := make([]*book.BookInfo, len(resp.Books))
+	for i := range resp.Books {
+		out[i] = resp.Books[i].BookInfo()
+	}
+	return out, nil
+}
@@ -0,0 +1,58 @@
+package isbndb_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/httpx/httpxtest"
+	"github.com/SouadAli10/book_scrapping_tool/provider/isbndb"
+)
+
+func TestLookupISBN(t *testing.T) {
+	c := &isbndb.Client{HTTPClient: httpxtest.Client(t, "testdata/isbn.json")}
+	b, err := c.LookupISBN(context.Background(), "9780261103573")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct{ field, got, want string }{
+		{"isbn_13", b.ISBN13, "9780261103573"},
+		{"title", b.Title, "The Hobbit"},
+		{"publish_date", b.PublishDate, "1997-06-02"},
+		{"format", string(b.Format), string(book.Paperback)},
+		{"source", b.Source, isbndb.Name},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.field, tc.got, tc.want)
+		}
+	}
+	if want := []string{"Tolkien, J. R. R."}; !slices.Equal(b.Authors, want) {
+		t.Errorf("authors = %q, want %q", b.Authors, want)
+	}
+	if w := float64(b.Weight); w < 199 || w > 200 {
+		t.Errorf("weight = %vg, want about 199.6g", w)
+	}
+}
+
+// TestSearchRequest checks the query sent for titles and authors with
+// query syntax, quotes, accents and other scripts.
+func TestSearchRequest(t *testing.T) {
+	hc, urls := httpxtest.Requests()
+	c := &isbndb.Client{APIKey: "test", HTTPClient: hc}
+	for _, tc := range []struct{ title, author, want string }{
+		{"Tintin & Milou #1", "", "Tintin%20&%20Milou%20%231?column=title&pageSize=10"},
+		{"Les Misérables", "Victor Hugo", "Les%20Mis%C3%A9rables%20Victor%20Hugo?column=title&pageSize=10"},
+		{"ノルウェイの森", "村上春樹", "%E3%83%8E%E3%83%AB%E3%82%A6%E3%82%A7%E3%82%A4%E3%81%AE%E6%A3%AE%20%E6%9D%91%E4%B8%8A%E6%98%A5%E6%A8%B9?column=title&pageSize=10"},
+		{"مدن الملح", "عبد الرحمن منيف", "%D9%85%D8%AF%D9%86%20%D8%A7%D9%84%D9%85%D9%84%D8%AD%20%D8%B9%D8%A8%D8%AF%20%D8%A7%D9%84%D8%B1%D8%AD%D9%85%D9%86%20%D9%85%D9%86%D9%8A%D9%81?column=title&pageSize=10"},
+		{"C++ Primer / 5th edition", "", "C%2B%2B%20Primer%20%20%205th%20edition?column=title&pageSize=10"},
+		{`The "Lord" of the Rings`, "", "The%20%22Lord%22%20of%20the%20Rings?column=title&pageSize=10"},
+		{"Who's Afraid? *^", "", "Who%27s%20Afraid%3F%20%2A%5E?column=title&pageSize=10"},
+	} {
+		c.Search(context.Background(), tc.title, tc.author)
+		sent := urls()
+		if got := sent[len(sent)-1]; got != "https://api2.isbndb.com/books/"+tc.want {
+			t.Errorf("search %q, %q: requested\n%s\nwant\n%s", tc.title, tc.author, got, "https://api2.isbndb.com/books/"+tc.want)
+		}
+	}
+}
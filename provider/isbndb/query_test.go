@@ -0,0 +1,18 @@
+package isbndb
+
+import "testing"
+
+func TestPathQuery(t *testing.T) {
+	for _, tc := range []struct{ query, want string }{
+		{"Tintin & Milou #1", "Tintin%20&%20Milou%20%231"},
+		{"Les Misérables Victor Hugo", "Les%20Mis%C3%A9rables%20Victor%20Hugo"},
+		{"ノルウェイの森", "%E3%83%8E%E3%83%AB%E3%82%A6%E3%82%A7%E3%82%A4%E3%81%AE%E6%A3%AE"},
+		{"مدن الملح", "%D9%85%D8%AF%D9%86%20%D8%A7%D9%84%D9%85%D9%84%D8%AD"},
+		{"C++ Primer / 5th edition", "C%2B%2B%20Primer%20%20%205th%20edition"},
+		{"100% Wolf?", "100%25%20Wolf%3F"},
+	} {
+		if got := pathQuery(tc.query); got != tc.want {
+			t.Errorf("pathQuery(%q) = %s, want %s", tc.query, got, tc.want)
+		}
+	}
+}
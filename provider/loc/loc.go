@@ -0,0 +1,205 @@
+// Package loc implements a provider for the Library of Congress catalog
+// through its SRU interface, which serves MODS records. The catalog is
+// the authoritative source for LC classifications and LCCNs.
+package loc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/country"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// Name is the provider name used in configuration and provenance.
+const Name = "loc"
+
+// DefaultBaseURL is the Library of Congress SRU endpoint.
+const DefaultBaseURL = "http://lx2.loc.gov:210/lcdb"
+
+// Response is an SRU searchRetrieve response carrying MODS records.
+type Response struct {
+	NumberOfRecords int    `xml:"numberOfRecords"`
+	Records         []MODS `xml:"records>record>recordData>mods"`
+}
+
+// Authority is an element value qualified by an authority or type
+// attribute.
+type Authority struct {
+	Authority string `xml:"authority,attr"`
+	Type      string `xml:"type,attr"`
+	Value     string `xml:",chardata"`
+}
+
+// MODS is the subset of a MODS record the provider maps.
+type MODS struct {
+	TitleInfo []struct {
+		Type     string `xml:"type,attr"`
+		NonSort  string `xml:"nonSort"`
+		Title    string `xml:"title"`
+		SubTitle string `xml:"subTitle"`
+	} `xml:"titleInfo"`
+	Names []struct {
+		Type      string   `xml:"type,attr"`
+		NameParts []string `xml:"namePart"`
+	} `xml:"name"`
+	OriginInfo struct {
+		Places     []Authority `xml:"place>placeTerm"`
+		Publishers []string    `xml:"publisher"`
+		DateIssued []string    `xml:"dateIssued"`
+	} `xml:"originInfo"`
+	Languages      []Authority `xml:"language>languageTerm"`
+	Extent         string      `xml:"physicalDescription>extent"`
+	Forms          []string    `xml:"physicalDescription>form"`
+	Topics         []string    `xml:"subject>topic"`
+	Classification []Authority `xml:"classification"`
+	Identifiers    []Authority `xml:"identifier"`
+}
+
+// BookInfo maps m into the canonical record.
+func (m *MODS) BookInfo() *book.BookInfo {
+	b := &book.BookInfo{Source: Name}
+	for _, t := range m.TitleInfo {
+		if t.Type == "" && b.Title == "" {
+			b.Title = strings.TrimRight(strings.TrimSpace(t.NonSort+t.Title), " /:")
+			b.Subtitle = strings.TrimRight(strings.TrimSpace(t.SubTitle), " /:")
+		}
+	}
+	for _, n := range m.Names {
+		if n.Type == "personal" && len(n.NameParts) > 0 {
+			b.Authors = append(b.Authors, strings.TrimRight(n.NameParts[0], " ,"))
+		}
+	}
+	for _, p := range m.OriginInfo.Places {
+		switch {
+		case p.Type == "text":
+			b.PublishPlaces = append(b.PublishPlaces, strings.Trim(p.Value, " :[]"))
+		case p.Authority == "marccountry" && b.PublishCountry == "":
+			b.PublishCountry = country.FromMARC(p.Value)
+		}
+	}
+	for _, p := range m.OriginInfo.Publishers {
+		b.Publishers = append(b.Publishers, strings.TrimRight(p, " ,"))
+	}
+	if len(m.OriginInfo.DateIssued) > 0 {
+		b.PublishDate = strings.Trim(m.OriginInfo.DateIssued[0], " .[]c©")
+	}
+	for _, l := range m.Languages {
+		if l.Authority == "iso639-2b" {
+			b.Languages = append(b.Languages, l.Value)
+		}
+	}
+	fmt.Sscanf(m.Extent, "%d", &b.Pages)
+	// MODS only tells print from electronic and sound recordings apart.
+	for _, f := range append(m.Forms, m.Extent) {
+		if pf := book.ParseFormat(f); pf == book.Ebook || pf == book.Audio {
+			b.Format, b.Binding = pf, f
+			break
+		}
+	}
+	b.Subjects = book.Union(nil, m.Topics)
+	for _, c := range m.Classification {
+		switch c.Authority {
+		case "lcc":
+			if b.LCC == "" {
+				b.LCC = strings.TrimSpace(c.Value)
+			}
+		case "ddc":
+			if b.DeweyDecimal == "" {
+				b.DeweyDecimal = strings.TrimSpace(c.Value)
+			}
+		}
+	}
+	for _, id := range m.Identifiers {
+		v := strings.TrimSpace(id.Value)
+		switch id.Type {
+		case "lccn":
+			if b.LCCN == "" {
+				b.LCCN = v
+			}
+		case "oclc":
+			if b.OCLC == "" {
+				b.OCLC = provider.OCLCNumber(v)
+			}
+		case "isbn":
+			// Values often carry a qualifier: "9780261103573 (pbk.)".
+			code := isbn.Normalize(strings.Fields(v + " ")[0])
+			switch {
+			case len(code) == 13 && b.ISBN13 == "":
+				b.ISBN13 = code
+			case len(code) == 10 && b.ISBN10 == "":
+				b.ISBN10 = code
+			}
+		}
+	}
+	return b
+}
+
+// Client queries the Library of Congress SRU endpoint.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+var _ provider.Provider = (*Client)(nil)
+
+// Name implements provider.Provider.
+func (c *Client) Name() string { return Name }
+
+func (c *Client) search(ctx context.Context, cql string, max int) ([]MODS, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	q := url.Values{
+		"version":        {"1.1"},
+		"operation":      {"searchRetrieve"},
+		"query":          {cql},
+		"recordSchema":   {"mods"},
+		"maximumRecords": {fmt.Sprint(max)},
+	}
+	var resp Response
+	if err := provider.GetXML(ctx, c.HTTPClient, base+"?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Records) == 0 {
+		return nil, provider.ErrNotFound
+	}
+	return resp.Records, nil
+}
+
+// cqlQuote quotes a CQL search term.
+func cqlQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// LookupISBN implements provider.Provider.
+func (c *Client) LookupISBN(ctx context.Context, code string) (*book.BookInfo, error) {
+	recs, err := c.search(ctx, "bath.isbn="+cqlQuote(isbn.Normalize(code)), 1)
+	if err != nil {
+		return nil, err
+	}
+	return recs[0].BookInfo(), nil
+}
+
+// Search implements provider.Provider.
+func (c *Client) Search(ctx context.Context, title, author string) ([]*book.BookInfo, error) {
+	cql := "dc.title=" + cqlQuote(title)
+	if author != "" {
+		cql += " and dc.creator=" + cqlQuote(author)
+	}
+	recs, err := c.search(ctx, cql, 10)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*book.BookInfo, len(recs))
+	for i := range recs {
+		out[i] = recs[i].BookInfo()
+	}
+	return out, nil
+}
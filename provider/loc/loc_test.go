@@ -0,0 +1,34 @@
+package loc_test
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/SouadAli10/book_scrapping_tool/httpx/httpxtest"
+	"github.com/SouadAli10/book_scrapping_tool/provider/loc"
+)
+
+func TestLookupISBN(t *testing.T) {
+	c := &loc.Client{HTTPClient: httpxtest.Client(t, "testdata/isbn.json")}
+	b, err := c.LookupISBN(context.Background(), "9780261103573")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct{ field, got, want string }{
+		{"isbn_10", b.ISBN10, "0261103571"},
+		{"title", b.Title, "The hobbit, or, There and back again"},
+		{"publish_country", b.PublishCountry, "GB"},
+		{"dewey_decimal", b.DeweyDecimal, "823/.912"},
+		{"lcc", b.LCC, "PR6039.O32 H6 1997"},
+		{"lccn", b.LCCN, "2002513593"},
+		{"source", b.Source, loc.Name},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.field, tc.got, tc.want)
+		}
+	}
+	if want := []string{"Middle Earth (Imaginary place)", "Fantasy fiction"}; !slices.Equal(b.Subjects, want) {
+		t.Errorf("subjects = %q, want %q", b.Subjects, want)
+	}
+}
@@ -0,0 +1,166 @@
+// Package mock implements a provider that invents plausible book records
+// without any network access, for demos and training. The same ISBN
+// always yields the same record.
+package mock
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/gtin"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/units"
+)
+
+// Name is the provider name used in configuration and provenance.
+const Name = "mock"
+
+// Client is the mock provider. It also invents records for the barcodes
+// of other items, as an item provider. ISBN-13s and barcodes ending in 0
+// are reported as not found, so demos show failed rows too.
+type Client struct{}
+
+// Name implements provider.Provider.
+func (*Client) Name() string { return Name }
+
+var (
+	adjectives = []string{"Silent", "Hidden", "Last", "Golden", "Forgotten", "Northern", "Broken", "Secret", "Distant", "Bright"}
+	nouns      = []string{"Garden", "River", "Library", "Harbour", "Map", "Orchard", "Lantern", "Voyage", "Archive", "Winter"}
+	subtitles  = []string{"", "A Novel", "", "Stories", "", "A History", "", "Poems"}
+	givenNames = []string{"Amira", "Jonas", "Leila", "Tomas", "Nadia", "Owen", "Yara", "Felix", "Hana", "Rami"}
+	surnames   = []string{"Haddad", "Lindqvist", "Moreau", "Okafor", "Brennan", "Sato", "Kowalski", "Aziz", "Fischer", "Quinn"}
+	// publishers[i] is based in places[i], in countries[i].
+	publishers = []string{"Demo House", "Sample & Sons", "Placeholder Press", "Example Books"}
+	places     = []string{"London", "New York", "Beirut", "Toronto"}
+	countries  = []string{"GB", "US", "LB", "CA"}
+	subjects   = []string{"Fiction", "History", "Travel", "Poetry", "Nature", "Biography", "Mystery", "Philosophy"}
+	formats    = []string{"Paperback", "Hardcover", "Paperback", "Mass Market Paperback"}
+	series     = []string{"The Lantern Cycle", "Harbour Tales"}
+	kinds      = []string{"dvd", "board game", "blu-ray", "audio cd"}
+	studios    = []string{"Demo Pictures", "Sample Games", "Placeholder Media"}
+	genres     = []string{"Family", "Strategy", "Documentary", "Drama", "Party"}
+)
+
+// seed is a deterministic pseudo-random stream derived from a key.
+type seed struct{ h uint64 }
+
+func newSeed(key string) *seed {
+	f := fnv.New64a()
+	f.Write([]byte(key))
+	return &seed{h: f.Sum64()}
+}
+
+// next returns a value in [0, n).
+func (s *seed) next(n int) int {
+	// xorshift64
+	s.h ^= s.h << 13
+	s.h ^= s.h >> 7
+	s.h ^= s.h << 17
+	return int(s.h % uint64(n))
+}
+
+func (s *seed) pick(list []string) string { return list[s.next(len(list))] }
+
+// LookupISBN implements provider.Provider.
+func (c *Client) LookupISBN(ctx context.Context, code string) (*book.BookInfo, error) {
+	code13 := isbn.To13(code)
+	if !isbn.Valid(code13) || strings.HasSuffix(code13, "0") {
+		return nil, provider.ErrNotFound
+	}
+	s := newSeed(code13)
+	title := "The " + s.pick(adjectives) + " " + s.pick(nouns)
+	author := s.pick(givenNames) + " " + s.pick(surnames)
+	return record(s, code13, title, author), nil
+}
+
+// Search implements provider.Provider. It returns a single record with
+// the title and author asked for.
+func (c *Client) Search(ctx context.Context, title, author string) ([]*book.BookInfo, error) {
+	if strings.TrimSpace(title) == "" {
+		return nil, provider.ErrNotFound
+	}
+	s := newSeed(strings.ToLower(title + "|" + author))
+	if author == "" {
+		author = s.pick(givenNames) + " " + s.pick(surnames)
+	}
+	return []*book.BookInfo{record(s, fakeISBN(s), title, author)}, nil
+}
+
+// LookupBarcode implements provider.ItemProvider.
+func (c *Client) LookupBarcode(ctx context.Context, code string) (*book.BookInfo, error) {
+	code = gtin.Normalize(code)
+	if !gtin.Valid(code) || strings.HasSuffix(code, "0") {
+		return nil, provider.ErrNotFound
+	}
+	s := newSeed(code)
+	kind := s.pick(kinds)
+	title := "The " + s.pick(adjectives) + " " + s.pick(nouns)
+	return &book.BookInfo{
+		GTIN:        code,
+		Kind:        kind,
+		Title:       title,
+		Publishers:  []string{s.pick(studios)},
+		PublishDate: strconv.Itoa(1980 + s.next(45)),
+		Subjects:    []string{s.pick(genres)},
+		Description: fmt.Sprintf("Demonstration record for the %s %q, generated by the mock provider.", kind, title),
+		Source:      Name,
+	}, nil
+}
+
+// fakeISBN returns a valid ISBN-13 in the 979-8 range.
+func fakeISBN(s *seed) string {
+	digits := "9798"
+	for len(digits) < 12 {
+		digits += strconv.Itoa(s.next(10))
+	}
+	sum := 0
+	for i, d := range digits {
+		w := 1
+		if i%2 == 1 {
+			w = 3
+		}
+		sum += int(d-'0') * w
+	}
+	return digits + strconv.Itoa((10-sum%10)%10)
+}
+
+func record(s *seed, code13, title, author string) *book.BookInfo {
+	binding := s.pick(formats)
+	year := 1950 + s.next(75)
+	b := &book.BookInfo{
+		ISBN13:       code13,
+		ISBN10:       isbn.To10(code13),
+		Title:        title,
+		Subtitle:     s.pick(subtitles),
+		Authors:      []string{author},
+		PublishDate:  strconv.Itoa(year),
+		Pages:        96 + s.next(600),
+		Binding:      binding,
+		Format:       book.ParseFormat(binding),
+		Languages:    []string{"eng"},
+		Subjects:     []string{s.pick(subjects), s.pick(subjects)},
+		DeweyDecimal: fmt.Sprintf("%03d.%d", s.next(1000), s.next(10)),
+		Description:  fmt.Sprintf("Demonstration record for %q by %s, generated by the mock provider.", title, author),
+		Source:       Name,
+	}
+	i := s.next(len(publishers))
+	b.Publishers, b.PublishPlaces, b.PublishCountry = []string{publishers[i]}, []string{places[i]}, countries[i]
+	if b.Subjects[0] == b.Subjects[1] {
+		b.Subjects = b.Subjects[:1]
+	}
+	b.Dimensions = units.Dimensions{
+		Height:    units.Length(18 + s.next(7)),
+		Width:     units.Length(11 + s.next(5)),
+		Thickness: units.Length(1 + float64(b.Pages)/250),
+	}
+	b.Weight = units.Weight(b.Pages) * 0.6
+	if s.next(4) == 0 {
+		b.Series, b.SeriesPosition = s.pick(series), strconv.Itoa(1+s.next(6))
+	}
+	return b
+}
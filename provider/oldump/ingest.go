@@ -0,0 +1,232 @@
+package oldump
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/provider/openlibrary"
+)
+
+// batchSize is the number of records written per transaction.
+const batchSize = 10000
+
+// Counts are the records an ingestion indexed.
+type Counts struct {
+	// Editions counts the editions with an ISBN, and ISBNs their
+	// ISBNs. Editions without one cannot be looked up and are skipped.
+	Editions int
+	ISBNs    int
+	Works    int
+	Authors  int
+	// Skipped counts the other records: editions without ISBN,
+	// redirects, deletions and the like.
+	Skipped int
+}
+
+// Ingest adds the records of an OpenLibrary dump read from r to the
+// index at path, created with the named database/sql driver if needed.
+// The dump is in the tab-separated format of the published dumps (type,
+// key, revision, last modified, JSON record), gzip-compressed or not;
+// the editions, works and authors dumps may be ingested in any order, or
+// the all-types dump at once. Records already indexed are replaced.
+// progress, when not nil, is called every batch.
+func Ingest(ctx context.Context, driver, path string, r io.Reader, progress func(Counts)) (Counts, error) {
+	var n Counts
+	br := bufio.NewReaderSize(r, 1<<20)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return n, fmt.Errorf("oldump: %w", err)
+		}
+		defer zr.Close()
+		br = bufio.NewReaderSize(zr, 1<<20)
+	}
+	db, err := sql.Open(driver, path)
+	if err != nil {
+		return n, fmt.Errorf("oldump: %w", err)
+	}
+	defer db.Close()
+	// Pragmas apply to a connection; bulk loading trades durability for
+	// speed, since an interrupted ingestion is simply run again.
+	db.SetMaxOpenConns(1)
+	for _, stmt := range []string{schema, `PRAGMA synchronous = OFF`, `PRAGMA journal_mode = MEMORY`} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return n, fmt.Errorf("oldump: %w", err)
+		}
+	}
+	w := &writer{db: db}
+	defer w.rollback()
+	pending := 0
+	for {
+		line, err := br.ReadBytes('\n')
+		if len(line) > 0 {
+			if err := w.begin(ctx); err != nil {
+				return n, err
+			}
+			if err := w.add(ctx, line, &n); err != nil {
+				return n, err
+			}
+			if pending++; pending == batchSize {
+				if err := w.commit(); err != nil {
+					return n, err
+				}
+				pending = 0
+				if progress != nil {
+					progress(n)
+				}
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return n, fmt.Errorf("oldump: %w", err)
+		}
+	}
+	if err := w.commit(); err != nil {
+		return n, err
+	}
+	if progress != nil {
+		progress(n)
+	}
+	return n, nil
+}
+
+// writer writes records in batches, each in a transaction.
+type writer struct {
+	db                          *sql.DB
+	tx                          *sql.Tx
+	edition, isbn, work, author *sql.Stmt
+}
+
+func (w *writer) begin(ctx context.Context) error {
+	if w.tx != nil {
+		return nil
+	}
+	tx, err := w.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("oldump: %w", err)
+	}
+	w.tx = tx
+	for _, s := range []struct {
+		stmt  **sql.Stmt
+		query string
+	}{
+		{&w.edition, `INSERT OR REPLACE INTO editions (key, work, data) VALUES (?, ?, ?)`},
+		{&w.isbn, `INSERT OR REPLACE INTO isbns (isbn, edition) VALUES (?, ?)`},
+		{&w.work, `INSERT OR REPLACE INTO works (key, data) VALUES (?, ?)`},
+		{&w.author, `INSERT OR REPLACE INTO authors (key, name) VALUES (?, ?)`},
+	} {
+		if *s.stmt, err = tx.PrepareContext(ctx, s.query); err != nil {
+			return fmt.Errorf("oldump: %w", err)
+		}
+	}
+	return nil
+}
+
+func (w *writer) commit() error {
+	if w.tx == nil {
+		return nil
+	}
+	err := w.tx.Commit()
+	w.tx = nil
+	if err != nil {
+		return fmt.Errorf("oldump: %w", err)
+	}
+	return nil
+}
+
+func (w *writer) rollback() {
+	if w.tx != nil {
+		w.tx.Rollback()
+	}
+}
+
+// add indexes one line of a dump.
+func (w *writer) add(ctx context.Context, line []byte, n *Counts) error {
+	cols := strings.SplitN(strings.TrimRight(string(line), "\r\n"), "\t", 5)
+	if len(cols) != 5 {
+		n.Skipped++
+		return nil
+	}
+	typ, key, data := cols[0], cols[1], []byte(cols[4])
+	switch typ {
+	case "/type/edition":
+		var e openlibrary.Edition
+		if err := json.Unmarshal(data, &e); err != nil {
+			return fmt.Errorf("oldump: edition %s: %w", key, err)
+		}
+		isbns := make(map[string]bool)
+		for _, code := range append(e.ISBN13, e.ISBN10...) {
+			if c := isbn.To13(code); isbn.Valid13(c) {
+				isbns[c] = true
+			}
+		}
+		if len(isbns) == 0 {
+			n.Skipped++
+			return nil
+		}
+		workKey := ""
+		if len(e.Works) > 0 {
+			workKey = e.Works[0].Key
+		}
+		compact, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		if _, err := w.edition.ExecContext(ctx, key, workKey, string(compact)); err != nil {
+			return fmt.Errorf("oldump: %w", err)
+		}
+		for c := range isbns {
+			if _, err := w.isbn.ExecContext(ctx, c, key); err != nil {
+				return fmt.Errorf("oldump: %w", err)
+			}
+		}
+		n.Editions++
+		n.ISBNs += len(isbns)
+	case "/type/work":
+		var wk work
+		if err := json.Unmarshal(data, &wk); err != nil {
+			return fmt.Errorf("oldump: work %s: %w", key, err)
+		}
+		compact, err := json.Marshal(wk)
+		if err != nil {
+			return err
+		}
+		if _, err := w.work.ExecContext(ctx, key, string(compact)); err != nil {
+			return fmt.Errorf("oldump: %w", err)
+		}
+		n.Works++
+	case "/type/author":
+		var a struct {
+			Name         string `json:"name"`
+			PersonalName string `json:"personal_name"`
+		}
+		if err := json.Unmarshal(data, &a); err != nil {
+			return fmt.Errorf("oldump: author %s: %w", key, err)
+		}
+		name := strings.TrimSpace(a.Name)
+		if name == "" {
+			name = strings.TrimSpace(a.PersonalName)
+		}
+		if name == "" {
+			n.Skipped++
+			return nil
+		}
+		if _, err := w.author.ExecContext(ctx, key, name); err != nil {
+			return fmt.Errorf("oldump: %w", err)
+		}
+		n.Authors++
+	default:
+		n.Skipped++
+	}
+	return nil
+}
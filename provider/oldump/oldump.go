@@ -0,0 +1,174 @@
+// Package oldump looks books up in a local index of the OpenLibrary bulk
+// data dumps, for lookups without network calls. Ingest builds the index
+// in an SQLite database from the editions, works and authors dumps;
+// Client answers ISBN lookups from it with the openlibrary mapping.
+package oldump
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"sync"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/provider/openlibrary"
+)
+
+// Name is the provider name used in configuration and provenance.
+const Name = "oldump"
+
+const schema = `CREATE TABLE IF NOT EXISTS editions (
+	key  TEXT PRIMARY KEY,
+	work TEXT NOT NULL DEFAULT '',
+	data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS isbns (
+	isbn    TEXT PRIMARY KEY,
+	edition TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS works (
+	key  TEXT PRIMARY KEY,
+	data TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS authors (
+	key  TEXT PRIMARY KEY,
+	name TEXT NOT NULL
+)`
+
+// work is what the index keeps of a work record.
+type work struct {
+	Description openlibrary.Text `json:"description,omitempty"`
+	Subjects    []string         `json:"subjects,omitempty"`
+	Covers      []int            `json:"covers,omitempty"`
+	Authors     []struct {
+		Author openlibrary.Key `json:"author"`
+	} `json:"authors,omitempty"`
+}
+
+// Client looks books up in the index at Path, opened read-only with the
+// named database/sql driver, which must be an SQLite driver accepting
+// URI file names. The index is opened on first use.
+type Client struct {
+	Driver string
+	Path   string
+
+	once sync.Once
+	db   *sql.DB
+	err  error
+}
+
+var _ provider.Provider = (*Client)(nil)
+
+// Name implements provider.Provider.
+func (c *Client) Name() string { return Name }
+
+func (c *Client) open() (*sql.DB, error) {
+	c.once.Do(func() {
+		abs, err := filepath.Abs(c.Path)
+		if err != nil {
+			c.err = err
+			return
+		}
+		dsn := (&url.URL{Scheme: "file", Path: filepath.ToSlash(abs), RawQuery: "mode=ro"}).String()
+		if c.db, c.err = sql.Open(c.Driver, dsn); c.err == nil {
+			if c.err = c.db.Ping(); c.err != nil {
+				c.db.Close()
+			}
+		}
+		if c.err != nil {
+			c.err = fmt.Errorf("oldump: open %s: %w", abs, c.err)
+		}
+	})
+	return c.db, c.err
+}
+
+// Close closes the index, if it was opened.
+func (c *Client) Close() error {
+	if c.db == nil {
+		return nil
+	}
+	return c.db.Close()
+}
+
+// LookupISBN implements provider.Provider. Author names come from the
+// authors dump, and the description from the work, when those dumps were
+// ingested.
+func (c *Client) LookupISBN(ctx context.Context, code string) (*book.BookInfo, error) {
+	db, err := c.open()
+	if err != nil {
+		return nil, err
+	}
+	n := isbn.To13(code)
+	if !isbn.Valid13(n) {
+		return nil, provider.ErrNotFound
+	}
+	var data, workKey string
+	err = db.QueryRowContext(ctx,
+		`SELECT e.data, e.work FROM isbns i JOIN editions e ON e.key = i.edition WHERE i.isbn = ?`, n).Scan(&data, &workKey)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, provider.ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("oldump: %w", err)
+	}
+	var e openlibrary.Edition
+	if err := json.Unmarshal([]byte(data), &e); err != nil {
+		return nil, fmt.Errorf("oldump: edition of %s: %w", n, err)
+	}
+	b := e.BookInfo()
+	b.Source = Name
+	if b.ISBN13 == "" {
+		b.ISBN13 = n
+	}
+	var w *work
+	if workKey != "" {
+		err := db.QueryRowContext(ctx, `SELECT data FROM works WHERE key = ?`, workKey).Scan(&data)
+		switch {
+		case err == nil:
+			w = new(work)
+			if err := json.Unmarshal([]byte(data), w); err != nil {
+				return nil, fmt.Errorf("oldump: work %s: %w", workKey, err)
+			}
+		case !errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("oldump: %w", err)
+		}
+	}
+	authors := e.Authors
+	if len(authors) == 0 && w != nil {
+		for _, a := range w.Authors {
+			authors = append(authors, a.Author)
+		}
+	}
+	for _, k := range authors {
+		var name string
+		err := db.QueryRowContext(ctx, `SELECT name FROM authors WHERE key = ?`, k.Key).Scan(&name)
+		switch {
+		case err == nil:
+			b.Authors = append(b.Authors, name)
+		case !errors.Is(err, sql.ErrNoRows):
+			return nil, fmt.Errorf("oldump: %w", err)
+		}
+	}
+	if w != nil {
+		b.Description = w.Description.String()
+		if len(b.Subjects) == 0 {
+			b.Subjects = w.Subjects
+		}
+		if b.CoverURL == "" && len(w.Covers) > 0 && w.Covers[0] > 0 {
+			b.CoverURL = openlibrary.CoverURL(w.Covers[0])
+		}
+	}
+	return b, nil
+}
+
+// Search implements provider.Provider. The index is keyed by ISBN, so
+// searches find nothing and are left to the other providers.
+func (c *Client) Search(ctx context.Context, title, author string) ([]*book.BookInfo, error) {
+	return nil, provider.ErrNotFound
+}
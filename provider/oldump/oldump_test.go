@@ -0,0 +1,71 @@
+//go:build sqlite
+
+package oldump_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/provider/oldump"
+)
+
+func TestIngestAndLookup(t *testing.T) {
+	ctx := context.Background()
+	index := filepath.Join(t.TempDir(), "openlibrary.db")
+	for _, tc := range []struct {
+		file string
+		want oldump.Counts
+	}{
+		{"testdata/editions.txt.gz", oldump.Counts{Editions: 1, ISBNs: 1, Skipped: 2}},
+		{"testdata/works_authors.txt", oldump.Counts{Works: 1, Authors: 1}},
+	} {
+		f, err := os.Open(tc.file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		n, err := oldump.Ingest(ctx, "sqlite", index, f, nil)
+		f.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != tc.want {
+			t.Errorf("%s: counts = %+v, want %+v", tc.file, n, tc.want)
+		}
+	}
+
+	c := &oldump.Client{Driver: "sqlite", Path: index}
+	defer c.Close()
+	b, err := c.LookupISBN(ctx, "0140328726")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct{ field, got, want string }{
+		{"isbn_13", b.ISBN13, "9780140328721"},
+		{"title", b.Title, "Fantastic Mr Fox"},
+		{"ol_work_id", b.OLWorkID, "OL45W"},
+		{"source", b.Source, oldump.Name},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.field, tc.got, tc.want)
+		}
+	}
+	if want := []string{"Roald Dahl"}; !slices.Equal(b.Authors, want) {
+		t.Errorf("authors = %q, want %q", b.Authors, want)
+	}
+	if want := []string{"Foxes"}; !slices.Equal(b.Subjects, want) {
+		t.Errorf("subjects = %q, want %q", b.Subjects, want)
+	}
+	if b.Description == "" {
+		t.Error("description from the work is missing")
+	}
+	if _, err := c.LookupISBN(ctx, "9780000000002"); !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("unknown ISBN: err = %v, want ErrNotFound", err)
+	}
+}
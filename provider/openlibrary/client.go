@@ -0,0 +1,181 @@
+package openlibrary
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// DefaultBaseURL is the public OpenLibrary endpoint.
+const DefaultBaseURL = "https://openlibrary.org"
+
+// Client queries the OpenLibrary APIs. The zero value is usable.
+type Client struct {
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+var _ provider.Provider = (*Client)(nil)
+
+// Name implements provider.Provider.
+func (c *Client) Name() string { return Name }
+
+func (c *Client) baseURL() string {
+	if c.BaseURL != "" {
+		return c.BaseURL
+	}
+	return DefaultBaseURL
+}
+
+func (c *Client) get(ctx context.Context, path string, q url.Values, v any) error {
+	u := c.baseURL() + path
+	if len(q) > 0 {
+		u += "?" + q.Encode()
+	}
+	return provider.GetJSON(ctx, c.HTTPClient, u, v)
+}
+
+// Edition fetches the edition record for an ISBN.
+func (c *Client) Edition(ctx context.Context, code string) (*Edition, error) {
+	var e Edition
+	if err := c.get(ctx, "/isbn/"+url.PathEscape(isbn.Normalize(code))+".json", nil, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// Data fetches the data API record for an ISBN.
+func (c *Client) Data(ctx context.Context, code string) (*DataRecord, error) {
+	key := "ISBN:" + isbn.Normalize(code)
+	q := url.Values{"bibkeys": {key}, "jscmd": {"data"}, "format": {"json"}}
+	var resp DataResponse
+	if err := c.get(ctx, "/api/books", q, &resp); err != nil {
+		return nil, err
+	}
+	r, ok := resp[key]
+	if !ok {
+		return nil, provider.ErrNotFound
+	}
+	return &r, nil
+}
+
+// LookupISBN implements provider.Provider. It combines the data API,
+// which resolves author names, with the edition record, which carries
+// the languages, and the work record, which carries the description.
+// The edition and work are fetched alongside the data API call.
+func (c *Client) LookupISBN(ctx context.Context, code string) (*book.BookInfo, error) {
+	var (
+		wg sync.WaitGroup
+		e  *Edition
+		w  *Work
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		var err error
+		if e, err = c.Edition(ctx, code); err == nil && len(e.Works) > 0 {
+			w, _ = c.Work(ctx, e.Works[0].ID())
+		}
+	}()
+	r, err := c.Data(ctx, code)
+	wg.Wait()
+	if err != nil {
+		return nil, err
+	}
+	b := r.BookInfo()
+	if e != nil {
+		b.Fill(e.BookInfo())
+	}
+	if w != nil {
+		b.Description = w.Description.String()
+	}
+	return b, nil
+}
+
+// Work fetches a work record by its ID, e.g. "OL27479W".
+func (c *Client) Work(ctx context.Context, id string) (*Work, error) {
+	var w Work
+	if err := c.get(ctx, "/works/"+url.PathEscape(id)+".json", nil, &w); err != nil {
+		return nil, err
+	}
+	return &w, nil
+}
+
+// editionsPage is the number of editions asked for per request.
+const editionsPage = 100
+
+// MaxWorkEditions caps the editions WorkEditions fetches, and so its
+// requests to ten pages: classics have thousands of editions.
+const MaxWorkEditions = 10 * editionsPage
+
+// WorkEditions fetches the edition records of a work, e.g. "OL27479W",
+// up to limit of them, or up to MaxWorkEditions when limit is zero.
+// Works with more editions than fit in one response are read page by
+// page, following the offset of the "next" link.
+func (c *Client) WorkEditions(ctx context.Context, id string, limit int) ([]Edition, error) {
+	if limit <= 0 || limit > MaxWorkEditions {
+		limit = MaxWorkEditions
+	}
+	var eds []Edition
+	offset := ""
+	for {
+		n := min(editionsPage, limit-len(eds))
+		q := url.Values{"limit": {strconv.Itoa(n)}}
+		if offset != "" {
+			q.Set("offset", offset)
+		}
+		var resp struct {
+			Links struct {
+				Next string `json:"next"`
+			} `json:"links"`
+			Entries []Edition `json:"entries"`
+		}
+		if err := c.get(ctx, "/works/"+url.PathEscape(id)+"/editions.json", q, &resp); err != nil {
+			return nil, err
+		}
+		eds = append(eds, resp.Entries...)
+		if len(resp.Entries) == 0 || len(eds) >= limit {
+			return eds, nil
+		}
+		next, err := url.Parse(resp.Links.Next)
+		if err != nil || next.Query().Get("offset") == "" {
+			return eds, nil
+		}
+		offset = next.Query().Get("offset")
+	}
+}
+
+// SearchDocs runs a title/author search and returns the raw results.
+func (c *Client) SearchDocs(ctx context.Context, title, author string) ([]SearchDoc, error) {
+	q := url.Values{"title": {title}, "limit": {"10"}}
+	if author != "" {
+		q.Set("author", author)
+	}
+	var resp SearchResponse
+	if err := c.get(ctx, "/search.json", q, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Docs, nil
+}
+
+// Search implements provider.Provider.
+func (c *Client) Search(ctx context.Context, title, author string) ([]*book.BookInfo, error) {
+	docs, err := c.SearchDocs(ctx, title, author)
+	if err != nil {
+		return nil, err
+	}
+	if len(docs) == 0 {
+		return nil, provider.ErrNotFound
+	}
+	out := make([]*book.BookInfo, len(docs))
+	for i := range docs {
+		out[i] = docs[i].BookInfo()
+	}
+	return out, nil
+}
@@ -0,0 +1,103 @@
+package openlibrary
+
+import (
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// Named is a {"name": ..., "url": ...} object as used by the data API.
+type Named struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// DataResponse is the body of /api/books?jscmd=data&format=json, keyed by
+// the requested bibkey (e.g. "ISBN:9780261103573").
+type DataResponse map[string]DataRecord
+
+// DataRecord is one entry of a DataResponse.
+type DataRecord struct {
+	Key           string          `json:"key"`
+	Title         provider.String `json:"title"`
+	Subtitle      string          `json:"subtitle"`
+	Authors       []Named         `json:"authors"`
+	PublishDate   provider.String `json:"publish_date"`
+	Publishers    []Named         `json:"publishers"`
+	PublishPlaces []Named         `json:"publish_places"`
+	Pages         provider.Int    `json:"number_of_pages"`
+	Subjects      []Named         `json:"subjects"`
+	Identifiers   struct {
+		ISBN10 []string `json:"isbn_10"`
+		ISBN13 []string `json:"isbn_13"`
+		LCCN   []string `json:"lccn"`
+		OCLC   []string `json:"oclc"`
+	} `json:"identifiers"`
+	Classifications struct {
+		Dewey []string `json:"dewey_decimal_class"`
+		LCC   []string `json:"lc_classifications"`
+	} `json:"classifications"`
+	Cover struct {
+		Small  string `json:"small"`
+		Medium string `json:"medium"`
+		Large  string `json:"large"`
+	} `json:"cover"`
+}
+
+// BookInfo maps r into the canonical record. The data API does not
+// report languages; callers merge them from the edition record.
+func (r *DataRecord) BookInfo() *book.BookInfo {
+	b := &book.BookInfo{
+		Title:         string(r.Title),
+		Subtitle:      r.Subtitle,
+		Authors:       names(r.Authors),
+		PublishDate:   string(r.PublishDate),
+		Publishers:    names(r.Publishers),
+		PublishPlaces: names(r.PublishPlaces),
+		Pages:         int(r.Pages),
+		Subjects:      names(r.Subjects),
+		CoverURL:      firstNonEmpty(r.Cover.Large, r.Cover.Medium, r.Cover.Small),
+		Source:        Name,
+	}
+	if len(r.Identifiers.ISBN13) > 0 {
+		b.ISBN13 = r.Identifiers.ISBN13[0]
+	}
+	if len(r.Identifiers.ISBN10) > 0 {
+		b.ISBN10 = r.Identifiers.ISBN10[0]
+	}
+	b.LCCN = first(r.Identifiers.LCCN)
+	b.OCLC = first(r.Identifiers.OCLC)
+	b.OLEditionID = Key{r.Key}.ID()
+	b.DeweyDecimal = first(r.Classifications.Dewey)
+	b.LCC = first(r.Classifications.LCC)
+	return b
+}
+
+func names(ns []Named) []string {
+	var out []string
+	for _, n := range ns {
+		if n.Name != "" {
+			out = append(out, n.Name)
+		}
+	}
+	return out
+}
+
+func firstNonEmpty(vs ...string) string {
+	for _, v := range vs {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func first(vs []string) string {
+	for _, v := range vs {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
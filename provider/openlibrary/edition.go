@@ -0,0 +1,137 @@
+// Package openlibrary decodes OpenLibrary API responses and maps them into
+// the canonical book.BookInfo.
+package openlibrary
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/country"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/units"
+)
+
+// Key is OpenLibrary's reference object, e.g. {"key": "/languages/eng"}.
+type Key struct {
+	Key string `json:"key"`
+}
+
+// ID returns the last path segment of the key, e.g. "eng" for
+// "/languages/eng" or "OL23919A" for "/authors/OL23919A".
+func (k Key) ID() string {
+	return k.Key[strings.LastIndex(k.Key, "/")+1:]
+}
+
+// Edition is the edition record served by /isbn/{isbn}.json and
+// /books/{olid}.json.
+type Edition struct {
+	Key           string           `json:"key"`
+	Title         provider.String  `json:"title"`
+	Subtitle      string           `json:"subtitle"`
+	ISBN10        provider.Strings `json:"isbn_10"`
+	ISBN13        provider.Strings `json:"isbn_13"`
+	PublishDate   provider.String  `json:"publish_date"`
+	Publishers    provider.Strings `json:"publishers"`
+	PublishPlaces []string         `json:"publish_places"`
+	// PublishCountry is a MARC country code such as "nyu" or "enk".
+	PublishCountry string       `json:"publish_country"`
+	Pages          provider.Int `json:"number_of_pages"`
+	Languages      []Key        `json:"languages"`
+	Authors        []Key        `json:"authors"`
+	Works          []Key        `json:"works"`
+	Subjects       []string     `json:"subjects"`
+	// Series holds free-text series statements such as "Discworld ; 1".
+	Series []string `json:"series"`
+	// PhysicalFormat is free text such as "Paperback" or "E-book".
+	PhysicalFormat string `json:"physical_format"`
+	// EditionName is free text such as "5th ed." or "International
+	// edition".
+	EditionName string `json:"edition_name"`
+	// PhysicalDimensions is free text such as "24 x 16 x 3 centimeters".
+	PhysicalDimensions string `json:"physical_dimensions"`
+	// Weight is free text such as "1.2 pounds".
+	Weight string   `json:"weight"`
+	Dewey  []string `json:"dewey_decimal_class"`
+	LCC    []string `json:"lc_classifications"`
+	LCCN   []string `json:"lccn"`
+	OCLC   []string `json:"oclc_numbers"`
+	Covers []int    `json:"covers"`
+}
+
+// LanguageCodes extracts the ISO 639-2 codes from the edition's language
+// references.
+func (e *Edition) LanguageCodes() []string {
+	return languageCodes(e.Languages)
+}
+
+func languageCodes(keys []Key) []string {
+	var codes []string
+	for _, k := range keys {
+		if id := k.ID(); id != "" {
+			codes = append(codes, id)
+		}
+	}
+	return codes
+}
+
+// BookInfo maps e into the canonical record. Author references are left
+// unresolved; the caller fills in names when it has fetched them.
+func (e *Edition) BookInfo() *book.BookInfo {
+	b := &book.BookInfo{
+		Title:          string(e.Title),
+		Subtitle:       e.Subtitle,
+		PublishDate:    string(e.PublishDate),
+		Publishers:     e.Publishers,
+		PublishPlaces:  e.PublishPlaces,
+		PublishCountry: country.FromMARC(e.PublishCountry),
+		Pages:          int(e.Pages),
+		Languages:      e.LanguageCodes(),
+		Subjects:       e.Subjects,
+		Source:         Name,
+	}
+	if len(e.ISBN13) > 0 {
+		b.ISBN13 = e.ISBN13[0]
+	}
+	if len(e.ISBN10) > 0 {
+		b.ISBN10 = e.ISBN10[0]
+	}
+	b.Binding, b.Format = e.PhysicalFormat, book.ParseFormat(e.PhysicalFormat)
+	b.Dimensions, _ = units.ParseDimensions(e.PhysicalDimensions)
+	b.Weight, _ = units.ParseWeight(e.Weight)
+	b.DeweyDecimal, b.LCC, b.LCCN = first(e.Dewey), first(e.LCC), first(e.LCCN)
+	b.OCLC = first(e.OCLC)
+	b.OLEditionID = Key{e.Key}.ID()
+	if len(e.Works) > 0 {
+		b.OLWorkID = e.Works[0].ID()
+	}
+	if len(e.Series) > 0 {
+		b.Series, b.SeriesPosition = ParseSeries(e.Series[0])
+	}
+	if len(e.Covers) > 0 && e.Covers[0] > 0 {
+		b.CoverURL = CoverURL(e.Covers[0])
+	}
+	return b
+}
+
+// Name is the provider name used in configuration and provenance.
+const Name = "openlibrary"
+
+// CoverURL returns the large cover image URL for an OpenLibrary cover ID.
+func CoverURL(id int) string {
+	return "https://covers.openlibrary.org/b/id/" + strconv.Itoa(id) + "-L.jpg"
+}
+
+var seriesPosRe = regexp.MustCompile(`^(.*?)\s*(?:[;,]|--|\(|\bno\.|\bvol(?:ume)?\.?|\bbook|#)\s*#?\s*([0-9]+(?:\.[0-9]+)?)\)?\.?$`)
+
+// ParseSeries splits an OpenLibrary series statement such as
+// "Discworld ; 1", "Harry Potter (3)" or "Penguin classics" into a name
+// and position.
+func ParseSeries(s string) (name, position string) {
+	s = strings.TrimSpace(s)
+	if m := seriesPosRe.FindStringSubmatch(s); m != nil && m[1] != "" {
+		return strings.TrimRight(m[1], " ,;"), m[2]
+	}
+	return strings.TrimRight(s, " .;,"), ""
+}
@@ -0,0 +1,210 @@
+package openlibrary_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/httpx/httpxtest"
+	"github.com/SouadAli10/book_scrapping_tool/provider/openlibrary"
+)
+
+func TestLookupISBN(t *testing.T) {
+	c := &openlibrary.Client{HTTPClient: httpxtest.Client(t, "testdata/isbn.json")}
+	b, err := c.LookupISBN(context.Background(), "978-0-261-10357-3")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct{ field, got, want string }{
+		{"isbn_13", b.ISBN13, "9780261103573"},
+		{"isbn_10", b.ISBN10, "0261103571"},
+		{"title", b.Title, "The Hobbit"},
+		{"publish_date", b.PublishDate, "1997"},
+		{"format", string(b.Format), string(book.Paperback)},
+		{"lcc", b.LCC, "PR6039.O32 H6 1997"},
+		{"lccn", b.LCCN, "2002513593"},
+		{"series", b.Series, "Tolkien paperbacks"},
+		{"ol_work_id", b.OLWorkID, "OL262758W"},
+		{"ol_edition_id", b.OLEditionID, "OL7353617M"},
+		{"source", b.Source, openlibrary.Name},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.field, tc.got, tc.want)
+		}
+	}
+	if want := []string{"J. R. R. Tolkien"}; !slices.Equal(b.Authors, want) {
+		t.Errorf("authors = %q, want %q", b.Authors, want)
+	}
+	if b.Pages != 310 {
+		t.Errorf("pages = %d, want 310", b.Pages)
+	}
+	if b.Description == "" {
+		t.Error("description missing; it comes from the work record")
+	}
+}
+
+// TestLookupISBNOddTypes covers records whose fields come in unexpected
+// types and a work that no longer exists.
+func TestLookupISBNOddTypes(t *testing.T) {
+	c := &openlibrary.Client{HTTPClient: httpxtest.Client(t, "testdata/odd-types.json")}
+	b, err := c.LookupISBN(context.Background(), "9780451524935")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Title != "1984" || b.Pages != 328 || b.PublishDate != "1961" {
+		t.Errorf("got title %q, %d pages, published %q; want 1984, 328, 1961", b.Title, b.Pages, b.PublishDate)
+	}
+	if want := []string{"Signet Classic"}; !slices.Equal(b.Publishers, want) {
+		t.Errorf("publishers = %q, want %q", b.Publishers, want)
+	}
+}
+
+func TestSearch(t *testing.T) {
+	c := &openlibrary.Client{HTTPClient: httpxtest.Client(t, "testdata/search.json")}
+	results, err := c.Search(context.Background(), "The Hobbit", "Tolkien")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want 2", len(results))
+	}
+	if got := results[0]; got.ISBN13 != "9780261103573" || got.Title != "The Hobbit" {
+		t.Errorf("first result = %s %q, want 9780261103573 \"The Hobbit\"", got.ISBN13, got.Title)
+	}
+	if !results[0].WorkLevel {
+		t.Error("search result not marked as a work")
+	}
+	if got := results[1].Title; got != "The Hobbit Companion" {
+		t.Errorf("second result = %q, want \"The Hobbit Companion\"", got)
+	}
+}
+
+// TestSearchDocISBNs checks that both ISBNs of a search result are
+// those of one edition, whatever the order of the work's ISBN list.
+func TestSearchDocISBNs(t *testing.T) {
+	for _, tc := range []struct {
+		isbns          []string
+		isbn13, isbn10 string
+	}{
+		{[]string{"0618260307", "9780261103573", "9780007136599"}, "9780618260300", "0618260307"},
+		{[]string{"9780261103573", "0618260307"}, "9780261103573", "0261103571"},
+		{[]string{"9791032305690", "0618260307"}, "9791032305690", ""},
+		{[]string{"123", "9780261103573"}, "9780261103573", "0261103571"},
+		{nil, "", ""},
+	} {
+		d := openlibrary.SearchDoc{Key: "/works/OL27482W", Title: "The Hobbit", ISBN: tc.isbns, EditionKey: []string{"OL1M", "OL2M", "OL3M"}}
+		b := d.BookInfo()
+		if b.ISBN13 != tc.isbn13 || b.ISBN10 != tc.isbn10 || !b.WorkLevel {
+			t.Errorf("%q: isbn_13 %q, isbn_10 %q, work level %v; want %q, %q, true", tc.isbns, b.ISBN13, b.ISBN10, b.WorkLevel, tc.isbn13, tc.isbn10)
+		}
+	}
+}
+
+func TestWorkEditions(t *testing.T) {
+	c := &openlibrary.Client{HTTPClient: httpxtest.Client(t, "testdata/editions.json")}
+	eds, err := c.WorkEditions(context.Background(), "OL15815337W", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eds) != 5 {
+		t.Fatalf("got %d editions, want 5", len(eds))
+	}
+	e := eds[1]
+	if e.EditionName != "Eighth edition" || e.PhysicalFormat != "Paperback" || !slices.Equal(e.ISBN13, []string{"9781305272378"}) {
+		t.Errorf("got edition %q, format %q, ISBNs %q; want Eighth edition, Paperback, 9781305272378", e.EditionName, e.PhysicalFormat, e.ISBN13)
+	}
+}
+
+// TestWorkEditionsPages follows the "next" links of a work whose
+// editions span several pages, served by editionPages.
+func TestWorkEditionsPages(t *testing.T) {
+	for _, tc := range []struct {
+		total, limit int
+		want         int
+		requests     []string
+	}{
+		{250, 100, 100, []string{"limit=100"}},
+		{250, 150, 150, []string{"limit=100", "limit=50&offset=100"}},
+		{250, 0, 250, []string{"limit=100", "limit=100&offset=100", "limit=100&offset=200"}},
+		{40, 0, 40, []string{"limit=100"}},
+		{5000, 0, openlibrary.MaxWorkEditions, nil},
+	} {
+		srv, requests := editionPages(t, tc.total)
+		c := &openlibrary.Client{BaseURL: srv.URL, HTTPClient: srv.Client()}
+		eds, err := c.WorkEditions(context.Background(), "OL1W", tc.limit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(eds) != tc.want || eds[len(eds)-1].Key != "/books/OL"+strconv.Itoa(tc.want)+"M" {
+			t.Errorf("%d editions, limit %d: got %d, last %s; want %d", tc.total, tc.limit, len(eds), eds[len(eds)-1].Key, tc.want)
+		}
+		got := requests()
+		if tc.requests == nil && len(got) != openlibrary.MaxWorkEditions/100 {
+			t.Errorf("%d editions, limit %d: %d requests, want %d", tc.total, tc.limit, len(got), openlibrary.MaxWorkEditions/100)
+		}
+		if tc.requests != nil && !slices.Equal(got, tc.requests) {
+			t.Errorf("%d editions, limit %d: requests %q, want %q", tc.total, tc.limit, got, tc.requests)
+		}
+	}
+}
+
+// editionPages serves total editions of work OL1W, keyed OL1M to OL<total>M,
+// in pages linked like OpenLibrary's, and returns the queries it got.
+func editionPages(t *testing.T, total int) (*httptest.Server, func() []string) {
+	var (
+		mu      sync.Mutex
+		queries []string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		queries = append(queries, r.URL.RawQuery)
+		mu.Unlock()
+		q := r.URL.Query()
+		limit, _ := strconv.Atoi(q.Get("limit"))
+		offset, _ := strconv.Atoi(q.Get("offset"))
+		end := min(offset+limit, total)
+		links := map[string]string{"self": r.URL.RequestURI(), "work": "/works/OL1W"}
+		if end < total {
+			links["next"] = "/works/OL1W/editions.json?offset=" + strconv.Itoa(end)
+		}
+		var entries []map[string]string
+		for i := offset; i < end; i++ {
+			entries = append(entries, map[string]string{"key": "/books/OL" + strconv.Itoa(i+1) + "M"})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"links": links, "size": total, "entries": entries})
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return queries
+	}
+}
+
+// TestSearchRequest checks the query sent for titles and authors with
+// query syntax, quotes, accents and other scripts.
+func TestSearchRequest(t *testing.T) {
+	hc, urls := httpxtest.Requests()
+	c := &openlibrary.Client{HTTPClient: hc}
+	for _, tc := range []struct{ title, author, want string }{
+		{"Tintin & Milou #1", "", "/search.json?limit=10&title=Tintin+%26+Milou+%231"},
+		{"Les Misérables", "Victor Hugo", "/search.json?author=Victor+Hugo&limit=10&title=Les+Mis%C3%A9rables"},
+		{"ノルウェイの森", "村上春樹", "/search.json?author=%E6%9D%91%E4%B8%8A%E6%98%A5%E6%A8%B9&limit=10&title=%E3%83%8E%E3%83%AB%E3%82%A6%E3%82%A7%E3%82%A4%E3%81%AE%E6%A3%AE"},
+		{"مدن الملح", "عبد الرحمن منيف", "/search.json?author=%D8%B9%D8%A8%D8%AF+%D8%A7%D9%84%D8%B1%D8%AD%D9%85%D9%86+%D9%85%D9%86%D9%8A%D9%81&limit=10&title=%D9%85%D8%AF%D9%86+%D8%A7%D9%84%D9%85%D9%84%D8%AD"},
+		{"C++ Primer / 5th edition", "", "/search.json?limit=10&title=C%2B%2B+Primer+%2F+5th+edition"},
+		{`The "Lord" of the Rings`, "", "/search.json?limit=10&title=The+%22Lord%22+of+the+Rings"},
+		{"Who's Afraid? *^", "", "/search.json?limit=10&title=Who%27s+Afraid%3F+%2A%5E"},
+	} {
+		c.Search(context.Background(), tc.title, tc.author)
+		sent := urls()
+		if got := sent[len(sent)-1]; got != openlibrary.DefaultBaseURL+tc.want {
+			t.Errorf("search %q, %q: requested\n%s\nwant\n%s", tc.title, tc.author, got, openlibrary.DefaultBaseURL+tc.want)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package openlibrary
+
+import (
+	"strconv"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// SearchResponse is the body of /search.json.
+type SearchResponse struct {
+	NumFound int                      `json:"numFound"`
+	Docs     provider.List[SearchDoc] `json:"docs"`
+}
+
+// SearchDoc is one work-level result of the search API. Unlike the
+// edition record, languages are bare ISO 639-2 codes here.
+type SearchDoc struct {
+	Key              string          `json:"key"`
+	Title            provider.String `json:"title"`
+	Subtitle         string          `json:"subtitle"`
+	AuthorName       []string        `json:"author_name"`
+	FirstPublishYear provider.Int    `json:"first_publish_year"`
+	Publisher        []string        `json:"publisher"`
+	PublishPlace     []string        `json:"publish_place"`
+	ISBN             []string        `json:"isbn"`
+	Language         []string        `json:"language"`
+	PagesMedian      provider.Int    `json:"number_of_pages_median"`
+	Subject          []string        `json:"subject"`
+	CoverID          int             `json:"cover_i"`
+	EditionKey       []string        `json:"edition_key"`
+}
+
+// BookInfo maps d into the canonical record of the work. Its ISBNs are
+// those of one edition, the first with a valid ISBN, since the ISBN
+// list of a work mixes the ISBNs of all its editions in no order.
+func (d *SearchDoc) BookInfo() *book.BookInfo {
+	b := &book.BookInfo{
+		Title:         string(d.Title),
+		Subtitle:      d.Subtitle,
+		Authors:       d.AuthorName,
+		Publishers:    d.Publisher,
+		PublishPlaces: d.PublishPlace,
+		Pages:         int(d.PagesMedian),
+		Languages:     d.Language,
+		Subjects:      d.Subject,
+		Source:        Name,
+		WorkLevel:     true,
+	}
+	// Search results are works; the edition is not known.
+	b.OLWorkID = Key{d.Key}.ID()
+	if d.FirstPublishYear > 0 {
+		b.PublishDate = strconv.Itoa(int(d.FirstPublishYear))
+	}
+	for _, s := range d.ISBN {
+		if isbn.Valid(s) {
+			b.ISBN13, b.ISBN10 = isbn.To13(s), isbn.To10(s)
+			break
+		}
+	}
+	if d.CoverID > 0 {
+		b.CoverURL = CoverURL(d.CoverID)
+	}
+	if n := len(d.EditionKey); n > 1 {
+		b.Warn("merged from %d related editions", n)
+	}
+	return b
+}
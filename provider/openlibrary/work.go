@@ -0,0 +1,43 @@
+package openlibrary
+
+import (
+	"encoding/json"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+)
+
+// Text is a free-text field that OpenLibrary serves either as a plain
+// string or as {"type": "/type/text", "value": "..."}.
+type Text string
+
+// UnmarshalJSON accepts both representations.
+func (t *Text) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*t = Text(s)
+		return nil
+	}
+	var obj struct {
+		Value string `json:"value"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+	*t = Text(obj.Value)
+	return nil
+}
+
+// String returns the text with markup removed.
+func (t Text) String() string {
+	return book.PlainText(string(t))
+}
+
+// Work is the work record served by /works/{id}.json. Descriptions are
+// usually attached to the work rather than to individual editions.
+type Work struct {
+	Key         string   `json:"key"`
+	Title       string   `json:"title"`
+	Description Text     `json:"description"`
+	Subjects    []string `json:"subjects"`
+	Covers      []int    `json:"covers"`
+}
@@ -0,0 +1,168 @@
+// Package provider defines the interface implemented by book metadata
+// sources and the HTTP helpers they share.
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+)
+
+// ErrNotFound is returned when a provider has no record for a lookup.
+var ErrNotFound = errors.New("not found")
+
+// Provider is a source of book metadata.
+type Provider interface {
+	// Name returns the provider's configuration name, e.g. "openlibrary".
+	Name() string
+	// LookupISBN fetches the record for an ISBN-10 or ISBN-13.
+	LookupISBN(ctx context.Context, isbn string) (*book.BookInfo, error)
+	// Search returns candidate records for a title and optional author,
+	// best matches first.
+	Search(ctx context.Context, title, author string) ([]*book.BookInfo, error)
+}
+
+// VolumeProvider is implemented by providers that know the volumes of
+// series rather than ISBNs, such as comics and manga databases. The
+// enricher asks them for the volume once another provider has found the
+// book and its series.
+type VolumeProvider interface {
+	// LookupVolume fetches volume of series; volume is the position in
+	// the series, or "" for the series as a whole.
+	LookupVolume(ctx context.Context, series, volume string) (*book.BookInfo, error)
+}
+
+// ItemProvider is a source of records for items other than books, such
+// as DVDs and board games, looked up by their product barcode. The
+// records use the book fields that apply and set Kind and GTIN.
+type ItemProvider interface {
+	// Name returns the provider's configuration name.
+	Name() string
+	// LookupBarcode fetches the record for a UPC-A, EAN-8, EAN-13 or
+	// GTIN-14.
+	LookupBarcode(ctx context.Context, gtin string) (*book.BookInfo, error)
+}
+
+// UserAgent is sent with every provider request.
+const UserAgent = "booktool (+https://github.com/SouadAli10/book_scrapping_tool)"
+
+// StatusError is returned for unexpected HTTP responses.
+type StatusError struct {
+	URL        string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("GET %s: %s", e.URL, http.StatusText(e.StatusCode))
+}
+
+// GetJSON fetches url with c and decodes the JSON body into v. A 404
+// response is reported as ErrNotFound. Fields of an unexpected type are
+// left empty rather than failing the lookup; see the flexible types in
+// flex.go for fields that are known to vary.
+func GetJSON(ctx context.Context, c *http.Client, url string, v any) error {
+	return get(ctx, c, url, "application/json", func(r io.Reader) error {
+		return tolerateTypes(json.NewDecoder(r).Decode(v))
+	})
+}
+
+// GetXML is like GetJSON for XML responses.
+func GetXML(ctx context.Context, c *http.Client, url string, v any) error {
+	return get(ctx, c, url, "application/xml", func(r io.Reader) error {
+		return xml.NewDecoder(r).Decode(v)
+	})
+}
+
+func get(ctx context.Context, c *http.Client, url, accept string, decode func(io.Reader) error) error {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", accept)
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		io.Copy(io.Discard, resp.Body)
+		return ErrNotFound
+	case resp.StatusCode != http.StatusOK:
+		io.Copy(io.Discard, resp.Body)
+		return &StatusError{URL: url, StatusCode: resp.StatusCode}
+	}
+	if err := decode(resp.Body); err != nil {
+		return fmt.Errorf("decode %s: %w", url, err)
+	}
+	return nil
+}
+
+// WithHeader returns a transport that sets header key to value on every
+// request before passing it to rt (http.DefaultTransport when nil).
+func WithHeader(rt http.RoundTripper, key, value string) http.RoundTripper {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	return headerTransport{rt: rt, key: key, value: value}
+}
+
+type headerTransport struct {
+	rt         http.RoundTripper
+	key, value string
+}
+
+func (t headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set(t.key, t.value)
+	return t.rt.RoundTrip(req)
+}
+
+// Ping checks that the server behind url answers HTTP. Any response,
+// including an error status, counts as reachable; only network failures
+// are reported.
+func Ping(ctx context.Context, c *http.Client, url string) error {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// OCLCNumber returns the digits of an OCLC number as catalogs write it,
+// e.g. "(OCoLC)12345678", "ocm12345678" or "ocn123456789", or "" when s
+// is not one.
+func OCLCNumber(s string) string {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "(OCoLC)")
+	for _, prefix := range []string{"ocm", "ocn", "on"} {
+		if rest, ok := strings.CutPrefix(s, prefix); ok {
+			s = rest
+			break
+		}
+	}
+	s = strings.TrimLeft(s, "0")
+	if s == "" || strings.Trim(s, "0123456789") != "" {
+		return ""
+	}
+	return s
+}
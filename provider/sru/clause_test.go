@@ -0,0 +1,25 @@
+package sru
+
+import "testing"
+
+func TestClause(t *testing.T) {
+	for _, tc := range []struct {
+		catalog *Catalog
+		s, want string
+	}{
+		{DNB, "Tintin & Milou #1", `tit = "Tintin & Milou #1"`},
+		{DNB, "Les Misérables", `tit = "Les Misérables"`},
+		{DNB, "ノルウェイの森", `tit = "ノルウェイの森"`},
+		{DNB, `Le "Petit" Prince`, `tit = "Le \"Petit\" Prince"`},
+		{DNB, `C:\Users`, `tit = "C:\\Users"`},
+		{DNB, "Who's Afraid of Virginia Woolf?", `tit = "Who's Afraid of Virginia Woolf\?"`},
+		{DNB, "Harry Pott*", `tit = "Harry Pott\*"`},
+		{DNB, "^The End", `tit = "\^The End"`},
+		{BnF, "C++ Primer / 5th edition", `bib.title all "C++ Primer / 5th edition"`},
+	} {
+		c := &Client{Catalog: tc.catalog}
+		if got := c.clause(tc.catalog.Title, tc.s); got != tc.want {
+			t.Errorf("clause(%q) = %s, want %s", tc.s, got, tc.want)
+		}
+	}
+}
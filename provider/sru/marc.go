@@ -0,0 +1,271 @@
+package sru
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/country"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// Record is a MARC record in MARCXML or MARCXchange, the XML packings
+// of MARC 21 and UNIMARC, which share their structure.
+type Record struct {
+	ControlFields []struct {
+		Tag   string `xml:"tag,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"controlfield"`
+	DataFields []DataField `xml:"datafield"`
+}
+
+// DataField is a variable data field of a Record.
+type DataField struct {
+	Tag       string `xml:"tag,attr"`
+	Subfields []struct {
+		Code  string `xml:"code,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"subfield"`
+}
+
+// Sub returns the first subfield code of f, or "".
+func (f *DataField) Sub(code string) string {
+	for _, s := range f.Subfields {
+		if s.Code == code {
+			return strings.TrimSpace(s.Value)
+		}
+	}
+	return ""
+}
+
+// Control returns the control field tag of r, or "".
+func (r *Record) Control(tag string) string {
+	for _, c := range r.ControlFields {
+		if c.Tag == tag {
+			return c.Value
+		}
+	}
+	return ""
+}
+
+// Fields returns the data fields of r with one of tags, in record order.
+func (r *Record) Fields(tags ...string) []*DataField {
+	var out []*DataField
+	for i := range r.DataFields {
+		for _, t := range tags {
+			if r.DataFields[i].Tag == t {
+				out = append(out, &r.DataFields[i])
+				break
+			}
+		}
+	}
+	return out
+}
+
+// first returns subfield code of the first field of r with one of tags
+// that has it, or "".
+func (r *Record) first(code string, tags ...string) string {
+	for _, f := range r.Fields(tags...) {
+		if v := f.Sub(code); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// oclc returns the OCLC number among the system control numbers of r,
+// in 035 in both formats: "(OCoLC)12345678", or "".
+func (r *Record) oclc() string {
+	for _, f := range r.Fields("035") {
+		if v := f.Sub("a"); strings.HasPrefix(v, "(OCoLC)") {
+			return provider.OCLCNumber(v)
+		}
+	}
+	return ""
+}
+
+// Format is a MARC format: the meaning of the fields of its records.
+type Format func(r *Record, source string) *book.BookInfo
+
+// trim removes the ISBD punctuation ending MARC subfields.
+func trim(s string) string {
+	return strings.TrimRight(strings.TrimSpace(s), " /:;,=.")
+}
+
+// addISBN fills the ISBN of b from an ISBN subfield, whose value often
+// carries a qualifier: "978-3-446-23050-1 (Gb.)".
+func addISBN(b *book.BookInfo, v string) {
+	fields := strings.Fields(v)
+	if len(fields) == 0 {
+		return
+	}
+	code := isbn.Normalize(fields[0])
+	switch {
+	case len(code) == 13 && b.ISBN13 == "":
+		b.ISBN13 = code
+	case len(code) == 10 && b.ISBN10 == "":
+		b.ISBN10 = code
+	}
+}
+
+// MARC21 maps MARC 21 records, as the British Library and the Deutsche
+// Nationalbibliothek catalog them.
+func MARC21(r *Record, source string) *book.BookInfo {
+	b := &book.BookInfo{Source: source}
+	for _, f := range r.Fields("020") {
+		addISBN(b, f.Sub("a"))
+	}
+	if f := r.Fields("245"); len(f) > 0 {
+		b.Title = trim(f[0].Sub("a"))
+		b.Subtitle = trim(f[0].Sub("b"))
+	}
+	for _, f := range r.Fields("100", "700") {
+		if a := trim(f.Sub("a")); a != "" {
+			b.Authors = append(b.Authors, a)
+		}
+	}
+	// RDA records publish in 264, older ones in 260.
+	for _, f := range r.Fields("264", "260") {
+		if p := strings.Trim(trim(f.Sub("a")), "[]"); p != "" {
+			b.PublishPlaces = append(b.PublishPlaces, p)
+		}
+		if p := trim(f.Sub("b")); p != "" {
+			b.Publishers = append(b.Publishers, p)
+		}
+		if d := f.Sub("c"); d != "" && b.PublishDate == "" {
+			b.PublishDate = date(d)
+		}
+		if len(b.Publishers) > 0 {
+			break
+		}
+	}
+	// 008 holds the country of publication at 15-17 and the language
+	// at 35-37.
+	if f := r.Control("008"); len(f) >= 38 {
+		b.PublishCountry = country.FromMARC(strings.TrimSpace(f[15:18]))
+		if l := strings.TrimSpace(f[35:38]); l != "" && l != "|||" {
+			b.Languages = append(b.Languages, l)
+		}
+	}
+	for _, f := range r.Fields("041") {
+		for _, s := range f.Subfields {
+			if s.Code == "a" {
+				b.Languages = book.Union(b.Languages, []string{strings.TrimSpace(s.Value)})
+			}
+		}
+	}
+	if f := r.Fields("300"); len(f) > 0 {
+		b.Pages = pages(f[0].Sub("a"))
+	}
+	var topics []string
+	for _, f := range r.Fields("650", "689") {
+		topics = append(topics, trim(f.Sub("a")))
+	}
+	b.Subjects = book.Union(nil, topics)
+	b.DeweyDecimal = r.first("a", "082")
+	if f := r.Fields("050"); len(f) > 0 {
+		b.LCC = strings.TrimSpace(f[0].Sub("a") + " " + f[0].Sub("b"))
+	}
+	b.LCCN = r.first("a", "010")
+	b.OCLC = r.oclc()
+	if f := r.Fields("490", "830"); len(f) > 0 {
+		b.Series = trim(f[0].Sub("a"))
+		b.SeriesPosition = trim(f[0].Sub("v"))
+	}
+	return b
+}
+
+// UNIMARC maps UNIMARC records, as the Bibliothèque nationale de France
+// catalogs them.
+func UNIMARC(r *Record, source string) *book.BookInfo {
+	b := &book.BookInfo{Source: source}
+	for _, f := range r.Fields("010") {
+		addISBN(b, f.Sub("a"))
+	}
+	if f := r.Fields("200"); len(f) > 0 {
+		b.Title = trim(f[0].Sub("a"))
+		b.Subtitle = trim(f[0].Sub("e"))
+	}
+	// 700 is the main author, 701 the co-authors: surname in $a and
+	// forenames in $b.
+	for _, f := range r.Fields("700", "701") {
+		a := trim(f.Sub("a"))
+		if a == "" {
+			continue
+		}
+		if fn := trim(f.Sub("b")); fn != "" {
+			a += ", " + fn
+		}
+		b.Authors = append(b.Authors, a)
+	}
+	// Newer records publish in 214, older ones in 210.
+	for _, f := range r.Fields("214", "210") {
+		if p := strings.Trim(trim(f.Sub("a")), "[]"); p != "" {
+			b.PublishPlaces = append(b.PublishPlaces, p)
+		}
+		if p := trim(f.Sub("c")); p != "" {
+			b.Publishers = append(b.Publishers, p)
+		}
+		if d := f.Sub("d"); d != "" && b.PublishDate == "" {
+			b.PublishDate = date(d)
+		}
+		if len(b.Publishers) > 0 {
+			break
+		}
+	}
+	// 102 holds ISO 3166 codes already.
+	b.PublishCountry = strings.ToUpper(r.first("a", "102"))
+	for _, f := range r.Fields("101") {
+		for _, s := range f.Subfields {
+			if s.Code == "a" {
+				b.Languages = book.Union(b.Languages, []string{strings.TrimSpace(s.Value)})
+			}
+		}
+	}
+	if f := r.Fields("215"); len(f) > 0 {
+		b.Pages = pages(f[0].Sub("a"))
+	}
+	var topics []string
+	for _, f := range r.Fields("606", "607") {
+		topics = append(topics, trim(f.Sub("a")))
+	}
+	b.Subjects = book.Union(nil, topics)
+	b.DeweyDecimal = r.first("a", "676")
+	b.LCC = r.first("a", "680")
+	b.OCLC = r.oclc()
+	if f := r.Fields("225", "461"); len(f) > 0 {
+		b.Series = trim(f[0].Sub("a"))
+		b.SeriesPosition = trim(f[0].Sub("v"))
+	}
+	return b
+}
+
+// year matches the year of a publication date, which catalogs write
+// as "2019.", "[2019]", "© 2019", "cop. 2019" or "DL 2019".
+var year = regexp.MustCompile(`[12]\d{3}`)
+
+// date returns the year of a publication date, or the date as it is
+// when it has none.
+func date(d string) string {
+	if y := year.FindString(d); y != "" {
+		return y
+	}
+	return trim(d)
+}
+
+// pageCount matches the page count of an extent: "310 p." in English
+// and French records, "310 S." in German ones.
+var pageCount = regexp.MustCompile(`(\d+)\s*(?:p\.|pp\.|pages|S\.|Seiten)`)
+
+// pages returns the page count of an extent such as "XII, 310 S. : Ill."
+// or "1 vol. (310 p.)", or 0.
+func pages(extent string) int {
+	m := pageCount.FindStringSubmatch(extent)
+	if m == nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(m[1])
+	return n
+}
@@ -0,0 +1,143 @@
+// Package sru implements providers for the catalogs of national
+// libraries through their SRU interfaces, which serve MARC records: the
+// British Library, the Deutsche Nationalbibliothek and the Bibliothèque
+// nationale de France. They cover the books published in their
+// countries, which the other sources know poorly.
+package sru
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// Catalog describes the SRU interface of a library catalog.
+type Catalog struct {
+	// Name is the provider name used in configuration and provenance.
+	Name    string
+	BaseURL string
+	// Version is the SRU version the catalog speaks.
+	Version string
+	// Schema is the record schema of MARC records, in Format.
+	Schema string
+	Format Format
+	// ISBN, Title and Author are the CQL indexes searched, and Relation
+	// the relation they are searched with.
+	ISBN, Title, Author string
+	Relation            string
+}
+
+// The catalogs of the national libraries.
+var (
+	BL = &Catalog{
+		Name:    "bl",
+		BaseURL: "https://sru.bl.uk/SRU",
+		Version: "1.1",
+		Schema:  "marcxml",
+		Format:  MARC21,
+		ISBN:    "bath.isbn", Title: "dc.title", Author: "dc.creator",
+		Relation: "=",
+	}
+	DNB = &Catalog{
+		Name:    "dnb",
+		BaseURL: "https://services.dnb.de/sru/dnb",
+		Version: "1.1",
+		Schema:  "MARC21-xml",
+		Format:  MARC21,
+		ISBN:    "isbn", Title: "tit", Author: "per",
+		Relation: "=",
+	}
+	BnF = &Catalog{
+		Name:    "bnf",
+		BaseURL: "https://catalogue.bnf.fr/api/SRU",
+		Version: "1.2",
+		Schema:  "unimarcXchange",
+		Format:  UNIMARC,
+		ISBN:    "bib.isbn", Title: "bib.title", Author: "bib.author",
+		Relation: "all",
+	}
+)
+
+// Response is an SRU searchRetrieve response carrying MARC records.
+type Response struct {
+	NumberOfRecords int      `xml:"numberOfRecords"`
+	Records         []Record `xml:"records>record>recordData>record"`
+}
+
+// Client queries the SRU interface of a catalog.
+type Client struct {
+	Catalog *Catalog
+	// BaseURL overrides the catalog's, for tests and mirrors.
+	BaseURL    string
+	HTTPClient *http.Client
+}
+
+var _ provider.Provider = (*Client)(nil)
+
+// Name implements provider.Provider.
+func (c *Client) Name() string { return c.Catalog.Name }
+
+func (c *Client) search(ctx context.Context, cql string, max int) ([]Record, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = c.Catalog.BaseURL
+	}
+	q := url.Values{
+		"version":        {c.Catalog.Version},
+		"operation":      {"searchRetrieve"},
+		"query":          {cql},
+		"recordSchema":   {c.Catalog.Schema},
+		"recordPacking":  {"xml"},
+		"maximumRecords": {fmt.Sprint(max)},
+	}
+	var resp Response
+	if err := provider.GetXML(ctx, c.HTTPClient, base+"?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Records) == 0 {
+		return nil, provider.ErrNotFound
+	}
+	return resp.Records, nil
+}
+
+// cqlEscaper escapes the characters that end a quoted CQL term, and
+// the masking characters, so that titles such as "Who's Afraid?" are
+// searched as written rather than as patterns.
+var cqlEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, `*`, `\*`, `?`, `\?`, `^`, `\^`)
+
+// clause returns the CQL clause searching index for s.
+func (c *Client) clause(index, s string) string {
+	return index + " " + c.Catalog.Relation + " " + `"` + cqlEscaper.Replace(s) + `"`
+}
+
+// LookupISBN implements provider.Provider.
+func (c *Client) LookupISBN(ctx context.Context, code string) (*book.BookInfo, error) {
+	recs, err := c.search(ctx, c.clause(c.Catalog.ISBN, isbn.Normalize(code)), 1)
+	if err != nil {
+		return nil, err
+	}
+	return c.Catalog.Format(&recs[0], c.Catalog.Name), nil
+}
+
+// Search implements provider.Provider.
+func (c *Client) Search(ctx context.Context, title, author string) ([]*book.BookInfo, error) {
+	cql := c.clause(c.Catalog.Title, title)
+	if author != "" {
+		cql += " and " + c.clause(c.Catalog.Author, author)
+	}
+	recs, err := c.search(ctx, cql, 10)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]*book.BookInfo, len(recs))
+	for i := range recs {
+		out[i] = c.Catalog.Format(&recs[i], c.Catalog.Name)
+	}
+	return out, nil
+}
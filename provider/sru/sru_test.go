@@ -0,0 +1,155 @@
+package sru_test
+
+import (
+	"context"
+	"encoding/xml"
+	"slices"
+	"testing"
+
+	"github.com/SouadAli10/book_scrapping_tool/httpx/httpxtest"
+	"github.com/SouadAli10/book_scrapping_tool/provider/sru"
+)
+
+func TestLookupISBN(t *testing.T) {
+	for _, tc := range []struct {
+		catalog  *sru.Catalog
+		isbn     string
+		fields   map[string]string
+		authors  []string
+		subjects []string
+		pages    int
+	}{
+		{
+			catalog: sru.DNB,
+			isbn:    "978-3-498-03528-0",
+			fields: map[string]string{
+				"title": "Die Vermessung der Welt", "subtitle": "Roman", "publisher": "Rowohlt",
+				"publish_date": "2005", "publish_country": "DE", "language": "ger", "dewey_decimal": "833.92",
+			},
+			authors:  []string{"Kehlmann, Daniel"},
+			subjects: []string{"Humboldt, Alexander von", "Gauß, Carl Friedrich"},
+			pages:    302,
+		},
+		{
+			catalog: sru.BnF,
+			isbn:    "9782070360024",
+			fields: map[string]string{
+				"title": "L'étranger", "publisher": "Gallimard", "publish_date": "1972", "publish_country": "FR",
+				"language": "fre", "dewey_decimal": "843.914", "series": "Folio", "series_position": "2",
+			},
+			authors: []string{"Camus, Albert"},
+			pages:   186,
+		},
+		{
+			catalog: sru.BL,
+			isbn:    "9780007230181",
+			fields: map[string]string{
+				"title": "Wolf Hall", "publisher": "Fourth Estate", "publish_date": "2009", "publish_country": "GB",
+				"language": "eng", "dewey_decimal": "823.914",
+			},
+			authors:  []string{"Mantel, Hilary"},
+			subjects: []string{"Cromwell, Thomas", "Historical fiction"},
+			pages:    653,
+		},
+	} {
+		t.Run(tc.catalog.Name, func(t *testing.T) {
+			c := &sru.Client{Catalog: tc.catalog, HTTPClient: httpxtest.Client(t, "testdata/"+tc.catalog.Name+".json")}
+			b, err := c.LookupISBN(context.Background(), tc.isbn)
+			if err != nil {
+				t.Fatal(err)
+			}
+			first := func(s []string) string {
+				if len(s) == 0 {
+					return ""
+				}
+				return s[0]
+			}
+			got := map[string]string{
+				"title": b.Title, "subtitle": b.Subtitle, "publisher": first(b.Publishers),
+				"publish_date": b.PublishDate, "publish_country": b.PublishCountry, "language": first(b.Languages),
+				"dewey_decimal": b.DeweyDecimal, "series": b.Series, "series_position": b.SeriesPosition,
+			}
+			for field, want := range tc.fields {
+				if got[field] != want {
+					t.Errorf("%s = %q, want %q", field, got[field], want)
+				}
+			}
+			if b.ISBN13 == "" || b.Source != tc.catalog.Name {
+				t.Errorf("isbn_13 = %q, source = %q", b.ISBN13, b.Source)
+			}
+			if !slices.Equal(b.Authors, tc.authors) {
+				t.Errorf("authors = %q, want %q", b.Authors, tc.authors)
+			}
+			if !slices.Equal(b.Subjects, tc.subjects) {
+				t.Errorf("subjects = %q, want %q", b.Subjects, tc.subjects)
+			}
+			if b.Pages != tc.pages {
+				t.Errorf("pages = %d, want %d", b.Pages, tc.pages)
+			}
+		})
+	}
+}
+
+// TestOCLC checks that the OCLC number is read from the system control
+// numbers in 035 in the forms catalogs write it, and that other control
+// numbers are left out.
+func TestOCLC(t *testing.T) {
+	for _, tc := range []struct {
+		format sru.Format
+		field  string
+		want   string
+	}{
+		{sru.MARC21, "(OCoLC)ocn310154836", "310154836"},
+		{sru.MARC21, "(OCoLC)ocm01234567", "1234567"},
+		{sru.MARC21, "(OCoLC)on1234567890", "1234567890"},
+		{sru.MARC21, "(OCoLC)00012345", "12345"},
+		{sru.MARC21, "(DE-599)DNB974478792", ""},
+		{sru.MARC21, "(OCoLC)ocn", ""},
+		{sru.UNIMARC, "(OCoLC)406476934", "406476934"},
+	} {
+		data := `<record xmlns="http://www.loc.gov/MARC21/slim">` +
+			`<datafield tag="035" ind1=" " ind2=" "><subfield code="a">(Uk)014757633</subfield></datafield>` +
+			`<datafield tag="035" ind1=" " ind2=" "><subfield code="a">` + tc.field + `</subfield></datafield>` +
+			`</record>`
+		var r sru.Record
+		if err := xml.Unmarshal([]byte(data), &r); err != nil {
+			t.Fatal(err)
+		}
+		if got := tc.format(&r, "test").OCLC; got != tc.want {
+			t.Errorf("035 %q: oclc = %q, want %q", tc.field, got, tc.want)
+		}
+	}
+}
+
+// TestSearchRequest checks the query sent for titles and authors with
+// query syntax, quotes, accents and other scripts. CQL
+// quotes, backslashes and masking characters are escaped.
+func TestSearchRequest(t *testing.T) {
+	hc, urls := httpxtest.Requests()
+	for _, tc := range []struct {
+		catalog             *sru.Catalog
+		title, author, want string
+	}{
+		{sru.DNB, "Tintin & Milou #1", "", "https://services.dnb.de/sru/dnb?maximumRecords=10&operation=searchRetrieve&query=tit+%3D+%22Tintin+%26+Milou+%231%22&recordPacking=xml&recordSchema=MARC21-xml&version=1.1"},
+		{sru.DNB, "Les Misérables", "Victor Hugo", "https://services.dnb.de/sru/dnb?maximumRecords=10&operation=searchRetrieve&query=tit+%3D+%22Les+Mis%C3%A9rables%22+and+per+%3D+%22Victor+Hugo%22&recordPacking=xml&recordSchema=MARC21-xml&version=1.1"},
+		{sru.DNB, "ノルウェイの森", "村上春樹", "https://services.dnb.de/sru/dnb?maximumRecords=10&operation=searchRetrieve&query=tit+%3D+%22%E3%83%8E%E3%83%AB%E3%82%A6%E3%82%A7%E3%82%A4%E3%81%AE%E6%A3%AE%22+and+per+%3D+%22%E6%9D%91%E4%B8%8A%E6%98%A5%E6%A8%B9%22&recordPacking=xml&recordSchema=MARC21-xml&version=1.1"},
+		{sru.DNB, "مدن الملح", "عبد الرحمن منيف", "https://services.dnb.de/sru/dnb?maximumRecords=10&operation=searchRetrieve&query=tit+%3D+%22%D9%85%D8%AF%D9%86+%D8%A7%D9%84%D9%85%D9%84%D8%AD%22+and+per+%3D+%22%D8%B9%D8%A8%D8%AF+%D8%A7%D9%84%D8%B1%D8%AD%D9%85%D9%86+%D9%85%D9%86%D9%8A%D9%81%22&recordPacking=xml&recordSchema=MARC21-xml&version=1.1"},
+		{sru.DNB, "C++ Primer / 5th edition", "", "https://services.dnb.de/sru/dnb?maximumRecords=10&operation=searchRetrieve&query=tit+%3D+%22C%2B%2B+Primer+%2F+5th+edition%22&recordPacking=xml&recordSchema=MARC21-xml&version=1.1"},
+		{sru.DNB, `The "Lord" of the Rings`, "", "https://services.dnb.de/sru/dnb?maximumRecords=10&operation=searchRetrieve&query=tit+%3D+%22The+%5C%22Lord%5C%22+of+the+Rings%22&recordPacking=xml&recordSchema=MARC21-xml&version=1.1"},
+		{sru.DNB, "Who's Afraid? *^", "", "https://services.dnb.de/sru/dnb?maximumRecords=10&operation=searchRetrieve&query=tit+%3D+%22Who%27s+Afraid%5C%3F+%5C%2A%5C%5E%22&recordPacking=xml&recordSchema=MARC21-xml&version=1.1"},
+		{sru.BnF, "Tintin & Milou #1", "", "https://catalogue.bnf.fr/api/SRU?maximumRecords=10&operation=searchRetrieve&query=bib.title+all+%22Tintin+%26+Milou+%231%22&recordPacking=xml&recordSchema=unimarcXchange&version=1.2"},
+		{sru.BnF, "Les Misérables", "Victor Hugo", "https://catalogue.bnf.fr/api/SRU?maximumRecords=10&operation=searchRetrieve&query=bib.title+all+%22Les+Mis%C3%A9rables%22+and+bib.author+all+%22Victor+Hugo%22&recordPacking=xml&recordSchema=unimarcXchange&version=1.2"},
+		{sru.BnF, "ノルウェイの森", "村上春樹", "https://catalogue.bnf.fr/api/SRU?maximumRecords=10&operation=searchRetrieve&query=bib.title+all+%22%E3%83%8E%E3%83%AB%E3%82%A6%E3%82%A7%E3%82%A4%E3%81%AE%E6%A3%AE%22+and+bib.author+all+%22%E6%9D%91%E4%B8%8A%E6%98%A5%E6%A8%B9%22&recordPacking=xml&recordSchema=unimarcXchange&version=1.2"},
+		{sru.BnF, "مدن الملح", "عبد الرحمن منيف", "https://catalogue.bnf.fr/api/SRU?maximumRecords=10&operation=searchRetrieve&query=bib.title+all+%22%D9%85%D8%AF%D9%86+%D8%A7%D9%84%D9%85%D9%84%D8%AD%22+and+bib.author+all+%22%D8%B9%D8%A8%D8%AF+%D8%A7%D9%84%D8%B1%D8%AD%D9%85%D9%86+%D9%85%D9%86%D9%8A%D9%81%22&recordPacking=xml&recordSchema=unimarcXchange&version=1.2"},
+		{sru.BnF, "C++ Primer / 5th edition", "", "https://catalogue.bnf.fr/api/SRU?maximumRecords=10&operation=searchRetrieve&query=bib.title+all+%22C%2B%2B+Primer+%2F+5th+edition%22&recordPacking=xml&recordSchema=unimarcXchange&version=1.2"},
+		{sru.BnF, `The "Lord" of the Rings`, "", "https://catalogue.bnf.fr/api/SRU?maximumRecords=10&operation=searchRetrieve&query=bib.title+all+%22The+%5C%22Lord%5C%22+of+the+Rings%22&recordPacking=xml&recordSchema=unimarcXchange&version=1.2"},
+		{sru.BnF, "Who's Afraid? *^", "", "https://catalogue.bnf.fr/api/SRU?maximumRecords=10&operation=searchRetrieve&query=bib.title+all+%22Who%27s+Afraid%5C%3F+%5C%2A%5C%5E%22&recordPacking=xml&recordSchema=unimarcXchange&version=1.2"},
+	} {
+		c := &sru.Client{Catalog: tc.catalog, HTTPClient: hc}
+		c.Search(context.Background(), tc.title, tc.author)
+		sent := urls()
+		if got := sent[len(sent)-1]; got != tc.want {
+			t.Errorf("%s search %q, %q: requested\n%s\nwant\n%s", tc.catalog.Name, tc.title, tc.author, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+)
+
+// LookupStats are the counters kept per provider by Stats.
+type LookupStats struct {
+	Provider string
+	// Found, NotFound and Errors count the lookups and searches by
+	// outcome; a search without candidates is not found.
+	Found    int
+	NotFound int
+	Errors   int
+	Latency  httpx.Histogram
+}
+
+// Stats counts the lookups of providers wrapped with Instrument, for
+// monitoring a long-running service.
+type Stats struct {
+	mu        sync.Mutex
+	providers map[string]*LookupStats
+}
+
+func (s *Stats) observe(name string, d time.Duration, found bool, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.providers == nil {
+		s.providers = make(map[string]*LookupStats)
+	}
+	ls := s.providers[name]
+	if ls == nil {
+		ls = &LookupStats{Provider: name}
+		s.providers[name] = ls
+	}
+	switch {
+	case err == nil && found:
+		ls.Found++
+	case err == nil || errors.Is(err, ErrNotFound):
+		ls.NotFound++
+	default:
+		ls.Errors++
+	}
+	ls.Latency.Observe(d)
+}
+
+// Snapshot returns a copy of the counters, sorted by provider.
+func (s *Stats) Snapshot() []LookupStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]LookupStats, 0, len(s.providers))
+	for _, ls := range s.providers {
+		c := *ls
+		c.Latency = ls.Latency.Clone()
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Provider < out[j].Provider })
+	return out
+}
+
+// Instrumented reports the lookups of a provider to Stats.
+type Instrumented struct {
+	Provider
+	stats *Stats
+}
+
+// Instrument wraps p so that its lookups are counted in s.
+func (s *Stats) Instrument(p Provider) *Instrumented {
+	return &Instrumented{Provider: p, stats: s}
+}
+
+// LookupISBN implements Provider.
+func (p *Instrumented) LookupISBN(ctx context.Context, isbn string) (*book.BookInfo, error) {
+	start := time.Now()
+	b, err := p.Provider.LookupISBN(ctx, isbn)
+	p.stats.observe(p.Name(), time.Since(start), b != nil, err)
+	return b, err
+}
+
+// Search implements Provider.
+func (p *Instrumented) Search(ctx context.Context, title, author string) ([]*book.BookInfo, error) {
+	start := time.Now()
+	bs, err := p.Provider.Search(ctx, title, author)
+	p.stats.observe(p.Name(), time.Since(start), len(bs) > 0, err)
+	return bs, err
+}
+
+// LookupVolume implements VolumeProvider. It returns ErrNotFound when the
+// wrapped provider does not know series volumes.
+func (p *Instrumented) LookupVolume(ctx context.Context, series, volume string) (*book.BookInfo, error) {
+	vp, ok := p.Provider.(VolumeProvider)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	start := time.Now()
+	b, err := vp.LookupVolume(ctx, series, volume)
+	p.stats.observe(p.Name(), time.Since(start), b != nil, err)
+	return b, err
+}
@@ -0,0 +1,173 @@
+// Package upcitemdb implements an item provider for the UPCitemdb
+// product database (https://www.upcitemdb.com/api/explorer), which knows
+// the UPC and EAN barcodes of DVDs, CDs, games and most retail goods.
+// The free trial endpoint needs no key and allows 100 lookups a day; an
+// API key selects the paid endpoint.
+//
+// Products map into the reduced model of items other than books: title,
+// brand as publisher, category, description, image, size and weight.
+package upcitemdb
+
+import (
+	"cmp"
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/gtin"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/units"
+)
+
+// Name is the provider name used in configuration and provenance.
+const Name = "upcitemdb"
+
+// DefaultBaseURL is the UPCitemdb API endpoint.
+const DefaultBaseURL = "https://api.upcitemdb.com/prod"
+
+// Item is a product of the lookup response.
+type Item struct {
+	EAN         string   `json:"ean"`
+	UPC         string   `json:"upc"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Brand       string   `json:"brand"`
+	Publisher   string   `json:"publisher"`
+	Category    string   `json:"category"`
+	Dimension   string   `json:"dimension"`
+	Weight      string   `json:"weight"`
+	Images      []string `json:"images"`
+}
+
+type lookupResponse struct {
+	Code  string `json:"code"`
+	Total int    `json:"total"`
+	Items []Item `json:"items"`
+}
+
+// kinds maps words of the category or title to item kinds, the more
+// specific first.
+var kinds = []struct{ word, kind string }{
+	{"blu-ray", "blu-ray"},
+	{"dvd", "dvd"},
+	{"vinyl", "vinyl record"},
+	{"cd", "audio cd"},
+	{"music", "audio cd"},
+	{"video game", "video game"},
+	{"board game", "board game"},
+	{"puzzle", "puzzle"},
+	{"game", "game"},
+}
+
+var (
+	// edition matches the format and year retailers append to titles,
+	// e.g. "(DVD, 2008)" or "[Blu-ray]".
+	edition = regexp.MustCompile(`\s*[(\[]([^)\]]*)[)\]]\s*$`)
+	year    = regexp.MustCompile(`\b(?:19|20)\d\d\b`)
+)
+
+// kind guesses the kind of item from the words of its category and
+// title, in the singular.
+func kind(category, title string) string {
+	words := strings.FieldsFunc(strings.ToLower(category+" "+title), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '-'
+	})
+	for i, w := range words {
+		words[i] = strings.TrimSuffix(w, "s")
+	}
+	text := " " + strings.Join(words, " ") + " "
+	for _, k := range kinds {
+		if strings.Contains(text, " "+k.word+" ") {
+			return k.kind
+		}
+	}
+	return ""
+}
+
+// BookInfo maps it into the canonical record.
+func (it *Item) BookInfo() *book.BookInfo {
+	out := &book.BookInfo{
+		GTIN:        it.EAN,
+		Kind:        kind(it.Category, it.Title),
+		Title:       strings.TrimSpace(it.Title),
+		Description: book.PlainText(it.Description),
+		Source:      Name,
+	}
+	if out.GTIN == "" {
+		out.GTIN = it.UPC
+	}
+	// Strip the edition notes from the title, keeping their year.
+	for {
+		m := edition.FindStringSubmatch(out.Title)
+		if m == nil {
+			break
+		}
+		if out.PublishDate == "" {
+			out.PublishDate = year.FindString(m[1])
+		}
+		out.Title = strings.TrimSpace(out.Title[:len(out.Title)-len(m[0])])
+	}
+	if p := cmp.Or(strings.TrimSpace(it.Publisher), strings.TrimSpace(it.Brand)); p != "" {
+		out.Publishers = []string{p}
+	}
+	if i := strings.LastIndex(it.Category, ">"); i >= 0 {
+		out.Subjects = []string{strings.TrimSpace(it.Category[i+1:])}
+	} else if c := strings.TrimSpace(it.Category); c != "" {
+		out.Subjects = []string{c}
+	}
+	if len(it.Images) > 0 {
+		out.CoverURL = it.Images[0]
+	}
+	if d, err := units.ParseDimensions(it.Dimension); err == nil {
+		out.Dimensions = d
+	}
+	if w, err := units.ParseWeight(it.Weight); err == nil {
+		out.Weight = w
+	}
+	return out
+}
+
+// Client queries UPCitemdb. The zero value uses the trial endpoint.
+type Client struct {
+	BaseURL    string
+	APIKey     string
+	HTTPClient *http.Client
+}
+
+var _ provider.ItemProvider = (*Client)(nil)
+
+// Name implements provider.ItemProvider.
+func (c *Client) Name() string { return Name }
+
+// LookupBarcode implements provider.ItemProvider.
+func (c *Client) LookupBarcode(ctx context.Context, code string) (*book.BookInfo, error) {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	hc := c.HTTPClient
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	path := "/trial/lookup"
+	if c.APIKey != "" {
+		// The paid endpoint takes the key in headers.
+		path = "/v1/lookup"
+		authed := *hc
+		authed.Transport = provider.WithHeader(provider.WithHeader(hc.Transport, "user_key", c.APIKey), "key_type", "3scale")
+		hc = &authed
+	}
+	var resp lookupResponse
+	q := url.Values{"upc": {gtin.Normalize(code)}}
+	if err := provider.GetJSON(ctx, hc, base+path+"?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Items) == 0 {
+		return nil, provider.ErrNotFound
+	}
+	return resp.Items[0].BookInfo(), nil
+}
@@ -0,0 +1,48 @@
+package upcitemdb_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/SouadAli10/book_scrapping_tool/httpx/httpxtest"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+	"github.com/SouadAli10/book_scrapping_tool/provider/upcitemdb"
+)
+
+func TestLookupBarcode(t *testing.T) {
+	c := &upcitemdb.Client{HTTPClient: httpxtest.Client(t, "testdata/barcode.json")}
+	b, err := c.LookupBarcode(context.Background(), "883929247318")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct{ field, got, want string }{
+		{"gtin", b.GTIN, "0883929247318"},
+		{"kind", b.Kind, "dvd"},
+		{"title", b.Title, "The Dark Knight"},
+		{"publish_date", b.PublishDate, "2008"},
+		{"cover_url", b.CoverURL, "https://images-na.ssl-images-amazon.com/images/I/51k0qa6qH-L.jpg"},
+		{"source", b.Source, upcitemdb.Name},
+	} {
+		if tc.got != tc.want {
+			t.Errorf("%s = %q, want %q", tc.field, tc.got, tc.want)
+		}
+	}
+	if want := []string{"Warner Home Video"}; !slices.Equal(b.Publishers, want) {
+		t.Errorf("publishers = %q, want %q", b.Publishers, want)
+	}
+	if want := []string{"DVDs & Videos"}; !slices.Equal(b.Subjects, want) {
+		t.Errorf("subjects = %q, want %q", b.Subjects, want)
+	}
+	if b.Weight < 150 || b.Weight > 170 {
+		t.Errorf("weight = %v g, want about 159", b.Weight)
+	}
+}
+
+func TestLookupBarcodeNotFound(t *testing.T) {
+	c := &upcitemdb.Client{HTTPClient: httpxtest.Client(t, "testdata/barcode.json")}
+	if _, err := c.LookupBarcode(context.Background(), "012345678905"); !errors.Is(err, provider.ErrNotFound) {
+		t.Errorf("err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,197 @@
+// Package reprice sets the prices of a catalog from current market
+// prices with a small rule engine: the market price is multiplied by a
+// factor for the condition of the copy, rounded to a price ending such
+// as .99 and kept between a floor and a ceiling.
+package reprice
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/profile"
+)
+
+// Basis selects the market price of a condition rules start from.
+type Basis string
+
+const (
+	// Median starts from the median offer, the going price.
+	Median Basis = "median"
+	// Min starts from the cheapest offer, to undercut the market.
+	Min Basis = "min"
+)
+
+// ParseBasis parses a basis name; "" selects Median.
+func ParseBasis(s string) (Basis, error) {
+	switch b := Basis(strings.ToLower(strings.TrimSpace(s))); b {
+	case "":
+		return Median, nil
+	case Median, Min:
+		return b, nil
+	}
+	return "", fmt.Errorf("unknown price basis %q (want median or min)", s)
+}
+
+// DefaultFactors are the factors applied to the market price by
+// condition. Copies in a condition that cannot be read use the factor of
+// profile.UnknownCondition.
+var DefaultFactors = map[profile.Condition]float64{
+	profile.New:              1,
+	profile.LikeNew:          0.95,
+	profile.VeryGood:         0.9,
+	profile.Good:             0.8,
+	profile.Acceptable:       0.65,
+	profile.Poor:             0.5,
+	profile.UnknownCondition: 0.8,
+}
+
+// DefaultEnding is the price ending prices are rounded to.
+const DefaultEnding = 0.99
+
+// Rules compute new prices. The zero value applies DefaultFactors to the
+// median market price, without rounding or limits.
+type Rules struct {
+	Basis Basis
+	// Factors overrides DefaultFactors by condition.
+	Factors map[profile.Condition]float64
+	// Ending rounds prices to the nearest amount with these cents, e.g.
+	// 0.99; zero keeps the cents.
+	Ending float64
+	// Floor and Ceiling bound the new prices; zero leaves them
+	// unbounded. They are applied after rounding, so they hold exactly.
+	Floor, Ceiling float64
+}
+
+// ParseCondition reads the condition names of rule configuration: those
+// of profile.ParseCondition, or "other" for conditions that cannot be
+// read.
+func ParseCondition(s string) (profile.Condition, error) {
+	if strings.EqualFold(strings.TrimSpace(s), "other") {
+		return profile.UnknownCondition, nil
+	}
+	c := profile.ParseCondition(s)
+	if c == profile.UnknownCondition {
+		return c, fmt.Errorf("unknown condition %q (want new, like new, very good, good, acceptable, poor or other)", s)
+	}
+	return c, nil
+}
+
+// Factor returns the factor applied to copies in condition c.
+func (r *Rules) Factor(c profile.Condition) float64 {
+	if f, ok := r.Factors[c]; ok {
+		return f
+	}
+	return DefaultFactors[c]
+}
+
+// Market returns the market price of copies in condition c: the new
+// price for new copies, the used price otherwise, falling back to the
+// new price when there are no used offers.
+func (r *Rules) Market(p book.Prices, c profile.Condition) float64 {
+	newPrice, usedPrice := p.NewMedian, p.UsedMedian
+	if r.Basis == Min {
+		newPrice, usedPrice = p.NewMin, p.UsedMin
+	}
+	if c != profile.New && usedPrice > 0 {
+		return usedPrice
+	}
+	return newPrice
+}
+
+// Price returns the new price of a copy in condition c, and false when
+// there is no market price to start from.
+func (r *Rules) Price(p book.Prices, c profile.Condition) (float64, bool) {
+	market := r.Market(p, c)
+	if market <= 0 {
+		return 0, false
+	}
+	return r.Round(market * r.Factor(c)), true
+}
+
+// Round rounds v to the price ending and keeps it between the floor and
+// the ceiling.
+func (r *Rules) Round(v float64) float64 {
+	if r.Ending > 0 {
+		v = math.Max(math.Round(v-r.Ending), 0) + r.Ending
+	}
+	if r.Floor > 0 {
+		v = math.Max(v, r.Floor)
+	}
+	if r.Ceiling > 0 {
+		v = math.Min(v, r.Ceiling)
+	}
+	return math.Round(v*100) / 100
+}
+
+// ParseAmount reads a price cell such as "12.50", "$12.50" or "12,50 €".
+func ParseAmount(s string) (float64, bool) {
+	s = strings.TrimFunc(s, func(r rune) bool { return (r < '0' || r > '9') && r != '.' && r != ',' })
+	if !strings.Contains(s, ".") {
+		s = strings.Replace(s, ",", ".", 1)
+	}
+	s = strings.ReplaceAll(s, ",", "")
+	v, err := strconv.ParseFloat(s, 64)
+	return v, err == nil && v > 0
+}
+
+// Change is the repricing of one catalog row.
+type Change struct {
+	// Row is the spreadsheet row number, below the header.
+	Row       int
+	ISBN      string
+	Title     string
+	Condition profile.Condition
+	// Market is the market price the new price starts from.
+	Market float64
+	Factor float64
+	// Old is the current price, zero when the row has none; New is zero
+	// when the row is not repriced, as told by Note.
+	Old, New float64
+	Note     string
+}
+
+// Changed reports whether c sets a different price.
+func (c *Change) Changed() bool {
+	return c.New > 0 && math.Abs(c.New-c.Old) >= 0.005
+}
+
+// ReportHeader is the header row written by WriteReport.
+var ReportHeader = []string{
+	columns.RowHeader, "ISBN", "Title", "Condition", "Market Price", "Factor",
+	"Current Price", "New Price", "Change", "Change %", "Note",
+}
+
+// WriteReport writes changes as CSV, for review before the new prices
+// are written.
+func WriteReport(w io.Writer, changes []Change) error {
+	amount := func(v float64) string {
+		if v <= 0 {
+			return ""
+		}
+		return strconv.FormatFloat(v, 'f', 2, 64)
+	}
+	cw := csv.NewWriter(w)
+	cw.Write(ReportHeader)
+	for _, c := range changes {
+		var diff, pct, factor string
+		if c.New > 0 {
+			factor = strconv.FormatFloat(c.Factor, 'f', -1, 64)
+			diff = strconv.FormatFloat(c.New-c.Old, 'f', 2, 64)
+			if c.Old > 0 {
+				pct = strconv.FormatFloat(100*(c.New-c.Old)/c.Old, 'f', 1, 64)
+			}
+		}
+		cw.Write([]string{
+			strconv.Itoa(c.Row), c.ISBN, c.Title, c.Condition.String(), amount(c.Market), factor,
+			amount(c.Old), amount(c.New), diff, pct, c.Note,
+		})
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,222 @@
+// Package seal encrypts files at rest with NaCl secretbox
+// (XSalsa20-Poly1305). Keys come from a 32-byte key file or from a
+// passphrase stretched with scrypt.
+package seal
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/crypto/nacl/secretbox"
+	"golang.org/x/crypto/scrypt"
+)
+
+// magic starts every sealed file. The byte after it says how the key is
+// derived: keyFile or passphrase.
+var magic = []byte("BTSEAL1")
+
+const (
+	modeKey        byte = 'k'
+	modePassphrase byte = 'p'
+	saltLen             = 16
+	nonceLen            = 24
+)
+
+// ErrDecrypt is returned for data that cannot be opened with the key:
+// a wrong key or passphrase, or tampered data.
+var ErrDecrypt = errors.New("seal: wrong key or corrupted data")
+
+// IsSealed reports whether data was produced by a Sealer.
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// Sealer encrypts and decrypts with one key.
+type Sealer struct {
+	key        *[32]byte
+	passphrase []byte
+
+	mu sync.Mutex
+	// salt and derived are the passphrase salt and key used for
+	// sealing; opened caches keys derived for the salts of existing
+	// files, since scrypt is deliberately slow.
+	salt    []byte
+	derived *[32]byte
+	opened  map[string]*[32]byte
+}
+
+// FromKey returns a Sealer using a 32-byte key.
+func FromKey(key []byte) (*Sealer, error) {
+	if len(key) != 32 {
+		return nil, fmt.Errorf("seal: key is %d bytes, want 32", len(key))
+	}
+	k := new([32]byte)
+	copy(k[:], key)
+	return &Sealer{key: k}, nil
+}
+
+// FromPassphrase returns a Sealer deriving keys from passphrase.
+func FromPassphrase(passphrase string) (*Sealer, error) {
+	if passphrase == "" {
+		return nil, errors.New("seal: empty passphrase")
+	}
+	return &Sealer{passphrase: []byte(passphrase), opened: make(map[string]*[32]byte)}, nil
+}
+
+// LoadKeyFile reads a key file holding 32 raw bytes, or their hex or
+// base64 encoding.
+func LoadKeyFile(path string) (*Sealer, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("seal: %w", err)
+	}
+	if len(data) == 32 {
+		return FromKey(data)
+	}
+	s := strings.TrimSpace(string(data))
+	if k, err := hex.DecodeString(s); err == nil {
+		return FromKey(k)
+	}
+	if k, err := base64.StdEncoding.DecodeString(s); err == nil {
+		return FromKey(k)
+	}
+	return nil, fmt.Errorf("seal: %s: want 32 bytes, raw, hex or base64", path)
+}
+
+// NewKey returns a random key encoded for a key file.
+func NewKey() (string, error) {
+	k := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, k); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(k) + "\n", nil
+}
+
+func derive(passphrase, salt []byte) (*[32]byte, error) {
+	k, err := scrypt.Key(passphrase, salt, 1<<15, 8, 1, 32)
+	if err != nil {
+		return nil, err
+	}
+	out := new([32]byte)
+	copy(out[:], k)
+	return out, nil
+}
+
+// sealKey returns the key and salt used for new data.
+func (s *Sealer) sealKey() (*[32]byte, []byte, error) {
+	if s.key != nil {
+		return s.key, nil, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.derived == nil {
+		salt := make([]byte, saltLen)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return nil, nil, err
+		}
+		k, err := derive(s.passphrase, salt)
+		if err != nil {
+			return nil, nil, err
+		}
+		s.salt, s.derived = salt, k
+		s.opened[string(salt)] = k
+	}
+	return s.derived, s.salt, nil
+}
+
+func (s *Sealer) openKey(salt []byte) (*[32]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if k, ok := s.opened[string(salt)]; ok {
+		return k, nil
+	}
+	k, err := derive(s.passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	s.opened[string(salt)] = k
+	return k, nil
+}
+
+// Seal encrypts plaintext.
+func (s *Sealer) Seal(plaintext []byte) ([]byte, error) {
+	key, salt, err := s.sealKey()
+	if err != nil {
+		return nil, err
+	}
+	var nonce [nonceLen]byte
+	if _, err := io.ReadFull(rand.Reader, nonce[:]); err != nil {
+		return nil, err
+	}
+	out := append([]byte(nil), magic...)
+	if salt == nil {
+		out = append(out, modeKey)
+	} else {
+		out = append(append(out, modePassphrase), salt...)
+	}
+	out = append(out, nonce[:]...)
+	return secretbox.Seal(out, plaintext, &nonce, key), nil
+}
+
+// Open decrypts data produced by Seal.
+func (s *Sealer) Open(data []byte) ([]byte, error) {
+	if !IsSealed(data) || len(data) < len(magic)+1 {
+		return nil, errors.New("seal: data is not sealed")
+	}
+	mode, rest := data[len(magic)], data[len(magic)+1:]
+	var key *[32]byte
+	switch {
+	case mode == modeKey && s.key != nil:
+		key = s.key
+	case mode == modePassphrase && s.passphrase != nil:
+		if len(rest) < saltLen {
+			return nil, ErrDecrypt
+		}
+		k, err := s.openKey(rest[:saltLen])
+		if err != nil {
+			return nil, err
+		}
+		key, rest = k, rest[saltLen:]
+	case mode == modeKey:
+		return nil, errors.New("seal: data was sealed with a key file, not a passphrase")
+	case mode == modePassphrase:
+		return nil, errors.New("seal: data was sealed with a passphrase, not a key file")
+	default:
+		return nil, ErrDecrypt
+	}
+	if len(rest) < nonceLen {
+		return nil, ErrDecrypt
+	}
+	var nonce [nonceLen]byte
+	copy(nonce[:], rest)
+	out, ok := secretbox.Open(nil, rest[nonceLen:], &nonce, key)
+	if !ok {
+		return nil, ErrDecrypt
+	}
+	return out, nil
+}
+
+// ReadFile reads path, decrypting it if it is sealed. A nil Sealer
+// reads plain files and rejects sealed ones.
+func (s *Sealer) ReadFile(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil || !IsSealed(data) {
+		return data, err
+	}
+	if s == nil {
+		return nil, fmt.Errorf("%s is encrypted; give a key file or passphrase", path)
+	}
+	out, err := s.Open(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return out, nil
+}
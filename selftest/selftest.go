@@ -0,0 +1,239 @@
+// Package selftest replays recorded provider responses through the
+// provider mappings and compares the canonical records with golden
+// files, so changes to a mapping cannot silently change enrichment
+// results.
+//
+// A fixture is a JSON file naming a provider, the lookup to run (an ISBN,
+// or a title and author) and the responses recorded for it. Its golden
+// file, with the same name and a .golden extension, holds the expected
+// Outcome.
+package selftest
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// Builtin holds the fixtures shipped with booktool.
+//
+//go:embed fixtures
+var builtin embed.FS
+
+// Builtin returns the fixtures shipped with booktool.
+func Builtin() fs.FS {
+	sub, _ := fs.Sub(builtin, "fixtures")
+	return sub
+}
+
+// Fixture is one recorded lookup.
+type Fixture struct {
+	// Name is the file name without extension; it is not stored.
+	Name     string `json:"-"`
+	Provider string `json:"provider"`
+	ISBN     string `json:"isbn,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Author   string `json:"author,omitempty"`
+	// Responses are matched to requests by method and URL.
+	Responses []Response `json:"responses"`
+}
+
+// Response is a recorded HTTP response. Fixtures share the format of
+// httpx cassettes.
+type Response = httpx.Interaction
+
+// Outcome is the result of a fixture's lookup, as stored in golden
+// files.
+type Outcome struct {
+	Book    *book.BookInfo   `json:"book,omitempty"`
+	Results []*book.BookInfo `json:"results,omitempty"`
+	Error   string           `json:"error,omitempty"`
+}
+
+// Transport answers requests from the fixture's recorded responses and
+// fails requests that were not recorded.
+func (f *Fixture) Transport() http.RoundTripper {
+	c := &httpx.Cassette{Interactions: f.Responses}
+	return c.Middleware(httpx.ModeReplay)(nil)
+}
+
+// Recorder passes requests to next and appends every response to the
+// fixture.
+func (f *Fixture) Recorder(next http.RoundTripper) http.RoundTripper {
+	var mu sync.Mutex
+	return httpx.RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		resp, err := next.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(data))
+		r := Response{URL: req.URL.String(), Status: resp.StatusCode}
+		if req.Method != http.MethodGet {
+			r.Method = req.Method
+		}
+		r.SetBody(data)
+		mu.Lock()
+		f.Responses = append(f.Responses, r)
+		mu.Unlock()
+		return resp, nil
+	})
+}
+
+// Run performs the fixture's lookup with p.
+func (f *Fixture) Run(ctx context.Context, p provider.Provider) *Outcome {
+	var o Outcome
+	var err error
+	if f.ISBN != "" {
+		o.Book, err = p.LookupISBN(ctx, f.ISBN)
+	} else {
+		o.Results, err = p.Search(ctx, f.Title, f.Author)
+	}
+	if err != nil {
+		o.Error = err.Error()
+	}
+	return &o
+}
+
+// Marshal encodes o the way golden files store it.
+func (o *Outcome) Marshal() []byte {
+	data, _ := json.MarshalIndent(o, "", "  ")
+	return append(data, '\n')
+}
+
+// Load reads the fixtures in the root of fsys, sorted by name.
+func Load(fsys fs.FS) ([]*Fixture, error) {
+	names, err := fs.Glob(fsys, "*.json")
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []*Fixture
+	for _, name := range names {
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return nil, err
+		}
+		f := &Fixture{Name: strings.TrimSuffix(name, ".json")}
+		if err := json.Unmarshal(data, f); err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if f.Provider == "" || (f.ISBN == "" && f.Title == "") {
+			return nil, fmt.Errorf("%s: want a provider and an isbn or title", name)
+		}
+		fixtures = append(fixtures, f)
+	}
+	return fixtures, nil
+}
+
+// GoldenName returns the golden file name of a fixture.
+func GoldenName(fixture string) string {
+	return fixture + ".golden"
+}
+
+// Marshal encodes f as a fixture file, with the responses sorted by URL
+// since concurrent requests are recorded in any order.
+func (f *Fixture) Marshal() []byte {
+	slices.SortStableFunc(f.Responses, func(a, b Response) int { return strings.Compare(a.URL, b.URL) })
+	data, _ := json.MarshalIndent(f, "", "  ")
+	return append(data, '\n')
+}
+
+// ErrNoGolden is returned by Check for fixtures without a golden file.
+var ErrNoGolden = errors.New("no golden file")
+
+// Check runs f with p and compares the outcome with the golden file in
+// fsys. It returns the outcome and one line per difference.
+func Check(ctx context.Context, fsys fs.FS, f *Fixture, p provider.Provider) (*Outcome, []string, error) {
+	got := f.Run(ctx, p)
+	want, err := fs.ReadFile(fsys, GoldenName(f.Name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return got, nil, ErrNoGolden
+	}
+	if err != nil {
+		return got, nil, err
+	}
+	diffs, err := Diff(want, got.Marshal())
+	return got, diffs, err
+}
+
+// Diff compares two JSON documents field by field and describes each
+// difference as "path: want X, got Y".
+func Diff(want, got []byte) ([]string, error) {
+	var w, g any
+	if err := json.Unmarshal(want, &w); err != nil {
+		return nil, fmt.Errorf("golden: %w", err)
+	}
+	if err := json.Unmarshal(got, &g); err != nil {
+		return nil, err
+	}
+	wf, gf := make(map[string]string), make(map[string]string)
+	flatten("", w, wf)
+	flatten("", g, gf)
+	var diffs []string
+	for _, k := range sortedUnion(wf, gf) {
+		wv, wok := wf[k]
+		gv, gok := gf[k]
+		switch {
+		case !wok:
+			diffs = append(diffs, fmt.Sprintf("%s: unexpected %s", k, gv))
+		case !gok:
+			diffs = append(diffs, fmt.Sprintf("%s: missing, want %s", k, wv))
+		case wv != gv:
+			diffs = append(diffs, fmt.Sprintf("%s: want %s, got %s", k, wv, gv))
+		}
+	}
+	return diffs, nil
+}
+
+// flatten maps every leaf of v to its dotted path.
+func flatten(prefix string, v any, out map[string]string) {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, e := range v {
+			flatten(join(prefix, k), e, out)
+		}
+	case []any:
+		for i, e := range v {
+			flatten(fmt.Sprintf("%s[%d]", prefix, i), e, out)
+		}
+	default:
+		data, _ := json.Marshal(v)
+		out[prefix] = string(data)
+	}
+}
+
+func join(prefix, k string) string {
+	if prefix == "" {
+		return k
+	}
+	return prefix + "." + k
+}
+
+func sortedUnion(a, b map[string]string) []string {
+	keys := slices.Collect(maps.Keys(a))
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,97 @@
+// Package series determines the series a book belongs to and its
+// position in it. Series data reported by a provider or by Wikidata is
+// taken as confirmed; as a last resort the series is inferred from
+// common title patterns such as "Mort (Discworld, #4)".
+package series
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+)
+
+// Confidence tells how a series was determined.
+type Confidence string
+
+const (
+	// Confirmed series come from structured provider or Wikidata data.
+	Confirmed Confidence = "confirmed"
+	// Inferred series were parsed from the title and may be wrong.
+	Inferred Confidence = "inferred"
+)
+
+// Info is the series of one book.
+type Info struct {
+	Name       string
+	Position   string
+	Confidence Confidence
+	Source     string
+}
+
+// Resolver looks up series information. Wikidata is optional.
+type Resolver struct {
+	Wikidata *Wikidata
+}
+
+// Resolve returns the series of b, or a zero Info when none is found.
+func (r *Resolver) Resolve(ctx context.Context, b *book.BookInfo) Info {
+	if b.Series != "" {
+		c := Confirmed
+		if b.SeriesInferred {
+			c = Inferred
+		}
+		return Info{Name: b.Series, Position: b.SeriesPosition, Confidence: c, Source: b.Source}
+	}
+	if r.Wikidata != nil && b.OLWorkID != "" {
+		if in, err := r.Wikidata.ByOpenLibraryWork(ctx, b.OLWorkID); err == nil && in.Name != "" {
+			return in
+		}
+	}
+	return FromTitle(b.FullTitle())
+}
+
+var titlePatterns = []*regexp.Regexp{
+	// "Mort (Discworld, #4)", "Mort (Discworld #4)"
+	regexp.MustCompile(`\(\s*([^()#]+?),?\s*#\s*([0-9]+(?:\.[0-9]+)?)\s*\)`),
+	// "Dune (Dune Chronicles, Book 1)", "Naruto (Vol. 3)"
+	regexp.MustCompile(`(?i)\(\s*([^()]*?),?\s*(?:book|vol\.?|volume|tome|band)\s*([0-9]+)\s*\)`),
+	// "The Wheel of Time, Book 2: The Great Hunt"
+	regexp.MustCompile(`(?i)^([^:]+?),?\s+(?:book|vol\.?|volume)\s+([0-9]+)\s*[:\-–]`),
+	// "Naruto, Vol. 3", as manga volumes are titled
+	regexp.MustCompile(`(?i)^(.+?),?\s+(?:vol\.?|volume)\s*([0-9]+)\s*$`),
+	// "Foundation Series 3"
+	regexp.MustCompile(`(?i)^(.+?\bseries)\s+([0-9]+)\b`),
+}
+
+// FromTitle infers a series from title patterns. The result is marked
+// Inferred.
+func FromTitle(title string) Info {
+	for _, re := range titlePatterns {
+		m := re.FindStringSubmatch(title)
+		if m == nil {
+			continue
+		}
+		name := strings.TrimSpace(m[1])
+		if name == "" {
+			continue
+		}
+		return Info{Name: name, Position: m[2], Confidence: Inferred, Source: "title"}
+	}
+	return Info{}
+}
+
+// Apply records in on b so later stages and output see the series.
+func (in Info) Apply(b *book.BookInfo) {
+	if in.Name == "" {
+		return
+	}
+	// Series parsed from the title show in the Series Confidence
+	// column rather than as a source.
+	if b.Series == "" && in.Confidence != Inferred {
+		b.Credit(in.Source, "series")
+	}
+	b.Series, b.SeriesPosition = in.Name, in.Position
+	b.SeriesInferred = in.Confidence == Inferred
+}
@@ -0,0 +1,72 @@
+package series
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// DefaultSPARQLEndpoint is the public Wikidata query service.
+const DefaultSPARQLEndpoint = "https://query.wikidata.org/sparql"
+
+// Wikidata queries the Wikidata SPARQL endpoint for "part of the series"
+// (P179) statements and their "series ordinal" (P1545) qualifiers. Works
+// are matched by their OpenLibrary ID (P648), which, unlike ISBNs in
+// Wikidata, is stored without hyphenation.
+type Wikidata struct {
+	Endpoint   string
+	HTTPClient *http.Client
+}
+
+type sparqlResponse struct {
+	Results struct {
+		Bindings []map[string]struct {
+			Value string `json:"value"`
+		} `json:"bindings"`
+	} `json:"results"`
+}
+
+const seriesQuery = `SELECT ?seriesLabel ?ordinal WHERE {
+  ?work wdt:P648 "%s" .
+  ?work p:P179 ?st .
+  ?st ps:P179 ?series .
+  OPTIONAL { ?st pq:P1545 ?ordinal . }
+  SERVICE wikibase:label { bd:serviceParam wikibase:language "en". }
+} LIMIT 1`
+
+// ByOpenLibraryWork returns the series of the work with the given
+// OpenLibrary ID, e.g. "OL27479W".
+func (w *Wikidata) ByOpenLibraryWork(ctx context.Context, workID string) (Info, error) {
+	endpoint := w.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultSPARQLEndpoint
+	}
+	// Work IDs are [A-Z0-9]; strip anything else before embedding.
+	id := strings.Map(func(r rune) rune {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, workID)
+	q := url.Values{
+		"query":  {strings.Replace(seriesQuery, "%s", id, 1)},
+		"format": {"json"},
+	}
+	var resp sparqlResponse
+	if err := provider.GetJSON(ctx, w.HTTPClient, endpoint+"?"+q.Encode(), &resp); err != nil {
+		return Info{}, err
+	}
+	if len(resp.Results.Bindings) == 0 {
+		return Info{}, provider.ErrNotFound
+	}
+	b := resp.Results.Bindings[0]
+	return Info{
+		Name:       b["seriesLabel"].Value,
+		Position:   b["ordinal"].Value,
+		Confidence: Confirmed,
+		Source:     "wikidata",
+	}, nil
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Role grants access to a set of endpoints. Each role includes the
+// rights of the roles below it.
+type Role int
+
+const (
+	// Viewer may query the catalog.
+	Viewer Role = iota + 1
+	// Operator may also run enrichment jobs and see the job history.
+	Operator
+	// Admin may also change the provider configuration.
+	Admin
+)
+
+var roleNames = map[Role]string{Viewer: "viewer", Operator: "operator", Admin: "admin"}
+
+func (r Role) String() string {
+	if n, ok := roleNames[r]; ok {
+		return n
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+// ParseRole parses "viewer", "operator" or "admin".
+func ParseRole(s string) (Role, error) {
+	for r, n := range roleNames {
+		if strings.EqualFold(s, n) {
+			return r, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown role %q (want viewer, operator or admin)", s)
+}
+
+// User is an API client identified by a bearer token.
+type User struct {
+	Name  string
+	Token string
+	Role  Role
+}
+
+// anonymous is the user of servers without configured users.
+var anonymous = &User{Name: "anonymous", Role: Admin}
+
+type userKey struct{}
+
+// UserFrom returns the authenticated user of a request context.
+func UserFrom(ctx context.Context) *User {
+	u, _ := ctx.Value(userKey{}).(*User)
+	return u
+}
+
+// authenticate finds the user whose token the request carries in an
+// "Authorization: Bearer" header, or in the web UI's session cookie.
+// With no users configured every request is served as an anonymous
+// admin.
+func (s *Server) authenticate(r *http.Request) *User {
+	if len(s.Users) == 0 {
+		return anonymous
+	}
+	tok, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		if c, err := r.Cookie(tokenCookie); err == nil {
+			tok = c.Value
+		}
+	}
+	return s.userForToken(tok)
+}
+
+// userForToken returns the user with token tok, or nil.
+func (s *Server) userForToken(tok string) *User {
+	if len(s.Users) == 0 {
+		return anonymous
+	}
+	if tok == "" {
+		return nil
+	}
+	for i := range s.Users {
+		u := &s.Users[i]
+		if subtle.ConstantTimeCompare([]byte(u.Token), []byte(tok)) == 1 {
+			return u
+		}
+	}
+	return nil
+}
+
+// require wraps h so that only users with at least role reach it.
+func (s *Server) require(role Role, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := s.authenticate(r)
+		if u == nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="booktool"`)
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid token"))
+			return
+		}
+		if u.Role < role {
+			writeError(w, http.StatusForbidden, fmt.Errorf("%s role required", role))
+			return
+		}
+		h(w, r.WithContext(context.WithValue(r.Context(), userKey{}, u)))
+	}
+}
@@ -0,0 +1,294 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/gtin"
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/jobs"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// DefaultMaxUpload caps uploaded files when Server.MaxUpload is zero.
+const DefaultMaxUpload = 32 << 20
+
+// DefaultMaxJobs is the number of enrichment jobs run at once when
+// Server.MaxJobs is zero; later uploads wait their turn.
+const DefaultMaxJobs = 2
+
+// queue limits concurrent jobs and tracks progress for the readiness
+// probe.
+type queue struct {
+	once    sync.Once
+	slots   chan struct{}
+	mu      sync.Mutex
+	pending int
+	last    time.Time
+}
+
+func (s *Server) slots() chan struct{} {
+	s.queue.once.Do(func() {
+		n := s.MaxJobs
+		if n <= 0 {
+			n = DefaultMaxJobs
+		}
+		s.queue.slots = make(chan struct{}, n)
+	})
+	return s.queue.slots
+}
+
+func (q *queue) add(n int) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.pending == 0 {
+		q.last = time.Now()
+	}
+	q.pending += n
+}
+
+func (q *queue) progress() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.last = time.Now()
+}
+
+// QueueProgress reports the jobs waiting or running and the time of the
+// last row finished, for a Stalled readiness check.
+func (s *Server) QueueProgress() (pending int, last time.Time) {
+	s.queue.mu.Lock()
+	defer s.queue.mu.Unlock()
+	return s.queue.pending, s.queue.last
+}
+
+func (s *Server) newEnricher() (*enrich.Enricher, error) {
+	return s.Enricher(s.Settings.Providers())
+}
+
+func (s *Server) lookupBook(w http.ResponseWriter, r *http.Request) {
+	e, err := s.newEnricher()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	// With item providers the path may hold the barcode of another item.
+	code := isbn.Normalize(r.PathValue("isbn"))
+	if !isbn.Valid(code) && (len(e.Items) == 0 || !gtin.IsItem(code)) {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid ISBN %q", r.PathValue("isbn")))
+		return
+	}
+	b, err := e.Lookup(r.Context(), &input.Row{ISBN: code})
+	switch {
+	case errors.Is(err, provider.ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case err != nil:
+		writeError(w, http.StatusBadGateway, err)
+	default:
+		writeJSON(w, http.StatusOK, b)
+	}
+}
+
+// upload returns the uploaded file and its name, from a multipart form
+// field "file" or from the raw request body with the name in the
+// "filename" query parameter. Bodies over the upload limit fail with an
+// *http.MaxBytesError.
+func (s *Server) upload(w http.ResponseWriter, r *http.Request) ([]byte, string, error) {
+	max := s.MaxUpload
+	if max <= 0 {
+		max = DefaultMaxUpload
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, max)
+	ct, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if ct == "multipart/form-data" {
+		if err := r.ParseMultipartForm(max); err != nil {
+			return nil, "", err
+		}
+		f, h, err := r.FormFile("file")
+		if err != nil {
+			return nil, "", fmt.Errorf("file: %w", err)
+		}
+		defer f.Close()
+		data, err := io.ReadAll(f)
+		return data, h.Filename, err
+	}
+	data, err := io.ReadAll(r.Body)
+	return data, r.URL.Query().Get("filename"), err
+}
+
+// task is an uploaded file ready to be enriched.
+type task struct {
+	job     *jobs.Job
+	e       *enrich.Enricher
+	rows    input.Reader
+	format  *output.Format
+	table   columns.Layout
+	outName string
+	// data is the uploaded file.
+	data []byte
+
+	// mu guards job.Rows and job.Failed while the task runs, for
+	// progress reports.
+	mu sync.Mutex
+}
+
+// newTask reads the upload of r and records a new job for it. The query
+// parameters, or form fields of a multipart upload, input_format, sheet
+// and format select the formats; by
+// default the result has the format of the upload. On failure it
+// returns the HTTP status to answer with.
+func (s *Server) newTask(w http.ResponseWriter, r *http.Request) (*task, int, error) {
+	data, name, err := s.upload(w, r)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return nil, http.StatusRequestEntityTooLarge, err
+	}
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	q := r.URL.Query()
+	if r.MultipartForm != nil {
+		q = r.Form
+	}
+	rows, err := input.NewReader(bytes.NewReader(data), q.Get("input_format"), name, input.Options{Sheet: q.Get("sheet")})
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	t := &task{rows: rows, data: data, table: s.Table}
+	if tab, ok := input.AsTabular(rows); ok && s.PassThrough {
+		t.table = columns.PassThrough(s.Table, tab.Header())
+	}
+	t.outName = strings.TrimSuffix(name, filepath.Ext(name)) + "_enriched" + filepath.Ext(name)
+	if name == "" {
+		t.outName = "enriched.xlsx"
+	}
+	if t.format, err = output.Resolve(q.Get("format"), t.outName); err != nil {
+		rows.Close()
+		return nil, http.StatusBadRequest, err
+	}
+	if exts := t.format.Extensions; len(exts) > 0 && !strings.EqualFold(filepath.Ext(t.outName), exts[0]) {
+		t.outName = strings.TrimSuffix(t.outName, filepath.Ext(t.outName)) + exts[0]
+	}
+	if t.e, err = s.newEnricher(); err != nil {
+		rows.Close()
+		return nil, http.StatusInternalServerError, err
+	}
+
+	cfg, _ := json.Marshal(map[string]any{"providers": s.Settings.Providers(), "format": t.format.Name})
+	t.job = &jobs.Job{
+		User:    UserFrom(r.Context()).Name,
+		Kind:    "enrich",
+		Input:   name,
+		Config:  string(cfg),
+		Status:  jobs.Running,
+		Started: time.Now(),
+	}
+	if err := s.Jobs.Create(r.Context(), t.job); err != nil {
+		rows.Close()
+		return nil, http.StatusInternalServerError, err
+	}
+	s.logf("%s started job %d: enrich %q", t.job.User, t.job.ID, name)
+	return t, 0, nil
+}
+
+// contentType returns the MIME type of the task's result.
+func (t *task) contentType() string {
+	if ctype := mime.TypeByExtension(filepath.Ext(t.outName)); ctype != "" {
+		return ctype
+	}
+	return "application/octet-stream"
+}
+
+// counts returns the rows done and failed so far.
+func (t *task) counts() (rows, failed int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.job.Rows, t.job.Failed
+}
+
+// enrichFile answers POST /enrich with the enriched file.
+func (s *Server) enrichFile(w http.ResponseWriter, r *http.Request) {
+	t, status, err := s.newTask(w, r)
+	if err != nil {
+		writeError(w, status, err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := s.run(r.Context(), t, &buf); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", t.contentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": t.outName}))
+	w.Header().Set("X-Booktool-Job", strconv.FormatInt(t.job.ID, 10))
+	w.Header().Set("X-Booktool-Rows", strconv.Itoa(t.job.Rows))
+	w.Header().Set("X-Booktool-Failed", strconv.Itoa(t.job.Failed))
+	w.Write(buf.Bytes())
+}
+
+// run waits for a free slot, enriches t into dst and records the
+// outcome. It closes the task's input.
+func (s *Server) run(ctx context.Context, t *task, dst io.Writer) (err error) {
+	defer t.rows.Close()
+	defer func() { s.finishJob(t, err) }()
+	s.queue.add(1)
+	defer s.queue.add(-1)
+	select {
+	case s.slots() <- struct{}{}:
+		defer func() { <-s.slots() }()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	ow, err := t.format.New(dst)
+	if err != nil {
+		return err
+	}
+	if err := ow.WriteHeader(t.table.Header()); err != nil {
+		return err
+	}
+	err = t.e.Run(ctx, t.rows, func(res *enrich.Result) error {
+		t.mu.Lock()
+		t.job.Rows++
+		if res.Err != nil {
+			t.job.Failed++
+		}
+		t.mu.Unlock()
+		s.queue.progress()
+		return ow.Write(&output.Record{Index: res.Row.Index, Book: res.Book, Cells: t.table.Row(res), Err: res.Err})
+	})
+	if err != nil {
+		return err
+	}
+	return ow.Close()
+}
+
+// finishJob records the outcome of a job. It uses a fresh context so a
+// cancelled request is still recorded.
+func (s *Server) finishJob(t *task, err error) {
+	t.mu.Lock()
+	job := t.job
+	job.Finished, job.Status = time.Now(), jobs.Done
+	if err != nil {
+		job.Status, job.Error = jobs.Failed, err.Error()
+	}
+	t.mu.Unlock()
+	if uerr := s.Jobs.Update(context.Background(), job); uerr != nil {
+		s.logf("job %d: cannot record outcome: %v", job.ID, uerr)
+	}
+	s.logf("%s finished job %d: %s, %d rows, %d failed", job.User, job.ID, job.Status, job.Rows, job.Failed)
+}
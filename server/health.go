@@ -0,0 +1,98 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultCheckTimeout bounds a readiness check when Health.Timeout is zero.
+const DefaultCheckTimeout = 5 * time.Second
+
+// Check is one readiness condition.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Health serves the liveness and readiness probes.
+type Health struct {
+	Checks []Check
+	// Timeout bounds each readiness check; zero selects
+	// DefaultCheckTimeout.
+	Timeout time.Duration
+}
+
+// Status is the JSON body of the readiness probe.
+type Status struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
+}
+
+// Run runs every check concurrently and reports "ok" or the error of
+// each, along with whether all passed.
+func (h *Health) Run(ctx context.Context) (Status, bool) {
+	timeout := h.Timeout
+	if timeout <= 0 {
+		timeout = DefaultCheckTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	results := make([]error, len(h.Checks))
+	var wg sync.WaitGroup
+	for i, c := range h.Checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.Run(ctx)
+		}()
+	}
+	wg.Wait()
+
+	st := Status{Status: "ok", Checks: make(map[string]string, len(h.Checks))}
+	ready := true
+	for i, c := range h.Checks {
+		st.Checks[c.Name] = "ok"
+		if err := results[i]; err != nil {
+			st.Checks[c.Name] = err.Error()
+			st.Status, ready = "unavailable", false
+		}
+	}
+	return st, ready
+}
+
+// Live answers the liveness probe. It only shows that the process is
+// serving requests; dependencies are the readiness probe's job, so that
+// an upstream outage does not get the pod restarted.
+func (h *Health) Live(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	fmt.Fprintln(w, "ok")
+}
+
+// Ready answers the readiness probe with the Status as JSON, and 503
+// when any check fails.
+func (h *Health) Ready(w http.ResponseWriter, r *http.Request) {
+	st, ok := h.Run(r.Context())
+	w.Header().Set("Content-Type", "application/json")
+	if !ok {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(st)
+}
+
+// Stalled returns a check that fails when work is pending but no
+// progress has been made for longer than maxIdle. progress reports the
+// number of pending items and the time of the last completed one.
+func Stalled(name string, maxIdle time.Duration, progress func() (pending int, last time.Time)) Check {
+	return Check{Name: name, Run: func(context.Context) error {
+		pending, last := progress()
+		if idle := time.Since(last); pending > 0 && idle > maxIdle {
+			return fmt.Errorf("%d pending, no progress for %s", pending, idle.Round(time.Second))
+		}
+		return nil
+	}}
+}
@@ -0,0 +1,83 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/jobs"
+)
+
+// DefaultJobsLimit caps GET /jobs when no limit is given.
+const DefaultJobsLimit = 100
+
+// parseFilter reads a jobs.Filter from the query parameters user, kind,
+// status, since, until (RFC 3339 or YYYY-MM-DD) and limit.
+func parseFilter(q url.Values) (jobs.Filter, error) {
+	f := jobs.Filter{
+		User:   q.Get("user"),
+		Kind:   q.Get("kind"),
+		Status: jobs.Status(q.Get("status")),
+		Limit:  DefaultJobsLimit,
+	}
+	var err error
+	if f.Since, err = parseTime(q.Get("since")); err != nil {
+		return f, fmt.Errorf("since: %w", err)
+	}
+	if f.Until, err = parseTime(q.Get("until")); err != nil {
+		return f, fmt.Errorf("until: %w", err)
+	}
+	if s := q.Get("limit"); s != "" {
+		if f.Limit, err = strconv.Atoi(s); err != nil || f.Limit < 0 {
+			return f, fmt.Errorf("limit: invalid value %q", s)
+		}
+	}
+	return f, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+func (s *Server) listJobs(w http.ResponseWriter, r *http.Request) {
+	f, err := parseFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	js, err := s.Jobs.List(r.Context(), f)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if js == nil {
+		js = []*jobs.Job{}
+	}
+	writeJSON(w, http.StatusOK, js)
+}
+
+func (s *Server) getJob(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid job ID %q", r.PathValue("id")))
+		return
+	}
+	j, err := s.Jobs.Get(r.Context(), id)
+	switch {
+	case errors.Is(err, jobs.ErrNotFound):
+		writeError(w, http.StatusNotFound, err)
+	case err != nil:
+		writeError(w, http.StatusInternalServerError, err)
+	default:
+		writeJSON(w, http.StatusOK, j)
+	}
+}
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+)
+
+// metrics answers GET /metrics with the provider lookups and the HTTP
+// requests of the service in the Prometheus text format: counters by
+// provider and outcome, by host for requests, cache hits and errors,
+// and latency histograms of both.
+func (s *Server) metrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	bw := bufio.NewWriter(w)
+	defer bw.Flush()
+	if s.Lookups != nil {
+		stats := s.Lookups.Snapshot()
+		family(bw, "booktool_provider_lookups_total", "counter", "Provider lookups and searches, by outcome.")
+		for _, ls := range stats {
+			for _, c := range []struct {
+				outcome string
+				n       int
+			}{{"found", ls.Found}, {"not_found", ls.NotFound}, {"error", ls.Errors}} {
+				sample(bw, "booktool_provider_lookups_total", labels("provider", ls.Provider, "outcome", c.outcome), float64(c.n))
+			}
+		}
+		family(bw, "booktool_provider_lookup_duration_seconds", "histogram", "Duration of provider lookups and searches, cache hits included.")
+		for _, ls := range stats {
+			histogram(bw, "booktool_provider_lookup_duration_seconds", labels("provider", ls.Provider), ls.Latency)
+		}
+	}
+	if s.HTTPMetrics != nil {
+		hosts := s.HTTPMetrics.Snapshot()
+		for _, c := range []struct {
+			name, help string
+			n          func(httpx.HostStats) int
+		}{
+			{"booktool_http_requests_total", "Outbound HTTP requests, cache hits included.", func(h httpx.HostStats) int { return h.Requests }},
+			{"booktool_http_cache_hits_total", "Outbound HTTP requests answered by the response cache.", func(h httpx.HostStats) int { return h.CacheHits }},
+			{"booktool_http_errors_total", "Outbound HTTP requests that failed, or were answered with 429 or 5xx.", func(h httpx.HostStats) int { return h.Errors }},
+		} {
+			family(bw, c.name, "counter", c.help)
+			for _, h := range hosts {
+				sample(bw, c.name, labels("host", h.Host), float64(c.n(h)))
+			}
+		}
+		family(bw, "booktool_http_request_duration_seconds", "histogram", "Duration of outbound HTTP requests answered by the network.")
+		for _, h := range hosts {
+			histogram(bw, "booktool_http_request_duration_seconds", labels("host", h.Host), h.Network)
+		}
+	}
+}
+
+func family(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func sample(w io.Writer, name, labels string, v float64) {
+	fmt.Fprintf(w, "%s%s %s\n", name, labels, strconv.FormatFloat(v, 'g', -1, 64))
+}
+
+// histogram writes the buckets, sum and count of h.
+func histogram(w io.Writer, name, lbls string, h httpx.Histogram) {
+	// Add the le label to the others.
+	inner := strings.TrimSuffix(strings.TrimPrefix(lbls, "{"), "}")
+	if inner != "" {
+		inner += ","
+	}
+	for i, b := range httpx.LatencyBuckets {
+		n := 0
+		if i < len(h.Buckets) {
+			n = h.Buckets[i]
+		}
+		fmt.Fprintf(w, "%s_bucket{%sle=%q} %d\n", name, inner, seconds(b), n)
+	}
+	fmt.Fprintf(w, "%s_bucket{%sle=\"+Inf\"} %d\n", name, inner, h.Count)
+	sample(w, name+"_sum", lbls, h.Sum.Seconds())
+	sample(w, name+"_count", lbls, float64(h.Count))
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'g', -1, 64)
+}
+
+// labels formats name, value pairs as a Prometheus label set.
+func labels(pairs ...string) string {
+	var b strings.Builder
+	b.WriteByte('{')
+	for i := 0; i+1 < len(pairs); i += 2 {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(pairs[i] + `="` + labelValue.Replace(pairs[i+1]) + `"`)
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+var labelValue = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
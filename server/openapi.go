@@ -0,0 +1,264 @@
+package server
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/jobs"
+)
+
+// errorBody is the JSON body of error responses.
+type errorBody struct {
+	Error string `json:"error"`
+}
+
+// openAPI answers GET /openapi.json with the OpenAPI 3 description of
+// the JSON API, for client generators and API explorers. Its schemas
+// are derived from the types the handlers encode, so they follow them.
+func (s *Server) openAPI(w http.ResponseWriter, r *http.Request) {
+	s.spec.once.Do(func() { s.spec.doc = s.buildOpenAPI() })
+	writeJSON(w, http.StatusOK, s.spec.doc)
+}
+
+// spec caches the OpenAPI document.
+type spec struct {
+	once sync.Once
+	doc  map[string]any
+}
+
+type obj = map[string]any
+
+func (s *Server) buildOpenAPI() obj {
+	schemas := make(obj)
+	ref := func(v any) obj { return schemaOf(reflect.TypeOf(v), schemas) }
+	version := s.Version
+	if version == "" {
+		version = "devel"
+	}
+	jsonBody := func(schema obj) obj {
+		return obj{"content": obj{"application/json": obj{"schema": schema}}}
+	}
+	resp := func(desc string, schema obj) obj {
+		r := jsonBody(schema)
+		r["description"] = desc
+		return r
+	}
+	errResp := func(desc string) obj { return resp(desc, ref(errorBody{})) }
+	// op describes an operation open to role and above.
+	op := func(id, summary string, role Role, responses obj) obj {
+		responses["401"] = errResp("Missing or invalid token.")
+		responses["403"] = errResp("The token's role is below " + role.String() + ".")
+		return obj{
+			"operationId": id,
+			"summary":     summary,
+			"description": "Needs the " + role.String() + " role or above.",
+			"responses":   responses,
+		}
+	}
+	query := func(name, desc string, schema obj) obj {
+		return obj{"name": name, "in": "query", "description": desc, "schema": schema}
+	}
+	str := obj{"type": "string"}
+	date := obj{"type": "string", "description": "RFC 3339 time or YYYY-MM-DD date"}
+
+	getBook := op("getBook", "Look a book up by ISBN", Viewer, obj{
+		"200": resp("The merged record.", ref(book.BookInfo{})),
+		"400": errResp("Invalid ISBN."),
+		"404": errResp("No provider knows the ISBN."),
+		"502": errResp("The providers failed."),
+	})
+	getBook["parameters"] = []obj{{"name": "isbn", "in": "path", "required": true, "schema": str,
+		"description": "ISBN-10 or ISBN-13, with or without hyphens; other barcodes with item providers"}}
+
+	enrichFile := op("enrichFile", "Enrich a spreadsheet", Operator, obj{
+		"200": obj{
+			"description": "The enriched file, as an attachment.",
+			"headers": obj{
+				"X-Booktool-Job":    obj{"description": "Job ID", "schema": obj{"type": "integer", "format": "int64"}},
+				"X-Booktool-Rows":   obj{"description": "Rows enriched", "schema": obj{"type": "integer"}},
+				"X-Booktool-Failed": obj{"description": "Rows whose lookup failed", "schema": obj{"type": "integer"}},
+			},
+			"content": obj{"application/octet-stream": obj{"schema": obj{"type": "string", "format": "binary"}}},
+		},
+		"400": errResp("Unreadable upload or unknown format."),
+		"500": errResp("The job failed."),
+	})
+	enrichFile["parameters"] = []obj{
+		query("filename", "Name of a raw upload, whose extension selects the formats", str),
+		query("input_format", "Input format; detected from the content by default", str),
+		query("sheet", "Worksheet of spreadsheet uploads (default: first)", str),
+		query("format", "Output format (default: the upload's)", str),
+	}
+	enrichFile["requestBody"] = obj{
+		"required": true,
+		"content": obj{
+			"multipart/form-data": obj{"schema": obj{
+				"type":       "object",
+				"required":   []string{"file"},
+				"properties": obj{"file": obj{"type": "string", "format": "binary"}, "input_format": str, "sheet": str, "format": str},
+			}},
+			"application/octet-stream": obj{"schema": obj{"type": "string", "format": "binary"}},
+		},
+	}
+
+	listJobs := op("listJobs", "List the job history, newest first", Operator, obj{
+		"200": resp("The matching jobs.", obj{"type": "array", "items": ref(jobs.Job{})}),
+		"400": errResp("Invalid filter."),
+	})
+	listJobs["parameters"] = []obj{
+		query("user", "Jobs of this user", str),
+		query("kind", "Jobs of this kind, e.g. enrich", str),
+		query("status", "Jobs in this status", obj{"type": "string", "enum": []jobs.Status{jobs.Running, jobs.Done, jobs.Failed}}),
+		query("since", "Jobs started at or after this time", date),
+		query("until", "Jobs started before this time", date),
+		query("limit", "Maximum number of jobs", obj{"type": "integer", "default": DefaultJobsLimit}),
+	}
+	getJob := op("getJob", "Get a job", Operator, obj{
+		"200": resp("The job.", ref(jobs.Job{})),
+		"400": errResp("Invalid job ID."),
+		"404": errResp("Unknown job."),
+	})
+	getJob["parameters"] = []obj{{"name": "id", "in": "path", "required": true, "schema": obj{"type": "integer", "format": "int64"}}}
+
+	getProviders := op("getProviders", "Get the provider list", Admin, obj{
+		"200": resp("The providers, in priority order.", ref(providersBody{})),
+	})
+	putProviders := op("setProviders", "Replace the provider list", Admin, obj{
+		"200": resp("The new providers.", ref(providersBody{})),
+		"400": errResp("Empty list or unknown provider."),
+	})
+	putProviders["requestBody"] = obj{"required": true, "content": obj{"application/json": obj{"schema": ref(providersBody{})}}}
+
+	ready := resp("Every check passed.", ref(Status{}))
+	unavailable := resp("A check failed.", ref(Status{}))
+	return obj{
+		"openapi": "3.0.3",
+		"info": obj{
+			"title":       "booktool",
+			"description": "Book metadata enrichment API. Send the token of a user of the server's configuration as a bearer token; servers without users need none.",
+			"version":     version,
+		},
+		"paths": obj{
+			"/book/{isbn}":     obj{"get": getBook},
+			"/enrich":          obj{"post": enrichFile},
+			"/jobs":            obj{"get": listJobs},
+			"/jobs/{id}":       obj{"get": getJob},
+			"/admin/providers": obj{"get": getProviders, "put": putProviders},
+			"/healthz": obj{"get": obj{
+				"operationId": "live",
+				"summary":     "Liveness probe",
+				"security":    []obj{},
+				"responses": obj{"200": obj{
+					"description": "The process is serving.",
+					"content":     obj{"text/plain": obj{"schema": str}},
+				}},
+			}},
+			"/readyz": obj{"get": obj{
+				"operationId": "ready",
+				"summary":     "Readiness probe",
+				"security":    []obj{},
+				"responses":   obj{"200": ready, "503": unavailable},
+			}},
+			"/metrics": obj{"get": obj{
+				"operationId": "metrics",
+				"summary":     "Prometheus metrics: provider lookups, cache hits, errors and latency histograms",
+				"security":    []obj{},
+				"responses": obj{"200": obj{
+					"description": "The metrics, in the Prometheus text format.",
+					"content":     obj{"text/plain": obj{"schema": str}},
+				}},
+			}},
+		},
+		"components": obj{
+			"schemas":         schemas,
+			"securitySchemes": obj{"bearer": obj{"type": "http", "scheme": "bearer"}},
+		},
+		"security": []obj{{"bearer": []string{}}},
+	}
+}
+
+var timeType = reflect.TypeFor[time.Time]()
+
+// schemaOf returns the JSON schema of values of t as encoding/json
+// writes them. Named structs are added to schemas and referenced.
+func schemaOf(t reflect.Type, schemas obj) obj {
+	if t == timeType {
+		return obj{"type": "string", "format": "date-time"}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return schemaOf(t.Elem(), schemas)
+	case reflect.String:
+		return obj{"type": "string"}
+	case reflect.Bool:
+		return obj{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return obj{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return obj{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return obj{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return obj{"type": "array", "items": schemaOf(t.Elem(), schemas)}
+	case reflect.Map:
+		return obj{"type": "object", "additionalProperties": schemaOf(t.Elem(), schemas)}
+	case reflect.Struct:
+	default:
+		return obj{}
+	}
+	name := schemaName(t)
+	if name != "" {
+		if _, ok := schemas[name]; !ok {
+			schemas[name] = obj{} // placeholder against recursion
+			schemas[name] = structSchema(t, schemas)
+		}
+		return obj{"$ref": "#/components/schemas/" + name}
+	}
+	return structSchema(t, schemas)
+}
+
+// schemaName names the schema of a struct type in the document.
+func schemaName(t reflect.Type) string {
+	switch t {
+	case reflect.TypeFor[book.BookInfo]():
+		return "Book"
+	case reflect.TypeFor[providersBody]():
+		return "Providers"
+	case reflect.TypeFor[errorBody]():
+		return "Error"
+	case reflect.TypeFor[Status]():
+		return "Readiness"
+	}
+	return t.Name()
+}
+
+func structSchema(t reflect.Type, schemas obj) obj {
+	props := make(obj)
+	var required []string
+	for i := range t.NumField() {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		props[name] = schemaOf(f.Type, schemas)
+		if !strings.Contains(opts, "omitempty") && !strings.Contains(opts, "omitzero") && f.Type.Kind() != reflect.Pointer {
+			required = append(required, name)
+		}
+	}
+	s := obj{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
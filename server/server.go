@@ -0,0 +1,93 @@
+// Package server exposes the enricher over HTTP for deployment as a
+// long-running service.
+package server
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/SouadAli10/book_scrapping_tool/columns"
+	"github.com/SouadAli10/book_scrapping_tool/enrich"
+	"github.com/SouadAli10/book_scrapping_tool/httpx"
+	"github.com/SouadAli10/book_scrapping_tool/jobs"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// Server is the booktool HTTP service.
+type Server struct {
+	Health *Health
+	// Jobs records the job history.
+	Jobs     jobs.Store
+	Settings *Settings
+	// Users lists the API clients. When empty, authentication is off
+	// and every request has the admin role.
+	Users []User
+	// Log receives audit messages; nil discards them.
+	Log *log.Logger
+	// Enricher builds the lookup pipeline for a provider list.
+	Enricher func(providers []string) (*enrich.Enricher, error)
+	// Table is the layout of enriched files.
+	Table columns.Layout
+	// PassThrough keeps every column of uploads with a header row; see
+	// columns.PassThrough.
+	PassThrough bool
+	// MaxUpload caps uploads in bytes; zero selects DefaultMaxUpload.
+	MaxUpload int64
+	// MaxJobs caps concurrent enrichment jobs; zero selects
+	// DefaultMaxJobs.
+	MaxJobs int
+	// Version is the API version reported by /openapi.json.
+	Version string
+	// Lookups and HTTPMetrics are reported at /metrics: the lookups of
+	// the providers Enricher instruments with Lookups, and the requests
+	// of its HTTP client. Either may be nil.
+	Lookups     *provider.Stats
+	HTTPMetrics *httpx.Metrics
+
+	queue queue
+	ui    ui
+	spec  spec
+}
+
+// Handler returns the service's routes: the JSON API, described at
+// /openapi.json, the web UI under / and /ui/, and the Prometheus metrics
+// at /metrics. Probes, metrics and the API description need no
+// authentication.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", s.Health.Live)
+	mux.HandleFunc("GET /readyz", s.Health.Ready)
+	mux.HandleFunc("GET /openapi.json", s.openAPI)
+	mux.HandleFunc("GET /metrics", s.metrics)
+	mux.HandleFunc("GET /book/{isbn}", s.require(Viewer, s.lookupBook))
+	mux.HandleFunc("POST /enrich", s.require(Operator, s.enrichFile))
+	mux.HandleFunc("GET /jobs", s.require(Operator, s.listJobs))
+	mux.HandleFunc("GET /jobs/{id}", s.require(Operator, s.getJob))
+	mux.HandleFunc("GET /admin/providers", s.require(Admin, s.getProviders))
+	mux.HandleFunc("PUT /admin/providers", s.require(Admin, s.putProviders))
+	mux.HandleFunc("GET /{$}", s.page(Operator, s.uiIndex))
+	mux.HandleFunc("GET /ui/login", s.uiLoginForm)
+	mux.HandleFunc("POST /ui/login", s.uiLogin)
+	mux.HandleFunc("POST /ui/enrich", s.page(Operator, s.uiEnrich))
+	mux.HandleFunc("GET /ui/jobs/{id}", s.page(Operator, s.uiJob))
+	mux.HandleFunc("GET /ui/jobs/{id}/progress", s.require(Operator, s.uiProgress))
+	mux.HandleFunc("GET /ui/jobs/{id}/download", s.page(Operator, s.uiDownload))
+	return mux
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.Log != nil {
+		s.Log.Printf(format, args...)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
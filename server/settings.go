@@ -0,0 +1,66 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+)
+
+// Settings is the provider configuration admins can change at runtime.
+type Settings struct {
+	mu        sync.RWMutex
+	providers []string
+	// Validate checks a new provider list before it is applied.
+	Validate func(providers []string) error
+}
+
+// NewSettings returns settings starting with providers.
+func NewSettings(providers []string, validate func([]string) error) *Settings {
+	return &Settings{providers: providers, Validate: validate}
+}
+
+// Providers returns the provider names in priority order.
+func (s *Settings) Providers() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]string(nil), s.providers...)
+}
+
+// SetProviders replaces the provider list after validating it.
+func (s *Settings) SetProviders(ps []string) error {
+	if len(ps) == 0 {
+		return fmt.Errorf("no providers given")
+	}
+	if s.Validate != nil {
+		if err := s.Validate(ps); err != nil {
+			return err
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.providers = append([]string(nil), ps...)
+	return nil
+}
+
+type providersBody struct {
+	Providers []string `json:"providers"`
+}
+
+func (s *Server) getProviders(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, providersBody{s.Settings.Providers()})
+}
+
+func (s *Server) putProviders(w http.ResponseWriter, r *http.Request) {
+	var body providersBody
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.Settings.SetProviders(body.Providers); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	s.logf("%s set providers to %v", UserFrom(r.Context()).Name, body.Providers)
+	writeJSON(w, http.StatusOK, providersBody{s.Settings.Providers()})
+}
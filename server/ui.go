@@ -0,0 +1,242 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+	"mime"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/input"
+	"github.com/SouadAli10/book_scrapping_tool/jobs"
+	"github.com/SouadAli10/book_scrapping_tool/output"
+)
+
+// ResultTTL is how long the web UI keeps an enriched file for download.
+const ResultTTL = time.Hour
+
+// tokenCookie carries the access token of a web UI session.
+const tokenCookie = "booktool_token"
+
+//go:embed ui/*.html
+var uiFiles embed.FS
+
+var uiTemplates = template.Must(template.ParseFS(uiFiles, "ui/*.html"))
+
+// ui holds the jobs started from the web UI until their results expire.
+type ui struct {
+	mu    sync.Mutex
+	tasks map[int64]*uiTask
+}
+
+// uiTask is a job started from the web UI. Its result is kept in memory
+// for download.
+type uiTask struct {
+	*task
+	total int
+	// result is the enriched file, set under task.mu when the job is
+	// done.
+	result []byte
+}
+
+func (u *ui) add(t *uiTask) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.tasks == nil {
+		u.tasks = make(map[int64]*uiTask)
+	}
+	for id, old := range u.tasks {
+		old.mu.Lock()
+		expired := !old.job.Finished.IsZero() && time.Since(old.job.Finished) > ResultTTL
+		old.mu.Unlock()
+		if expired {
+			delete(u.tasks, id)
+		}
+	}
+	u.tasks[t.job.ID] = t
+}
+
+func (u *ui) get(id int64) *uiTask {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.tasks[id]
+}
+
+func render(w http.ResponseWriter, status int, name string, data any) {
+	var buf bytes.Buffer
+	if err := uiTemplates.ExecuteTemplate(&buf, name, data); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(buf.Bytes())
+}
+
+// page wraps a web UI handler like require, but sends unauthenticated
+// visitors to the sign-in form instead of answering 401.
+func (s *Server) page(role Role, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		u := s.authenticate(r)
+		if u == nil {
+			http.Redirect(w, r, "/ui/login", http.StatusSeeOther)
+			return
+		}
+		if u.Role < role {
+			render(w, http.StatusForbidden, "error", fmt.Sprintf("This page needs the %s role; you are signed in as %s (%s).", role, u.Name, u.Role))
+			return
+		}
+		h(w, r.WithContext(context.WithValue(r.Context(), userKey{}, u)))
+	}
+}
+
+func (s *Server) uiIndex(w http.ResponseWriter, r *http.Request) {
+	data := struct {
+		Formats []string
+		User    string
+	}{Formats: output.Names()}
+	if len(s.Users) > 0 {
+		data.User = UserFrom(r.Context()).Name
+	}
+	render(w, http.StatusOK, "index", data)
+}
+
+func (s *Server) uiLoginForm(w http.ResponseWriter, r *http.Request) {
+	render(w, http.StatusOK, "login", "")
+}
+
+// uiLogin checks the submitted token and keeps it in a cookie, which
+// authenticate accepts in place of the Authorization header.
+func (s *Server) uiLogin(w http.ResponseWriter, r *http.Request) {
+	tok := r.PostFormValue("token")
+	u := s.userForToken(tok)
+	if u == nil {
+		render(w, http.StatusUnauthorized, "login", "Unknown token.")
+		return
+	}
+	s.logf("%s signed in to the web UI", u.Name)
+	http.SetCookie(w, &http.Cookie{
+		Name:     tokenCookie,
+		Value:    tok,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.Redirect(w, r, "/", http.StatusSeeOther)
+}
+
+// uiEnrich starts a job in the background and sends the browser to its
+// progress page.
+func (s *Server) uiEnrich(w http.ResponseWriter, r *http.Request) {
+	t, status, err := s.newTask(w, r)
+	if err != nil {
+		render(w, status, "error", err.Error())
+		return
+	}
+	ut := &uiTask{task: t, total: countRows(t.data, r.FormValue("input_format"), t.job.Input, r.FormValue("sheet"))}
+	t.data = nil
+	s.ui.add(ut)
+	go func() {
+		var buf bytes.Buffer
+		err := s.run(context.Background(), t, &buf)
+		if err == nil {
+			t.mu.Lock()
+			ut.result = buf.Bytes()
+			t.mu.Unlock()
+		}
+	}()
+	http.Redirect(w, r, "/ui/jobs/"+strconv.FormatInt(t.job.ID, 10), http.StatusSeeOther)
+}
+
+// countRows counts the rows of an upload, for the progress bar.
+func countRows(data []byte, format, name, sheet string) int {
+	rows, err := input.NewReader(bytes.NewReader(data), format, name, input.Options{Sheet: sheet})
+	if err != nil {
+		return 0
+	}
+	defer rows.Close()
+	n := 0
+	for {
+		if _, err := rows.Next(); err != nil {
+			return n
+		}
+		n++
+	}
+}
+
+// uiTaskFor returns the task of the job in the request path, if the user
+// may see it: its owner and admins may.
+func (s *Server) uiTaskFor(r *http.Request) (*uiTask, error) {
+	id, err := strconv.ParseInt(r.PathValue("id"), 10, 64)
+	if err != nil {
+		return nil, jobs.ErrNotFound
+	}
+	t := s.ui.get(id)
+	if t == nil {
+		return nil, jobs.ErrNotFound
+	}
+	if u := UserFrom(r.Context()); u.Role < Admin && u.Name != t.job.User {
+		return nil, jobs.ErrNotFound
+	}
+	return t, nil
+}
+
+func (s *Server) uiJob(w http.ResponseWriter, r *http.Request) {
+	t, err := s.uiTaskFor(r)
+	if err != nil {
+		render(w, http.StatusNotFound, "error", "This job does not exist or its result has expired.")
+		return
+	}
+	render(w, http.StatusOK, "job", map[string]any{
+		"ID":     t.job.ID,
+		"Input":  t.job.Input,
+		"Output": t.outName,
+		"Total":  t.total,
+	})
+}
+
+// uiProgress reports a job's progress as JSON for the progress page.
+func (s *Server) uiProgress(w http.ResponseWriter, r *http.Request) {
+	t, err := s.uiTaskFor(r)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	status := t.job.Status
+	if status == jobs.Done && t.result == nil {
+		status = jobs.Running // finished, but the result is not stored yet
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"status": status,
+		"rows":   t.job.Rows,
+		"failed": t.job.Failed,
+		"total":  max(t.total, t.job.Rows),
+		"error":  t.job.Error,
+	})
+}
+
+func (s *Server) uiDownload(w http.ResponseWriter, r *http.Request) {
+	t, err := s.uiTaskFor(r)
+	if err != nil {
+		render(w, http.StatusNotFound, "error", "This job does not exist or its result has expired.")
+		return
+	}
+	t.mu.Lock()
+	result := t.result
+	t.mu.Unlock()
+	if result == nil {
+		render(w, http.StatusConflict, "error", "The job has not finished yet.")
+		return
+	}
+	w.Header().Set("Content-Type", t.contentType())
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": t.outName}))
+	w.Write(result)
+}
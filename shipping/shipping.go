@@ -0,0 +1,145 @@
+// Package shipping estimates the cost of shipping a book from its weight
+// and dimensions, using either a configurable rate table or a carrier
+// API behind the Estimator interface.
+package shipping
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+
+	"github.com/SouadAli10/book_scrapping_tool/units"
+)
+
+// ErrNoRate is returned when a parcel exceeds every tier of a rate table.
+var ErrNoRate = errors.New("shipping: parcel exceeds all rate tiers")
+
+// ErrNoWeight is returned when a parcel has no known weight.
+var ErrNoWeight = errors.New("shipping: weight unknown")
+
+// Parcel is the physical description of a single book to ship.
+type Parcel struct {
+	Dimensions units.Dimensions
+	Weight     units.Weight
+}
+
+// Quote is an estimated shipping price.
+type Quote struct {
+	Service  string
+	Cost     float64
+	Currency string
+}
+
+// Estimator produces a quote for a parcel. RateTable implements it
+// offline; carrier API clients can implement it as well.
+type Estimator interface {
+	Estimate(ctx context.Context, p Parcel) (Quote, error)
+}
+
+// Tier is one row of a rate table. A parcel fits a tier when its billable
+// weight and longest side are within the tier's limits; zero limits are
+// unbounded.
+type Tier struct {
+	Name       string  `json:"name"`
+	MaxWeightG float64 `json:"max_weight_g"`
+	MaxSideCm  float64 `json:"max_side_cm"`
+	Cost       float64 `json:"cost"`
+}
+
+// RateTable estimates costs from a list of weight tiers.
+type RateTable struct {
+	Currency string `json:"currency"`
+	// PackagingG is added to every parcel's weight to account for the
+	// envelope or box.
+	PackagingG float64 `json:"packaging_g"`
+	// DimDivisor enables dimensional weight when non-zero: the billable
+	// weight is max(actual, L*W*H/DimDivisor) with sides in cm and the
+	// result in kg, as carriers quote it (typically 5000).
+	DimDivisor float64 `json:"dim_divisor"`
+	Tiers      []Tier  `json:"tiers"`
+}
+
+// DefaultRateTable is a small letter/parcel table used when no table is
+// configured. Sellers are expected to replace it with their own rates.
+var DefaultRateTable = RateTable{
+	Currency:   "EUR",
+	PackagingG: 50,
+	Tiers: []Tier{
+		{Name: "Large letter", MaxWeightG: 500, MaxSideCm: 35.3, Cost: 2.95},
+		{Name: "Small parcel", MaxWeightG: 2000, MaxSideCm: 45, Cost: 4.99},
+		{Name: "Medium parcel", MaxWeightG: 10000, MaxSideCm: 61, Cost: 8.99},
+	},
+}
+
+// LoadRateTable reads a JSON rate table from path.
+func LoadRateTable(path string) (*RateTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("shipping: %w", err)
+	}
+	var t RateTable
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("shipping: parse %s: %w", path, err)
+	}
+	if len(t.Tiers) == 0 {
+		return nil, fmt.Errorf("shipping: %s defines no tiers", path)
+	}
+	return &t, nil
+}
+
+// BillableWeight returns the weight a carrier charges for p under t.
+func (t *RateTable) BillableWeight(p Parcel) units.Weight {
+	w := p.Weight + units.Weight(t.PackagingG)
+	if t.DimDivisor > 0 {
+		d := p.Dimensions
+		vol := float64(d.Height) * float64(d.Width) * float64(d.Thickness)
+		if dim := units.Weight(vol / t.DimDivisor * 1000); dim > w {
+			w = dim
+		}
+	}
+	return w
+}
+
+// Estimate returns the cheapest tier that fits p.
+func (t *RateTable) Estimate(_ context.Context, p Parcel) (Quote, error) {
+	if p.Weight <= 0 {
+		return Quote{}, ErrNoWeight
+	}
+	w := float64(t.BillableWeight(p))
+	side := longestSide(p.Dimensions)
+	tiers := append([]Tier(nil), t.Tiers...)
+	sort.SliceStable(tiers, func(i, j int) bool { return tiers[i].Cost < tiers[j].Cost })
+	for _, tier := range tiers {
+		if tier.MaxWeightG > 0 && w > tier.MaxWeightG {
+			continue
+		}
+		if tier.MaxSideCm > 0 && side > tier.MaxSideCm {
+			continue
+		}
+		return Quote{Service: tier.Name, Cost: tier.Cost, Currency: t.Currency}, nil
+	}
+	return Quote{}, ErrNoRate
+}
+
+func longestSide(d units.Dimensions) float64 {
+	return math.Max(float64(d.Height), math.Max(float64(d.Width), float64(d.Thickness)))
+}
+
+// Columns returns the header names of the shipping columns.
+func Columns(currency string) []string {
+	return []string{"Shipping Tier", "Shipping Cost (" + currency + ")"}
+}
+
+// Row formats q as the cells matching Columns. A failed estimate yields
+// empty cells rather than a misleading zero.
+func Row(q Quote, err error) []string {
+	if err != nil {
+		return []string{"", ""}
+	}
+	return []string{q.Service, strconv.FormatFloat(q.Cost, 'f', 2, 64)}
+}
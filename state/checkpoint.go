@@ -0,0 +1,77 @@
+package state
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Checkpoint records where a run that stopped early left off, so that
+// the next run can continue from there.
+type Checkpoint struct {
+	Input string `json:"input"`
+	// Output is where the stopped run wrote its rows.
+	Output string `json:"output"`
+	// Next is the index of the first input row not yet enriched.
+	Next int `json:"next"`
+	// Args are the flags that continue the run, with paths relative to
+	// Dir, the working directory of the stopped run.
+	Args []string  `json:"args"`
+	Dir  string    `json:"dir,omitempty"`
+	Time time.Time `json:"time"`
+}
+
+// checkpointFile names the checkpoint of an input in the checkpoints
+// area. Inputs are named by their absolute path when they have one.
+func (d Dir) checkpointFile(input string) string {
+	if abs, err := filepath.Abs(input); err == nil {
+		if _, err := os.Stat(abs); err == nil {
+			input = abs
+		}
+	}
+	sum := sha256.Sum256([]byte(input))
+	return filepath.Join(d.Path(Checkpoints), hex.EncodeToString(sum[:8])+".json")
+}
+
+// SaveCheckpoint records c as the checkpoint of c.Input, replacing any
+// earlier one.
+func (d Dir) SaveCheckpoint(c Checkpoint) error {
+	if _, err := d.Ensure(Checkpoints); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(d.checkpointFile(c.Input), append(data, '\n'), 0o600)
+}
+
+// LoadCheckpoint returns the checkpoint of input; ok is false when there
+// is none.
+func (d Dir) LoadCheckpoint(input string) (c Checkpoint, ok bool, err error) {
+	data, err := os.ReadFile(d.checkpointFile(input))
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, false, nil
+	}
+	if err != nil {
+		return c, false, err
+	}
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, false, err
+	}
+	return c, true, nil
+}
+
+// RemoveCheckpoint removes the checkpoint of input, if any.
+func (d Dir) RemoveCheckpoint(input string) error {
+	err := os.Remove(d.checkpointFile(input))
+	if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+	return err
+}
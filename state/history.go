@@ -0,0 +1,70 @@
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// historyFile is the run log in the history area, one JSON object per
+// line.
+const historyFile = "runs.jsonl"
+
+// Run summarizes one enrichment run.
+type Run struct {
+	Start     time.Time     `json:"start"`
+	Duration  time.Duration `json:"duration"`
+	Input     string        `json:"input"`
+	Output    string        `json:"output"`
+	Providers []string      `json:"providers,omitempty"`
+	Rows      int           `json:"rows"`
+	Failed    int           `json:"failed"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// AddRun appends r to the run history.
+func (d Dir) AddRun(r Run) error {
+	dir, err := d.Ensure(History)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(filepath.Join(dir, historyFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Runs returns the run history, oldest first. Lines that cannot be
+// decoded are skipped.
+func (d Dir) Runs() ([]Run, error) {
+	f, err := os.Open(filepath.Join(d.Path(History), historyFile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var runs []Run
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var r Run
+		if json.Unmarshal(sc.Bytes(), &r) == nil {
+			runs = append(runs, r)
+		}
+	}
+	return runs, sc.Err()
+}
@@ -0,0 +1,159 @@
+// Package state defines the layout of the state directory, where booktool
+// keeps everything that outlives a run: the response cache, checkpoints
+// of interrupted runs, the run history, credentials and downloaded data
+// tables. It defaults to ~/.booktool, so runs no longer depend on the
+// working directory.
+package state
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// EnvDir names the environment variable overriding the default
+// location.
+const EnvDir = "BOOKTOOL_STATE_DIR"
+
+// The areas of a state directory.
+const (
+	Cache       = "cache"
+	Checkpoints = "checkpoints"
+	History     = "history"
+	Credentials = "credentials"
+	Data        = "data"
+	Dumps       = "dumps"
+)
+
+// Area is one subdirectory of a state directory.
+type Area struct {
+	Name    string
+	Summary string
+	// Keep is set for areas that Clean leaves alone unless named
+	// explicitly.
+	Keep bool
+}
+
+// Areas lists the areas in display order.
+var Areas = []Area{
+	{Cache, "cached provider responses", false},
+	{Checkpoints, "progress of interrupted runs", false},
+	{History, "summaries of past runs", false},
+	{Credentials, "API keys and service account files", true},
+	{Data, "downloaded data tables", true},
+	{Dumps, "local indexes of bulk data dumps", true},
+}
+
+// Dir is a state directory.
+type Dir struct {
+	Root string
+}
+
+// Resolve returns the state directory at root, or the default one when
+// root is empty: $BOOKTOOL_STATE_DIR, else .booktool in the home
+// directory.
+func Resolve(root string) Dir {
+	if root == "" {
+		root = os.Getenv(EnvDir)
+	}
+	if root == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			home = "."
+		}
+		root = filepath.Join(home, ".booktool")
+	}
+	return Dir{Root: root}
+}
+
+// Path returns the directory of an area.
+func (d Dir) Path(area string) string {
+	return filepath.Join(d.Root, area)
+}
+
+// Ensure creates the directory of an area, private to the user since it
+// may hold credentials or cached personal data, and returns its path.
+func (d Dir) Ensure(area string) (string, error) {
+	p := d.Path(area)
+	return p, os.MkdirAll(p, 0o700)
+}
+
+// Usage is the disk usage of an area.
+type Usage struct {
+	Files int
+	Bytes int64
+}
+
+func (u Usage) String() string {
+	return fmt.Sprintf("%d files, %s", u.Files, formatBytes(u.Bytes))
+}
+
+// Usage returns the number and size of the files in an area; a missing
+// area is empty.
+func (d Dir) Usage(area string) (Usage, error) {
+	var u Usage
+	err := filepath.WalkDir(d.Path(area), func(path string, e fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if e.Type().IsRegular() {
+			info, err := e.Info()
+			if err != nil {
+				return err
+			}
+			u.Files++
+			u.Bytes += info.Size()
+		}
+		return nil
+	})
+	if errors.Is(err, fs.ErrNotExist) {
+		err = nil
+	}
+	return u, err
+}
+
+// Clean removes the contents of the named areas. With no names, it
+// cleans every area not marked Keep.
+func (d Dir) Clean(areas ...string) error {
+	if len(areas) == 0 {
+		for _, a := range Areas {
+			if !a.Keep {
+				areas = append(areas, a.Name)
+			}
+		}
+	}
+	for _, name := range areas {
+		if !slices.ContainsFunc(Areas, func(a Area) bool { return a.Name == name }) {
+			return fmt.Errorf("unknown state area %q (want %s)", name, strings.Join(names(), ", "))
+		}
+		if err := os.RemoveAll(d.Path(name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func names() []string {
+	var n []string
+	for _, a := range Areas {
+		n = append(n, a.Name)
+	}
+	return n
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
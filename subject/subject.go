@@ -0,0 +1,254 @@
+// Package subject cleans up the subject headings providers report.
+// OpenLibrary in particular mixes catalog headings ("Fiction, fantasy,
+// epic"), housekeeping tags ("Accessible book", "nyt:…") and the same
+// heading in several spellings. A Normalizer drops the junk, rewrites
+// inverted headings in the "Fiction / Fantasy / Epic" form retailers
+// use, title-cases, maps headings through a controlled vocabulary and
+// removes duplicates.
+package subject
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/SouadAli10/book_scrapping_tool/datatable"
+)
+
+// TableVersion is the date the subject vocabulary was last refreshed.
+const TableVersion = "2026-10-17"
+
+func init() {
+	datatable.Register(datatable.Table{
+		Name:    "subjects",
+		Summary: "subject heading vocabulary and junk headings",
+		Version: TableVersion,
+		Export:  func() ([]byte, error) { return json.MarshalIndent(vocabulary, "", "  ") },
+		Load:    loadTable,
+	})
+}
+
+// loadTable replaces vocabulary with a downloaded table.
+func loadTable(data []byte) error {
+	var v map[string]string
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+	if len(v) == 0 {
+		return errors.New("no subject headings")
+	}
+	vocabulary = v
+	return nil
+}
+
+// vocabulary maps headings, by Key, to their preferred term. Headings
+// mapped to "" are junk: library housekeeping rather than subjects.
+var vocabulary = map[string]string{
+	"accessible book":           "",
+	"protected daisy":           "",
+	"in library":                "",
+	"lending library":           "",
+	"large type books":          "",
+	"open library staff picks":  "",
+	"internet archive wishlist": "",
+	"overdrive":                 "",
+	"general":                   "",
+	"fiction, general":          "Fiction",
+	"sf":                        "Science Fiction",
+	"sci-fi":                    "Science Fiction",
+	"scifi":                     "Science Fiction",
+	"ya":                        "Young Adult Fiction",
+	"ya fiction":                "Young Adult Fiction",
+	"graphic novels":            "Comics & Graphic Novels",
+	"comics":                    "Comics & Graphic Novels",
+}
+
+var (
+	// machineTag matches the tags OpenLibrary derives from lists and
+	// bestseller data, e.g. "nyt:hardcover-fiction=2012-05-06".
+	machineTag = regexp.MustCompile(`^[a-z_]+:\S`)
+	// readingLevel matches school reading levels, e.g. "Reading
+	// Level-Grade 11".
+	readingLevel = regexp.MustCompile(`(?i)^(?:reading level|accelerated reader)\b`)
+)
+
+// heads are the first parts of the inverted headings rewritten in the
+// "Fiction / Fantasy / Epic" form, e.g. "Fiction, fantasy, epic".
+var heads = map[string]bool{
+	"fiction":                 true,
+	"juvenile fiction":        true,
+	"juvenile nonfiction":     true,
+	"young adult fiction":     true,
+	"young adult nonfiction":  true,
+	"comics & graphic novels": true,
+}
+
+// small are the words title case leaves in lower case inside a heading.
+var small = map[string]bool{
+	"a": true, "an": true, "and": true, "as": true, "at": true, "but": true,
+	"by": true, "for": true, "from": true, "in": true, "into": true, "nor": true,
+	"of": true, "on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// Normalizer cleans up subject headings. The zero value applies the
+// built-in vocabulary and keeps every heading.
+type Normalizer struct {
+	// Max limits the headings kept per book; 0 keeps all.
+	Max int
+	// Vocabulary maps headings, by Key, to preferred terms ahead of the
+	// built-in vocabulary; a heading mapped to "" is dropped.
+	Vocabulary map[string]string
+}
+
+// Normalize returns subjects cleaned up, in their original order.
+func (n *Normalizer) Normalize(subjects []string) []string {
+	var out []string
+	seen := make(map[string]bool)
+	for _, s := range subjects {
+		s, ok := n.heading(s)
+		if !ok || seen[Key(s)] {
+			continue
+		}
+		seen[Key(s)] = true
+		out = append(out, s)
+		if n.Max > 0 && len(out) == n.Max {
+			break
+		}
+	}
+	return out
+}
+
+// heading normalizes one heading; ok is false for junk.
+func (n *Normalizer) heading(s string) (string, bool) {
+	s = strings.Trim(strings.Join(strings.Fields(s), " "), " .,;")
+	if s == "" || machineTag.MatchString(s) || readingLevel.MatchString(s) {
+		return "", false
+	}
+	if t, ok := n.term(s); ok {
+		return t, t != ""
+	}
+	if head, rest, ok := strings.Cut(s, ", "); ok && heads[strings.ToLower(head)] {
+		parts := []string{head}
+		for _, p := range strings.Split(rest, ", ") {
+			if !strings.EqualFold(p, "general") {
+				parts = append(parts, p)
+			}
+		}
+		s = strings.Join(parts, " / ")
+	}
+	s = TitleCase(s)
+	if t, ok := n.term(s); ok {
+		return t, t != ""
+	}
+	return s, true
+}
+
+// term looks s up in the vocabularies.
+func (n *Normalizer) term(s string) (string, bool) {
+	k := Key(s)
+	if t, ok := n.Vocabulary[k]; ok {
+		return t, true
+	}
+	t, ok := vocabulary[k]
+	return t, ok
+}
+
+// Key is the form headings are compared and looked up in: lower case,
+// with single spaces and no surrounding punctuation.
+func Key(s string) string {
+	return strings.ToLower(strings.Trim(strings.Join(strings.Fields(s), " "), " .,;"))
+}
+
+// TitleCase capitalizes the words of heading s, except small words such
+// as "of" inside a part. Parts are separated by " / " or " -- ", as in
+// "Middle Earth (Imaginary Place) -- Fiction". Words in mixed case, such
+// as "McCarthy", and capitals in a part that is not all capitals, such
+// as "USA", are kept.
+func TitleCase(s string) string {
+	var b strings.Builder
+	for i, part := range splitParts(s) {
+		if i%2 == 1 {
+			b.WriteString(part) // separator
+			continue
+		}
+		caps := part == strings.ToUpper(part)
+		for j, w := range strings.Split(part, " ") {
+			if j > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(titleWord(w, j == 0, caps))
+		}
+	}
+	return b.String()
+}
+
+// splitParts splits s at its part separators, keeping them at the odd
+// indexes.
+func splitParts(s string) []string {
+	var parts []string
+	for {
+		i, sep := strings.Index(s, " / "), " / "
+		if j := strings.Index(s, " -- "); j >= 0 && (i < 0 || j < i) {
+			i, sep = j, " -- "
+		}
+		if i < 0 {
+			return append(parts, s)
+		}
+		parts = append(parts, s[:i], sep)
+		s = s[i+len(sep):]
+	}
+}
+
+func titleWord(w string, first, caps bool) string {
+	lower := strings.ToLower(w)
+	switch {
+	case w != lower && w != strings.ToUpper(w):
+		return w
+	case w != lower && !caps:
+		return w
+	case !first && small[lower]:
+		return lower
+	}
+	// Capitalize the first letter, after any opening punctuation.
+	i := strings.IndexFunc(lower, unicode.IsLetter)
+	if i < 0 {
+		return lower
+	}
+	r, size := utf8.DecodeRuneInString(lower[i:])
+	return lower[:i] + string(unicode.ToUpper(r)) + lower[i+size:]
+}
+
+// LoadVocabulary reads a controlled vocabulary from a CSV file of
+// heading,term rows. A row with an empty term drops the heading.
+func LoadVocabulary(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.Comment = '#'
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("subject vocabulary %s: %w", path, err)
+	}
+	v := make(map[string]string, len(rows))
+	for i, row := range rows {
+		if len(row) > 2 {
+			return nil, fmt.Errorf("subject vocabulary %s: line %d: want heading,term, got %d fields", path, i+1, len(row))
+		}
+		term := ""
+		if len(row) == 2 {
+			term = strings.TrimSpace(row[1])
+		}
+		v[Key(row[0])] = term
+	}
+	return v, nil
+}
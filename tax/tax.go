@@ -0,0 +1,144 @@
+// Package tax assigns VAT/sales-tax categories to books. Printed books
+// are reduced- or zero-rated in many countries, and digital and audio
+// editions often follow their own rules, so the category depends on the
+// edition format as well as the destination country.
+package tax
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Category is a tax treatment class.
+type Category string
+
+const (
+	Printed  Category = "printed"
+	Digital  Category = "digital"
+	Audio    Category = "audio"
+	Standard Category = "standard"
+)
+
+// CategoryFor maps an edition format such as "Hardcover", "Paperback",
+// "eBook" or "Audio CD" to a tax category. Unknown or empty formats are
+// treated as printed books, which is what most inventories hold.
+func CategoryFor(format string) Category {
+	f := strings.ToLower(format)
+	switch {
+	case strings.Contains(f, "audio"):
+		return Audio
+	case strings.Contains(f, "ebook"), strings.Contains(f, "e-book"),
+		strings.Contains(f, "kindle"), strings.Contains(f, "epub"),
+		strings.Contains(f, "digital"):
+		return Digital
+	case f == "" || strings.Contains(f, "cover") || strings.Contains(f, "paperback") ||
+		strings.Contains(f, "book") || strings.Contains(f, "print"):
+		return Printed
+	}
+	return Standard
+}
+
+// Rule assigns a tax code and rate to a category in a country.
+type Rule struct {
+	Country  string   `json:"country"`
+	Category Category `json:"category"`
+	Code     string   `json:"code"`
+	Rate     float64  `json:"rate"`
+}
+
+// Table is a set of rules together with the destination used when a row
+// does not specify one.
+type Table struct {
+	Destination string `json:"destination"`
+	Rules       []Rule `json:"rules"`
+}
+
+// DefaultTable holds common European rates as a starting point. Rates
+// change; sellers should configure a table confirmed by their accountant.
+var DefaultTable = Table{
+	Destination: "DE",
+	Rules: []Rule{
+		{Country: "DE", Category: Printed, Rate: 7},
+		{Country: "DE", Category: Digital, Rate: 7},
+		{Country: "DE", Category: Audio, Rate: 7},
+		{Country: "DE", Category: Standard, Rate: 19},
+		{Country: "FR", Category: Printed, Rate: 5.5},
+		{Country: "FR", Category: Digital, Rate: 5.5},
+		{Country: "FR", Category: Audio, Rate: 5.5},
+		{Country: "FR", Category: Standard, Rate: 20},
+		{Country: "GB", Category: Printed, Rate: 0},
+		{Country: "GB", Category: Digital, Rate: 0},
+		{Country: "GB", Category: Standard, Rate: 20},
+	},
+}
+
+// LoadTable reads a JSON tax table from path.
+func LoadTable(path string) (*Table, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("tax: %w", err)
+	}
+	var t Table
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("tax: parse %s: %w", path, err)
+	}
+	return &t, nil
+}
+
+// Assignment is the result of classifying one book.
+type Assignment struct {
+	Category Category
+	Code     string
+	Rate     float64
+	// Known is false when the table had no rule for the country and
+	// category, in which case Code and Rate are empty.
+	Known bool
+}
+
+// Assign classifies a book of the given format shipped to country. An
+// empty country selects the table's default destination. Categories
+// without a rule fall back to the country's standard rule.
+func (t *Table) Assign(format, country string) Assignment {
+	if country == "" {
+		country = t.Destination
+	}
+	country = strings.ToUpper(country)
+	cat := CategoryFor(format)
+	a := Assignment{Category: cat}
+	for _, c := range []Category{cat, Standard} {
+		for _, r := range t.Rules {
+			if strings.EqualFold(r.Country, country) && r.Category == c {
+				a.Code = r.Code
+				if a.Code == "" {
+					a.Code = DefaultCode(country, c, r.Rate)
+				}
+				a.Rate, a.Known = r.Rate, true
+				return a
+			}
+		}
+	}
+	return a
+}
+
+// DefaultCode builds the tax code used when a rule does not set one,
+// e.g. "DE-PRINTED-7".
+func DefaultCode(country string, c Category, rate float64) string {
+	return strings.ToUpper(country) + "-" + strings.ToUpper(string(c)) + "-" +
+		strconv.FormatFloat(rate, 'f', -1, 64)
+}
+
+// Columns returns the header names of the tax columns.
+func Columns() []string {
+	return []string{"Tax Category", "Tax Code", "Tax Rate (%)"}
+}
+
+// Row formats a as the cells matching Columns.
+func Row(a Assignment) []string {
+	if !a.Known {
+		return []string{string(a.Category), "", ""}
+	}
+	return []string{string(a.Category), a.Code, strconv.FormatFloat(a.Rate, 'f', -1, 64)}
+}
@@ -0,0 +1,132 @@
+package translate
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// Default endpoints of the services.
+const (
+	DefaultLibreTranslateURL = "https://libretranslate.com/translate"
+	DefaultDeepLURL          = "https://api.deepl.com/v2/translate"
+	DefaultDeepLFreeURL      = "https://api-free.deepl.com/v2/translate"
+	DefaultGoogleURL         = "https://translation.googleapis.com/language/translate/v2"
+)
+
+// LibreTranslate translates with a LibreTranslate server, such as a
+// self-hosted one, which needs no key.
+type LibreTranslate struct {
+	HTTPClient *http.Client
+	// URL is the /translate endpoint; empty uses
+	// DefaultLibreTranslateURL.
+	URL    string
+	APIKey string
+}
+
+// Name implements Service.
+func (*LibreTranslate) Name() string { return "libretranslate" }
+
+// Translate implements Service.
+func (l *LibreTranslate) Translate(ctx context.Context, texts []string, target string) ([]string, error) {
+	u := l.URL
+	if u == "" {
+		u = DefaultLibreTranslateURL
+	}
+	in := map[string]any{"q": texts, "source": "auto", "target": target, "format": "text"}
+	if l.APIKey != "" {
+		in["api_key"] = l.APIKey
+	}
+	var out struct {
+		TranslatedText []string `json:"translatedText"`
+	}
+	if err := postJSON(ctx, l.HTTPClient, u, nil, in, &out); err != nil {
+		return nil, err
+	}
+	return out.TranslatedText, nil
+}
+
+// DeepL translates with the DeepL API. Keys of free accounts, which end
+// in ":fx", are sent to the free endpoint.
+type DeepL struct {
+	HTTPClient *http.Client
+	// URL overrides the endpoint chosen from the key.
+	URL    string
+	APIKey string
+}
+
+// Name implements Service.
+func (*DeepL) Name() string { return "deepl" }
+
+// deeplTargets are the DeepL codes of languages DeepL only knows in a
+// regional variant.
+var deeplTargets = map[string]string{"en": "EN-US", "pt": "PT-PT", "zh": "ZH-HANS"}
+
+// Translate implements Service.
+func (d *DeepL) Translate(ctx context.Context, texts []string, target string) ([]string, error) {
+	u := d.URL
+	switch {
+	case u != "":
+	case strings.HasSuffix(d.APIKey, ":fx"):
+		u = DefaultDeepLFreeURL
+	default:
+		u = DefaultDeepLURL
+	}
+	code, ok := deeplTargets[target]
+	if !ok {
+		code = strings.ToUpper(target)
+	}
+	header := http.Header{"Authorization": {"DeepL-Auth-Key " + d.APIKey}}
+	in := map[string]any{"text": texts, "target_lang": code}
+	var out struct {
+		Translations []struct {
+			Text string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := postJSON(ctx, d.HTTPClient, u, header, in, &out); err != nil {
+		return nil, err
+	}
+	res := make([]string, len(out.Translations))
+	for i, t := range out.Translations {
+		res[i] = t.Text
+	}
+	return res, nil
+}
+
+// Google translates with the Google Cloud Translation API (v2), which
+// needs an API key. The key is sent in a header rather than the URL, so
+// it does not show in errors and logs.
+type Google struct {
+	HTTPClient *http.Client
+	// URL is the endpoint; empty uses DefaultGoogleURL.
+	URL    string
+	APIKey string
+}
+
+// Name implements Service.
+func (*Google) Name() string { return "google" }
+
+// Translate implements Service.
+func (g *Google) Translate(ctx context.Context, texts []string, target string) ([]string, error) {
+	u := g.URL
+	if u == "" {
+		u = DefaultGoogleURL
+	}
+	header := http.Header{"X-Goog-Api-Key": {g.APIKey}}
+	in := map[string]any{"q": texts, "target": target, "format": "text"}
+	var out struct {
+		Data struct {
+			Translations []struct {
+				TranslatedText string `json:"translatedText"`
+			} `json:"translations"`
+		} `json:"data"`
+	}
+	if err := postJSON(ctx, g.HTTPClient, u, header, in, &out); err != nil {
+		return nil, err
+	}
+	res := make([]string, len(out.Data.Translations))
+	for i, t := range out.Data.Translations {
+		res[i] = t.TranslatedText
+	}
+	return res, nil
+}
@@ -0,0 +1,151 @@
+// Package translate machine-translates the title, subjects and
+// description of book records into the language of a catalog, such as
+// Arabic or French, through a pluggable translation service:
+// LibreTranslate, DeepL or Google Cloud Translation.
+package translate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/lang"
+	"github.com/SouadAli10/book_scrapping_tool/provider"
+)
+
+// Service translates texts.
+type Service interface {
+	Name() string
+	// Translate translates texts into target, an ISO 639-1 code,
+	// detecting their language. The translations are in the order of
+	// texts.
+	Translate(ctx context.Context, texts []string, target string) ([]string, error)
+}
+
+// Translator translates book records with a Service. Translations are
+// remembered for the run, so subjects shared by many books are only
+// sent, and paid for, once.
+type Translator struct {
+	Service Service
+	// Target is the ISO 639-1 code of the language translated into.
+	Target string
+
+	mu   sync.Mutex
+	memo map[string]string
+}
+
+// Translate returns the translation of b's title, subjects and
+// description. Books already in the target language, going by their
+// language field, are not sent and get a nil translation.
+func (t *Translator) Translate(ctx context.Context, b *book.BookInfo) (*book.Translation, error) {
+	if t.inTarget(b) {
+		return nil, nil
+	}
+	texts := append([]string{b.FullTitle(), b.Description}, b.Subjects...)
+	out, err := t.texts(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", t.Service.Name(), err)
+	}
+	return &book.Translation{
+		Language:    t.Target,
+		Title:       out[0],
+		Description: out[1],
+		Subjects:    out[2:],
+	}, nil
+}
+
+// inTarget reports whether every language of b is the target language;
+// a book of unknown language is translated.
+func (t *Translator) inTarget(b *book.BookInfo) bool {
+	if len(b.Languages) == 0 {
+		return false
+	}
+	for _, code := range b.Languages {
+		if l, ok := lang.Lookup(code); !ok || l.Alpha2 != t.Target {
+			return false
+		}
+	}
+	return true
+}
+
+// texts translates texts, sending only those not translated yet. Empty
+// texts stay empty.
+func (t *Translator) texts(ctx context.Context, texts []string) ([]string, error) {
+	out := make([]string, len(texts))
+	var todo []string
+	t.mu.Lock()
+	for i, s := range texts {
+		if tr, ok := t.memo[s]; ok || s == "" {
+			out[i] = tr
+		} else if !slices.Contains(todo, s) {
+			todo = append(todo, s)
+		}
+	}
+	t.mu.Unlock()
+	if len(todo) > 0 {
+		done, err := t.Service.Translate(ctx, todo, t.Target)
+		if err != nil {
+			return nil, err
+		}
+		if len(done) != len(todo) {
+			return nil, fmt.Errorf("got %d translations for %d texts", len(done), len(todo))
+		}
+		t.mu.Lock()
+		if t.memo == nil {
+			t.memo = make(map[string]string)
+		}
+		for i, s := range todo {
+			t.memo[s] = done[i]
+		}
+		for i, s := range texts {
+			if s != "" {
+				out[i] = t.memo[s]
+			}
+		}
+		t.mu.Unlock()
+	}
+	return out, nil
+}
+
+// postJSON sends in as JSON to u and decodes the JSON response into
+// out. Services report errors in the body, which is kept in the error.
+func postJSON(ctx context.Context, c *http.Client, u string, header http.Header, in, out any) error {
+	body, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	req.Header.Set("User-Agent", provider.UserAgent)
+	req.Header.Set("Content-Type", "application/json")
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 8<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(data))
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("decode %s: %w", u, err)
+	}
+	return nil
+}
@@ -0,0 +1,172 @@
+// Package translit handles text in right-to-left scripts: it tells the
+// direction of a text and romanizes Arabic script, so catalogs keep the
+// original title next to a Latin one their systems can sort and search.
+package translit
+
+import (
+	"strings"
+	"unicode"
+)
+
+// rtl are the scripts written right to left.
+var rtl = []*unicode.RangeTable{unicode.Arabic, unicode.Hebrew, unicode.Syriac, unicode.Thaana, unicode.Nko}
+
+// IsRTL reports whether s reads right to left: whether its first letter,
+// the one that sets the direction of a paragraph, is of a right-to-left
+// script. Digits and punctuation take the direction of their context.
+func IsRTL(s string) bool {
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			return unicode.In(r, rtl...)
+		}
+	}
+	return false
+}
+
+// HasArabic reports whether s holds Arabic letters.
+func HasArabic(s string) bool {
+	return strings.IndexFunc(s, func(r rune) bool {
+		return unicode.Is(unicode.Arabic, r) && unicode.IsLetter(r)
+	}) >= 0
+}
+
+// letters romanizes the Arabic letters, and the Persian and Urdu ones
+// found in Arabic-script catalogs, after the ALA-LC tables. Alef, with
+// or without hamza, waw and ya are handled by Romanize, as their
+// reading depends on their place in the word.
+var letters = map[rune]string{
+	'ء': "ʼ", 'آ': "ā", 'ؤ': "ʼ", 'ئ': "ʼ",
+	'ب': "b", 'ت': "t", 'ث': "th", 'ج': "j", 'ح': "ḥ", 'خ': "kh",
+	'د': "d", 'ذ': "dh", 'ر': "r", 'ز': "z", 'س': "s", 'ش': "sh",
+	'ص': "ṣ", 'ض': "ḍ", 'ط': "ṭ", 'ظ': "ẓ", 'ع': "ʻ", 'غ': "gh",
+	'ف': "f", 'ق': "q", 'ك': "k", 'ل': "l", 'م': "m", 'ن': "n",
+	'ه': "h", 'ة': "ah", 'ى': "á", 'ٱ': "",
+	'پ': "p", 'چ': "ch", 'ژ': "zh", 'ک': "k", 'گ': "g", 'ی': "y",
+	'ٹ': "ṭ", 'ڈ': "ḍ", 'ڑ': "ṛ", 'ں': "n", 'ہ': "h", 'ے': "e",
+}
+
+// vowels romanizes the vowel marks of voweled text.
+var vowels = map[rune]string{
+	'ً': "an", 'ٌ': "un", 'ٍ': "in", // tanwin
+	'َ': "a", 'ُ': "u", 'ِ': "i", 'ْ': "", 'ٰ': "ā",
+}
+
+// punct romanizes the Arabic punctuation.
+var punct = map[rune]string{'،': ",", '؛': ";", '؟': "?", '٪': "%", '«': "\"", '»': "\""}
+
+const shadda = 'ّ'
+
+// Romanize returns s with its Arabic script romanized in a simplified
+// ALA-LC style: the article al- is hyphenated, and alef, waw and ya
+// read as long vowels inside a word and as consonants starting one.
+// Short vowels are only written in voweled Arabic, so most titles
+// romanize to their consonants and long vowels: كِتَابُ الأَيّام gives
+// "kitābu al-ayyām", but كتاب الأيام gives "ktāb al-ayām". Text in other
+// scripts is kept. Romanize returns "" when s has no Arabic letters.
+func Romanize(s string) string {
+	if !HasArabic(s) {
+		return ""
+	}
+	var b strings.Builder
+	rs := []rune(s)
+	start := true // at the start of a word
+	for i := 0; i < len(rs); i++ {
+		r := rs[i]
+		switch {
+		case r == 'ا':
+			if start {
+				b.WriteString("a")
+			} else {
+				b.WriteString("ā")
+			}
+		case r == 'أ' || r == 'إ':
+			// The hamza is written inside a word; the vowel is the one
+			// marked on it, else the one the alef carries.
+			if !start {
+				b.WriteString("ʼ")
+			}
+			if !marked(rs, i+1) {
+				b.WriteString(map[rune]string{'أ': "a", 'إ': "i"}[r])
+			}
+		case r == 'و' || r == 'ي':
+			vowel, cons := "ū", "w"
+			if r == 'ي' {
+				vowel, cons = "ī", "y"
+			}
+			// A consonant when it starts the word or comes before a
+			// vowel, a long vowel otherwise.
+			if next := i + 1; start || marked(rs, next) || next < len(rs) && (rs[next] == shadda || rs[next] == 'ا') {
+				b.WriteString(cons)
+			} else {
+				b.WriteString(vowel)
+			}
+		case r == shadda:
+			// Doubles the consonant written before it.
+			if i > 0 {
+				b.WriteString(romanizeLetter(rs[i-1]))
+			}
+		case r == 'ـ': // tatweel, a stretch with no sound
+		case r >= '٠' && r <= '٩':
+			b.WriteRune('0' + r - '٠')
+		case r >= '۰' && r <= '۹':
+			b.WriteRune('0' + r - '۰')
+		default:
+			if v, ok := vowels[r]; ok {
+				// A short vowel before its long letter is written once,
+				// as the long vowel.
+				if !long(r, rs, i+1) {
+					b.WriteString(v)
+				}
+			} else if p, ok := punct[r]; ok {
+				b.WriteString(p)
+			} else if l, ok := letters[r]; ok {
+				b.WriteString(l)
+			} else if !unicode.Is(unicode.Mn, r) {
+				// Other marks, such as Quranic annotations, are dropped.
+				b.WriteRune(r)
+			}
+		}
+		// The article al- (ال) starts a word.
+		if start && r == 'ا' && i+1 < len(rs) && rs[i+1] == 'ل' && i+2 < len(rs) && unicode.IsLetter(rs[i+2]) {
+			b.WriteString("l-")
+			i++
+			start = true
+			continue
+		}
+		start = !unicode.IsLetter(r) && !unicode.Is(unicode.Mn, r) && r != 'ـ'
+	}
+	return b.String()
+}
+
+// romanizeLetter romanizes a consonant for doubling.
+func romanizeLetter(r rune) string {
+	switch r {
+	case 'و':
+		return "w"
+	case 'ي':
+		return "y"
+	}
+	return letters[r]
+}
+
+// marked reports whether rs[i] is a vowel mark.
+func marked(rs []rune, i int) bool {
+	return i < len(rs) && vowels[rs[i]] != ""
+}
+
+// long reports whether the short vowel mark v comes before rs[i], the
+// letter of the same long vowel, which is itself unmarked.
+func long(v rune, rs []rune, i int) bool {
+	if i >= len(rs) || marked(rs, i+1) || i+1 < len(rs) && rs[i+1] == shadda {
+		return false
+	}
+	switch v {
+	case 'َ':
+		return rs[i] == 'ا' || rs[i] == 'ى'
+	case 'ُ':
+		return rs[i] == 'و'
+	case 'ِ':
+		return rs[i] == 'ي'
+	}
+	return false
+}
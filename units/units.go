@@ -0,0 +1,240 @@
+// Package units normalizes the physical attributes reported by book
+// providers. Providers mix centimetres and inches, grams, ounces and
+// pounds; everything is parsed into a canonical base (centimetres and
+// grams) and formatted back out in the unit system chosen by the user.
+package units
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Length is a length in centimetres.
+type Length float64
+
+// Weight is a weight in grams.
+type Weight float64
+
+const (
+	cmPerInch   = 2.54
+	gramsPerOz  = 28.349523125
+	gramsPerLb  = 453.59237
+	gramsPerKg  = 1000
+	cmPerMetre  = 100
+	cmPerMilli  = 0.1
+	ozPerPound  = 16
+	inchesPerFt = 12
+)
+
+// System selects the units used when formatting output.
+type System string
+
+const (
+	Metric   System = "metric"
+	Imperial System = "imperial"
+)
+
+// ParseSystem parses a unit system name. The empty string selects Metric.
+func ParseSystem(s string) (System, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "metric", "si", "cm", "g":
+		return Metric, nil
+	case "imperial", "us", "in", "oz":
+		return Imperial, nil
+	}
+	return "", fmt.Errorf("unknown unit system %q (want metric or imperial)", s)
+}
+
+// LengthUnit returns the abbreviation used for lengths in s.
+func (s System) LengthUnit() string {
+	if s == Imperial {
+		return "in"
+	}
+	return "cm"
+}
+
+// WeightUnit returns the abbreviation used for weights in s.
+func (s System) WeightUnit() string {
+	if s == Imperial {
+		return "oz"
+	}
+	return "g"
+}
+
+// Length converts l to the length unit of s.
+func (s System) Length(l Length) float64 {
+	if s == Imperial {
+		return float64(l) / cmPerInch
+	}
+	return float64(l)
+}
+
+// Weight converts w to the weight unit of s.
+func (s System) Weight(w Weight) float64 {
+	if s == Imperial {
+		return float64(w) / gramsPerOz
+	}
+	return float64(w)
+}
+
+// FormatLength formats l as a bare number in the length unit of s, so that
+// spreadsheet columns stay numeric. Zero values format as "".
+func (s System) FormatLength(l Length) string {
+	if l <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(s.Length(l), 'f', 2, 64)
+}
+
+// FormatWeight formats w as a bare number in the weight unit of s.
+// Grams are rounded to whole numbers; ounces keep two decimals.
+func (s System) FormatWeight(w Weight) string {
+	if w <= 0 {
+		return ""
+	}
+	prec := 2
+	if s != Imperial {
+		prec = 0
+	}
+	return strconv.FormatFloat(s.Weight(w), 'f', prec, 64)
+}
+
+// Dimensions holds the physical size of a book.
+type Dimensions struct {
+	Height    Length
+	Width     Length
+	Thickness Length
+}
+
+// IsZero reports whether no dimension is known.
+func (d Dimensions) IsZero() bool {
+	return d.Height <= 0 && d.Width <= 0 && d.Thickness <= 0
+}
+
+// Columns returns the header names of the physical attribute columns in s.
+// The unit is part of the header so the cells themselves stay numeric.
+func (s System) Columns() []string {
+	lu, wu := s.LengthUnit(), s.WeightUnit()
+	return []string{
+		"Height (" + lu + ")",
+		"Width (" + lu + ")",
+		"Thickness (" + lu + ")",
+		"Weight (" + wu + ")",
+	}
+}
+
+// Row formats d and w as the cells matching Columns.
+func (s System) Row(d Dimensions, w Weight) []string {
+	return []string{
+		s.FormatLength(d.Height),
+		s.FormatLength(d.Width),
+		s.FormatLength(d.Thickness),
+		s.FormatWeight(w),
+	}
+}
+
+var quantityRe = regexp.MustCompile(`^\s*([0-9]+(?:[.,][0-9]+)?)\s*([a-zA-Z"'.]*)\s*$`)
+
+func splitQuantity(s string) (float64, string, error) {
+	m := quantityRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, "", fmt.Errorf("cannot parse quantity %q", s)
+	}
+	v, err := strconv.ParseFloat(strings.Replace(m[1], ",", ".", 1), 64)
+	if err != nil {
+		return 0, "", fmt.Errorf("cannot parse quantity %q: %w", s, err)
+	}
+	return v, strings.TrimSuffix(strings.ToLower(m[2]), "."), nil
+}
+
+// ParseLength parses strings such as "21 cm", "8.25 inches", "210mm" or
+// `9"`. A bare number is taken to be in centimetres.
+func ParseLength(s string) (Length, error) {
+	v, unit, err := splitQuantity(s)
+	if err != nil {
+		return 0, err
+	}
+	switch unit {
+	case "", "cm", "cms", "centimeter", "centimeters", "centimetre", "centimetres":
+		return Length(v), nil
+	case "mm", "millimeter", "millimeters", "millimetre", "millimetres":
+		return Length(v * cmPerMilli), nil
+	case "m", "meter", "meters", "metre", "metres":
+		return Length(v * cmPerMetre), nil
+	case "in", "inch", "inches", `"`, "''":
+		return Length(v * cmPerInch), nil
+	case "ft", "foot", "feet", "'":
+		return Length(v * inchesPerFt * cmPerInch), nil
+	}
+	return 0, fmt.Errorf("unknown length unit %q in %q", unit, s)
+}
+
+// ParseWeight parses strings such as "340 g", "12 ounces", "1.2 pounds"
+// or "0.5kg". A bare number is taken to be in grams.
+func ParseWeight(s string) (Weight, error) {
+	v, unit, err := splitQuantity(s)
+	if err != nil {
+		return 0, err
+	}
+	switch unit {
+	case "", "g", "gr", "gram", "grams", "gramme", "grammes":
+		return Weight(v), nil
+	case "kg", "kgs", "kilogram", "kilograms", "kilo", "kilos":
+		return Weight(v * gramsPerKg), nil
+	case "oz", "ounce", "ounces":
+		return Weight(v * gramsPerOz), nil
+	case "lb", "lbs", "pound", "pounds":
+		return Weight(v * gramsPerLb), nil
+	}
+	return 0, fmt.Errorf("unknown weight unit %q in %q", unit, s)
+}
+
+var dimsSepRe = regexp.MustCompile(`\s*[x×X*]\s*`)
+
+// ParseDimensions parses a combined size string such as "24 x 16 x 3 cm"
+// or "9.2 x 6.1 x 1.3 inches". A trailing unit applies to every value
+// that does not carry its own. Values are assigned as height, width and
+// thickness in that order.
+func ParseDimensions(s string) (Dimensions, error) {
+	parts := dimsSepRe.Split(strings.TrimSpace(s), -1)
+	if len(parts) == 0 || len(parts) > 3 {
+		return Dimensions{}, fmt.Errorf("cannot parse dimensions %q", s)
+	}
+	_, unit, err := splitQuantity(parts[len(parts)-1])
+	if err != nil {
+		return Dimensions{}, fmt.Errorf("cannot parse dimensions %q: %w", s, err)
+	}
+	var ls [3]Length
+	for i, p := range parts {
+		_, u, err := splitQuantity(p)
+		if err != nil {
+			return Dimensions{}, fmt.Errorf("cannot parse dimensions %q: %w", s, err)
+		}
+		if u == "" {
+			p += " " + unit
+		}
+		if ls[i], err = ParseLength(p); err != nil {
+			return Dimensions{}, err
+		}
+	}
+	return Dimensions{Height: ls[0], Width: ls[1], Thickness: ls[2]}, nil
+}
+
+// Ounces is a convenience for providers that report weight in ounces.
+func Ounces(v float64) Weight { return Weight(v * gramsPerOz) }
+
+// Pounds is a convenience for providers that report weight in pounds.
+func Pounds(v float64) Weight { return Weight(v * gramsPerLb) }
+
+// Inches is a convenience for providers that report lengths in inches.
+func Inches(v float64) Length { return Length(v * cmPerInch) }
+
+// PoundsOunces splits w into whole pounds and remaining ounces, the way
+// carriers usually quote parcel weights.
+func PoundsOunces(w Weight) (lb int, oz float64) {
+	total := float64(w) / gramsPerOz
+	lb = int(total / ozPerPound)
+	return lb, total - float64(lb*ozPerPound)
+}
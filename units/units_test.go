@@ -0,0 +1,128 @@
+package units_test
+
+import (
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/SouadAli10/book_scrapping_tool/units"
+)
+
+func TestParseLength(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want units.Length
+	}{
+		{"21", 21},
+		{"21 cm", 21},
+		{"21,5cm", 21.5},
+		{"210mm", 21},
+		{"0.21 m", 21},
+		{"8.25 inches", 20.955},
+		{"8 in.", 20.32},
+		{`9"`, 22.86},
+		{"1 ft", 30.48},
+	} {
+		got, err := units.ParseLength(tc.in)
+		if err != nil || math.Abs(float64(got-tc.want)) > 1e-9 {
+			t.Errorf("ParseLength(%q) = %v, %v, want %v", tc.in, got, err, tc.want)
+		}
+	}
+	for _, in := range []string{"", "cm", "21 furlongs", "21 x 14"} {
+		if got, err := units.ParseLength(in); err == nil {
+			t.Errorf("ParseLength(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestParseWeight(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want units.Weight
+	}{
+		{"340", 340},
+		{"340 g", 340},
+		{"0.5kg", 500},
+		{"12 ounces", 340.19427750},
+		{"1.2 pounds", 544.310844},
+		{"2 lbs", 907.18474},
+	} {
+		got, err := units.ParseWeight(tc.in)
+		if err != nil || math.Abs(float64(got-tc.want)) > 1e-6 {
+			t.Errorf("ParseWeight(%q) = %v, %v, want %v", tc.in, got, err, tc.want)
+		}
+	}
+	for _, in := range []string{"", "heavy", "3 stone"} {
+		if got, err := units.ParseWeight(in); err == nil {
+			t.Errorf("ParseWeight(%q) = %v, want an error", in, got)
+		}
+	}
+}
+
+func TestParseDimensions(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want units.Dimensions
+	}{
+		{"24 x 16 x 3 cm", units.Dimensions{Height: 24, Width: 16, Thickness: 3}},
+		{"24×16×3", units.Dimensions{Height: 24, Width: 16, Thickness: 3}},
+		{"9 x 6 x 1 inches", units.Dimensions{Height: 22.86, Width: 15.24, Thickness: 2.54}},
+		// A value's own unit wins over the trailing one.
+		{"240mm x 16 x 3 cm", units.Dimensions{Height: 24, Width: 16, Thickness: 3}},
+		{"24 x 16 cm", units.Dimensions{Height: 24, Width: 16}},
+	} {
+		got, err := units.ParseDimensions(tc.in)
+		if err != nil {
+			t.Errorf("ParseDimensions(%q): %v", tc.in, err)
+			continue
+		}
+		for _, l := range [][2]units.Length{{got.Height, tc.want.Height}, {got.Width, tc.want.Width}, {got.Thickness, tc.want.Thickness}} {
+			if math.Abs(float64(l[0]-l[1])) > 1e-9 {
+				t.Errorf("ParseDimensions(%q) = %+v, want %+v", tc.in, got, tc.want)
+				break
+			}
+		}
+	}
+	for _, in := range []string{"", "1 x 2 x 3 x 4 cm", "big x small"} {
+		if got, err := units.ParseDimensions(in); err == nil {
+			t.Errorf("ParseDimensions(%q) = %+v, want an error", in, got)
+		}
+	}
+}
+
+func TestSystemRow(t *testing.T) {
+	d := units.Dimensions{Height: 22.86, Width: 15.24}
+	w := units.Pounds(1)
+	for _, tc := range []struct {
+		sys           units.System
+		header, cells []string
+	}{
+		{units.Metric, []string{"Height (cm)", "Width (cm)", "Thickness (cm)", "Weight (g)"}, []string{"22.86", "15.24", "", "454"}},
+		{units.Imperial, []string{"Height (in)", "Width (in)", "Thickness (in)", "Weight (oz)"}, []string{"9.00", "6.00", "", "16.00"}},
+	} {
+		if got := tc.sys.Columns(); !slices.Equal(got, tc.header) {
+			t.Errorf("%s columns %q, want %q", tc.sys, got, tc.header)
+		}
+		if got := tc.sys.Row(d, w); !slices.Equal(got, tc.cells) {
+			t.Errorf("%s row %q, want %q", tc.sys, got, tc.cells)
+		}
+	}
+}
+
+func TestParseSystem(t *testing.T) {
+	for in, want := range map[string]units.System{"": units.Metric, " SI ": units.Metric, "Imperial": units.Imperial, "us": units.Imperial} {
+		if got, err := units.ParseSystem(in); err != nil || got != want {
+			t.Errorf("ParseSystem(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if _, err := units.ParseSystem("cubits"); err == nil {
+		t.Error("ParseSystem(cubits): no error")
+	}
+}
+
+func TestPoundsOunces(t *testing.T) {
+	lb, oz := units.PoundsOunces(units.Pounds(2) + units.Ounces(5.5))
+	if lb != 2 || math.Abs(oz-5.5) > 1e-9 {
+		t.Errorf("PoundsOunces = %d lb %v oz, want 2 lb 5.5 oz", lb, oz)
+	}
+}
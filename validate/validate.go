@@ -0,0 +1,190 @@
+// Package validate checks enriched records against the rules a catalog
+// expects of them before they are saved: an ISBN whose check digit is
+// right, a page count that is a number of pages, a publication date
+// that parses and languages given as ISO 639 codes. Providers sometimes
+// serve records that break them, and such data should be caught rather
+// than enter the catalog unnoticed.
+package validate
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+	"github.com/SouadAli10/book_scrapping_tool/isbn"
+	"github.com/SouadAli10/book_scrapping_tool/lang"
+)
+
+// Mode selects what happens to records breaking the rules.
+type Mode string
+
+const (
+	// Off skips validation.
+	Off Mode = ""
+	// Report lists the violations of each record on it, in
+	// book.BookInfo.Violations.
+	Report Mode = "report"
+	// Reject fails the rows of records with violations, with an error
+	// wrapping ErrInvalid.
+	Reject Mode = "reject"
+)
+
+// ParseMode parses a -validate flag value: off, report or reject. The
+// empty string selects Off.
+func ParseMode(s string) (Mode, error) {
+	switch m := Mode(strings.ToLower(strings.TrimSpace(s))); m {
+	case Off, "off":
+		return Off, nil
+	case Report, Reject:
+		return m, nil
+	}
+	return Off, fmt.Errorf("unknown validation mode %q (want off, report or reject)", s)
+}
+
+// ErrInvalid is wrapped by the errors of rejected records.
+var ErrInvalid = errors.New("invalid record")
+
+// Violation is a field of a record breaking a rule.
+type Violation struct {
+	// Field is the JSON name of the field, e.g. "publish_date".
+	Field string
+	Value string
+	// Problem says what is wrong with Value.
+	Problem string
+}
+
+func (v Violation) String() string {
+	return fmt.Sprintf("%s %q: %s", v.Field, v.Value, v.Problem)
+}
+
+// MaxPages is the largest page count taken for a real one; bigger
+// counts are mostly several values run together.
+const MaxPages = 10000
+
+// FirstYear is the earliest publication year taken for a real one,
+// that of the first printed books.
+const FirstYear = 1450
+
+// Record checks b and returns its violations, in field order.
+func Record(b *book.BookInfo) []Violation {
+	var vs []Violation
+	add := func(field, value, problem string) {
+		vs = append(vs, Violation{field, value, problem})
+	}
+	if b.ISBN13 != "" {
+		if p := checkISBN(b.ISBN13, 13); p != "" {
+			add("isbn_13", b.ISBN13, p)
+		}
+	}
+	if b.ISBN10 != "" {
+		if p := checkISBN(b.ISBN10, 10); p != "" {
+			add("isbn_10", b.ISBN10, p)
+		}
+	}
+	if b.PublishDate != "" {
+		if p := checkDate(b.PublishDate, time.Now()); p != "" {
+			add("publish_date", b.PublishDate, p)
+		}
+	}
+	if b.Pages < 0 || b.Pages > MaxPages {
+		add("pages", strconv.Itoa(b.Pages), fmt.Sprintf("not a page count (want 1 to %d)", MaxPages))
+	}
+	for _, code := range b.Languages {
+		if !isLanguageCode(code) {
+			add("languages", code, "not an ISO 639 language code")
+		}
+	}
+	return vs
+}
+
+// Check validates b in mode m: it records the violations on b in Report
+// mode, and returns an error listing them in Reject mode.
+func Check(m Mode, b *book.BookInfo) error {
+	if m == Off {
+		return nil
+	}
+	vs := Record(b)
+	if len(vs) == 0 {
+		return nil
+	}
+	list := make([]string, len(vs))
+	for i, v := range vs {
+		list[i] = v.String()
+	}
+	if m == Reject {
+		return fmt.Errorf("%w: %s", ErrInvalid, strings.Join(list, "; "))
+	}
+	b.Violations = list
+	return nil
+}
+
+// checkISBN returns what is wrong with code as an ISBN of n digits, or
+// "".
+func checkISBN(code string, n int) string {
+	c := isbn.Normalize(code)
+	switch {
+	case len(c) != n:
+		return fmt.Sprintf("not an ISBN-%d", n)
+	case n == 13 && !isbn.Valid13(c), n == 10 && !isbn.Valid10(c):
+		return "wrong check digit"
+	}
+	return ""
+}
+
+// dateLayouts are the forms of publication dates providers serve.
+var dateLayouts = []string{
+	"2006", "2006-01", "2006-01-02", "2006/01/02", "01/02/2006", "02.01.2006",
+	"January 2006", "Jan 2006", "January 2, 2006", "Jan 2, 2006", "2 January 2006", "2 Jan 2006",
+}
+
+// datePrefixes mark copyright and approximate dates, as in "©1990",
+// "circa 1990" or "ca. 1990".
+var datePrefixes = []string{"©", "circa", "ca.", "c."}
+
+// checkDate returns what is wrong with the publication date d, or "".
+// Copyright marks, approximate dates and the brackets of inferred dates
+// are allowed, and the year must fall between FirstYear and two years
+// after now, for announced books.
+func checkDate(d string, now time.Time) string {
+	s := strings.Trim(strings.TrimSpace(d), "[]")
+	for _, p := range datePrefixes {
+		if len(s) >= len(p) && strings.EqualFold(s[:len(p)], p) {
+			s = strings.TrimSpace(s[len(p):])
+			break
+		}
+	}
+	// "c1990" is a copyright date as MARC catalogs write it.
+	if len(s) > 1 && (s[0] == 'c' || s[0] == 'C') && s[1] >= '0' && s[1] <= '9' {
+		s = s[1:]
+	}
+	s = strings.TrimSuffix(strings.Trim(strings.TrimSpace(s), "[]"), ".")
+	for _, layout := range dateLayouts {
+		t, err := time.Parse(layout, s)
+		if err != nil {
+			continue
+		}
+		if y := t.Year(); y < FirstYear || y > now.Year()+2 {
+			return fmt.Sprintf("year %d out of range", y)
+		}
+		return ""
+	}
+	return "not a date"
+}
+
+// isLanguageCode reports whether code is an ISO 639-1 or 639-2 code,
+// as opposed to a language name or an unknown code.
+func isLanguageCode(code string) bool {
+	c := strings.ToLower(strings.TrimSpace(code))
+	c = c[strings.LastIndex(c, "/")+1:]
+	if i := strings.IndexAny(c, "-_"); i > 0 {
+		c = c[:i]
+	}
+	if len(c) != 2 && len(c) != 3 {
+		return false
+	}
+	_, ok := lang.Lookup(c)
+	return ok
+}
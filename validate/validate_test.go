@@ -0,0 +1,112 @@
+package validate
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/SouadAli10/book_scrapping_tool/book"
+)
+
+func TestRecord(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		book   book.BookInfo
+		fields []string
+	}{
+		{"valid", book.BookInfo{ISBN13: "9780306406157", ISBN10: "0306406152", PublishDate: "March 2003", Pages: 320, Languages: []string{"eng", "fr"}}, nil},
+		{"empty", book.BookInfo{}, nil},
+		{"check digits", book.BookInfo{ISBN13: "9780306406158", ISBN10: "0306406153"}, []string{"isbn_13", "isbn_10"}},
+		{"short isbn", book.BookInfo{ISBN13: "978030640615"}, []string{"isbn_13"}},
+		{"date", book.BookInfo{PublishDate: "someday"}, []string{"publish_date"}},
+		{"pages", book.BookInfo{Pages: 3201995}, []string{"pages"}},
+		{"negative pages", book.BookInfo{Pages: -1}, []string{"pages"}},
+		{"language names", book.BookInfo{Languages: []string{"English", "eng"}}, []string{"languages"}},
+		{"order", book.BookInfo{ISBN10: "123", PublishDate: "1200", Languages: []string{"xyz"}}, []string{"isbn_10", "publish_date", "languages"}},
+	} {
+		var fields []string
+		for _, v := range Record(&tc.book) {
+			fields = append(fields, v.Field)
+		}
+		if !slices.Equal(fields, tc.fields) {
+			t.Errorf("%s: violations in %q, want %q", tc.name, fields, tc.fields)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	b := &book.BookInfo{ISBN13: "9780306406157", Pages: -5}
+	if err := Check(Reject, b); !errors.Is(err, ErrInvalid) || len(b.Violations) > 0 {
+		t.Errorf("reject: error %v, violations %q; want ErrInvalid and none recorded", err, b.Violations)
+	}
+	if err := Check(Report, b); err != nil || len(b.Violations) != 1 {
+		t.Errorf("report: error %v, violations %q; want one recorded", err, b.Violations)
+	}
+	b = &book.BookInfo{Pages: -5}
+	if err := Check(Off, b); err != nil || len(b.Violations) > 0 {
+		t.Errorf("off: error %v, violations %q", err, b.Violations)
+	}
+}
+
+func TestCheckDate(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	for _, tc := range []struct {
+		date string
+		ok   bool
+	}{
+		{"2003", true},
+		{"2003-04", true},
+		{"2003-04-15", true},
+		{"15.04.2003", true},
+		{"April 15, 2003", true},
+		{"15 Apr 2003", true},
+		{"c1990", true},
+		{"©1990", true},
+		{"© 1990", true},
+		{"[1990]", true},
+		{"[c1990]", true},
+		{"1990.", true},
+		{"circa 1990", true},
+		{"Circa 1990", true},
+		{"ca. 1990", true},
+		{"c. 1990", true},
+		{"2028", true},
+		{"2029", false},
+		{"1449", false},
+		{"1450", true},
+		{"circa", false},
+		{"c", false},
+		{"cx1990", false},
+		{"someday", false},
+		{"1990s", false},
+	} {
+		p := checkDate(tc.date, now)
+		if ok := p == ""; ok != tc.ok {
+			t.Errorf("checkDate(%q) = %q, want ok %v", tc.date, p, tc.ok)
+		}
+	}
+}
+
+func TestIsLanguageCode(t *testing.T) {
+	for _, tc := range []struct {
+		code string
+		want bool
+	}{
+		{"eng", true},
+		{"en", true},
+		{"FRE", true},
+		{"fra", true},
+		{"/languages/ger", true},
+		{"en-US", true},
+		{"pt_BR", true},
+		{"English", false},
+		{"xyz", false},
+		{"e", false},
+		{"", false},
+	} {
+		if got := isLanguageCode(tc.code); got != tc.want {
+			t.Errorf("isLanguageCode(%q) = %v, want %v", tc.code, got, tc.want)
+		}
+	}
+}